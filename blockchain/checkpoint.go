@@ -0,0 +1,82 @@
+package blockchain
+
+import (
+	"fmt"
+
+	"github.com/kardiachain/go-kardia/configs"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// ErrCheckpointMismatch is returned when a block disagrees with the trusted
+// checkpoint.
+type ErrCheckpointMismatch struct {
+	Height     uint64
+	Hash       common.Hash
+	Checkpoint common.Hash
+}
+
+func (e ErrCheckpointMismatch) Error() string {
+	return fmt.Sprintf("block #%d %v doesn't match the trusted checkpoint %v",
+		e.Height, e.Hash.Hex(), e.Checkpoint.Hex())
+}
+
+// checkpoint is the trusted block a fast sync must go through.
+type checkpoint struct {
+	height uint64 // 0 if there's no checkpoint
+	hash   common.Hash
+	// hashChain skips the commit verification of the blocks below the
+	// checkpoint, they're only checked to chain up to it.
+	hashChain bool
+}
+
+func newCheckpoint(fastSync *configs.FastSyncConfig) checkpoint {
+	return checkpoint{
+		height:    fastSync.CheckpointHeight,
+		hash:      fastSync.CheckpointHash,
+		hashChain: fastSync.CheckpointHeight > 0 && fastSync.HashChainBelowCheckpoint,
+	}
+}
+
+// verify checks block against the checkpoint, if it's at its height.
+func (cp checkpoint) verify(block *types.Block) error {
+	if cp.height == 0 || block.Height() != cp.height || block.Hash().Equal(cp.hash) {
+		return nil
+	}
+	return ErrCheckpointMismatch{Height: block.Height(), Hash: block.Hash(), Checkpoint: cp.hash}
+}
+
+// below returns whether height is below the checkpoint and so the block at
+// height only needs to chain up to it.
+func (cp checkpoint) below(height uint64) bool {
+	return cp.hashChain && height < cp.height
+}
+
+// verifyHashChain checks that next is the block after the one with blockID
+// and that the commit it carries is the one its header commits to.
+func verifyHashChain(blockID types.BlockID, next *types.Block) error {
+	if !next.Header().LastBlockID.Equal(blockID) {
+		return fmt.Errorf("block #%d follows %v, not %v", next.Height(), next.Header().LastBlockID, blockID)
+	}
+	if next.LastCommit() == nil || !next.LastCommit().Hash().Equal(next.LastCommitHash()) {
+		return fmt.Errorf("block #%d carries another commit than the one in its header", next.Height())
+	}
+	if !next.LastCommit().BlockID.Equal(blockID) {
+		return fmt.Errorf("block #%d carries the commit for %v, not %v", next.Height(), next.LastCommit().BlockID, blockID)
+	}
+	return nil
+}
+
+// VerifyCheckpoint checks that the stored block at the height of the trusted
+// checkpoint, if any, is the checkpoint.
+func VerifyCheckpoint(store blockStore, fastSync *configs.FastSyncConfig) error {
+	cp := newCheckpoint(fastSync)
+	if cp.height == 0 || store.Height() < cp.height || store.Base() > cp.height {
+		return nil
+	}
+	block := store.LoadBlock(cp.height)
+	if block == nil {
+		return nil
+	}
+	return cp.verify(block)
+}
@@ -0,0 +1,79 @@
+package blockchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kardiachain/go-kardia/configs"
+	"github.com/kardiachain/go-kardia/lib/p2p"
+	"github.com/kardiachain/go-kardia/trie"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+func checkpointConfig(height uint64, block *types.Block, hashChain bool) *configs.FastSyncConfig {
+	cfg := configs.TestFastSyncConfig()
+	cfg.CheckpointHeight = height
+	if block != nil {
+		cfg.CheckpointHash = block.Hash()
+	}
+	cfg.HashChainBelowCheckpoint = hashChain
+	return cfg
+}
+
+// forkBlock makes a block at height other than the one of makePcBlock.
+func forkBlock(height uint64) *types.Block {
+	return types.NewBlock(&types.Header{Height: height, GasLimit: 1}, nil, nil, nil, trie.NewStackTrie(nil))
+}
+
+func TestCheckpointVerify(t *testing.T) {
+	block := makePcBlock(5)
+	cp := newCheckpoint(checkpointConfig(5, block, true))
+
+	assert.NoError(t, cp.verify(block))
+	assert.NoError(t, cp.verify(makePcBlock(4)))
+	assert.Error(t, cp.verify(forkBlock(5)))
+
+	assert.True(t, cp.below(4))
+	assert.False(t, cp.below(5))
+	assert.False(t, newCheckpoint(checkpointConfig(5, block, false)).below(4))
+	assert.False(t, newCheckpoint(checkpointConfig(0, nil, true)).below(4))
+	assert.NoError(t, newCheckpoint(checkpointConfig(0, nil, false)).verify(block))
+}
+
+func TestVerifyCheckpoint(t *testing.T) {
+	store := &mockBlockStore{blocks: make(map[uint64]*types.Block)}
+	for h := uint64(1); h <= 3; h++ {
+		store.SaveBlock(makePcBlock(h), nil, nil)
+	}
+
+	assert.NoError(t, VerifyCheckpoint(store, checkpointConfig(2, store.LoadBlock(2), false)))
+	assert.NoError(t, VerifyCheckpoint(store, checkpointConfig(10, makePcBlock(10), false)), "above the store")
+	err := VerifyCheckpoint(store, checkpointConfig(2, store.LoadBlock(3), false))
+	assert.IsType(t, ErrCheckpointMismatch{}, err)
+}
+
+func TestScHandleBlockResponseCheckpoint(t *testing.T) {
+	now := time.Now()
+	block := makePcBlock(6)
+	sc := newTestScheduler(scTestParams{
+		peers:       map[string]*scPeer{"P1": {height: 8, state: peerStateReady}},
+		allB:        []uint64{1, 2, 3, 4, 5, 6, 7, 8},
+		pending:     map[uint64]p2p.ID{6: "P1"},
+		pendingTime: map[uint64]time.Time{6: now},
+	})
+	sc.checkpoint = newCheckpoint(checkpointConfig(6, block, false))
+
+	event, err := sc.handleBlockResponse(bcBlockResponse{
+		peerID: "P1",
+		block:  forkBlock(6),
+		time:   now,
+	})
+	assert.NoError(t, err)
+	if assert.IsType(t, scPeerError{}, event) {
+		assert.IsType(t, ErrCheckpointMismatch{}, event.(scPeerError).reason)
+		assert.True(t, event.(scPeerError).violation)
+	}
+	assert.EqualValues(t, peerStateRemoved, sc.peers["P1"].state)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/kardiachain/go-kardia/kai/state/cstate"
+	"github.com/kardiachain/go-kardia/lib/common"
 	"github.com/kardiachain/go-kardia/lib/p2p"
 	"github.com/kardiachain/go-kardia/types"
 )
@@ -63,6 +64,14 @@ type pcState struct {
 
 	// the processorContext which contains the processor dependencies
 	context processorContext
+
+	// the trusted block the synced chain must go through
+	checkpoint checkpoint
+
+	// hashes of the blocks below the checkpoint known to chain up to it, down
+	// to anchorHeight
+	anchors      map[uint64]common.Hash
+	anchorHeight uint64
 }
 
 func (state *pcState) String() string {
@@ -77,6 +86,7 @@ func newPcState(context processorContext) *pcState {
 		draining:     false,
 		blocksSynced: 0,
 		context:      context,
+		anchors:      make(map[uint64]common.Hash),
 	}
 }
 
@@ -119,6 +129,53 @@ func (state *pcState) purgePeer(peerID p2p.ID) {
 	}
 }
 
+// trustedHash returns the hash of the block at height if it's the checkpoint or
+// known to chain up to it.
+func (state *pcState) trustedHash(height uint64) (common.Hash, bool) {
+	if height == state.checkpoint.height {
+		return state.checkpoint.hash, true
+	}
+	hash, ok := state.anchors[height]
+	return hash, ok
+}
+
+// anchor verifies backward from the checkpoint that the queued blocks chain up
+// to it, down to height. It returns false while a block in between is missing,
+// and a verification failure for the peers of a block breaking the chain.
+func (state *pcState) anchor(height uint64) (bool, *pcBlockVerificationFailure) {
+	h := state.anchorHeight
+	if h == 0 {
+		h = state.checkpoint.height
+	}
+	for ; h > height; h-- {
+		above, ok := state.queue[h]
+		if !ok {
+			return false, nil
+		}
+		below, ok := state.queue[h-1]
+		if !ok {
+			return false, nil
+		}
+		if hash, _ := state.trustedHash(h); !above.block.Hash().Equal(hash) {
+			state.purgePeer(above.peerID)
+			return false, &pcBlockVerificationFailure{height: h, firstPeerID: above.peerID, secondPeerID: above.peerID}
+		}
+		belowID := types.BlockID{Hash: below.block.Hash(), PartsHeader: below.block.MakePartSet(types.BlockPartSizeBytes).Header()}
+		if !above.block.Header().LastBlockID.Equal(belowID) {
+			// +above+ is trusted, +below+ is not the block it follows
+			state.purgePeer(below.peerID)
+			return false, &pcBlockVerificationFailure{height: h - 1, firstPeerID: below.peerID, secondPeerID: below.peerID}
+		}
+		if err := verifyHashChain(belowID, above.block); err != nil {
+			state.purgePeer(above.peerID)
+			return false, &pcBlockVerificationFailure{height: h, firstPeerID: above.peerID, secondPeerID: above.peerID}
+		}
+		state.anchors[h-1] = below.block.Hash()
+		state.anchorHeight = h - 1
+	}
+	return true, nil
+}
+
 // handle processes FSM events
 func (state *pcState) handle(event Event) (Event, error) {
 	switch event := event.(type) {
@@ -164,8 +221,23 @@ func (state *pcState) handle(event Event) (Event, error) {
 			firstID       = types.BlockID{Hash: first.Hash(), PartsHeader: firstParts.Header()}
 		)
 
-		// verify if +second+ last commit "confirms" +first+ block
-		err = state.context.verifyCommit(kaiState.ChainID, firstID, first.Height(), second.LastCommit())
+		anchored := false
+		if state.checkpoint.below(first.Height()) {
+			var failure *pcBlockVerificationFailure
+			if anchored, failure = state.anchor(first.Height()); failure != nil {
+				return *failure, nil
+			}
+		}
+		if anchored {
+			// verify if +second+, chaining up to the checkpoint, links to +first+
+			err = verifyHashChain(firstID, second)
+			if hash, _ := state.trustedHash(second.Height()); err == nil && !second.Hash().Equal(hash) {
+				err = fmt.Errorf("block #%d doesn't chain up to the checkpoint", second.Height())
+			}
+		} else {
+			// verify if +second+ last commit "confirms" +first+ block
+			err = state.context.verifyCommit(kaiState.ChainID, firstID, first.Height(), second.LastCommit())
+		}
 		if err != nil {
 			state.purgePeer(firstItem.peerID)
 			if firstItem.peerID != secondItem.peerID {
@@ -183,6 +255,7 @@ func (state *pcState) handle(event Event) (Event, error) {
 		}
 
 		delete(state.queue, first.Height())
+		delete(state.anchors, first.Height())
 		state.blocksSynced++
 
 		return pcBlockProcessed{height: first.Height(), peerID: firstItem.peerID}, nil
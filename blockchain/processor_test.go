@@ -305,3 +305,100 @@ func TestScFinishedEv(t *testing.T) {
 
 	executeProcessorTests(t, tests)
 }
+
+// makeLinkedChain makes blocks from 1 to height, each one carrying the commit
+// for the previous one.
+func makeLinkedChain(height uint64) []*types.Block {
+	chain := []*types.Block{nil, makePcBlock(1)}
+	for h := uint64(2); h <= height; h++ {
+		chain = append(chain, makeLinkedBlock(chain[h-1], h, 0))
+	}
+	return chain
+}
+
+// makeLinkedBlock makes the block at height following prev, fork telling
+// forked blocks apart.
+func makeLinkedBlock(prev *types.Block, height uint64, fork uint64) *types.Block {
+	prevID := types.BlockID{Hash: prev.Hash(), PartsHeader: prev.MakePartSet(types.BlockPartSizeBytes).Header()}
+	header := &types.Header{Height: height, LastBlockID: prevID, GasLimit: fork}
+	return types.NewBlock(header, nil, types.NewCommit(height-1, 0, prevID, nil), nil, trie.NewStackTrie(nil))
+}
+
+func makeCheckpointState(chain []*types.Block, cpHeight uint64, verBL []uint64) *pcState {
+	state := newPcState(newMockProcessorContext(cstate.LatestBlockState{}, verBL, nil))
+	state.checkpoint = newCheckpoint(checkpointConfig(cpHeight, chain[cpHeight], true))
+	return state
+}
+
+func TestPcProcessBelowCheckpoint(t *testing.T) {
+	chain := makeLinkedChain(6)
+	// Commit verification fails for every block, only the hash chain can
+	// verify them.
+	verBL := []uint64{1, 2, 3, 4, 5}
+
+	state := makeCheckpointState(chain, 5, verBL)
+	for h := uint64(1); h <= 6; h++ {
+		state.enqueue("P1", chain[h], h)
+	}
+	for h := uint64(1); h <= 4; h++ {
+		event, err := state.handle(rProcessBlock{})
+		assert.NoError(t, err)
+		assert.Equal(t, pcBlockProcessed{height: h, peerID: "P1"}, event)
+	}
+	// The checkpoint itself has its commit verified.
+	event, err := state.handle(rProcessBlock{})
+	assert.NoError(t, err)
+	assert.IsType(t, pcBlockVerificationFailure{}, event)
+	assert.EqualValues(t, 4, state.height())
+}
+
+func TestPcProcessBelowCheckpointNotReached(t *testing.T) {
+	chain := makeLinkedChain(5)
+
+	// Without the blocks up to the checkpoint, commits are verified.
+	state := makeCheckpointState(chain, 5, []uint64{1})
+	state.enqueue("P1", chain[1], 1)
+	state.enqueue("P1", chain[2], 2)
+	event, err := state.handle(rProcessBlock{})
+	assert.NoError(t, err)
+	assert.Equal(t, pcBlockVerificationFailure{height: 1, firstPeerID: "P1", secondPeerID: "P1"}, event)
+
+	state = makeCheckpointState(chain, 5, nil)
+	state.enqueue("P1", chain[1], 1)
+	state.enqueue("P1", chain[2], 2)
+	event, err = state.handle(rProcessBlock{})
+	assert.NoError(t, err)
+	assert.Equal(t, pcBlockProcessed{height: 1, peerID: "P1"}, event)
+}
+
+func TestPcProcessForgedChainBelowCheckpoint(t *testing.T) {
+	chain := makeLinkedChain(5)
+	// A forged block 2 carries the genuine commit for block 1, and a forged
+	// block 3 links to it.
+	forged2 := makeLinkedBlock(chain[1], 2, 1)
+	forged3 := makeLinkedBlock(forged2, 3, 1)
+
+	state := makeCheckpointState(chain, 5, nil)
+	state.enqueue("P1", chain[1], 1)
+	state.enqueue("P2", forged2, 2)
+	state.enqueue("P2", forged3, 3)
+	state.enqueue("P1", chain[4], 4)
+	state.enqueue("P1", chain[5], 5)
+
+	event, err := state.handle(rProcessBlock{})
+	assert.NoError(t, err)
+	assert.Equal(t, pcBlockVerificationFailure{height: 3, firstPeerID: "P2", secondPeerID: "P2"}, event)
+	assert.EqualValues(t, 0, state.height(), "no block applied")
+	assert.NotContains(t, state.queue, uint64(2))
+	assert.NotContains(t, state.queue, uint64(3))
+	assert.Contains(t, state.queue, uint64(4), "blocks of the honest peer are kept")
+
+	// The genuine blocks are then accepted.
+	state.enqueue("P3", chain[2], 2)
+	state.enqueue("P3", chain[3], 3)
+	for i, peerID := range []p2p.ID{"P1", "P3", "P3", "P1"} {
+		event, err := state.handle(rProcessBlock{})
+		assert.NoError(t, err)
+		assert.Equal(t, pcBlockProcessed{height: uint64(i + 1), peerID: peerID}, event)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/kardiachain/go-kardia/configs"
 	"github.com/kardiachain/go-kardia/kai/state/cstate"
 	"github.com/kardiachain/go-kardia/lib/behaviour"
+	"github.com/kardiachain/go-kardia/lib/event"
 	"github.com/kardiachain/go-kardia/lib/log"
 	"github.com/kardiachain/go-kardia/lib/p2p"
 	ksync "github.com/kardiachain/go-kardia/lib/sync"
@@ -40,7 +41,11 @@ type BlockchainReactor struct {
 	mtx           ksync.RWMutex
 	maxPeerHeight uint64
 	syncHeight    uint64
+	startHeight   uint64     // height of the store when the fast sync started
+	startTime     time.Time  // time the fast sync started
+	syncRate      float64    // moving average of the blocks synced per second
 	events        chan Event // non-nil during a fast sync
+	syncFeed      event.Feed // progress on fast sync start and end
 
 	reporter behaviour.Reporter
 	io       iIO
@@ -62,6 +67,7 @@ func newReactor(state cstate.LatestBlockState, store blockStore, reporter behavi
 	pContext := newProcessorContext(store, blockApplier, state)
 	// newPcState requires a processorContext
 	processor := newPcState(pContext)
+	processor.checkpoint = newCheckpoint(fastSync)
 	// Create a specific logger for blockchain reactor.
 	logger := log.New()
 	logger.AddTag(fastSync.ServiceName)
@@ -112,6 +118,12 @@ func (r *BlockchainReactor) setSyncHeight(height uint64) {
 	r.syncHeight = height
 }
 
+func (r *BlockchainReactor) setSyncRate(rate float64) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.syncRate = rate
+}
+
 // SyncHeight returns the height to which the BlockchainReactor has synced.
 func (r *BlockchainReactor) SyncHeight() uint64 {
 	r.mtx.RLock()
@@ -119,6 +131,50 @@ func (r *BlockchainReactor) SyncHeight() uint64 {
 	return r.syncHeight
 }
 
+// SyncProgress is the progress of a fast sync.
+type SyncProgress struct {
+	Syncing         bool
+	StartingHeight  uint64
+	CurrentHeight   uint64
+	HighestHeight   uint64 // highest height reported by the peers
+	BlocksPerSecond float64
+	ETA             time.Duration // zero while the rate is unknown
+}
+
+// Progress returns the progress of the current fast sync, or of the last one
+// if Syncing is false.
+func (r *BlockchainReactor) Progress() SyncProgress {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	p := SyncProgress{
+		Syncing:         r.events != nil,
+		StartingHeight:  r.startHeight,
+		CurrentHeight:   r.syncHeight,
+		HighestHeight:   r.maxPeerHeight,
+		BlocksPerSecond: r.syncRate,
+	}
+	if p.HighestHeight < p.CurrentHeight {
+		p.HighestHeight = p.CurrentHeight
+	}
+	// The moving average is only updated every 100 blocks, use the average
+	// since the start until then.
+	if p.BlocksPerSecond == 0 && p.CurrentHeight > p.StartingHeight {
+		if elapsed := time.Since(r.startTime).Seconds(); elapsed > 0 {
+			p.BlocksPerSecond = float64(p.CurrentHeight-p.StartingHeight) / elapsed
+		}
+	}
+	if p.BlocksPerSecond > 0 {
+		p.ETA = time.Duration(float64(p.HighestHeight-p.CurrentHeight) / p.BlocksPerSecond * float64(time.Second))
+	}
+	return p
+}
+
+// SubscribeSyncProgress subscribes to the progress at the start and at the
+// end of fast syncs.
+func (r *BlockchainReactor) SubscribeSyncProgress(ch chan<- SyncProgress) event.Subscription {
+	return r.syncFeed.Subscribe(ch)
+}
+
 // SetLogger sets the logger of the reactor.
 func (r *BlockchainReactor) SetLogger(logger log.Logger) {
 	r.logger = logger
@@ -134,10 +190,22 @@ func (r *BlockchainReactor) Start() error {
 		if err != nil {
 			return fmt.Errorf("failed to start fast sync: %w", err)
 		}
+		r.syncFeed.Send(r.Progress())
 	}
 	return nil
 }
 
+// SwitchToFastSync is called by the state sync reactor once it has restored the
+// state at state.LastBlockHeight, to fast sync the blocks above it.
+func (r *BlockchainReactor) SwitchToFastSync(state cstate.LatestBlockState) error {
+	state = state.Copy()
+	if err := r.startSync(&state); err != nil {
+		return err
+	}
+	r.syncFeed.Send(r.Progress())
+	return nil
+}
+
 // startSync begins a fast sync, signalled by r.events being non-nil. If state is non-nil,
 // the scheduler and processor is updated with this state on startup.
 func (r *BlockchainReactor) startSync(state *cstate.LatestBlockState) error {
@@ -147,6 +215,13 @@ func (r *BlockchainReactor) startSync(state *cstate.LatestBlockState) error {
 		return errors.New("fast sync already in progress")
 	}
 	r.events = make(chan Event, chBufferSize)
+	r.startHeight = r.store.Height()
+	if state != nil {
+		r.startHeight = state.LastBlockHeight
+	}
+	r.startTime = time.Now()
+	r.syncHeight = r.startHeight
+	r.syncRate = 0
 	go r.scheduler.start()
 	go r.processor.start()
 	if state != nil {
@@ -368,8 +443,10 @@ func (r *BlockchainReactor) demux(events <-chan Event) {
 				r.processor.send(event)
 			case scPeerError:
 				r.processor.send(event)
-				if err := r.reporter.Report(behaviour.BadMessage(event.peerID, "scPeerError")); err != nil {
-					r.logger.Error("Error reporting peer", "err", err)
+				if event.violation {
+					if err := r.reporter.Report(behaviour.BadMessage(event.peerID, event.reason.Error())); err != nil {
+						r.logger.Error("Error reporting peer", "err", err)
+					}
 				}
 			case scBlockRequest:
 				if err := r.io.sendBlockRequest(event.peerID, event.height); err != nil {
@@ -397,10 +474,16 @@ func (r *BlockchainReactor) demux(events <-chan Event) {
 			switch event := event.(type) {
 			case pcBlockProcessed:
 				r.setSyncHeight(event.height)
-				if r.syncHeight%100 == 0 {
-					lastRate = 0.9*lastRate + 0.1*(100/time.Since(lastHundred).Seconds())
-					r.logger.Info("Fast Sync Rate", "height", r.syncHeight,
-						"max_peer_height", r.maxPeerHeight, "blocks/s", lastRate)
+				if event.height%100 == 0 {
+					rate := 100 / time.Since(lastHundred).Seconds()
+					if lastRate == 0 {
+						lastRate = rate
+					} else {
+						lastRate = 0.9*lastRate + 0.1*rate
+					}
+					r.setSyncRate(lastRate)
+					r.logger.Info("Fast Sync Rate", "height", event.height,
+						"max_peer_height", r.Progress().HighestHeight, "blocks/s", lastRate)
 					lastHundred = time.Now()
 				}
 				r.scheduler.send(event)
@@ -412,6 +495,7 @@ func (r *BlockchainReactor) demux(events <-chan Event) {
 					r.logger.Error("Failed to switch to consensus reactor")
 				}
 				r.endSync()
+				r.syncFeed.Send(r.Progress())
 				return
 			case noOpEvent:
 			default:
@@ -548,6 +632,7 @@ func (r *BlockchainReactor) GetChannels() []*p2p.ChannelDescriptor {
 	return []*p2p.ChannelDescriptor{
 		{
 			ID:                  BlockchainChannel,
+			Name:                "blockchain",
 			Priority:            5,
 			SendQueueCapacity:   2000,
 			RecvBufferCapacity:  50 * 4096,
@@ -66,6 +66,13 @@ type mockBlockStore struct {
 	blocks map[uint64]*types.Block
 }
 
+func (ml *mockBlockStore) Base() uint64 {
+	if len(ml.blocks) == 0 {
+		return 0
+	}
+	return 1
+}
+
 func (ml *mockBlockStore) Height() uint64 {
 	return uint64(len(ml.blocks))
 }
@@ -449,6 +456,44 @@ func TestReactorSetSwitchNil(t *testing.T) {
 	assert.Nil(t, reactor.io)
 }
 
+func TestReactorProgress(t *testing.T) {
+	reactor := &BlockchainReactor{
+		startHeight:   100,
+		startTime:     time.Now().Add(-10 * time.Second),
+		syncHeight:    200,
+		maxPeerHeight: 1200,
+	}
+	progress := reactor.Progress()
+	assert.False(t, progress.Syncing)
+
+	reactor.events = make(chan Event)
+	progress = reactor.Progress()
+	assert.True(t, progress.Syncing)
+	assert.EqualValues(t, 100, progress.StartingHeight)
+	assert.EqualValues(t, 200, progress.CurrentHeight)
+	assert.EqualValues(t, 1200, progress.HighestHeight)
+	// Until the moving average is known, the average since the start.
+	assert.InDelta(t, 10, progress.BlocksPerSecond, 0.1)
+	assert.InDelta(t, 100, progress.ETA.Seconds(), 1)
+
+	reactor.setSyncRate(50)
+	progress = reactor.Progress()
+	assert.EqualValues(t, 50, progress.BlocksPerSecond)
+	assert.Equal(t, 20*time.Second, progress.ETA)
+
+	// The peers aren't known yet.
+	reactor.maxPeerHeight = 0
+	progress = reactor.Progress()
+	assert.EqualValues(t, 200, progress.HighestHeight)
+	assert.Zero(t, progress.ETA)
+
+	ch := make(chan SyncProgress, 1)
+	sub := reactor.SubscribeSyncProgress(ch)
+	defer sub.Unsubscribe()
+	reactor.syncFeed.Send(progress)
+	assert.Equal(t, progress, <-ch)
+}
+
 //----------------------------------------------
 // utility funcs
 
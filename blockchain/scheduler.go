@@ -51,6 +51,9 @@ type scPeerError struct {
 	priorityHigh
 	peerID p2p.ID
 	reason error
+	// the peer broke the protocol, e.g. sent a bad block or an invalid status,
+	// rather than being removed for timeouts or disconnections
+	violation bool
 }
 
 func (e scPeerError) String() string {
@@ -191,6 +194,9 @@ type scheduler struct {
 
 	// a map of heights to the peers that put the block in blockStateReceived
 	receivedBlocks map[uint64]p2p.ID
+
+	// the trusted block peers must agree with
+	checkpoint checkpoint
 }
 
 func (sc scheduler) String() string {
@@ -212,6 +218,7 @@ func newScheduler(initHeight uint64, startTime time.Time, fastSync *configs.Fast
 		targetPending:  fastSync.TargetPending,
 		peerTimeout:    fastSync.PeerTimeout,
 		minRecvRate:    fastSync.MinRecvRate,
+		checkpoint:     newCheckpoint(fastSync),
 	}
 
 	return &sc
@@ -544,6 +551,11 @@ func (sc *scheduler) handleBlockResponse(event bcBlockResponse) (Event, error) {
 		return noOp, nil
 	}
 
+	if err := sc.checkpoint.verify(event.block); err != nil {
+		sc.removePeer(event.peerID)
+		return scPeerError{peerID: event.peerID, reason: err, violation: true}, nil
+	}
+
 	err = sc.markReceived(event.peerID, event.block.Height(), event.size, event.time)
 	if err != nil {
 		sc.removePeer(event.peerID)
@@ -680,7 +692,7 @@ func (sc *scheduler) handleTrySchedule(event rTrySchedule) (Event, error) {
 func (sc *scheduler) handleStatusResponse(event bcStatusResponse) (Event, error) {
 	err := sc.setPeerRange(event.peerID, event.base, event.height)
 	if err != nil {
-		return scPeerError{peerID: event.peerID, reason: err}, nil
+		return scPeerError{peerID: event.peerID, reason: err, violation: true}, nil
 	}
 	return noOp, nil
 }
@@ -1355,6 +1355,7 @@ func checkScResults(t *testing.T, wantErr bool, err error, wantEvent Event, even
 	case scPeerError:
 		assert.Equal(t, wantEvent.peerID, event.(scPeerError).peerID)
 		assert.Equal(t, wantEvent.reason != nil, event.(scPeerError).reason != nil)
+		assert.Equal(t, wantEvent.violation, event.(scPeerError).violation)
 	case scBlockReceived:
 		assert.Equal(t, wantEvent.peerID, event.(scBlockReceived).peerID)
 		wantEvent.block.Hash()
@@ -1985,7 +1986,7 @@ func TestScHandleStatusResponse(t *testing.T) {
 				allB:   []uint64{5, 6, 7, 8, 9, 10},
 			},
 			args:      args{event: statusRespP1Ev},
-			wantEvent: scPeerError{peerID: "P1", reason: fmt.Errorf("some error")},
+			wantEvent: scPeerError{peerID: "P1", reason: fmt.Errorf("some error"), violation: true},
 		},
 
 		{
@@ -0,0 +1,248 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kardiachain/go-kardia/cmd/utils"
+	"github.com/kardiachain/go-kardia/configs"
+	"github.com/kardiachain/go-kardia/internal/flags"
+	"github.com/kardiachain/go-kardia/kai/kaidb"
+	"github.com/kardiachain/go-kardia/kai/state/cstate"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/log"
+	"github.com/kardiachain/go-kardia/mainchain/blockchain"
+	"github.com/kardiachain/go-kardia/mainchain/genesis"
+	"github.com/kardiachain/go-kardia/mainchain/staking"
+	"github.com/kardiachain/go-kardia/types"
+	"github.com/kardiachain/go-kardia/types/evidence"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	importCommand = &cli.Command{
+		Action:    importChain,
+		Name:      "import",
+		Usage:     "Import a blockchain file",
+		ArgsUsage: "<filename> (<filename 2> ... <filename N>) ",
+		Flags: flags.Merge([]cli.Flag{
+			utils.GenesisFlag,
+			utils.GCModeFlag,
+			utils.SnapshotFlag,
+			utils.CacheFlag,
+			utils.CacheDatabaseFlag,
+			utils.CacheTrieFlag,
+			utils.CacheGCFlag,
+			utils.CacheSnapshotFlag,
+			utils.CacheNoPrefetchFlag,
+			utils.CachePreimagesFlag,
+			configFileFlag,
+		}, utils.NetworkFlags, utils.DatabasePathFlags),
+		Description: `
+The import command replays the blocks of RLP-encoded block streams on top of
+the local chain. Every block is verified against the commit exported with it
+and the validator set stored in the consensus state, then executed.
+
+If only one file is used, an import error will result in failure. If several
+files are used, processing will proceed even if an individual file fails to
+import. Files ending in .gz are decompressed on the fly.`,
+	}
+	exportCommand = &cli.Command{
+		Action:    exportChain,
+		Name:      "export",
+		Usage:     "Export blockchain into file",
+		ArgsUsage: "<filename> [<blockNumFirst> [<blockNumLast>]]",
+		Flags: flags.Merge([]cli.Flag{
+			utils.GenesisFlag,
+			utils.CacheFlag,
+			configFileFlag,
+		}, utils.NetworkFlags, utils.DatabasePathFlags),
+		Description: `
+Requires a first argument of the file to write to.
+Optional second and third arguments control the first and
+last block to write, the last block defaults to the chain head.
+In this mode, the file will be appended if already existing. If the file ends with .gz, the output will
+be gzipped.`,
+	}
+)
+
+// blockImporter groups the components needed to replay blocks on a stopped node.
+type blockImporter struct {
+	chain      *blockchain.BlockChain
+	bOper      *blockchain.BlockOperations
+	blockExec  *cstate.BlockExecutor
+	stateStore cstate.Store
+	evPool     *evidence.Pool
+	genesis    *genesis.Genesis
+	consensus  *configs.ConsensusConfig
+	eventBus   *types.EventBus
+	db         kaidb.Database
+}
+
+// makeBlockImporter opens the chain database of the configured node and
+// assembles a block executor on top of it, mirroring the wiring of the
+// Kardiachain backend without any of its networking.
+func makeBlockImporter(ctx *cli.Context) (*blockImporter, error) {
+	stack, cfg := makeConfigNode(ctx)
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	cacheConfig := &blockchain.CacheConfig{
+		TrieCleanLimit:      cfg.Kai.TrieCleanCache,
+		TrieCleanNoPrefetch: cfg.Kai.NoPrefetch,
+		TrieDirtyLimit:      cfg.Kai.TrieDirtyCache,
+		TrieDirtyDisabled:   cfg.Kai.NoPruning,
+		TrieTimeLimit:       cfg.Kai.TrieTimeout,
+		SnapshotLimit:       cfg.Kai.SnapshotCache,
+		Preimages:           cfg.Kai.Preimages,
+	}
+	if !ctx.Bool(utils.SnapshotFlag.Name) {
+		cacheConfig.SnapshotLimit = 0 // Disabled
+	}
+	chain, err := blockchain.NewBlockChain(db, cacheConfig, cfg.Kai.Genesis)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	stakingUtil, err := staking.NewSmcStakingUtil()
+	if err != nil {
+		chain.Stop()
+		db.Close()
+		return nil, err
+	}
+	stateStore := cstate.NewStore(db)
+	if _, err := stateStore.LoadStateFromDBOrGenesisDoc(cfg.Kai.Genesis); err != nil {
+		chain.Stop()
+		db.Close()
+		return nil, err
+	}
+	evPool, err := evidence.NewPool(stateStore, db, chain)
+	if err != nil {
+		chain.Stop()
+		db.Close()
+		return nil, err
+	}
+	eventBus := types.NewEventBus()
+	if err := eventBus.Start(); err != nil {
+		chain.Stop()
+		db.Close()
+		return nil, err
+	}
+	logger := log.New()
+	bOper := blockchain.NewBlockOperations(logger, chain, nil, evPool, stakingUtil)
+	blockExec := cstate.NewBlockExecutor(stateStore, logger, evPool, bOper)
+	blockExec.SetEventBus(eventBus)
+
+	return &blockImporter{
+		chain:      chain,
+		bOper:      bOper,
+		blockExec:  blockExec,
+		stateStore: stateStore,
+		evPool:     evPool,
+		genesis:    cfg.Kai.Genesis,
+		consensus:  cfg.Kai.Consensus,
+		eventBus:   eventBus,
+		db:         db,
+	}, nil
+}
+
+// Close flushes the chain and releases the database.
+func (bi *blockImporter) Close() {
+	bi.blockExec.Stop()
+	bi.chain.Stop()
+	bi.eventBus.Stop()
+	bi.db.Close()
+}
+
+func importChain(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	bi, err := makeBlockImporter(ctx)
+	if err != nil {
+		utils.Fatalf("Failed to open chain: %v", err)
+	}
+	defer bi.Close()
+
+	// Import the chain
+	start := time.Now()
+
+	var importErr error
+
+	if ctx.Args().Len() == 1 {
+		if err := utils.ImportChain(bi.bOper, bi.blockExec, bi.stateStore, bi.genesis, ctx.Args().First()); err != nil {
+			importErr = err
+			log.Error("Import error", "err", err)
+		}
+	} else {
+		for _, arg := range ctx.Args().Slice() {
+			if err := utils.ImportChain(bi.bOper, bi.blockExec, bi.stateStore, bi.genesis, arg); err != nil {
+				importErr = err
+				log.Error("Import error", "file", arg, "err", err)
+				if err.Error() == "interrupted" {
+					break
+				}
+			}
+		}
+	}
+	fmt.Printf("Import done in %v.\n\n", time.Since(start))
+	fmt.Printf("Head: #%d\n", bi.chain.CurrentBlock().Height())
+	return importErr
+}
+
+func exportChain(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	bi, err := makeBlockImporter(ctx)
+	if err != nil {
+		utils.Fatalf("Failed to open chain: %v", err)
+	}
+	defer bi.Close()
+
+	start := time.Now()
+
+	fp := ctx.Args().First()
+	if ctx.Args().Len() < 2 {
+		err = utils.ExportChain(bi.chain, fp)
+	} else {
+		head := bi.chain.CurrentBlock().Height()
+		first, ferr := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+		last, lerr := head, error(nil)
+		if ctx.Args().Len() > 2 {
+			last, lerr = strconv.ParseUint(ctx.Args().Get(2), 10, 64)
+		}
+		if ferr != nil || lerr != nil {
+			utils.Fatalf("Export error in parsing parameters: block number not an integer\n")
+		}
+		if first == 0 {
+			utils.Fatalf("Export error: block number must be greater than 0\n")
+		}
+		if last > head {
+			utils.Fatalf("Export error: block number %d larger than head block %d\n", last, head)
+		}
+		err = utils.ExportAppendChain(bi.chain, fp, first, last)
+	}
+
+	if err != nil {
+		utils.Fatalf("Export error: %v\n", err)
+	}
+	fmt.Printf("Export done in %v\n", common.PrettyDuration(time.Since(start)))
+	return nil
+}
@@ -44,6 +44,14 @@ var (
 		utils.KeyStoreDirFlag,
 		utils.GCModeFlag,
 		utils.SnapshotFlag,
+		utils.StateSyncFlag,
+		utils.StateSyncTrustHeightFlag,
+		utils.StateSyncTrustHashFlag,
+		utils.FastSyncCheckpointHeightFlag,
+		utils.FastSyncCheckpointHashFlag,
+		utils.FastSyncHashChainFlag,
+		utils.ConsensusWalRetainFlag,
+		utils.PrivValidatorListenAddrFlag,
 		utils.TxLookupLimitFlag,
 		utils.CacheFlag,
 		utils.CacheDatabaseFlag,
@@ -89,8 +97,19 @@ func init() {
 	app.Action = kaigo
 	app.Copyright = "Copyright 2013-2023 The go-kardia Authors"
 	app.Commands = []*cli.Command{
+		// See chaincmd.go:
+		importCommand,
+		exportCommand,
+		// See verifycmd.go:
+		verifyChainCommand,
+		// See rollbackcmd.go:
+		rollbackCommand,
+		// See walcmd.go:
+		walCommand,
 		// See snapshot.go
 		snapshotCommand,
+		// See dbcmd.go
+		dbCommand,
 		dumpConfigCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
@@ -0,0 +1,111 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/kardiachain/go-kardia/cmd/utils"
+	"github.com/kardiachain/go-kardia/internal/flags"
+	"github.com/kardiachain/go-kardia/kai/state/cstate"
+	kai "github.com/kardiachain/go-kardia/mainchain"
+	"github.com/kardiachain/go-kardia/mainchain/blockchain"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	rollbackHeightFlag = &cli.Uint64Flag{
+		Name:  "height",
+		Usage: "Block height to roll the node back to",
+	}
+	rollbackRepairFlag = &cli.BoolFlag{
+		Name:  "repair",
+		Usage: "Roll back to the last consistent height instead of a given one",
+	}
+
+	rollbackCommand = &cli.Command{
+		Action:    rollback,
+		Name:      "rollback",
+		Usage:     "Rewind the chain, the consensus state, the evidence pool and the consensus WAL",
+		ArgsUsage: "",
+		Flags: flags.Merge([]cli.Flag{
+			rollbackHeightFlag,
+			rollbackRepairFlag,
+			utils.GenesisFlag,
+			configFileFlag,
+		}, utils.NetworkFlags, utils.DatabasePathFlags),
+		Description: `
+kaigo rollback --height N
+rewinds a stopped node to block N. The blocks above N are deleted along with
+their consensus states, the evidence they committed is put back to pending and
+the consensus WAL is restarted at N (the previous one is kept next to it with
+a .rollback-N suffix). The state of block N must still be available.
+
+kaigo rollback --repair
+rolls the node back to the highest height whose block, state, consensus state
+and app hash are consistent. The same check runs automatically at startup, but
+the node refuses to start if it would roll back more than 100 blocks or to
+genesis.`,
+	}
+)
+
+func rollback(ctx *cli.Context) error {
+	if ctx.IsSet(rollbackHeightFlag.Name) == ctx.Bool(rollbackRepairFlag.Name) {
+		utils.Fatalf("Exactly one of --%s and --%s is required", rollbackHeightFlag.Name, rollbackRepairFlag.Name)
+	}
+	stack, cfg := makeConfigNode(ctx)
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	cacheConfig := &blockchain.CacheConfig{
+		TrieCleanLimit: cfg.Kai.TrieCleanCache,
+		TrieDirtyLimit: cfg.Kai.TrieDirtyCache,
+		TrieTimeLimit:  cfg.Kai.TrieTimeout,
+	}
+	chain, err := blockchain.NewBlockChain(db, cacheConfig, cfg.Kai.Genesis)
+	if err != nil {
+		utils.Fatalf("Failed to open chain: %v", err)
+	}
+	defer chain.Stop()
+
+	var (
+		stateStore = cstate.NewStore(db)
+		walFile    = cfg.Kai.Consensus.WalFile()
+		head       = chain.CurrentBlock().Height()
+	)
+	if ctx.Bool(rollbackRepairFlag.Name) {
+		height, repaired, err := kai.Repair(db, chain, stateStore, walFile, 0)
+		if err != nil {
+			return err
+		}
+		if !repaired {
+			fmt.Printf("Chain is consistent at #%d, nothing to repair\n", height)
+			return nil
+		}
+		fmt.Printf("Rolled back from #%d to #%d\n", head, height)
+		return nil
+	}
+	height := ctx.Uint64(rollbackHeightFlag.Name)
+	if err := kai.Rollback(db, chain, stateStore, walFile, height); err != nil {
+		return err
+	}
+	fmt.Printf("Rolled back from #%d to #%d\n", head, height)
+	return nil
+}
@@ -18,7 +18,12 @@ package main
 
 import (
 	"bytes"
+	"compress/gzip"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/kardiachain/go-kardia/cmd/utils"
@@ -30,6 +35,7 @@ import (
 	"github.com/kardiachain/go-kardia/lib/crypto"
 	"github.com/kardiachain/go-kardia/lib/log"
 	"github.com/kardiachain/go-kardia/lib/rlp"
+	"github.com/kardiachain/go-kardia/mainchain/blockchain"
 	"github.com/kardiachain/go-kardia/trie"
 	"github.com/kardiachain/go-kardia/types"
 	cli "github.com/urfave/cli/v2"
@@ -129,6 +135,34 @@ verification. The default checking target is the HEAD state. It's basically iden
 to traverse-state, but the check granularity is smaller. 
 
 It's also usable without snapshot enabled.
+`,
+			},
+			{
+				Name:      "export",
+				Usage:     "Export the state of a given root into a portable file",
+				ArgsUsage: "<root> <file>",
+				Action:    exportSnapshotState,
+				Flags:     flags.Merge(utils.NetworkFlags, utils.DatabasePathFlags),
+				Description: `
+kaigo snapshot export <state-root> <file>
+streams the flat accounts, storage slots and contract codes of the given state
+root out of the snapshot into a chunked, checksummed file, headed by the root
+and the height of the block it belongs to. The root must be one of the recent
+states covered by the snapshot. Files ending in .gz are gzipped.
+`,
+			},
+			{
+				Name:      "import",
+				Usage:     "Import a state file written by 'snapshot export'",
+				ArgsUsage: "<file>",
+				Action:    importSnapshotState,
+				Flags:     flags.Merge(utils.NetworkFlags, utils.DatabasePathFlags),
+				Description: `
+kaigo snapshot import <file>
+rebuilds the state trie and contract codes from a file written by 'kaigo snapshot
+export', verifying the checksum of every chunk, every storage root and finally
+the state root. Along with the block and commit of the exported height, it lets
+a node start at a recent height instead of syncing from genesis.
 `,
 			},
 			// 			{
@@ -466,6 +500,119 @@ func traverseRawState(ctx *cli.Context) error {
 	return nil
 }
 
+// exportSnapshotState writes the state of the given root into a portable file.
+func exportSnapshotState(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	root, err := parseRoot(ctx.Args().First())
+	if err != nil {
+		log.Error("Failed to resolve state root", "err", err)
+		return err
+	}
+	stack, _ := makeConfigNode(ctx)
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, true)
+	defer chaindb.Close()
+
+	headBlock := rawdb.ReadHeadBlock(chaindb)
+	if headBlock == nil {
+		log.Error("Failed to load head block")
+		return errors.New("no head block")
+	}
+	// Locate the block of the root among the recent ones covered by the snapshot
+	var (
+		height uint64
+		found  bool
+	)
+	for h := headBlock.Height(); h+blockchain.TriesInMemory > headBlock.Height(); h-- {
+		if rawdb.ReadAppHash(chaindb, h) == root {
+			height, found = h, true
+			break
+		}
+		if h == 0 {
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("root %x is not the state of a recent block", root)
+	}
+	headRoot := rawdb.ReadAppHash(chaindb, headBlock.Height())
+	snapconfig := snapshot.Config{
+		CacheSize:  256,
+		Recovery:   false,
+		NoBuild:    true,
+		AsyncBuild: false,
+	}
+	snaptree, err := snapshot.New(snapconfig, chaindb, trie.NewDatabase(chaindb), headRoot)
+	if err != nil {
+		log.Error("Failed to open snapshot tree", "err", err)
+		return err
+	}
+
+	fn := ctx.Args().Get(1)
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	var (
+		writer io.Writer = fh
+		gz     *gzip.Writer
+	)
+	if strings.HasSuffix(fn, ".gz") {
+		gz = gzip.NewWriter(writer)
+		writer = gz
+	}
+	log.Info("Exporting state", "root", root, "height", height, "file", fn)
+	if err := snapshot.ExportState(snaptree, chaindb, root, height, writer); err != nil {
+		fh.Close()
+		return err
+	}
+	// The export is only complete once the compressed stream is flushed and
+	// the file is closed, so their errors are returned too.
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			fh.Close()
+			return err
+		}
+	}
+	return fh.Close()
+}
+
+// importSnapshotState rebuilds the state contained in a file written by
+// exportSnapshotState.
+func importSnapshotState(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, false)
+	defer chaindb.Close()
+
+	fn := ctx.Args().First()
+	fh, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return err
+		}
+	}
+	start := time.Now()
+	header, err := snapshot.ImportState(chaindb, trie.NewDatabase(chaindb).Scheme(), reader)
+	if err != nil {
+		log.Error("Failed to import state", "file", fn, "err", err)
+		return err
+	}
+	log.Info("Imported state", "root", header.Root, "height", header.Height, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
 func parseRoot(input string) (common.Hash, error) {
 	var h common.Hash
 	if err := h.UnmarshalText([]byte(input)); err != nil {
@@ -0,0 +1,245 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kardiachain/go-kardia/cmd/utils"
+	"github.com/kardiachain/go-kardia/internal/flags"
+	"github.com/kardiachain/go-kardia/kai/rawdb"
+	"github.com/kardiachain/go-kardia/kai/state"
+	"github.com/kardiachain/go-kardia/kai/state/cstate"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/log"
+	stypes "github.com/kardiachain/go-kardia/mainchain/staking/types"
+	"github.com/kardiachain/go-kardia/trie"
+	"github.com/kardiachain/go-kardia/types"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	verifyFromFlag = &cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First block height to re-execute (default = 1)",
+		Value: 1,
+	}
+	verifyToFlag = &cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Last block height to re-execute (default = chain head)",
+	}
+
+	verifyChainCommand = &cli.Command{
+		Action:    verifyChain,
+		Name:      "verify-chain",
+		Usage:     "Re-execute stored blocks and compare the results with the recorded ones",
+		ArgsUsage: "",
+		Flags: flags.Merge([]cli.Flag{
+			verifyFromFlag,
+			verifyToFlag,
+			utils.GenesisFlag,
+			utils.SnapshotFlag,
+			utils.CacheFlag,
+			configFileFlag,
+		}, utils.NetworkFlags, utils.DatabasePathFlags),
+		Description: `
+kaigo verify-chain --from N --to M
+re-executes the stored blocks N to M, each on top of the stored state of its
+parent, and compares the resulting app hash, receipts root and gas used with
+the ones recorded in the database. Nothing is written back.
+
+On the first mismatch, the command reports the first diverging transaction
+along with a diff of the accounts touched by the block, and exits with an error.
+The state of block N-1 must be available (archive node, or a recent height).`,
+	}
+)
+
+var errChainDiverged = errors.New("re-executed chain diverged from the recorded one")
+
+func verifyChain(ctx *cli.Context) error {
+	bi, err := makeBlockImporter(ctx)
+	if err != nil {
+		utils.Fatalf("Failed to open chain: %v", err)
+	}
+	defer bi.Close()
+
+	var (
+		head = bi.chain.CurrentBlock().Height()
+		from = ctx.Uint64(verifyFromFlag.Name)
+		to   = head
+	)
+	if ctx.IsSet(verifyToFlag.Name) {
+		to = ctx.Uint64(verifyToFlag.Name)
+	}
+	if from == 0 || from > to || to > head {
+		return fmt.Errorf("invalid range [%d, %d], head is #%d", from, to, head)
+	}
+	log.Info("Verifying chain", "from", from, "to", to)
+
+	var (
+		start    = time.Now()
+		reported = time.Now()
+	)
+	for height := from; height <= to; height++ {
+		if err := verifyBlock(bi, height); err != nil {
+			return err
+		}
+		if time.Since(reported) >= 8*time.Second {
+			log.Info("Verifying chain", "height", height, "verified", height-from+1, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	log.Info("Chain verified", "from", from, "to", to, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// verifyBlock re-executes the block at height on top of its parent state and
+// compares the outcome with what was recorded when the block was committed.
+func verifyBlock(bi *blockImporter, height uint64) error {
+	block := bi.chain.GetBlockByHeight(height)
+	if block == nil {
+		return fmt.Errorf("block #%d not found", height)
+	}
+	recorded := rawdb.ReadBlockInfo(bi.db, block.Hash(), height, bi.chain.Config())
+	if recorded == nil {
+		return fmt.Errorf("block info of #%d not found", height)
+	}
+	statedb, err := bi.chain.StateAt(height - 1)
+	if err != nil {
+		return fmt.Errorf("missing parent state of block #%d: %v", height, err)
+	}
+
+	var byzVals []stypes.Evidence
+	for _, ev := range block.Evidence().Evidence {
+		byzVals = append(byzVals, ev.VM()...)
+	}
+	commitInfo := cstate.BlockCommitInfo(bi.chain.Config(), block, bi.stateStore)
+	_, blockInfo, err := bi.bOper.ExecuteBlock(statedb, block, commitInfo, byzVals)
+	if err != nil {
+		return fmt.Errorf("failed to execute block #%d: %v", height, err)
+	}
+
+	var (
+		root         = statedb.IntermediateRoot(true)
+		wantRoot     = rawdb.ReadAppHash(bi.db, height)
+		receipts     = types.DeriveSha(blockInfo.Receipts, trie.NewStackTrie(nil))
+		wantReceipts = types.DeriveSha(recorded.Receipts, trie.NewStackTrie(nil))
+	)
+	if root == wantRoot && receipts == wantReceipts && blockInfo.GasUsed == recorded.GasUsed {
+		return nil
+	}
+	log.Error("Block diverged", "height", height, "hash", block.Hash(),
+		"root", root, "recorded root", wantRoot,
+		"receipts", receipts, "recorded receipts", wantReceipts,
+		"gas", blockInfo.GasUsed, "recorded gas", recorded.GasUsed)
+
+	reportDivergentTx(blockInfo.Receipts, recorded.Receipts)
+
+	// Diff the accounts touched by the re-execution against the recorded
+	// post-state, if it's still around.
+	if recordedState, err := bi.chain.StateAt(height); err != nil {
+		log.Warn("Recorded state unavailable, skipping state diff", "height", height, "err", err)
+	} else {
+		reportStateDiff(statedb, recordedState)
+	}
+	return errChainDiverged
+}
+
+// reportDivergentTx prints the first transaction whose re-executed receipt
+// differs from the recorded one. Transactions rejected during execution have
+// no receipt, so receipts are matched by transaction hash, both lists being in
+// block order.
+func reportDivergentTx(have, want types.Receipts) {
+	inHave := make(map[common.Hash]bool, len(have))
+	for _, r := range have {
+		inHave[r.TxHash] = true
+	}
+	inWant := make(map[common.Hash]bool, len(want))
+	for _, r := range want {
+		inWant[r.TxHash] = true
+	}
+	for i, j := 0, 0; i < len(have) || j < len(want); {
+		switch {
+		case i < len(have) && !inWant[have[i].TxHash]:
+			fmt.Printf("Transaction %v is only included by the re-execution\n", have[i].TxHash.Hex())
+			return
+		case j < len(want) && !inHave[want[j].TxHash]:
+			fmt.Printf("Transaction %v was not included by the re-execution\n", want[j].TxHash.Hex())
+			return
+		case i >= len(have) || j >= len(want) || have[i].TxHash != want[j].TxHash:
+			fmt.Println("The re-execution included the transactions in another order")
+			return
+		}
+		h, w := have[i], want[j]
+		if h.Status != w.Status || h.GasUsed != w.GasUsed || h.CumulativeGasUsed != w.CumulativeGasUsed ||
+			h.Bloom != w.Bloom || len(h.Logs) != len(w.Logs) {
+			fmt.Printf("First divergent transaction: %v\n", h.TxHash.Hex())
+			fmt.Printf("  status:     %d (recorded %d)\n", h.Status, w.Status)
+			fmt.Printf("  gas used:   %d (recorded %d)\n", h.GasUsed, w.GasUsed)
+			fmt.Printf("  cumulative: %d (recorded %d)\n", h.CumulativeGasUsed, w.CumulativeGasUsed)
+			fmt.Printf("  logs:       %d (recorded %d)\n", len(h.Logs), len(w.Logs))
+			return
+		}
+		i++
+		j++
+	}
+	fmt.Println("All transaction receipts match, the divergence comes from block level state transitions (rewards, staking, validator updates)")
+}
+
+// reportStateDiff prints the accounts modified by the re-execution whose
+// content differs from the recorded post-state.
+func reportStateDiff(have, want *state.StateDB) {
+	var diffs int
+	for _, addr := range have.DirtyAccounts() {
+		var (
+			balance, wantBalance   = have.GetBalance(addr), want.GetBalance(addr)
+			nonce, wantNonce       = have.GetNonce(addr), want.GetNonce(addr)
+			codeHash, wantCodeHash = have.GetCodeHash(addr), want.GetCodeHash(addr)
+			storage, wantStorage   = storageRoot(have, addr), storageRoot(want, addr)
+		)
+		if balance.Cmp(wantBalance) == 0 && nonce == wantNonce && codeHash == wantCodeHash && storage == wantStorage {
+			continue
+		}
+		diffs++
+		fmt.Printf("Account %v\n", addr.Hex())
+		if balance.Cmp(wantBalance) != 0 {
+			fmt.Printf("  balance:  %v (recorded %v)\n", balance, wantBalance)
+		}
+		if nonce != wantNonce {
+			fmt.Printf("  nonce:    %d (recorded %d)\n", nonce, wantNonce)
+		}
+		if codeHash != wantCodeHash {
+			fmt.Printf("  code:     %v (recorded %v)\n", codeHash.Hex(), wantCodeHash.Hex())
+		}
+		if storage != wantStorage {
+			fmt.Printf("  storage:  %v (recorded %v)\n", storage.Hex(), wantStorage.Hex())
+		}
+	}
+	fmt.Printf("%d account(s) differ from the recorded state\n", diffs)
+}
+
+func storageRoot(statedb *state.StateDB, addr common.Address) common.Hash {
+	tr, err := statedb.StorageTrie(addr)
+	if err != nil || tr == nil {
+		return types.EmptyRootHash
+	}
+	return tr.Hash()
+}
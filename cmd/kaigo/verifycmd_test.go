@@ -0,0 +1,81 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/internal/simnet"
+	"github.com/kardiachain/go-kardia/kai/rawdb"
+	"github.com/kardiachain/go-kardia/kai/state/cstate"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+func TestVerifyChain(t *testing.T) {
+	net, err := simnet.NewNetwork(simnet.Config{Validators: 1, Seed: 1})
+	require.NoError(t, err)
+	t.Cleanup(net.Stop)
+	node := net.Nodes[0]
+
+	tx, err := types.SignTx(types.HomesteadSigner{},
+		types.NewTransaction(0, common.HexToAddress("0x1"), big.NewInt(1), 100000, big.NewInt(1), nil), net.Account)
+	require.NoError(t, err)
+	for _, err := range node.TxPool.AddRemotesSync([]*types.Transaction{tx}) {
+		require.NoError(t, err)
+	}
+	require.NoError(t, net.Start())
+	require.NoError(t, net.WaitForHeight(3, 30*time.Second))
+	net.Stop()
+
+	bi := &blockImporter{
+		chain:      node.BlockChain,
+		bOper:      node.BlockOper,
+		stateStore: cstate.NewStore(node.DB),
+		db:         node.DB,
+	}
+	var txBlock *types.Block
+	for height := uint64(1); height <= node.Height(); height++ {
+		require.NoError(t, verifyBlock(bi, height), "height %d", height)
+		if block := node.BlockChain.GetBlockByHeight(height); len(block.Transactions()) > 0 {
+			txBlock = block
+		}
+	}
+	require.NotNil(t, txBlock, "the transaction wasn't committed")
+	height := txBlock.Height()
+
+	// A tampered receipt is detected.
+	info := rawdb.ReadBlockInfo(node.DB, txBlock.Hash(), height, node.BlockChain.Config())
+	require.Len(t, info.Receipts, 1)
+	info.Receipts[0].Status = types.ReceiptStatusFailed
+	rawdb.WriteBlockInfo(node.DB, txBlock.Hash(), height, info)
+	require.ErrorIs(t, verifyBlock(bi, height), errChainDiverged)
+
+	info.Receipts[0].Status = types.ReceiptStatusSuccessful
+	rawdb.WriteBlockInfo(node.DB, txBlock.Hash(), height, info)
+	require.NoError(t, verifyBlock(bi, height))
+
+	// So is a tampered state root.
+	rawdb.WriteAppHash(node.DB, height, common.BytesToHash([]byte("tampered")))
+	require.ErrorIs(t, verifyBlock(bi, height), errChainDiverged)
+}
@@ -0,0 +1,201 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kardiachain/go-kardia/cmd/utils"
+	"github.com/kardiachain/go-kardia/consensus"
+	"github.com/kardiachain/go-kardia/internal/flags"
+	"github.com/kardiachain/go-kardia/lib/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	walConsoleFlag = &cli.BoolFlag{
+		Name:  "console",
+		Usage: "Step through the messages interactively",
+	}
+
+	walCommand = &cli.Command{
+		Name:      "wal",
+		Usage:     "Inspect the consensus write-ahead log",
+		ArgsUsage: "",
+		Subcommands: []*cli.Command{
+			walDumpCommand,
+			walReplayCommand,
+		},
+	}
+	walDumpCommand = &cli.Command{
+		Action:    walDump,
+		Name:      "dump",
+		Usage:     "Print the messages of a consensus WAL as JSON",
+		ArgsUsage: "[<file>]",
+		Flags: flags.Merge([]cli.Flag{
+			configFileFlag,
+		}, utils.NetworkFlags, utils.DatabasePathFlags),
+		Description: `
+Prints every message of the WAL, one JSON object per line, with the file of
+the group it was read from, the time it was written, its type and the peer it
+came from. The WAL of the configured node is used when no file is given.`,
+	}
+	walReplayCommand = &cli.Command{
+		Action:    walReplay,
+		Name:      "replay",
+		Usage:     "Replay the consensus WAL of a stopped node",
+		ArgsUsage: "[<file>]",
+		Flags: flags.Merge([]cli.Flag{
+			walConsoleFlag,
+			utils.GenesisFlag,
+			configFileFlag,
+		}, utils.NetworkFlags, utils.DatabasePathFlags),
+		Description: `
+Plays the messages the WAL recorded for the height after the last committed
+block into a consensus state and prints the height, round and step after each
+of them. The replay is a sandbox: nothing is signed, written to the WAL or
+committed. With --console, the replay waits for a command before each step:
+
+  next [N]  play the next N messages (1 if omitted, also an empty line)
+  rs        print a summary of the round state
+  dump      print the full round state
+  quit      stop the replay`,
+	}
+)
+
+func walDump(ctx *cli.Context) error {
+	walFile := ctx.Args().First()
+	if walFile == "" {
+		_, cfg := makeConfigNode(ctx)
+		walFile = cfg.Kai.Consensus.WalFile()
+	}
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	return consensus.IterateWAL(walFile, func(index int, msg *consensus.TimedWALMessage) error {
+		data, err := json.Marshal(consensus.NewWALEntry(index, msg))
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "%s\n", data)
+		return err
+	})
+}
+
+func walReplay(ctx *cli.Context) error {
+	bi, err := makeBlockImporter(ctx)
+	if err != nil {
+		utils.Fatalf("Failed to open chain: %v", err)
+	}
+	defer bi.Close()
+
+	walFile := ctx.Args().First()
+	if walFile == "" {
+		walFile = bi.consensus.WalFile()
+	}
+	cs := consensus.NewConsensusState(log.New(), bi.consensus, bi.stateStore.Load(), bi.bOper, bi.blockExec, bi.evPool)
+	cs.SetEventBus(bi.eventBus)
+	player, err := consensus.NewWALPlayer(cs, walFile)
+	if err != nil {
+		return err
+	}
+	defer player.Close()
+	fmt.Printf("Replaying height %d from %s\n", player.Height(), walFile)
+
+	var (
+		console = ctx.Bool(walConsoleFlag.Name)
+		in      = bufio.NewScanner(os.Stdin)
+		steps   = 0
+	)
+	for count := 1; ; count++ {
+		for console && steps == 0 {
+			fmt.Print("> ")
+			if !in.Scan() {
+				return in.Err()
+			}
+			if steps, err = walConsoleCommand(in.Text(), player); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				fmt.Println(err)
+			}
+		}
+		msg, err := player.Next()
+		if errors.Is(err, io.EOF) {
+			fmt.Printf("Replayed %d messages of height %d\n", count-1, player.Height())
+			return nil
+		}
+		if msg == nil {
+			return err
+		}
+		entry := consensus.NewWALEntry(0, msg)
+		rs := player.RoundState()
+		fmt.Printf("#%d %s %-16s %-12s -> %d/%d/%s\n", count, entry.Time.Format("15:04:05.000"), entry.Type, entry.Peer, rs.Height, rs.Round, rs.Step)
+		if err != nil {
+			fmt.Printf("   error: %v\n", err)
+		}
+		if steps > 0 {
+			steps--
+		}
+	}
+}
+
+// walConsoleCommand runs a command of the replay console and returns the
+// number of messages to play next. It returns io.EOF to stop the replay.
+func walConsoleCommand(line string, player *consensus.WALPlayer) (int, error) {
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		return 1, nil
+	}
+	switch args[0] {
+	case "next", "n":
+		if len(args) == 1 {
+			return 1, nil
+		}
+		steps, err := strconv.Atoi(args[1])
+		if err != nil || steps < 1 {
+			return 0, fmt.Errorf("invalid number of messages %q", args[1])
+		}
+		return steps, nil
+	case "rs":
+		return 0, printJSON(player.RoundState().Simple())
+	case "dump":
+		return 0, printJSON(player.RoundState().Dump())
+	case "quit", "q":
+		return 0, io.EOF
+	default:
+		return 0, fmt.Errorf("unknown command %q, expected next [N], rs, dump or quit", args[0])
+	}
+}
+
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
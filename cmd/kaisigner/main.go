@@ -0,0 +1,136 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// kaisigner is a reference remote signer: it keeps a validator key out of
+// the node process and signs the votes and proposals the node sends it.
+package main
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kardiachain/go-kardia/internal/flags"
+	"github.com/kardiachain/go-kardia/lib/crypto"
+	"github.com/kardiachain/go-kardia/lib/log"
+	kos "github.com/kardiachain/go-kardia/lib/os"
+	"github.com/kardiachain/go-kardia/privval"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	keyFlag = &cli.StringFlag{
+		Name:     "key",
+		Usage:    "File holding the hex encoded validator private key",
+		Required: true,
+	}
+	addrFlag = &cli.StringFlag{
+		Name:     "addr",
+		Usage:    "Remote signer address of the node (tcp://host:port or unix:///path), see --privval.laddr",
+		Required: true,
+	}
+	chainIDFlag = &cli.StringFlag{
+		Name:     "chainid",
+		Usage:    "Chain ID of the votes and proposals to sign",
+		Required: true,
+	}
+	stateFlag = &cli.StringFlag{
+		Name:  "state",
+		Usage: "File holding the last sign state, which guards against double signing (default = " + privval.StateFileName + " next to the key)",
+	}
+	connKeyFlag = &cli.StringFlag{
+		Name:  "connkey",
+		Usage: "File holding the hex encoded key authenticating the connection (default = a new key on every start)",
+	}
+	retryFlag = &cli.DurationFlag{
+		Name:  "retry",
+		Usage: "Wait between attempts to connect to the node",
+		Value: time.Second,
+	}
+)
+
+var app = flags.NewApp("the go-kardia remote signer")
+
+func init() {
+	app.Action = kaisigner
+	app.Flags = []cli.Flag{
+		keyFlag,
+		addrFlag,
+		chainIDFlag,
+		stateFlag,
+		connKeyFlag,
+		retryFlag,
+	}
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func kaisigner(ctx *cli.Context) error {
+	key, err := crypto.LoadECDSA(ctx.String(keyFlag.Name))
+	if err != nil {
+		return fmt.Errorf("failed to load the validator key: %w", err)
+	}
+	stateFile := ctx.String(stateFlag.Name)
+	if stateFile == "" {
+		stateFile = filepath.Join(filepath.Dir(ctx.String(keyFlag.Name)), privval.StateFileName)
+	}
+	pv, err := privval.LoadFilePV(key, stateFile)
+	if err != nil {
+		return fmt.Errorf("failed to load the last sign state: %w", err)
+	}
+	var connKey *ecdsa.PrivateKey
+	if file := ctx.String(connKeyFlag.Name); file != "" {
+		connKey, err = crypto.LoadECDSA(file)
+	} else {
+		connKey, err = crypto.GenerateKey()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load the connection key: %w", err)
+	}
+
+	logger := log.New()
+	// Keep dialing, the node may restart.
+	endpoint, err := privval.NewSignerDialer(ctx.String(addrFlag.Name), connKey, logger,
+		privval.SignerDialerEndpointConnRetries(math.MaxInt32),
+		privval.SignerDialerEndpointRetryWaitInterval(ctx.Duration(retryFlag.Name)),
+	)
+	if err != nil {
+		return err
+	}
+	server := privval.NewSignerServer(endpoint, ctx.String(chainIDFlag.Name), pv)
+	if err := server.Start(); err != nil {
+		return err
+	}
+	logger.Info("Signing for the node", "address", pv.GetAddress(), "node", ctx.String(addrFlag.Name), "state", stateFile)
+
+	kos.TrapSignal(logger, func() {
+		if err := server.Stop(); err != nil {
+			logger.Error("Failed to stop the signer", "err", err)
+		}
+	})
+	<-server.Quit()
+	return nil
+}
@@ -18,18 +18,28 @@
 package utils
 
 import (
+	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/signal"
 	"runtime"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
+	"github.com/kardiachain/go-kardia/kai/state/cstate"
 	"github.com/kardiachain/go-kardia/lib/common"
 	"github.com/kardiachain/go-kardia/lib/log"
+	"github.com/kardiachain/go-kardia/lib/rlp"
 	kai "github.com/kardiachain/go-kardia/mainchain"
+	"github.com/kardiachain/go-kardia/mainchain/blockchain"
+	"github.com/kardiachain/go-kardia/mainchain/genesis"
 	"github.com/kardiachain/go-kardia/node"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+	"github.com/kardiachain/go-kardia/types"
 	"github.com/urfave/cli/v2"
 )
 
@@ -122,3 +132,230 @@ func monitorFreeDiskSpace(sigc chan os.Signal, path string, freeDiskSpaceCritica
 		time.Sleep(30 * time.Second)
 	}
 }
+
+// blockEntry is a single element of an exported block stream. Blocks and
+// commits have no RLP encoding of their own, so both are carried in their
+// protobuf wire format (the same one used by the block store) and the stream
+// is framed with RLP.
+type blockEntry struct {
+	Block      []byte
+	SeenCommit []byte
+}
+
+// ExportChain exports a blockchain into the specified file, truncating any data
+// already present in the file.
+func ExportChain(bc *blockchain.BlockChain, fn string) error {
+	log.Info("Exporting blockchain", "file", fn)
+	return exportChain(bc, fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 1, bc.CurrentBlock().Height())
+}
+
+// ExportAppendChain exports a blockchain range into the specified file, appending
+// to the file if data already exists in it.
+func ExportAppendChain(bc *blockchain.BlockChain, fn string, first uint64, last uint64) error {
+	log.Info("Exporting blockchain", "file", fn)
+	return exportChain(bc, fn, os.O_CREATE|os.O_APPEND|os.O_WRONLY, first, last)
+}
+
+func exportChain(bc *blockchain.BlockChain, fn string, flag int, first uint64, last uint64) error {
+	if first > last {
+		return fmt.Errorf("export failed: first (%d) is greater than last (%d)", first, last)
+	}
+	if head := bc.CurrentBlock().Height(); last > head {
+		return fmt.Errorf("export failed: last (%d) is greater than head (%d)", last, head)
+	}
+	// Open the file handle and potentially wrap with a gzip stream
+	fh, err := os.OpenFile(fn, flag, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+	// Iterate over the blocks and export them
+	if err := exportRange(bc, writer, first, last); err != nil {
+		return err
+	}
+	log.Info("Exported blockchain", "file", fn, "first", first, "last", last)
+	return nil
+}
+
+// exportRange writes the blocks in [first, last] along with the commit that
+// sealed each of them to w.
+func exportRange(bc *blockchain.BlockChain, w io.Writer, first uint64, last uint64) error {
+	var (
+		start    = time.Now()
+		reported = time.Now()
+	)
+	for height := first; height <= last; height++ {
+		block := bc.GetBlockByHeight(height)
+		if block == nil {
+			return fmt.Errorf("export failed on #%d: not found", height)
+		}
+		// The seen commit is only kept for the recent heights, fall back to the
+		// canonical commit carried by the next block.
+		commit := bc.LoadSeenCommit(height)
+		if commit == nil {
+			commit = bc.LoadBlockCommit(height)
+		}
+		if commit == nil {
+			return fmt.Errorf("export failed on #%d: commit not found", height)
+		}
+		entry, err := encodeBlockEntry(block, commit)
+		if err != nil {
+			return fmt.Errorf("export failed on #%d: %v", height, err)
+		}
+		if err := rlp.Encode(w, entry); err != nil {
+			return err
+		}
+		if time.Since(reported) >= 8*time.Second {
+			log.Info("Exporting blocks", "exported", height-first+1, "height", height, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	return nil
+}
+
+func encodeBlockEntry(block *types.Block, commit *types.Commit) (*blockEntry, error) {
+	pb, err := block.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	blockBz, err := proto.Marshal(pb)
+	if err != nil {
+		return nil, err
+	}
+	commitBz, err := proto.Marshal(commit.ToProto())
+	if err != nil {
+		return nil, err
+	}
+	return &blockEntry{Block: blockBz, SeenCommit: commitBz}, nil
+}
+
+func decodeBlockEntry(entry *blockEntry) (*types.Block, *types.Commit, error) {
+	pbb := new(kproto.Block)
+	if err := proto.Unmarshal(entry.Block, pbb); err != nil {
+		return nil, nil, err
+	}
+	block, err := types.BlockFromProtoUnsafe(pbb)
+	if err != nil {
+		return nil, nil, err
+	}
+	pbc := new(kproto.Commit)
+	if err := proto.Unmarshal(entry.SeenCommit, pbc); err != nil {
+		return nil, nil, err
+	}
+	commit, err := types.CommitFromProto(pbc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return block, commit, nil
+}
+
+// ImportChain replays the blocks of an exported stream on top of the current
+// chain head. Every block is verified against the validator set recorded in
+// the consensus state before it is saved and applied through the executor, so
+// an import is held to the same rules as fast sync. A chain without blocks
+// starts from the state of genesisDoc. The import can be safely interrupted:
+// the block being processed is finished before returning.
+func ImportChain(bOper *blockchain.BlockOperations, blockExec *cstate.BlockExecutor, stateStore cstate.Store,
+	genesisDoc *genesis.Genesis, fn string) error {
+	// Watch for Ctrl-C while the import is running.
+	// If a signal is received, the import will stop at the next block.
+	interrupt := make(chan os.Signal, 1)
+	stop := make(chan struct{})
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+	defer close(interrupt)
+	go func() {
+		if _, ok := <-interrupt; ok {
+			log.Info("Interrupted during import, stopping at next block")
+		}
+		close(stop)
+	}()
+	checkInterrupt := func() bool {
+		select {
+		case <-stop:
+			return true
+		default:
+			return false
+		}
+	}
+
+	log.Info("Importing blockchain", "file", fn)
+
+	// Open the file handle and potentially unwrap the gzip stream
+	fh, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return err
+		}
+	}
+	var (
+		stream   = rlp.NewStream(reader, 0)
+		state    = stateStore.Load()
+		start    = time.Now()
+		reported = time.Now()
+		imported int
+		skipped  int
+	)
+	// The state stored at height 0 is filled from the genesis block, which
+	// the first block doesn't link to.
+	if state.LastBlockHeight == 0 {
+		if state, err = cstate.MakeGenesisState(genesisDoc); err != nil {
+			return err
+		}
+	}
+	for n := 0; ; n++ {
+		if checkInterrupt() {
+			return errors.New("interrupted")
+		}
+		var entry blockEntry
+		if err := stream.Decode(&entry); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("at block %d: %v", n, err)
+		}
+		block, seenCommit, err := decodeBlockEntry(&entry)
+		if err != nil {
+			return fmt.Errorf("at block %d: %v", n, err)
+		}
+		height := block.Height()
+		// Skip the blocks we already have, the stream may overlap the local chain.
+		if height <= state.LastBlockHeight {
+			skipped++
+			continue
+		}
+		if want := state.LastBlockHeight + 1; height != want {
+			return fmt.Errorf("non contiguous import: want block #%d, got #%d", want, height)
+		}
+		var (
+			parts   = block.MakePartSet(types.BlockPartSizeBytes)
+			blockID = types.BlockID{Hash: block.Hash(), PartsHeader: parts.Header()}
+		)
+		if err := state.Validators.VerifyCommit(state.ChainID, blockID, height, seenCommit); err != nil {
+			return fmt.Errorf("invalid commit for block #%d: %v", height, err)
+		}
+		bOper.SaveBlock(block, parts, seenCommit)
+		if state, _, err = blockExec.ApplyBlock(state, blockID, block); err != nil {
+			return fmt.Errorf("failed to apply block #%d: %v", height, err)
+		}
+		imported++
+		if time.Since(reported) >= 8*time.Second {
+			log.Info("Importing blocks", "imported", imported, "height", height, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	log.Info("Imported blockchain", "file", fn, "imported", imported, "skipped", skipped,
+		"head", state.LastBlockHeight, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
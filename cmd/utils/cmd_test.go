@@ -0,0 +1,101 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/internal/simnet"
+	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
+	"github.com/kardiachain/go-kardia/kai/state/cstate"
+	"github.com/kardiachain/go-kardia/lib/log"
+	"github.com/kardiachain/go-kardia/mainchain/blockchain"
+	"github.com/kardiachain/go-kardia/mainchain/genesis"
+	"github.com/kardiachain/go-kardia/mainchain/staking"
+	"github.com/kardiachain/go-kardia/types"
+	"github.com/kardiachain/go-kardia/types/evidence"
+)
+
+// importTarget is a fresh chain, at the genesis of the network, to import
+// the exported blocks into.
+type importTarget struct {
+	chain      *blockchain.BlockChain
+	bOper      *blockchain.BlockOperations
+	blockExec  *cstate.BlockExecutor
+	stateStore cstate.Store
+}
+
+func newImportTarget(t *testing.T, g *genesis.Genesis) *importTarget {
+	db := memorydb.New()
+	_, _, err := genesis.SetupGenesisBlock(db, g)
+	require.NoError(t, err)
+	chain, err := blockchain.NewBlockChain(db, nil, nil)
+	require.NoError(t, err)
+	t.Cleanup(chain.Stop)
+	stakingUtil, err := staking.NewSmcStakingUtil()
+	require.NoError(t, err)
+	stateStore := cstate.NewStore(db)
+	_, err = stateStore.LoadStateFromDBOrGenesisDoc(g)
+	require.NoError(t, err)
+	evPool, err := evidence.NewPool(stateStore, db, chain)
+	require.NoError(t, err)
+	eventBus := types.NewEventBus()
+	require.NoError(t, eventBus.Start())
+	t.Cleanup(func() { _ = eventBus.Stop() })
+
+	logger := log.New()
+	bOper := blockchain.NewBlockOperations(logger, chain, nil, evPool, stakingUtil)
+	blockExec := cstate.NewBlockExecutor(stateStore, logger, evPool, bOper)
+	blockExec.SetEventBus(eventBus)
+	return &importTarget{chain: chain, bOper: bOper, blockExec: blockExec, stateStore: stateStore}
+}
+
+func TestExportImportChain(t *testing.T) {
+	const blocks = 5
+	net, err := simnet.NewNetwork(simnet.Config{Validators: 4, Seed: 1})
+	require.NoError(t, err)
+	require.NoError(t, net.Start())
+	t.Cleanup(net.Stop)
+	require.NoError(t, net.WaitForHeight(blocks, 30*time.Second))
+
+	source := net.Nodes[0].BlockChain
+	head := source.GetBlockByHeight(blocks)
+	require.NotNil(t, head)
+
+	for _, name := range []string{"chain.rlp", "chain.rlp.gz"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			fn := filepath.Join(t.TempDir(), name)
+			require.NoError(t, ExportAppendChain(source, fn, 1, blocks))
+
+			target := newImportTarget(t, net.Genesis)
+			require.NoError(t, ImportChain(target.bOper, target.blockExec, target.stateStore, net.Genesis, fn))
+			require.Equal(t, head.Hash(), target.chain.CurrentBlock().Hash())
+			require.EqualValues(t, blocks, target.stateStore.Load().LastBlockHeight)
+
+			// Importing the same stream again skips all of its blocks.
+			require.NoError(t, ImportChain(target.bOper, target.blockExec, target.stateStore, net.Genesis, fn))
+			require.Equal(t, head.Hash(), target.chain.CurrentBlock().Hash())
+		})
+	}
+}
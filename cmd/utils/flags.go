@@ -33,6 +33,7 @@ import (
 	"github.com/kardiachain/go-kardia/internal/flags"
 	"github.com/kardiachain/go-kardia/internal/kaiapi"
 	"github.com/kardiachain/go-kardia/kai/kaidb"
+	"github.com/kardiachain/go-kardia/lib/common"
 	"github.com/kardiachain/go-kardia/lib/log"
 	"github.com/kardiachain/go-kardia/lib/metrics"
 	"github.com/kardiachain/go-kardia/lib/metrics/exp"
@@ -100,6 +101,46 @@ var (
 		Value:    true,
 		Category: flags.KaiCategory,
 	}
+	StateSyncFlag = &cli.BoolFlag{
+		Name:     "statesync",
+		Usage:    "Restore the state of an empty node from a peer snapshot before fast syncing",
+		Category: flags.KaiCategory,
+	}
+	StateSyncTrustHeightFlag = &cli.Uint64Flag{
+		Name:     "statesync.trustheight",
+		Usage:    "Height of the trusted header state sync verifies the snapshot from (default = genesis)",
+		Category: flags.KaiCategory,
+	}
+	StateSyncTrustHashFlag = &cli.StringFlag{
+		Name:     "statesync.trusthash",
+		Usage:    "Hash of the trusted header at --statesync.trustheight",
+		Category: flags.KaiCategory,
+	}
+	FastSyncCheckpointHeightFlag = &cli.Uint64Flag{
+		Name:     "fastsync.checkpointheight",
+		Usage:    "Height of a trusted block the synced chain must go through",
+		Category: flags.KaiCategory,
+	}
+	FastSyncCheckpointHashFlag = &cli.StringFlag{
+		Name:     "fastsync.checkpointhash",
+		Usage:    "Hash of the trusted block at --fastsync.checkpointheight",
+		Category: flags.KaiCategory,
+	}
+	FastSyncHashChainFlag = &cli.BoolFlag{
+		Name:     "fastsync.hashchain",
+		Usage:    "Below the checkpoint, check that the synced blocks chain up to it instead of their commits",
+		Category: flags.KaiCategory,
+	}
+	ConsensusWalRetainFlag = &cli.Uint64Flag{
+		Name:     "consensus.walretain",
+		Usage:    "Number of committed heights the consensus WAL keeps the messages of (0 = bounded by size only)",
+		Category: flags.KaiCategory,
+	}
+	PrivValidatorListenAddrFlag = &cli.StringFlag{
+		Name:     "privval.laddr",
+		Usage:    "Address (tcp://host:port or unix:///path) to wait on for a remote signer of the validator votes",
+		Category: flags.KaiCategory,
+	}
 	BloomFilterSizeFlag = &cli.Uint64Flag{
 		Name:     "bloomfilter.size",
 		Usage:    "Megabytes of memory allocated to bloom-filter for pruning",
@@ -589,6 +630,39 @@ func SetKaiConfig(ctx *cli.Context, stack *node.Node, cfg *kai.Config) {
 		cfg.FastSync = configs.DefaultFastSyncConfig()
 		cfg.Consensus = configs.DefaultConsensusConfig()
 	}
+	if ctx.IsSet(FastSyncCheckpointHeightFlag.Name) {
+		cfg.FastSync.CheckpointHeight = ctx.Uint64(FastSyncCheckpointHeightFlag.Name)
+	}
+	if ctx.IsSet(FastSyncCheckpointHashFlag.Name) {
+		cfg.FastSync.CheckpointHash = common.HexToHash(ctx.String(FastSyncCheckpointHashFlag.Name))
+	}
+	if ctx.IsSet(FastSyncHashChainFlag.Name) {
+		cfg.FastSync.HashChainBelowCheckpoint = ctx.Bool(FastSyncHashChainFlag.Name)
+	}
+	if ctx.IsSet(PrivValidatorListenAddrFlag.Name) {
+		cfg.PrivValidatorListenAddr = ctx.String(PrivValidatorListenAddrFlag.Name)
+	}
+	if ctx.IsSet(ConsensusWalRetainFlag.Name) {
+		cfg.Consensus.WalRetainHeights = ctx.Uint64(ConsensusWalRetainFlag.Name)
+	}
+	if cfg.FastSync.CheckpointHeight > 0 && cfg.FastSync.CheckpointHash.IsZero() {
+		Fatalf("--%s requires --%s", FastSyncCheckpointHeightFlag.Name, FastSyncCheckpointHashFlag.Name)
+	}
+	if cfg.StateSync == nil {
+		cfg.StateSync = configs.DefaultStateSyncConfig()
+	}
+	if ctx.IsSet(StateSyncFlag.Name) {
+		cfg.StateSync.Enable = ctx.Bool(StateSyncFlag.Name)
+	}
+	if ctx.IsSet(StateSyncTrustHeightFlag.Name) {
+		cfg.StateSync.TrustHeight = ctx.Uint64(StateSyncTrustHeightFlag.Name)
+	}
+	if ctx.IsSet(StateSyncTrustHashFlag.Name) {
+		cfg.StateSync.TrustHash = common.HexToHash(ctx.String(StateSyncTrustHashFlag.Name))
+	}
+	if cfg.StateSync.TrustHeight > 0 && cfg.StateSync.TrustHash.IsZero() {
+		Fatalf("--%s requires --%s", StateSyncTrustHeightFlag.Name, StateSyncTrustHashFlag.Name)
+	}
 
 	// Gas oracle
 	cfg.GasOracle = oracles.DefaultOracleConfig()
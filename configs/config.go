@@ -152,6 +152,10 @@ type ConsensusConfig struct {
 	// Reactor sleep duration parameters are in milliseconds
 	PeerGossipSleepDuration     time.Duration `mapstructure:"peer_gossip_sleep_duration"`
 	PeerQueryMaj23SleepDuration time.Duration `mapstructure:"peer_query_maj23_sleep_duration"`
+
+	// Number of committed heights the WAL keeps the messages of, 0 to only
+	// bound it by the size of its files
+	WalRetainHeights uint64 `mapstructure:"wal_retain_heights"`
 }
 
 // DefaultConsensusConfig returns a default configuration for the consensus service
@@ -206,6 +210,29 @@ func (cfg *ConsensusConfig) Commit(t time.Time) time.Time {
 	return t.Add(cfg.TimeoutCommit)
 }
 
+// WithTimeoutParams returns a copy of the config with the non zero timeouts of
+// the consensus params.
+func (cfg *ConsensusConfig) WithTimeoutParams(params kaiproto.TimeoutParams) *ConsensusConfig {
+	c := *cfg
+	for _, t := range []struct {
+		timeout *time.Duration
+		param   time.Duration
+	}{
+		{&c.TimeoutPropose, params.Propose},
+		{&c.TimeoutProposeDelta, params.ProposeDelta},
+		{&c.TimeoutPrevote, params.Prevote},
+		{&c.TimeoutPrevoteDelta, params.PrevoteDelta},
+		{&c.TimeoutPrecommit, params.Precommit},
+		{&c.TimeoutPrecommitDelta, params.PrecommitDelta},
+		{&c.TimeoutCommit, params.Commit},
+	} {
+		if t.param > 0 {
+			*t.timeout = t.param
+		}
+	}
+	return &c
+}
+
 // Propose returns the amount of time to wait for a proposal
 func (cfg *ConsensusConfig) Propose(round uint32) time.Duration {
 	return time.Duration(
@@ -246,6 +273,17 @@ type FastSyncConfig struct {
 	SyncTimeout   time.Duration // maximum time the scheduler waits to advance in the fast sync process before finishing
 	PeerTimeout   time.Duration // maximum response time from a peer.
 	MinRecvRate   int64         // minimum receive rate from peer, otherwise prune.
+
+	// CheckpointHeight and CheckpointHash pin a trusted block: peers serving
+	// another block at that height are dropped, and the node refuses to start
+	// if its stored block differs.
+	CheckpointHeight uint64
+	CheckpointHash   common.Hash
+	// HashChainBelowCheckpoint skips the fast sync verification of the commits
+	// of the blocks below the checkpoint once the received blocks chain up to
+	// it, which needs TargetPending to reach the checkpoint. Blocks further
+	// down have their commits verified as usual.
+	HashChainBelowCheckpoint bool
 }
 
 func DefaultFastSyncConfig() *FastSyncConfig {
@@ -271,6 +309,26 @@ func TestFastSyncConfig() *FastSyncConfig {
 	}
 }
 
+type StateSyncConfig struct {
+	Enable         bool          // true if an empty node should restore the state from a peer snapshot before fast syncing.
+	TrustHeight    uint64        // height of the trusted header the snapshot header is verified from.
+	TrustHash      common.Hash   // hash of the trusted header.
+	TrustPeriod    time.Duration // time the trusted header is trusted for, so that the headers up to the snapshot can be skipped.
+	DiscoveryTime  time.Duration // time spent collecting snapshots from peers before picking one.
+	RequestTimeout time.Duration // maximum response time from a peer.
+	RequestBytes   uint64        // soft limit of the size of a state range response.
+}
+
+func DefaultStateSyncConfig() *StateSyncConfig {
+	return &StateSyncConfig{
+		Enable:         false,
+		TrustPeriod:    168 * time.Hour,
+		DiscoveryTime:  15 * time.Second,
+		RequestTimeout: 15 * time.Second,
+		RequestBytes:   512 * 1024,
+	}
+}
+
 // ======================= Genesis Utils Functions =======================
 
 type Contract struct {
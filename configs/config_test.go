@@ -0,0 +1,46 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package configs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	kaiproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+)
+
+func TestWithTimeoutParams(t *testing.T) {
+	cfg := DefaultConsensusConfig()
+
+	require.Equal(t, cfg, cfg.WithTimeoutParams(kaiproto.TimeoutParams{}), "unset timeouts keep the config")
+
+	timeouts := cfg.WithTimeoutParams(kaiproto.TimeoutParams{
+		Propose:   5 * time.Second,
+		Precommit: 1500 * time.Millisecond,
+		Commit:    2 * time.Second,
+	})
+	require.Equal(t, 5*time.Second, timeouts.TimeoutPropose)
+	require.Equal(t, 1500*time.Millisecond, timeouts.TimeoutPrecommit)
+	require.Equal(t, 2*time.Second, timeouts.TimeoutCommit)
+	require.Equal(t, cfg.TimeoutProposeDelta, timeouts.TimeoutProposeDelta)
+	require.Equal(t, cfg.TimeoutPrevote, timeouts.TimeoutPrevote)
+	require.Equal(t, 3000*time.Millisecond, cfg.TimeoutPropose, "the config itself is unchanged")
+}
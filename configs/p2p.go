@@ -77,6 +77,24 @@ type P2PConfig struct { //nolint: maligned
 	// Maximum number of outbound peers to connect to, excluding persistent peers
 	MaxNumOutboundPeers int `mapstructure:"max_num_outbound_peers"`
 
+	// Maximum number of inbound connections from a single IP (0 for no limit)
+	MaxInboundPerIP int `mapstructure:"max_inbound_per_ip"`
+
+	// Maximum number of inbound connections from a single /24 (IPv4) or /64
+	// (IPv6) subnet (0 for no limit)
+	MaxInboundPerSubnet int `mapstructure:"max_inbound_per_subnet"`
+
+	// Inbound handshakes per second allowed from a single IP (0 for no limit),
+	// and the number of handshakes it may burst above that rate
+	InboundHandshakeRate  float64 `mapstructure:"inbound_handshake_rate"`
+	InboundHandshakeBurst int     `mapstructure:"inbound_handshake_burst"`
+
+	// CIDRs whose inbound connections are never limited
+	InboundAllowCIDRs []string `mapstructure:"inbound_allow_cidrs"`
+
+	// CIDRs whose inbound connections are always refused, even if allowed
+	InboundDenyCIDRs []string `mapstructure:"inbound_deny_cidrs"`
+
 	// Time to wait before flushing messages out on the connection
 	FlushThrottleTimeout time.Duration `mapstructure:"flush_throttle_timeout"`
 
@@ -95,6 +113,23 @@ type P2PConfig struct { //nolint: maligned
 	// Toggle to disable guard against peers connecting from the same ip.
 	AllowDuplicateIP bool `mapstructure:"allow_duplicate_ip"`
 
+	// Trust score (0-100) below which a misbehaving peer is banned.
+	// Set to 0 to disable banning.
+	PeerBanThreshold int `mapstructure:"peer_ban_threshold"`
+
+	// Ban duration for a peer with a trust score of 0. Peers scoring closer to
+	// the threshold are banned for proportionally less time.
+	PeerBanDuration time.Duration `mapstructure:"peer_ban_duration"`
+
+	// Number of bad events a peer must be reported for before it can be
+	// banned, so that a single report never bans a peer.
+	PeerBanMinBadEvents int `mapstructure:"peer_ban_min_bad_events"`
+
+	// Set true to propagate proposal blocks as compact blocks to the peers
+	// supporting them, which rebuild the blocks from their tx pool. Off by
+	// default until it is enabled across the network
+	CompactBlocks bool `mapstructure:"compact_blocks"`
+
 	// Peer connection configuration.
 	HandshakeTimeout time.Duration `mapstructure:"handshake_timeout"`
 	DialTimeout      time.Duration `mapstructure:"dial_timeout"`
@@ -117,6 +152,11 @@ func DefaultP2PConfig() *P2PConfig {
 		UPNP:                         false,
 		MaxNumInboundPeers:           40,
 		MaxNumOutboundPeers:          15,
+		MaxInboundPerIP:              4,
+		MaxInboundPerSubnet:          16,
+		InboundHandshakeRate:         1,
+		InboundHandshakeBurst:        5,
+		InboundAllowCIDRs:            []string{"127.0.0.0/8", "::1/128"}, // local testnets run several nodes on one host
 		PersistentPeersMaxDialPeriod: 0 * time.Second,
 		FlushThrottleTimeout:         100 * time.Millisecond,
 		// MTU (Maximum Transmission Unit) for Etherenet is 1500 bytes
@@ -132,6 +172,10 @@ func DefaultP2PConfig() *P2PConfig {
 		PexReactor:              true,
 		SeedMode:                false,
 		AllowDuplicateIP:        false,
+		PeerBanThreshold:        30,
+		PeerBanDuration:         24 * time.Hour,
+		PeerBanMinBadEvents:     3,
+		CompactBlocks:           false,
 		HandshakeTimeout:        20 * time.Second,
 		DialTimeout:             3 * time.Second,
 		TestDialFail:            false,
@@ -0,0 +1,7 @@
+package paramsupgrade
+
+// Compiled from kvm/smc/dpos/upgrade/Params.sol with solc 0.8.21+commit.d9974bed
+// solc --optimize --evm-version istanbul --bin-runtime Params.sol
+const (
+	ParamsUpgradeSMCBytecode = `0x6080604052600436106101cd5760003560e01c8063741de148116100f7578063bb3d1d2311610095578063e37e526b11610064578063e37e526b146104ac578063ecdb9778146104c1578063f2fde38b146104d4578063f4a4f4d2146104f457600080fd5b8063bb3d1d2314610458578063c19e19b11461046d578063d58907ec14610482578063d7c1bf591461049757600080fd5b80639902f44e116100d15780639902f44e146103ec5780639cf974f0146104015780639d2f053c14610416578063b0c9569a1461044357600080fd5b8063741de1481461039a5780638da5cb5b146103af57806391add0d7146103d757600080fd5b8063476d41ec1161016f57806365062c5c1161013e57806365062c5c14610346578063693b6e9a1461035b578063705e724c14610370578063715018a61461038557600080fd5b8063476d41ec146102cc57806356a3b5fa146102e1578063578116a3146102f65780635bdcd0081461030b57600080fd5b80631a66cf64116101ab5780631a66cf641461024f5780631cd3b3bf146102645780633b4d01a7146102865780633f1f5e7f146102b757600080fd5b8063013cf08b146101d257806303a2216d1461020c5780631434ff341461023a575b600080fd5b3480156101de57600080fd5b506101f26101ed366004611375565b610514565b6040516102039594939291906113a4565b60405180910390f35b34801561021857600080fd5b5061022c6102273660046113fd565b610562565b604051908152602001610203565b34801561024657600080fd5b5061022c610573565b34801561025b57600080fd5b5061022c610584565b34801561027057600080fd5b5061028461027f36600461141f565b610590565b005b34801561029257600080fd5b506102a66102a1366004611375565b610677565b604051610203959493929190611453565b3480156102c357600080fd5b5061022c6107c8565b3480156102d857600080fd5b5061022c6107d4565b3480156102ed57600080fd5b5061022c6107e0565b34801561030257600080fd5b5061022c6107ec565b34801561031757600080fd5b5061032b610326366004611375565b6107f8565b60408051938452602084019290925290820152606001610203565b34801561035257600080fd5b5061022c610934565b34801561036757600080fd5b5061022c610940565b34801561037c57600080fd5b5061022c61094c565b34801561039157600080fd5b50610284610958565b3480156103a657600080fd5b5061022c610a10565b3480156103bb57600080fd5b506000546040516001600160a01b039091168152602001610203565b3480156103e357600080fd5b5061022c610a1c565b3480156103f857600080fd5b5061022c610a28565b34801561040d57600080fd5b5061022c610a34565b34801561042257600080fd5b5061022c610431366004611375565b60026020526000908152604090205481565b34801561044f57600080fd5b5061022c610a40565b34801561046457600080fd5b5060035461022c565b34801561047957600080fd5b5061022c610a4c565b34801561048e57600080fd5b5061022c610a58565b3480156104a357600080fd5b5061022c610a64565b3480156104b857600080fd5b5061022c610a70565b61022c6104cf3660046115cc565b610a7c565b3480156104e057600080fd5b506102846104ef3660046116a4565b610ba0565b34801561050057600080fd5b5061028461050f366004611375565b610cce565b6003818154811061052457600080fd5b6000918252602090912060099091020180546003820154600483015460068401546007909401546001600160a01b0390931694509092909160ff1685565b600061056d82611048565b92915050565b600061057f6007611048565b905090565b600061057f6006611048565b60035482106105ba5760405162461bcd60e51b81526004016105b1906116c1565b60405180910390fd5b42600383815481106105ce576105ce6116ed565b906000526020600020906009020160040154116106215760405162461bcd60e51b81526020600482015260116024820152701a5b9858dd1a5d99481c1c9bdc1bdcd85b607a1b60448201526064016105b1565b8060038381548110610635576106356116ed565b60009182526020808320338452600560099093020191909101905260409020805460ff1916600183600281111561066e5761066e61138e565b02179055505050565b6000806000606080600080600061068d896107f8565b92509250925082828260038c815481106106a9576106a96116ed565b906000526020600020906009020160010160038d815481106106cd576106cd6116ed565b90600052602060002090600902016002018180548060200260200160405190810160405280929190818152602001828054801561075957602002820191906000526020600020906000905b82829054906101000a900460ff16601f8111156107375761073761138e565b8152602060019283018181049485019490930390920291018084116107185790505b50505050509150808054806020026020016040519081016040528092919081815260200182805480156107ab57602002820191906000526020600020905b815481526020019060010190808311610797575b505050505090509750975097509750975050505091939590929450565b600061057f6005611048565b600061057f600b611048565b600061057f6009611048565b600061057f6011611048565b600080600060038054905084106108215760405162461bcd60e51b81526004016105b1906116c1565b600060038581548110610836576108366116ed565b600091825260209091206007600990920201015460ff16600281111561085e5761085e61138e565b036108775761086c84611076565b92509250925061092d565b60006003858154811061088c5761088c6116ed565b60009182526020822060086009909202010190600181526020019081526020016000205490506000600386815481106108c7576108c76116ed565b6000918252602082206008600990920201019060028152602001908152602001600020549050600060038781548110610902576109026116ed565b6000918252602080832083805260099290920290910160080190526040902054929550909350909150505b9193909250565b600061057f600c611048565b600061057f6003611048565b600061057f6001611048565b6000546001600160a01b0316158061097a57506000546001600160a01b031633145b6109c65760405162461bcd60e51b815260206004820181905260248201527f4f776e61626c653a2063616c6c6572206973206e6f7420746865206f776e657260448201526064016105b1565b600080546040516001600160a01b03909116907f8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0908390a3600080546001600160a01b0319169055565b600061057f600f611048565b600061057f6000611048565b600061057f600e611048565b600061057f6010611048565b600061057f6002611048565b600061057f600d611048565b600061057f6008611048565b600061057f600a611048565b600061057f6004611048565b6000610a886012611048565b341015610ac55760405162461bcd60e51b815260206004820152600b60248201526a1b5a5b8819195c1bdcda5d60aa1b60448201526064016105b1565b60038054600181018255600091909152835160099091027fc2575a0e9e593c00f959f8c92f12db2869c3395a3b0502d05e2516446f71f85b810191610b31917fc2575a0e9e593c00f959f8c92f12db2869c3395a3b0502d05e2516446f71f85c01906020870190611271565b508251610b479060028301906020860190611325565b5080546001600160a01b03191633178155346006820155426003820155610b6e6013611048565b610b789042611719565b600482015560078101805460ff19169055600354610b989060019061172c565b949350505050565b6000546001600160a01b03161580610bc257506000546001600160a01b031633145b610c0e5760405162461bcd60e51b815260206004820181905260248201527f4f776e61626c653a2063616c6c6572206973206e6f7420746865206f776e657260448201526064016105b1565b6001600160a01b038116610c735760405162461bcd60e51b815260206004820152602660248201527f4f776e61626c653a206e6577206f776e657220697320746865207a65726f206160448201526564647265737360d01b60648201526084016105b1565b600080546040516001600160a01b03808516939216917f8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e091a3600080546001600160a01b0319166001600160a01b0392909216919091179055565b6003548110610cef5760405162461bcd60e51b81526004016105b1906116c1565b600060038281548110610d0457610d046116ed565b600091825260209091206007600990920201015460ff166002811115610d2c57610d2c61138e565b14610d795760405162461bcd60e51b815260206004820152601760248201527f70726f706f73616c207374617475732070656e64696e6700000000000000000060448201526064016105b1565b4260038281548110610d8d57610d8d6116ed565b90600052602060002090600902016004015410610de05760405162461bcd60e51b8152602060048201526011602482015270125b9858dd1a5d99481c1c9bdc1bdcd85b607a1b60448201526064016105b1565b600060038281548110610df557610df56116ed565b906000526020600020906009020190506000806000610e1385611076565b60016000908152600888016020526040808220859055600282528082208490558180528120829055929550909350915081610e4e8486611719565b610e589190611719565b905060006003610e6983600261173f565b610e739190611756565b610e7e906001611719565b905080851015610f4b5760078601805460ff19166002179055600154604080516361d027b360e01b815290516001600160a01b03909216916361d027b3916004808201926020929091908290030181865afa158015610ee1573d6000803e3d6000fd5b505050506040513d601f19601f82011682018060405250810190610f059190611778565b6001600160a01b03166108fc87600601549081150290604051600060405180830381858888f19350505050158015610f41573d6000803e3d6000fd5b5050505050505050565b60005b6001870154811015610fc957610fb7876001018281548110610f7257610f726116ed565b90600052602060002090602091828204019190069054906101000a900460ff16886002018381548110610fa757610fa76116ed565b9060005260206000200154611245565b80610fc181611795565b915050610f4e565b508554600380546001600160a01b03909216916108fc91908a908110610ff157610ff16116ed565b9060005260206000209060090201600601549081150290604051600060405180830381858888f1935050505015801561102e573d6000803e3d6000fd5b50505050600792909201805460ff19166001179055505050565b60006002600083601f8111156110605761106061138e565b8152602001908152602001600020549050919050565b6000806000606080600160009054906101000a90046001600160a01b03166001600160a01b0316635e5a3cb66040518163ffffffff1660e01b8152600401600060405180830381865afa1580156110d1573d6000803e3d6000fd5b505050506040513d6000823e601f3d908101601f191682016040526110f99190810190611809565b909250905060008080805b855181101561123557600060038b81548110611122576111226116ed565b90600052602060002090600902016005016000888481518110611147576111476116ed565b6020908102919091018101516001600160a01b031682528101919091526040016000205460ff16905060018160028111156111845761118461138e565b036111b55785828151811061119b5761119b6116ed565b6020026020010151856111ae9190611719565b9450611222565b60028160028111156111c9576111c961138e565b036111fa578582815181106111e0576111e06116ed565b6020026020010151846111f39190611719565b9350611222565b85828151811061120c5761120c6116ed565b60200260200101518361121f9190611719565b92505b508061122d81611795565b915050611104565b5091989097509095509350505050565b806002600084601f81111561125c5761125c61138e565b81526020810191909152604001600020555050565b82805482825590600052602060002090601f016020900481019282156113155791602002820160005b838211156112e657835183826101000a81548160ff0219169083601f8111156112c5576112c561138e565b0217905550926020019260010160208160000104928301926001030261129a565b80156113135782816101000a81549060ff02191690556001016020816000010492830192600103026112e6565b505b50611321929150611360565b5090565b828054828255906000526020600020908101928215611315579160200282015b82811115611315578251825591602001919060010190611345565b5b808211156113215760008155600101611361565b60006020828403121561138757600080fd5b5035919050565b634e487b7160e01b600052602160045260246000fd5b6001600160a01b038616815260208101859052604081018490526060810183905260a08101600383106113d9576113d961138e565b8260808301529695505050505050565b8035602081106113f857600080fd5b919050565b60006020828403121561140f57600080fd5b611418826113e9565b9392505050565b6000806040838503121561143257600080fd5b8235915060208301356003811061144857600080fd5b809150509250929050565b600060a082018783526020878185015286604085015260a0606085015281865180845260c086019150828801935060005b818110156114af57845184811061149d5761149d61138e565b83529383019391830191600101611484565b50508481036080860152855180825290820192508186019060005b818110156114e6578251855293830193918301916001016114ca565b50929a9950505050505050505050565b634e487b7160e01b600052604160045260246000fd5b604051601f8201601f1916810167ffffffffffffffff81118282101715611535576115356114f6565b604052919050565b600067ffffffffffffffff821115611557576115576114f6565b5060051b60200190565b600082601f83011261157257600080fd5b813560206115876115828361153d565b61150c565b82815260059290921b840181019181810190868411156115a657600080fd5b8286015b848110156115c157803583529183019183016115aa565b509695505050505050565b600080604083850312156115df57600080fd5b823567ffffffffffffffff808211156115f757600080fd5b818501915085601f83011261160b57600080fd5b8135602061161b6115828361153d565b82815260059290921b8401810191818101908984111561163a57600080fd5b948201945b8386101561165f57611650866113e9565b8252948201949082019061163f565b9650508601359250508082111561167557600080fd5b5061168285828601611561565b9150509250929050565b6001600160a01b03811681146116a157600080fd5b50565b6000602082840312156116b657600080fd5b81356114188161168c565b6020808252601290820152711c1c9bdc1bdcd85b081b9bdd08199bdd5b9960721b604082015260600190565b634e487b7160e01b600052603260045260246000fd5b634e487b7160e01b600052601160045260246000fd5b8082018082111561056d5761056d611703565b8181038181111561056d5761056d611703565b808202811582820484141761056d5761056d611703565b60008261177357634e487b7160e01b600052601260045260246000fd5b500490565b60006020828403121561178a57600080fd5b81516114188161168c565b6000600182016117a7576117a7611703565b5060010190565b600082601f8301126117bf57600080fd5b815160206117cf6115828361153d565b82815260059290921b840181019181810190868411156117ee57600080fd5b8286015b848110156115c157805183529183019183016117f2565b6000806040838503121561181c57600080fd5b825167ffffffffffffffff8082111561183457600080fd5b818501915085601f83011261184857600080fd5b815160206118586115828361153d565b82815260059290921b8401810191818101908984111561187757600080fd5b948201945b8386101561189e57855161188f8161168c565b8252948201949082019061187c565b918801519196509093505050808211156118b757600080fd5b50611682858286016117ae56fea2646970667358221220603e41a06d7f901856128b2c4ac8a776c93b1a957bfc1f96fa2425e9a192d82864736f6c63430008150033`
+)
@@ -0,0 +1,661 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package consensus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+
+	cstypes "github.com/kardiachain/go-kardia/consensus/types"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/log"
+	"github.com/kardiachain/go-kardia/lib/p2p"
+	"github.com/kardiachain/go-kardia/lib/rlp"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// CompactBlocksCapability is advertised in the NodeInfo of the nodes which
+// propagate proposal blocks as compact blocks. Both ends of a connection have
+// to advertise it, otherwise the block parts are sent as usual.
+const CompactBlocksCapability = "compact_blocks"
+
+const (
+	// Time a peer has to rebuild a compact block before it is sent the parts.
+	compactBlockTimeout = time.Second
+	// Number of compact blocks kept to answer the requests of their txs.
+	compactBlockCacheSize = 4
+)
+
+// TxSource provides the transactions compact blocks are rebuilt from,
+// usually the tx pool.
+type TxSource interface {
+	// Range calls f on each transaction until f returns false.
+	Range(f func(tx *types.Transaction) bool)
+}
+
+// SetTxSource sets the transactions compact blocks are rebuilt from. Without
+// it, all the transactions of a compact block are requested from the peer.
+func (conR *ConsensusManager) SetTxSource(txs TxSource) {
+	conR.txSource = txs
+}
+
+// shortTxID is the short ID of a transaction in compact blocks, the first
+// 8 bytes of its hash.
+func shortTxID(hash common.Hash) uint64 {
+	return binary.BigEndian.Uint64(hash[:8])
+}
+
+//-------------------------------------
+
+// CompactBlockMessage is sent instead of the parts of the proposal block to
+// the peers supporting compact blocks. The block is sent without its
+// transactions, which the peer looks up by their short IDs.
+type CompactBlockMessage struct {
+	Height           uint64
+	Round            uint32
+	BlockPartsHeader types.PartSetHeader
+	Block            *kproto.Block // without Data.Txs
+	ShortTxIDs       []uint64
+}
+
+// ValidateBasic performs basic validation.
+func (m *CompactBlockMessage) ValidateBasic() error {
+	if err := m.BlockPartsHeader.ValidateBasic(); err != nil {
+		return fmt.Errorf("wrong BlockPartsHeader: %v", err)
+	}
+	if m.Block == nil {
+		return errors.New("nil Block")
+	}
+	if len(m.Block.Data.Txs) != 0 {
+		return fmt.Errorf("compact block has %d txs", len(m.Block.Data.Txs))
+	}
+	return nil
+}
+
+// String returns a string representation.
+func (m *CompactBlockMessage) String() string {
+	return fmt.Sprintf("[CompactBlock H:%v R:%v BP:%v Txs:%v]", m.Height, m.Round, m.BlockPartsHeader, len(m.ShortTxIDs))
+}
+
+// BlockTxsRequestMessage is sent to request the transactions of a compact
+// block missing from the tx pool, by their index in the block.
+type BlockTxsRequestMessage struct {
+	Height  uint64
+	Round   uint32
+	Indexes []uint32
+}
+
+// ValidateBasic performs basic validation.
+func (m *BlockTxsRequestMessage) ValidateBasic() error {
+	if len(m.Indexes) == 0 {
+		return errors.New("empty Indexes")
+	}
+	return nil
+}
+
+// String returns a string representation.
+func (m *BlockTxsRequestMessage) String() string {
+	return fmt.Sprintf("[BlockTxsRequest H:%v R:%v Txs:%v]", m.Height, m.Round, len(m.Indexes))
+}
+
+// BlockTxsResponseMessage answers a BlockTxsRequestMessage with the encoded
+// transactions, in the order of the requested indexes. No transactions tell
+// that the request can't be served, the peer then falls back to the block
+// parts.
+type BlockTxsResponseMessage struct {
+	Height uint64
+	Round  uint32
+	Txs    [][]byte
+}
+
+// ValidateBasic performs basic validation.
+func (m *BlockTxsResponseMessage) ValidateBasic() error {
+	return nil
+}
+
+// String returns a string representation.
+func (m *BlockTxsResponseMessage) String() string {
+	return fmt.Sprintf("[BlockTxsResponse H:%v R:%v Txs:%v]", m.Height, m.Round, len(m.Txs))
+}
+
+// CompactBlockAckMessage tells the sender of a compact block whether it was
+// rebuilt into the proposal block. If it was not, the sender falls back to
+// the block parts.
+type CompactBlockAckMessage struct {
+	Height  uint64
+	Round   uint32
+	Rebuilt bool
+}
+
+// ValidateBasic performs basic validation.
+func (m *CompactBlockAckMessage) ValidateBasic() error {
+	return nil
+}
+
+// String returns a string representation.
+func (m *CompactBlockAckMessage) String() string {
+	return fmt.Sprintf("[CompactBlockAck H:%v R:%v Rebuilt:%v]", m.Height, m.Round, m.Rebuilt)
+}
+
+//-------------------------------------
+
+// compactBlock is a compact block of ours, with the encoded transactions
+// peers may request.
+type compactBlock struct {
+	msg *CompactBlockMessage
+	bz  []byte
+	txs [][]byte
+}
+
+func newCompactBlock(height uint64, round uint32, block *types.Block, parts *types.PartSet) (*compactBlock, error) {
+	pbb, err := block.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	txs := pbb.Data.Txs
+	pbb.Data.Txs = nil
+
+	ids := make([]uint64, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		ids[i] = shortTxID(tx.Hash())
+	}
+	msg := &CompactBlockMessage{
+		Height:           height,
+		Round:            round,
+		BlockPartsHeader: parts.Header(),
+		Block:            pbb,
+		ShortTxIDs:       ids,
+	}
+	bz := MustEncode(msg)
+	if len(bz) > maxMsgSize {
+		return nil, fmt.Errorf("compact block is too big: %d bytes", len(bz))
+	}
+	return &compactBlock{msg: msg, bz: bz, txs: txs}, nil
+}
+
+// compactBlockCache holds our most recent compact blocks.
+type compactBlockCache struct {
+	mtx    sync.Mutex
+	blocks []*compactBlock
+}
+
+// get returns the compact block of the proposal block, building it if it is
+// not cached.
+func (c *compactBlockCache) get(height uint64, round uint32, block *types.Block, parts *types.PartSet) (*compactBlock, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for _, cb := range c.blocks {
+		if cb.msg.Height == height && cb.msg.Round == round && cb.msg.BlockPartsHeader.Equals(parts.Header()) {
+			return cb, nil
+		}
+	}
+	cb, err := newCompactBlock(height, round, block, parts)
+	if err != nil {
+		return nil, err
+	}
+	c.blocks = append(c.blocks, cb)
+	if len(c.blocks) > compactBlockCacheSize {
+		c.blocks = c.blocks[1:]
+	}
+	return cb, nil
+}
+
+// find returns the cached compact block of the height and round, nil if
+// there is none.
+func (c *compactBlockCache) find(height uint64, round uint32) *compactBlock {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for _, cb := range c.blocks {
+		if cb.msg.Height == height && cb.msg.Round == round {
+			return cb
+		}
+	}
+	return nil
+}
+
+// rebuildCompactBlock returns the parts of the compact block, given all its
+// encoded transactions. It fails if the parts don't match the header of the
+// compact block.
+func rebuildCompactBlock(msg *CompactBlockMessage, txs [][]byte) (*types.PartSet, error) {
+	pbb := *msg.Block
+	pbb.Data.Txs = txs
+	bz, err := proto.Marshal(&pbb)
+	if err != nil {
+		return nil, err
+	}
+	parts := types.NewPartSetFromData(bz, types.BlockPartSizeBytes)
+	if !parts.Header().Equals(msg.BlockPartsHeader) {
+		return nil, fmt.Errorf("rebuilt parts header %v, expected %v", parts.Header(), msg.BlockPartsHeader)
+	}
+	return parts, nil
+}
+
+// lookupTxs returns the encoded transactions of the short IDs found in the
+// tx source, and the indexes of the missing ones. IDs shared by several
+// transactions of the source count as missing.
+func (conR *ConsensusManager) lookupTxs(ids []uint64) ([][]byte, []uint32) {
+	found := make(map[uint64]*types.Transaction, len(ids))
+	for _, id := range ids {
+		found[id] = nil
+	}
+	ambiguous := make(map[uint64]bool)
+	if conR.txSource != nil {
+		conR.txSource.Range(func(tx *types.Transaction) bool {
+			id := shortTxID(tx.Hash())
+			if prev, ok := found[id]; ok {
+				if prev != nil && prev.Hash() != tx.Hash() {
+					ambiguous[id] = true
+				}
+				found[id] = tx
+			}
+			return true
+		})
+	}
+
+	txs := make([][]byte, len(ids))
+	var missing []uint32
+	for i, id := range ids {
+		if tx := found[id]; tx != nil && !ambiguous[id] {
+			if bz, err := rlp.EncodeToBytes(tx); err == nil {
+				txs[i] = bz
+				continue
+			}
+		}
+		missing = append(missing, uint32(i))
+	}
+	return txs, missing
+}
+
+// compactBlocksWith returns whether compact blocks are exchanged with the
+// peer.
+func (conR *ConsensusManager) compactBlocksWith(peer p2p.Peer) bool {
+	if conR.Switch == nil {
+		return false
+	}
+	ours, ok := conR.Switch.NodeInfo().(p2p.DefaultNodeInfo)
+	if !ok || !ours.HasCapability(CompactBlocksCapability) {
+		return false
+	}
+	theirs, ok := peer.NodeInfo().(p2p.DefaultNodeInfo)
+	return ok && theirs.HasCapability(CompactBlocksCapability)
+}
+
+// gossipCompactBlock sends the compact block of the proposal block to the
+// peer, if they exchange compact blocks. It returns whether the block parts
+// are held back while the peer rebuilds the block.
+func (conR *ConsensusManager) gossipCompactBlock(logger log.Logger, peer p2p.Peer, ps *PeerState,
+	rs *cstypes.RoundState, prs *cstypes.PeerRoundState) bool {
+	if !ps.compactBlocks || prs.ProposalBlockParts.IsFull() {
+		return false
+	}
+	header := prs.ProposalBlockPartsHeader
+	if sent, done := ps.compactBlockState(header); sent {
+		return !done
+	}
+	// Relay the parts of blocks we don't have in full yet
+	if !rs.ProposalBlockParts.IsComplete() || rs.ProposalBlock == nil {
+		return false
+	}
+	cb, err := conR.compactBlocks.get(rs.Height, rs.Round, rs.ProposalBlock, rs.ProposalBlockParts)
+	if err != nil {
+		logger.Debug("Sending block parts instead of compact block", "err", err)
+		return false
+	}
+	logger.Debug("Sending compact block", "height", prs.Height, "round", prs.Round)
+	if peer.Send(DataChannel, cb.bz) {
+		ps.setCompactBlockSent(rs.Height, rs.Round, header)
+		compactBlocksSentMeter.Mark(1)
+	}
+	return true
+}
+
+// receiveCompactBlock rebuilds the compact block of the peer from the tx
+// source, or requests the missing transactions. Only the first compact block
+// of the peer for a height and round is rebuilt.
+func (conR *ConsensusManager) receiveCompactBlock(src p2p.Peer, ps *PeerState, msg *CompactBlockMessage) {
+	if !ps.compactBlocks {
+		conR.ackCompactBlock(src, msg.Height, msg.Round, false)
+		return
+	}
+	rs := conR.conS.GetRoundState()
+	if rs.Height != msg.Height {
+		conR.ackCompactBlock(src, msg.Height, msg.Round, false)
+		return
+	}
+	if rs.ProposalBlockParts.HasHeader(msg.BlockPartsHeader) && rs.ProposalBlockParts.IsComplete() {
+		// We have all the parts, the peer has nothing left to send
+		conR.ackCompactBlock(src, msg.Height, msg.Round, true)
+		return
+	}
+	if !ps.setCompactBlockReceived(msg.Height, msg.Round) {
+		conR.ackCompactBlock(src, msg.Height, msg.Round, false)
+		return
+	}
+
+	txs, missing := conR.lookupTxs(msg.ShortTxIDs)
+	if len(missing) == 0 {
+		conR.addCompactBlock(src, ps, msg, txs)
+		return
+	}
+	// Requesting most of the txs costs about as much as the parts
+	if 2*len(missing) > len(msg.ShortTxIDs) {
+		conR.ackCompactBlock(src, msg.Height, msg.Round, false)
+		return
+	}
+	compactBlockMissingTxsMeter.Mark(int64(len(missing)))
+	ps.setPendingCompactBlock(&pendingCompactBlock{msg: msg, txs: txs, missing: missing, requestedAt: time.Now()})
+	if !src.TrySend(DataChannel, MustEncode(&BlockTxsRequestMessage{
+		Height:  msg.Height,
+		Round:   msg.Round,
+		Indexes: missing,
+	})) {
+		ps.takePendingCompactBlock(msg.Height, msg.Round)
+		conR.ackCompactBlock(src, msg.Height, msg.Round, false)
+	}
+}
+
+// receiveBlockTxsRequest sends the peer the requested transactions of our
+// compact block, or no transactions if it can't serve them.
+func (conR *ConsensusManager) receiveBlockTxsRequest(src p2p.Peer, ps *PeerState, msg *BlockTxsRequestMessage) {
+	cb := conR.compactBlocks.find(msg.Height, msg.Round)
+	if cb == nil {
+		// Too old, the peer falls back to the parts
+		conR.sendBlockTxs(src, msg.Height, msg.Round, nil)
+		return
+	}
+	txs := make([][]byte, len(msg.Indexes))
+	size := 0
+	for i, index := range msg.Indexes {
+		if int(index) >= len(cb.txs) {
+			conR.Switch.MarkPeerAsBad(src, fmt.Errorf("requested tx %d of a compact block with %d txs", index, len(cb.txs)))
+			return
+		}
+		txs[i] = cb.txs[index]
+		size += len(txs[i])
+	}
+	if size > maxMsgSize/2 {
+		ps.setCompactBlockFailed(msg.Height, msg.Round)
+		conR.sendBlockTxs(src, msg.Height, msg.Round, nil)
+		return
+	}
+	conR.sendBlockTxs(src, msg.Height, msg.Round, txs)
+}
+
+func (conR *ConsensusManager) sendBlockTxs(src p2p.Peer, height uint64, round uint32, txs [][]byte) {
+	src.TrySend(DataChannel, MustEncode(&BlockTxsResponseMessage{
+		Height: height,
+		Round:  round,
+		Txs:    txs,
+	}))
+}
+
+// receiveBlockTxsResponse rebuilds the pending compact block of the peer with
+// the transactions it was missing.
+func (conR *ConsensusManager) receiveBlockTxsResponse(src p2p.Peer, ps *PeerState, msg *BlockTxsResponseMessage) {
+	pending := ps.takePendingCompactBlock(msg.Height, msg.Round)
+	if pending == nil {
+		return
+	}
+	if len(msg.Txs) != len(pending.missing) {
+		conR.ackCompactBlock(src, msg.Height, msg.Round, false)
+		return
+	}
+	for i, index := range pending.missing {
+		pending.txs[index] = msg.Txs[i]
+	}
+	conR.addCompactBlock(src, ps, pending.msg, pending.txs)
+}
+
+// addCompactBlock rebuilds the parts of the compact block, and hands them to
+// the consensus state as if the peer had sent them. The peer is acked once
+// the consensus state completes the proposal block with them, see
+// ackRebuiltCompactBlocks. Until then, or if the parts are rejected, the
+// peer falls back to the block parts when its compact block times out.
+func (conR *ConsensusManager) addCompactBlock(src p2p.Peer, ps *PeerState, msg *CompactBlockMessage, txs [][]byte) {
+	parts, err := rebuildCompactBlock(msg, txs)
+	if err != nil {
+		conR.Logger.Debug("Failed to rebuild compact block", "peer", src, "height", msg.Height, "round", msg.Round, "err", err)
+		conR.ackCompactBlock(src, msg.Height, msg.Round, false)
+		return
+	}
+	ps.setCompactBlockRebuilt(msg.Height, msg.Round, parts.Header())
+	for i := 0; i < int(parts.Total()); i++ {
+		ps.SetHasProposalBlockPart(msg.Height, msg.Round, i)
+		conR.conS.peerMsgQueue <- msgInfo{&BlockPartMessage{
+			Height: msg.Height,
+			Round:  msg.Round,
+			Part:   parts.GetPart(i),
+		}, src.ID()}
+	}
+	compactBlocksRebuiltMeter.Mark(1)
+}
+
+// ackRebuiltCompactBlocks acks the compact blocks rebuilt into the complete
+// proposal block of the round state.
+func (conR *ConsensusManager) ackRebuiltCompactBlocks(rs *cstypes.RoundState) {
+	if rs.ProposalBlockParts == nil {
+		return
+	}
+	header := rs.ProposalBlockParts.Header()
+	for _, peer := range conR.Switch.Peers().List() {
+		ps, ok := peer.Get(types.PeerStateKey).(*PeerState)
+		if !ok {
+			continue
+		}
+		if round, ok := ps.takeCompactBlockRebuilt(rs.Height, header); ok {
+			conR.ackCompactBlock(peer, rs.Height, round, true)
+		}
+	}
+}
+
+func (conR *ConsensusManager) ackCompactBlock(src p2p.Peer, height uint64, round uint32, rebuilt bool) {
+	src.TrySend(DataChannel, MustEncode(&CompactBlockAckMessage{
+		Height:  height,
+		Round:   round,
+		Rebuilt: rebuilt,
+	}))
+}
+
+//-------------------------------------
+
+// sentCompactBlock is the last compact block sent to a peer.
+type sentCompactBlock struct {
+	height uint64
+	round  uint32
+	header types.PartSetHeader
+	sentAt time.Time
+	done   bool // acked or timed out
+}
+
+// receivedCompactBlock is the last compact block received from a peer.
+type receivedCompactBlock struct {
+	height  uint64
+	round   uint32
+	header  types.PartSetHeader
+	rebuilt bool // rebuilt, not acked yet
+}
+
+// pendingCompactBlock is a compact block of a peer waiting for the
+// transactions missing from the tx source.
+type pendingCompactBlock struct {
+	msg         *CompactBlockMessage
+	txs         [][]byte
+	missing     []uint32
+	requestedAt time.Time
+}
+
+// compactBlockState returns whether the compact block of the block parts
+// header was sent to the peer, and whether the peer is done with it.
+func (ps *PeerState) compactBlockState(header types.PartSetHeader) (sent bool, done bool) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	cb := &ps.compactBlock
+	if cb.sentAt.IsZero() || !cb.header.Equals(header) {
+		return false, false
+	}
+	if !cb.done && time.Since(cb.sentAt) > compactBlockTimeout {
+		cb.done = true
+		compactBlockFallbackMeter.Mark(1)
+	}
+	return true, cb.done
+}
+
+func (ps *PeerState) setCompactBlockSent(height uint64, round uint32, header types.PartSetHeader) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	ps.compactBlock = sentCompactBlock{
+		height: height,
+		round:  round,
+		header: header,
+		sentAt: time.Now(),
+	}
+}
+
+// setCompactBlockFailed makes the peer fall back to the block parts of the
+// compact block sent for the height and round.
+func (ps *PeerState) setCompactBlockFailed(height uint64, round uint32) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	cb := &ps.compactBlock
+	if cb.height != height || cb.round != round || cb.done {
+		return
+	}
+	cb.done = true
+	compactBlockFallbackMeter.Mark(1)
+}
+
+// ApplyCompactBlockAckMessage updates the peer state for the ack of a compact
+// block. The peer acks a rebuilt block once its parts completed the proposal
+// block, so all of them are known to the peer.
+func (ps *PeerState) ApplyCompactBlockAckMessage(msg *CompactBlockAckMessage) {
+	if !msg.Rebuilt {
+		ps.setCompactBlockFailed(msg.Height, msg.Round)
+		return
+	}
+
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	cb := &ps.compactBlock
+	if cb.height != msg.Height || cb.round != msg.Round {
+		return
+	}
+	cb.done = true
+	if ps.PRS.ProposalBlockParts == nil || !ps.PRS.ProposalBlockPartsHeader.Equals(cb.header) {
+		return
+	}
+	for i := 0; i < ps.PRS.ProposalBlockParts.Size(); i++ {
+		ps.PRS.ProposalBlockParts.SetIndex(i, true)
+	}
+}
+
+// setCompactBlockReceived records the compact block of the peer for the
+// height and round. It returns false if the peer already sent one for them.
+// A compact block of another height or round waiting for its transactions is
+// dropped.
+func (ps *PeerState) setCompactBlockReceived(height uint64, round uint32) bool {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	ps.dropStalePendingCompactBlock(height, round)
+	if ps.compactBlockRecv.height == height && ps.compactBlockRecv.round == round {
+		return false
+	}
+	ps.compactBlockRecv = receivedCompactBlock{height: height, round: round}
+	return true
+}
+
+// setCompactBlockRebuilt records that the compact block of the peer for the
+// height and round was rebuilt into the parts with the header.
+func (ps *PeerState) setCompactBlockRebuilt(height uint64, round uint32, header types.PartSetHeader) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	recv := &ps.compactBlockRecv
+	if recv.height != height || recv.round != round {
+		return
+	}
+	recv.header = header
+	recv.rebuilt = true
+}
+
+// takeCompactBlockRebuilt returns the round of the compact block of the peer
+// rebuilt into the parts with the header at the height, and whether there is
+// one left to ack.
+func (ps *PeerState) takeCompactBlockRebuilt(height uint64, header types.PartSetHeader) (uint32, bool) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	recv := &ps.compactBlockRecv
+	if !recv.rebuilt || recv.height != height || !recv.header.Equals(header) {
+		return 0, false
+	}
+	recv.rebuilt = false
+	return recv.round, true
+}
+
+func (ps *PeerState) setPendingCompactBlock(pending *pendingCompactBlock) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	ps.pendingCompactBlock = pending
+}
+
+// takePendingCompactBlock removes and returns the pending compact block of
+// the height and round, nil if there is none or if it timed out.
+func (ps *PeerState) takePendingCompactBlock(height uint64, round uint32) *pendingCompactBlock {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	pending := ps.pendingCompactBlock
+	if pending != nil && time.Since(pending.requestedAt) > compactBlockTimeout {
+		ps.pendingCompactBlock = nil
+		return nil
+	}
+	if pending == nil || pending.msg.Height != height || pending.msg.Round != round {
+		return nil
+	}
+	ps.pendingCompactBlock = nil
+	return pending
+}
+
+// dropStalePendingCompactBlock drops the pending compact block if it is not
+// of the height and round, or if its transactions weren't received in time,
+// in which case the peer already fell back to the block parts.
+// The caller must hold ps.mtx.
+func (ps *PeerState) dropStalePendingCompactBlock(height uint64, round uint32) {
+	pending := ps.pendingCompactBlock
+	if pending == nil {
+		return
+	}
+	if pending.msg.Height != height || pending.msg.Round != round || time.Since(pending.requestedAt) > compactBlockTimeout {
+		ps.pendingCompactBlock = nil
+	}
+}
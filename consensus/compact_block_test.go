@@ -0,0 +1,179 @@
+package consensus
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cstypes "github.com/kardiachain/go-kardia/consensus/types"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/crypto"
+	"github.com/kardiachain/go-kardia/lib/p2p/mock"
+	"github.com/kardiachain/go-kardia/trie"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+type txsSource []*types.Transaction
+
+func (s txsSource) Range(f func(tx *types.Transaction) bool) {
+	for _, tx := range s {
+		if !f(tx) {
+			return
+		}
+	}
+}
+
+func makeCompactBlockTest(t *testing.T, numTxs int) (*types.Block, *types.PartSet, []*types.Transaction) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	txs := make([]*types.Transaction, numTxs)
+	for i := range txs {
+		tx := types.NewTransaction(uint64(i), common.HexToAddress("0x1"), big.NewInt(1), 100000, big.NewInt(1), nil)
+		txs[i], err = types.SignTx(types.HomesteadSigner{}, tx, key)
+		require.NoError(t, err)
+	}
+	block := types.NewBlock(&types.Header{Height: 2, GasLimit: 1}, txs, &types.Commit{}, nil, trie.NewStackTrie(nil))
+	return block, block.MakePartSet(types.BlockPartSizeBytes), txs
+}
+
+func TestCompactBlockMessage(t *testing.T) {
+	block, parts, _ := makeCompactBlockTest(t, 3)
+	cb, err := newCompactBlock(2, 1, block, parts)
+	require.NoError(t, err)
+	assert.Len(t, cb.txs, 3)
+
+	msg, err := decodeMsg(cb.bz)
+	require.NoError(t, err)
+	require.NoError(t, msg.ValidateBasic())
+	assert.Equal(t, cb.bz, MustEncode(msg))
+
+	withTxs := *cb.msg.Block
+	withTxs.Data.Txs = cb.txs
+	assert.Error(t, (&CompactBlockMessage{BlockPartsHeader: parts.Header(), Block: &withTxs}).ValidateBasic())
+	assert.Error(t, (&CompactBlockMessage{BlockPartsHeader: parts.Header()}).ValidateBasic())
+	assert.Error(t, (&BlockTxsRequestMessage{Height: 2}).ValidateBasic())
+}
+
+func TestRebuildCompactBlock(t *testing.T) {
+	block, parts, txs := makeCompactBlockTest(t, 4)
+	cb, err := newCompactBlock(2, 0, block, parts)
+	require.NoError(t, err)
+
+	conR := &ConsensusManager{}
+	conR.SetTxSource(txsSource{txs[3], txs[0], txs[2]})
+	found, missing := conR.lookupTxs(cb.msg.ShortTxIDs)
+	assert.Equal(t, []uint32{1}, missing)
+
+	_, err = rebuildCompactBlock(cb.msg, found)
+	assert.Error(t, err, "rebuilt without a tx")
+
+	found[1] = cb.txs[1]
+	rebuilt, err := rebuildCompactBlock(cb.msg, found)
+	require.NoError(t, err)
+	for i := 0; i < int(parts.Total()); i++ {
+		assert.Equal(t, parts.GetPart(i).Bytes, rebuilt.GetPart(i).Bytes)
+	}
+}
+
+func TestPeerStateCompactBlockReceived(t *testing.T) {
+	ps := NewPeerState(nil)
+	assert.True(t, ps.setCompactBlockReceived(2, 0))
+	assert.False(t, ps.setCompactBlockReceived(2, 0), "second compact block of the round")
+	assert.True(t, ps.setCompactBlockReceived(2, 1))
+
+	ps.setPendingCompactBlock(&pendingCompactBlock{msg: &CompactBlockMessage{Height: 2, Round: 1}, requestedAt: time.Now()})
+	require.NotNil(t, ps.takePendingCompactBlock(2, 1))
+}
+
+func TestPeerStateCompactBlockUnanswered(t *testing.T) {
+	pending := func(height uint64, round uint32, requestedAt time.Time) *pendingCompactBlock {
+		return &pendingCompactBlock{msg: &CompactBlockMessage{Height: height, Round: round}, requestedAt: requestedAt}
+	}
+	ps := NewPeerState(nil)
+
+	// A compact block of the next round drops the one waiting for its txs.
+	require.True(t, ps.setCompactBlockReceived(2, 0))
+	ps.setPendingCompactBlock(pending(2, 0, time.Now()))
+	assert.True(t, ps.setCompactBlockReceived(2, 1))
+	assert.Nil(t, ps.takePendingCompactBlock(2, 0))
+
+	// So does the peer moving to a new height.
+	ps.setPendingCompactBlock(pending(2, 1, time.Now()))
+	ps.ApplyNewRoundStepMessage(&NewRoundStepMessage{Height: 3, Round: 0, Step: cstypes.RoundStepNewHeight})
+	assert.Nil(t, ps.takePendingCompactBlock(2, 1))
+
+	// A response arriving after the timeout is ignored.
+	ps.setPendingCompactBlock(pending(3, 0, time.Now().Add(-2*compactBlockTimeout)))
+	assert.Nil(t, ps.takePendingCompactBlock(3, 0))
+	assert.Nil(t, ps.pendingCompactBlock)
+}
+
+// sentMsgsPeer records the messages sent to the peer.
+type sentMsgsPeer struct {
+	*mock.Peer
+	msgs []Message
+}
+
+func (p *sentMsgsPeer) TrySend(chID byte, msgBytes []byte) bool {
+	msg, err := decodeMsg(msgBytes)
+	if err != nil {
+		panic(err)
+	}
+	p.msgs = append(p.msgs, msg)
+	return true
+}
+
+func TestBlockTxsRequestNotServed(t *testing.T) {
+	block, parts, _ := makeCompactBlockTest(t, 2)
+	conR := &ConsensusManager{}
+	requester := &sentMsgsPeer{Peer: mock.NewPeer(nil)}
+
+	// The compact block left the cache, the request is answered without txs.
+	conR.receiveBlockTxsRequest(requester, NewPeerState(requester), &BlockTxsRequestMessage{Height: 2, Round: 0, Indexes: []uint32{1}})
+	require.Len(t, requester.msgs, 1)
+	resp, ok := requester.msgs[0].(*BlockTxsResponseMessage)
+	require.True(t, ok)
+	assert.Empty(t, resp.Txs)
+
+	// The requester drops its pending compact block and makes the sender
+	// fall back to the block parts.
+	cb, err := newCompactBlock(2, 0, block, parts)
+	require.NoError(t, err)
+	sender := &sentMsgsPeer{Peer: mock.NewPeer(nil)}
+	ps := NewPeerState(sender)
+	require.True(t, ps.setCompactBlockReceived(2, 0))
+	ps.setPendingCompactBlock(&pendingCompactBlock{msg: cb.msg, txs: make([][]byte, 2), missing: []uint32{1}, requestedAt: time.Now()})
+	conR.receiveBlockTxsResponse(sender, ps, resp)
+	assert.Nil(t, ps.pendingCompactBlock)
+	require.Len(t, sender.msgs, 1)
+	assert.Equal(t, &CompactBlockAckMessage{Height: 2, Round: 0, Rebuilt: false}, sender.msgs[0])
+}
+
+func TestPeerStateCompactBlockRebuilt(t *testing.T) {
+	_, parts, _ := makeCompactBlockTest(t, 2)
+	header := parts.Header()
+	ps := NewPeerState(nil)
+
+	// Not acked before being rebuilt.
+	require.True(t, ps.setCompactBlockReceived(2, 1))
+	_, ok := ps.takeCompactBlockRebuilt(2, header)
+	assert.False(t, ok)
+
+	ps.setCompactBlockRebuilt(2, 1, header)
+	_, ok = ps.takeCompactBlockRebuilt(2, types.PartSetHeader{Total: 1})
+	assert.False(t, ok, "other proposal block completed")
+	round, ok := ps.takeCompactBlockRebuilt(2, header)
+	assert.True(t, ok)
+	assert.EqualValues(t, 1, round)
+	_, ok = ps.takeCompactBlockRebuilt(2, header)
+	assert.False(t, ok, "acked once")
+
+	// A compact block of an older round isn't marked rebuilt.
+	require.True(t, ps.setCompactBlockReceived(2, 2))
+	ps.setCompactBlockRebuilt(2, 1, header)
+	_, ok = ps.takeCompactBlockRebuilt(2, header)
+	assert.False(t, ok)
+}
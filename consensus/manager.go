@@ -60,6 +60,9 @@ type ConsensusManager struct {
 	targetPending   int
 	mtx             sync.RWMutex
 	eventBus        *types.EventBus
+
+	txSource      TxSource
+	compactBlocks compactBlockCache
 }
 
 // NewConsensusManager returns a new ConsensusManager with the given
@@ -102,6 +105,37 @@ func (conR *ConsensusManager) Validators() []*types.Validator {
 	return conR.conS.Validators.CurrentValidators()
 }
 
+// GetRoundState returns a shallow copy of the consensus round state.
+func (conR *ConsensusManager) GetRoundState() *cstypes.RoundState {
+	return conR.conS.GetRoundState()
+}
+
+// PeerRoundState is the round state of a peer.
+type PeerRoundState struct {
+	NodeAddress string                  `json:"node_address"`
+	RoundState  *cstypes.PeerRoundState `json:"round_state"`
+}
+
+// PeerRoundStates returns the round states of the connected peers.
+func (conR *ConsensusManager) PeerRoundStates() []PeerRoundState {
+	if conR.Switch == nil {
+		return nil
+	}
+	peers := conR.Switch.Peers().List()
+	states := make([]PeerRoundState, 0, len(peers))
+	for _, peer := range peers {
+		ps, ok := peer.Get(types.PeerStateKey).(*PeerState)
+		if !ok { // peer does not have a state yet
+			continue
+		}
+		states = append(states, PeerRoundState{
+			NodeAddress: peer.SocketAddr().String(),
+			RoundState:  ps.GetRoundState(),
+		})
+	}
+	return states
+}
+
 func (conR *ConsensusManager) OnStart() error {
 	conR.Logger.Info("Consensus manager ", "waitSync", conR.WaitSync())
 	conR.subscribeToBroadcastEvents()
@@ -167,6 +201,7 @@ func (conR *ConsensusManager) GetChannels() []*p2p.ChannelDescriptor {
 	return []*p2p.ChannelDescriptor{
 		{
 			ID:                  StateChannel,
+			Name:                "consensus_state",
 			Priority:            8,
 			SendQueueCapacity:   64,
 			RecvMessageCapacity: maxMsgSize,
@@ -174,6 +209,7 @@ func (conR *ConsensusManager) GetChannels() []*p2p.ChannelDescriptor {
 		},
 		{
 			ID:                  DataChannel,
+			Name:                "consensus_data",
 			Priority:            12,
 			SendQueueCapacity:   64,
 			RecvBufferCapacity:  8388608, // 8 Mbs
@@ -181,6 +217,7 @@ func (conR *ConsensusManager) GetChannels() []*p2p.ChannelDescriptor {
 		},
 		{
 			ID:                  VoteChannel,
+			Name:                "consensus_vote",
 			Priority:            10,
 			SendQueueCapacity:   64,
 			RecvBufferCapacity:  524288, // 512 Kbs
@@ -188,6 +225,7 @@ func (conR *ConsensusManager) GetChannels() []*p2p.ChannelDescriptor {
 		},
 		{
 			ID:                  VoteSetBitsChannel,
+			Name:                "consensus_vote_bits",
 			Priority:            5,
 			SendQueueCapacity:   8,
 			RecvBufferCapacity:  4096,
@@ -199,6 +237,7 @@ func (conR *ConsensusManager) GetChannels() []*p2p.ChannelDescriptor {
 // InitPeer implements Reactor by creating a state for the peer.
 func (conR *ConsensusManager) InitPeer(peer p2p.Peer) p2p.Peer {
 	peerState := NewPeerState(peer).SetLogger(conR.Logger)
+	peerState.compactBlocks = conR.compactBlocksWith(peer)
 	peer.Set(types.PeerStateKey, peerState)
 	return peer
 }
@@ -248,13 +287,13 @@ func (conR *ConsensusManager) Receive(chID byte, src p2p.Peer, msgBytes []byte)
 	msg, err := decodeMsg(msgBytes)
 	if err != nil {
 		conR.Logger.Error("Error decoding message", "src", src, "chId", chID, "msg", msg, "err", err, "bytes", msgBytes)
-		conR.Switch.StopPeerForError(src, err)
+		conR.Switch.MarkPeerAsBad(src, err)
 		return
 	}
 
 	if err = msg.ValidateBasic(); err != nil {
 		conR.Logger.Error("peer sent us invalid msg", "peer", src, "msg", msg, "err", err)
-		conR.Switch.StopPeerForError(src, err)
+		conR.Switch.MarkPeerAsBad(src, err)
 		return
 	}
 
@@ -296,7 +335,7 @@ func (conR *ConsensusManager) Receive(chID byte, src p2p.Peer, msgBytes []byte)
 			// Peer claims to have a maj23 for some BlockID at H,R,S,
 			err := votes.SetPeerMaj23(msg.Round, msg.Type, ps.peer.ID(), msg.BlockID)
 			if err != nil {
-				conR.Switch.StopPeerForError(src, err)
+				conR.Switch.MarkPeerAsBad(src, err)
 				return
 			}
 			// Respond with a VoteSetBitsMessage showing which votes we have.
@@ -331,6 +370,14 @@ func (conR *ConsensusManager) Receive(chID byte, src p2p.Peer, msgBytes []byte)
 			ps.SetHasProposalBlockPart(msg.Height, msg.Round, int(msg.Part.Index))
 			//conR.Metrics.BlockParts.With("peer_id", string(src.ID())).Add(1)
 			conR.conS.peerMsgQueue <- msgInfo{msg, src.ID()}
+		case *CompactBlockMessage:
+			conR.receiveCompactBlock(src, ps, msg)
+		case *BlockTxsRequestMessage:
+			conR.receiveBlockTxsRequest(src, ps, msg)
+		case *BlockTxsResponseMessage:
+			conR.receiveBlockTxsResponse(src, ps, msg)
+		case *CompactBlockAckMessage:
+			ps.ApplyCompactBlockAckMessage(msg)
 		default:
 			conR.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
 		}
@@ -400,6 +447,11 @@ func (conR *ConsensusManager) subscribeToBroadcastEvents() {
 		func(data kevents.EventData) {
 			conR.broadcastNewValidBlockMessage(data.(*cstypes.RoundState))
 		})
+
+	conR.conS.evsw.AddListenerForEvent(subscriber, types.EventCompleteProposal,
+		func(data kevents.EventData) {
+			conR.ackRebuiltCompactBlocks(data.(*cstypes.RoundState))
+		})
 }
 
 func (conR *ConsensusManager) unsubscribeFromBroadcastEvents() {
@@ -474,7 +526,10 @@ OuterLoop:
 		prs := ps.GetRoundState()
 
 		// Send proposal Block parts?
-		if rs.ProposalBlockParts.HasHeader(prs.ProposalBlockPartsHeader) {
+		// Peers supporting compact blocks get them instead, unless they fail to
+		// rebuild the block.
+		if rs.ProposalBlockParts.HasHeader(prs.ProposalBlockPartsHeader) &&
+			!conR.gossipCompactBlock(logger, peer, ps, rs, prs) {
 			if index, ok := rs.ProposalBlockParts.BitArray().Sub(prs.ProposalBlockParts.Copy()).PickRandom(); ok {
 				part := rs.ProposalBlockParts.GetPart(index)
 				msg := &BlockPartMessage{
@@ -992,6 +1047,11 @@ type PeerState struct {
 
 	mtx sync.Mutex             // NOTE: Modify below using setters, never directly.
 	PRS cstypes.PeerRoundState `json:"round_state"` // Exposed.
+
+	compactBlocks       bool // whether compact blocks are exchanged with the peer
+	compactBlock        sentCompactBlock
+	compactBlockRecv    receivedCompactBlock
+	pendingCompactBlock *pendingCompactBlock
 }
 
 // NewPeerState returns a new PeerState for the given Peer
@@ -1273,6 +1333,7 @@ func (ps *PeerState) ApplyNewRoundStepMessage(msg *NewRoundStepMessage) {
 	ps.PRS.Step = msg.Step
 	ps.PRS.StartTime = uint64(startTime)
 	if (psHeight != msg.Height) || (psRound != msg.Round) {
+		ps.dropStalePendingCompactBlock(msg.Height, msg.Round)
 		ps.PRS.Proposal = false
 		ps.PRS.ProposalBlockPartsHeader = types.PartSetHeader{}
 		ps.PRS.ProposalBlockParts = nil
@@ -0,0 +1,137 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package consensus
+
+import (
+	"time"
+
+	cstypes "github.com/kardiachain/go-kardia/consensus/types"
+	"github.com/kardiachain/go-kardia/lib/metrics"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+var (
+	heightGauge = metrics.NewRegisteredGauge("consensus/height", nil)
+	roundsGauge = metrics.NewRegisteredGauge("consensus/rounds", nil) // round the last block was committed in
+
+	// Time spent in each step of a round, the wait steps included.
+	proposeStepTimer   = metrics.NewRegisteredTimer("consensus/step/propose", nil)
+	prevoteStepTimer   = metrics.NewRegisteredTimer("consensus/step/prevote", nil)
+	precommitStepTimer = metrics.NewRegisteredTimer("consensus/step/precommit", nil)
+	commitStepTimer    = metrics.NewRegisteredTimer("consensus/step/commit", nil)
+
+	blockIntervalTimer = metrics.NewRegisteredTimer("consensus/block/interval", nil) // time between the last two blocks
+
+	// Validators of the last commit, and the ones whose precommit is missing
+	// from it.
+	validatorsGauge             = metrics.NewRegisteredGauge("consensus/validators/total", nil)
+	validatorsPowerGauge        = metrics.NewRegisteredGauge("consensus/validators/power", nil)
+	missingValidatorsGauge      = metrics.NewRegisteredGauge("consensus/validators/missing", nil)
+	missingValidatorsPowerGauge = metrics.NewRegisteredGauge("consensus/validators/missing_power", nil)
+
+	// Validators punished by the evidence of the last block.
+	byzantineValidatorsGauge      = metrics.NewRegisteredGauge("consensus/validators/byzantine", nil)
+	byzantineValidatorsPowerGauge = metrics.NewRegisteredGauge("consensus/validators/byzantine_power", nil)
+
+	proposalLatencyTimer = metrics.NewRegisteredTimer("consensus/proposal/latency", nil) // from signing to receipt
+	proposerGauge        = metrics.NewRegisteredGauge("consensus/proposer", nil)         // 1 if the node proposes the round
+
+	blockPartsMeter          = metrics.NewRegisteredMeter("consensus/block_parts/received", nil)
+	duplicateBlockPartsMeter = metrics.NewRegisteredMeter("consensus/block_parts/duplicate", nil)
+
+	compactBlocksSentMeter      = metrics.NewRegisteredMeter("consensus/compact_blocks/sent", nil)
+	compactBlocksRebuiltMeter   = metrics.NewRegisteredMeter("consensus/compact_blocks/rebuilt", nil)
+	compactBlockFallbackMeter   = metrics.NewRegisteredMeter("consensus/compact_blocks/fallback", nil) // peers sent the parts instead
+	compactBlockMissingTxsMeter = metrics.NewRegisteredMeter("consensus/compact_blocks/missing_txs", nil)
+)
+
+// stepTimer returns the timer of the time spent in step, nil for the steps
+// between rounds.
+func stepTimer(step cstypes.RoundStepType) metrics.Timer {
+	switch step {
+	case cstypes.RoundStepPropose:
+		return proposeStepTimer
+	case cstypes.RoundStepPrevote, cstypes.RoundStepPrevoteWait:
+		return prevoteStepTimer
+	case cstypes.RoundStepPrecommit, cstypes.RoundStepPrecommitWait:
+		return precommitStepTimer
+	case cstypes.RoundStepCommit:
+		return commitStepTimer
+	default:
+		return nil
+	}
+}
+
+// recordMetrics records the metrics of the block committed at height, with
+// the state before the block is applied.
+func (cs *ConsensusState) recordMetrics(height uint64, block *types.Block) {
+	roundsGauge.Update(int64(cs.CommitRound))
+
+	// The last commit of the block is signed by the last validators.
+	if cs.LastValidators != nil && block.LastCommit() != nil {
+		var missing, missingPower int64
+		for i, sig := range block.LastCommit().Signatures {
+			if sig.Absent() && i < cs.LastValidators.Size() {
+				missing++
+				missingPower += cs.LastValidators.Validators[i].VotingPower
+			}
+		}
+		validatorsGauge.Update(int64(cs.LastValidators.Size()))
+		validatorsPowerGauge.Update(cs.LastValidators.TotalVotingPower())
+		missingValidatorsGauge.Update(missing)
+		missingValidatorsPowerGauge.Update(missingPower)
+	}
+
+	var byzantine, byzantinePower int64
+	for _, ev := range block.Evidence().Evidence {
+		for _, infraction := range ev.VM() {
+			byzantine++
+			byzantinePower += infraction.VotingPower.Int64()
+		}
+	}
+	byzantineValidatorsGauge.Update(byzantine)
+	byzantineValidatorsPowerGauge.Update(byzantinePower)
+
+	if height > cs.state.InitialHeight && !cs.state.LastBlockTime.IsZero() {
+		blockIntervalTimer.Update(block.Header().Time.Sub(cs.state.LastBlockTime))
+	}
+}
+
+// recordStepMetrics records the time spent in the step being left for step
+// in round. The wait steps count with the step they wait in.
+func (cs *ConsensusState) recordStepMetrics(round uint32, step cstypes.RoundStepType) {
+	timer := stepTimer(cs.Step)
+	if round == cs.Round && timer == stepTimer(step) {
+		return
+	}
+	now := time.Now()
+	if timer != nil && !cs.stepStartTime.IsZero() {
+		timer.Update(now.Sub(cs.stepStartTime))
+	}
+	cs.stepStartTime = now
+}
+
+// recordProposalMetrics records the latency of the proposal received, unless
+// it is replayed from the WAL, long after it was signed.
+func (cs *ConsensusState) recordProposalMetrics(proposal *types.Proposal) {
+	if cs.replayMode {
+		return
+	}
+	proposalLatencyTimer.UpdateSince(proposal.Timestamp)
+}
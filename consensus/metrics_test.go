@@ -0,0 +1,105 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package consensus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	cstypes "github.com/kardiachain/go-kardia/consensus/types"
+	"github.com/kardiachain/go-kardia/lib/metrics"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// enableTimers replaces the step and proposal timers, no-ops unless metrics
+// are enabled at startup, with fresh ones for the duration of the test.
+func enableTimers(t *testing.T) {
+	enabled := metrics.Enabled
+	timers := []*metrics.Timer{&proposeStepTimer, &prevoteStepTimer, &precommitStepTimer, &commitStepTimer, &proposalLatencyTimer}
+	saved := make([]metrics.Timer, len(timers))
+	metrics.Enabled = true
+	for i, timer := range timers {
+		saved[i] = *timer
+		*timer = metrics.NewTimer()
+	}
+	t.Cleanup(func() {
+		for i, timer := range timers {
+			*timer = saved[i]
+		}
+		metrics.Enabled = enabled
+	})
+}
+
+func TestStepTimer(t *testing.T) {
+	enableTimers(t)
+	assert.Nil(t, stepTimer(cstypes.RoundStepNewHeight))
+	assert.Nil(t, stepTimer(cstypes.RoundStepNewRound))
+	assert.Equal(t, proposeStepTimer, stepTimer(cstypes.RoundStepPropose))
+	assert.Equal(t, prevoteStepTimer, stepTimer(cstypes.RoundStepPrevote))
+	assert.Equal(t, prevoteStepTimer, stepTimer(cstypes.RoundStepPrevoteWait))
+	assert.Equal(t, precommitStepTimer, stepTimer(cstypes.RoundStepPrecommit))
+	assert.Equal(t, precommitStepTimer, stepTimer(cstypes.RoundStepPrecommitWait))
+	assert.Equal(t, commitStepTimer, stepTimer(cstypes.RoundStepCommit))
+}
+
+func TestRecordStepMetrics(t *testing.T) {
+	enableTimers(t)
+	cs := &ConsensusState{}
+
+	// The steps between rounds aren't timed.
+	cs.updateRoundStep(0, cstypes.RoundStepNewHeight)
+	cs.updateRoundStep(0, cstypes.RoundStepPropose)
+	assert.Zero(t, proposeStepTimer.Count())
+
+	cs.stepStartTime = time.Now().Add(-time.Second)
+	cs.updateRoundStep(0, cstypes.RoundStepPrevote)
+	assert.EqualValues(t, 1, proposeStepTimer.Count())
+	assert.GreaterOrEqual(t, proposeStepTimer.Min(), int64(time.Second))
+
+	// A wait step counts with the step it waits in, until the next round.
+	cs.stepStartTime = time.Now().Add(-time.Second)
+	cs.updateRoundStep(0, cstypes.RoundStepPrevoteWait)
+	assert.Zero(t, prevoteStepTimer.Count())
+	cs.stepStartTime = cs.stepStartTime.Add(-time.Second)
+	cs.updateRoundStep(1, cstypes.RoundStepPrevoteWait)
+	assert.EqualValues(t, 1, prevoteStepTimer.Count())
+	assert.GreaterOrEqual(t, prevoteStepTimer.Min(), int64(2*time.Second))
+
+	cs.updateRoundStep(1, cstypes.RoundStepCommit)
+	cs.updateRoundStep(1, cstypes.RoundStepNewHeight)
+	assert.EqualValues(t, 1, commitStepTimer.Count())
+	assert.Zero(t, precommitStepTimer.Count())
+}
+
+func TestRecordProposalMetrics(t *testing.T) {
+	enableTimers(t)
+	cs := &ConsensusState{}
+	proposal := &types.Proposal{Timestamp: time.Now().Add(-time.Second)}
+
+	cs.replayMode = true
+	cs.recordProposalMetrics(proposal)
+	assert.Zero(t, proposalLatencyTimer.Count(), "replayed proposal")
+
+	cs.replayMode = false
+	cs.recordProposalMetrics(proposal)
+	assert.EqualValues(t, 1, proposalLatencyTimer.Count())
+	assert.GreaterOrEqual(t, proposalLatencyTimer.Min(), int64(time.Second))
+}
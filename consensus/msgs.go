@@ -135,6 +135,51 @@ func MsgToProto(msg Message) (*kcons.Message, error) {
 		pb = kcons.Message{
 			Sum: vsb,
 		}
+	case *CompactBlockMessage:
+		if msg.Block == nil {
+			return nil, errors.New("compact block msg to proto error: nil block")
+		}
+		pb = kcons.Message{
+			Sum: &kcons.Message_CompactBlock{
+				CompactBlock: &kcons.CompactBlock{
+					Height:             msg.Height,
+					Round:              msg.Round,
+					BlockPartSetHeader: msg.BlockPartsHeader.ToProto(),
+					Block:              *msg.Block,
+					ShortTxIDs:         msg.ShortTxIDs,
+				},
+			},
+		}
+	case *BlockTxsRequestMessage:
+		pb = kcons.Message{
+			Sum: &kcons.Message_BlockTxsRequest{
+				BlockTxsRequest: &kcons.BlockTxsRequest{
+					Height:  msg.Height,
+					Round:   msg.Round,
+					Indexes: msg.Indexes,
+				},
+			},
+		}
+	case *BlockTxsResponseMessage:
+		pb = kcons.Message{
+			Sum: &kcons.Message_BlockTxsResponse{
+				BlockTxsResponse: &kcons.BlockTxsResponse{
+					Height: msg.Height,
+					Round:  msg.Round,
+					Txs:    msg.Txs,
+				},
+			},
+		}
+	case *CompactBlockAckMessage:
+		pb = kcons.Message{
+			Sum: &kcons.Message_CompactBlockAck{
+				CompactBlockAck: &kcons.CompactBlockAck{
+					Height:  msg.Height,
+					Round:   msg.Round,
+					Rebuilt: msg.Rebuilt,
+				},
+			},
+		}
 
 	default:
 		return nil, fmt.Errorf("consensus: message not recognized: %T", msg)
@@ -245,6 +290,36 @@ func MsgFromProto(msg *kcons.Message) (Message, error) {
 			BlockID: *bi,
 			Votes:   bits,
 		}
+	case *kcons.Message_CompactBlock:
+		psh, err := types.PartSetHeaderFromProto(&msg.CompactBlock.BlockPartSetHeader)
+		if err != nil {
+			return nil, fmt.Errorf("compactBlock msg to proto error: %w", err)
+		}
+		pb = &CompactBlockMessage{
+			Height:           msg.CompactBlock.Height,
+			Round:            msg.CompactBlock.Round,
+			BlockPartsHeader: *psh,
+			Block:            &msg.CompactBlock.Block,
+			ShortTxIDs:       msg.CompactBlock.ShortTxIDs,
+		}
+	case *kcons.Message_BlockTxsRequest:
+		pb = &BlockTxsRequestMessage{
+			Height:  msg.BlockTxsRequest.Height,
+			Round:   msg.BlockTxsRequest.Round,
+			Indexes: msg.BlockTxsRequest.Indexes,
+		}
+	case *kcons.Message_BlockTxsResponse:
+		pb = &BlockTxsResponseMessage{
+			Height: msg.BlockTxsResponse.Height,
+			Round:  msg.BlockTxsResponse.Round,
+			Txs:    msg.BlockTxsResponse.Txs,
+		}
+	case *kcons.Message_CompactBlockAck:
+		pb = &CompactBlockAckMessage{
+			Height:  msg.CompactBlockAck.Height,
+			Round:   msg.CompactBlockAck.Round,
+			Rebuilt: msg.CompactBlockAck.Rebuilt,
+		}
 	default:
 		return nil, fmt.Errorf("consensus: message not recognized: %T", msg)
 	}
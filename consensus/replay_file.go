@@ -0,0 +1,98 @@
+package consensus
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	cstypes "github.com/kardiachain/go-kardia/consensus/types"
+)
+
+// WALPlayer plays the messages a WAL recorded for a height into a
+// ConsensusState, one at a time, to see how the height went. The height is
+// the one after the state of the ConsensusState, which must not be started.
+// The player is a sandbox: the ConsensusState doesn't sign, doesn't write to
+// its WAL and stops short of committing the block.
+type WALPlayer struct {
+	cs     *ConsensusState
+	wal    *BaseWAL
+	gr     io.ReadCloser
+	dec    *WALDecoder
+	height uint64
+}
+
+// NewWALPlayer returns a player of the messages of walFile for the height of
+// cs.
+func NewWALPlayer(cs *ConsensusState, walFile string) (*WALPlayer, error) {
+	if _, err := os.Stat(walFile); err != nil {
+		return nil, err
+	}
+	wal, err := NewWAL(walFile)
+	if err != nil {
+		return nil, err
+	}
+	wal.SetLogger(cs.Logger.New("wal", walFile))
+
+	height := cs.Height
+	endHeight := height - 1
+	if height == cs.state.InitialHeight {
+		endHeight = 0
+	}
+	gr, found, err := wal.SearchForEndHeight(int64(endHeight), &WALSearchOptions{IgnoreDataCorruptionErrors: true})
+	if err != nil {
+		wal.Group().Close()
+		return nil, err
+	}
+	if !found {
+		wal.Group().Close()
+		return nil, fmt.Errorf("cannot replay height %d. WAL does not contain #ENDHEIGHT for %d", height, endHeight)
+	}
+
+	cs.privValidator = nil
+	cs.replayMode = true
+	cs.dryRun = true
+	// Timeouts are played from the WAL, the scheduled ones are only drained.
+	if err := cs.timeoutTicker.Start(); err != nil {
+		gr.Close()
+		wal.Group().Close()
+		return nil, err
+	}
+	return &WALPlayer{
+		cs:     cs,
+		wal:    wal,
+		gr:     gr,
+		dec:    NewWALDecoder(gr),
+		height: height,
+	}, nil
+}
+
+// Height returns the height played.
+func (p *WALPlayer) Height() uint64 {
+	return p.height
+}
+
+// Next plays the next message of the height and returns it. It returns
+// io.EOF once all the messages of the height have been played.
+func (p *WALPlayer) Next() (*TimedWALMessage, error) {
+	msg, err := p.dec.Decode()
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := msg.Msg.(EndHeightMessage); ok && m.Height >= int64(p.height) {
+		return nil, io.EOF
+	}
+	return msg, p.cs.readReplayMessage(msg, nil)
+}
+
+// RoundState returns the round state after the messages played so far.
+func (p *WALPlayer) RoundState() *cstypes.RoundState {
+	return p.cs.GetRoundState()
+}
+
+// Close releases the WAL.
+func (p *WALPlayer) Close() error {
+	p.cs.timeoutTicker.Stop()
+	err := p.gr.Close()
+	p.wal.Group().Close()
+	return err
+}
@@ -95,7 +95,8 @@ type ConsensusState struct {
 	service.BaseService
 
 	config          *cfg.ConsensusConfig
-	privValidator   types.PrivValidator // for signing votes
+	timeouts        *cfg.ConsensusConfig // config with the timeouts of the consensus params
+	privValidator   types.PrivValidator  // for signing votes
 	blockOperations BaseBlockOperations
 	blockExec       *cstate.BlockExecutor
 	evpool          evidencePool // TODO(namdoh): Add mem pool.
@@ -118,10 +119,14 @@ type ConsensusState struct {
 	// For tests where we want to limit the number of transitions the state makes
 	nSteps int
 
+	// when the current step was entered, for the step metrics
+	stepStartTime time.Time
+
 	// a Write-Ahead Log ensures we can recover from any kind of crash
 	// and helps us avoid signing conflicting votes
 	wal          WAL
 	replayMode   bool // so we don't log signing errors during replay
+	dryRun       bool // stop short of committing blocks, see WALPlayer
 	doWALCatchup bool // determines if we even try to do the catchup
 
 	// Synchronous pubsub between consensus state and manager.
@@ -143,6 +148,7 @@ func NewConsensusState(
 ) *ConsensusState {
 	cs := &ConsensusState{
 		config:           config,
+		timeouts:         config,
 		blockExec:        blockExec,
 		blockOperations:  blockOperations,
 		peerMsgQueue:     make(chan msgInfo, msgQueueSize),
@@ -384,6 +390,8 @@ func (cs *ConsensusState) updateToState(state cstate.LatestBlockState) {
 		height = state.InitialHeight
 	}
 
+	cs.timeouts = cs.config.WithTimeoutParams(state.ConsensusParams.Timeout)
+
 	// RoundState fields
 	cs.updateHeight(height)
 	cs.updateRoundStep(1, cstypes.RoundStepNewHeight)
@@ -394,9 +402,9 @@ func (cs *ConsensusState) updateToState(state cstate.LatestBlockState) {
 		// And alternative solution that relies on clocks:
 		//  cs.StartTime = state.LastBlockTime.Add(timeoutCommit)
 		//cs.Logger.Trace("cs.CommitTime is 0")
-		cs.StartTime = cs.config.Commit(ktime.Now())
+		cs.StartTime = cs.timeouts.Commit(ktime.Now())
 	} else {
-		cs.StartTime = cs.config.Commit(cs.CommitTime)
+		cs.StartTime = cs.timeouts.Commit(cs.CommitTime)
 	}
 	cs.Validators = validators
 	cs.Proposal = nil
@@ -507,6 +515,7 @@ func (cs *ConsensusState) setProposal(proposal *types.Proposal) error {
 		return ErrInvalidProposalPOLRound
 	}
 	cs.Proposal = proposal
+	cs.recordProposalMetrics(proposal)
 	// We don't update cs.ProposalBlockParts if it is already set.
 	// This happens if we're already in cstypes.RoundStepCommit or if there is a valid block in the current round.
 	// TODO: We can check if Proposal is for a different block as this is a sign of misbehavior!
@@ -567,7 +576,7 @@ func (cs *ConsensusState) tryAddVote(vote *types.Vote, peerID p2p.ID) (bool, err
 		// But if it's a conflicting sig, add it to the cs.evpool.
 		// If it's otherwise invalid, punish peer.
 		if voteErr, ok := err.(*types.ErrVoteConflictingVotes); ok {
-			if vote.ValidatorAddress.Equal(cs.privValidator.GetAddress()) {
+			if cs.privValidator != nil && vote.ValidatorAddress.Equal(cs.privValidator.GetAddress()) {
 				cs.Logger.Error("Found conflicting vote from ourselves. Did you unsafe_reset a validator?",
 					"height", vote.Height,
 					"round", vote.Round,
@@ -833,6 +842,7 @@ func (cs *ConsensusState) signAddVote(signedMsgType kproto.SignedMsgType, hash c
 
 // Updates ConsensusState to the current round and round step.
 func (cs *ConsensusState) updateRoundStep(round uint32, step cstypes.RoundStepType) {
+	cs.recordStepMetrics(round, step)
 	cs.Round = round
 	cs.Step = step
 }
@@ -902,7 +912,7 @@ func (cs *ConsensusState) SetProposalAndBlock(
 }
 
 func (cs *ConsensusState) updateHeight(height uint64) {
-	//namdoh@ cs.metrics.Height.Set(float64(height))
+	heightGauge.Update(int64(height))
 	cs.Height = height
 }
 
@@ -930,6 +940,10 @@ func (cs *ConsensusState) addProposalBlockPart(msg *BlockPartMessage, peerID p2p
 	if err != nil {
 		return added, err
 	}
+	blockPartsMeter.Mark(1)
+	if !added {
+		duplicateBlockPartsMeter.Mark(1)
+	}
 
 	if added && cs.ProposalBlockParts.IsComplete() {
 		bz, err := ioutil.ReadAll(cs.ProposalBlockParts.GetReader())
@@ -953,6 +967,7 @@ func (cs *ConsensusState) addProposalBlockPart(msg *BlockPartMessage, peerID p2p
 		if err := cs.eventBus.PublishEventCompleteProposal(cs.CompleteProposalEvent()); err != nil {
 			cs.Logger.Error("Error publishing event complete proposal", "err", err)
 		}
+		cs.evsw.FireEvent(types.EventCompleteProposal, &cs.RoundState)
 
 		// Update Valid* if we can.
 		prevotes := cs.Votes.Prevotes(cs.Round)
@@ -1094,7 +1109,8 @@ func (cs *ConsensusState) enterPropose(height uint64, round uint32) {
 	}()
 
 	// If we don't get the proposal quick enough, enterPrevote
-	cs.scheduleTimeout(cs.config.Propose(round), height, round, cstypes.RoundStepPropose)
+	cs.scheduleTimeout(cs.timeouts.Propose(round), height, round, cstypes.RoundStepPropose)
+	proposerGauge.Update(0)
 
 	// TODO(namdoh): For now this any node is a validator. Remove it once we
 	// restrict who can be validator.
@@ -1112,6 +1128,7 @@ func (cs *ConsensusState) enterPropose(height uint64, round uint32) {
 	logger.Debug("This node is a validator")
 	if cs.isProposer() {
 		logger.Trace("Our turn to propose")
+		proposerGauge.Update(1)
 		//namdoh@ logger.Info("enterPropose: Our turn to propose", "proposer", cs.Validators.GetProposer().Address, "privValidator", cs.privValidator)
 		cs.decideProposal(height, round)
 	} else {
@@ -1198,7 +1215,7 @@ func (cs *ConsensusState) enterPrevoteWait(height uint64, round uint32) {
 	}()
 
 	// Wait for some more prevotes; enterPrecommit
-	cs.scheduleTimeout(cs.config.Prevote(round), height, round, cstypes.RoundStepPrevoteWait)
+	cs.scheduleTimeout(cs.timeouts.Prevote(round), height, round, cstypes.RoundStepPrevoteWait)
 }
 
 // Enter: `timeoutPrevote` after any +2/3 prevotes.
@@ -1333,7 +1350,7 @@ func (cs *ConsensusState) enterPrecommitWait(height uint64, round uint32) {
 	}()
 
 	// Wait for some more precommits; enterNewRound
-	cs.scheduleTimeout(cs.config.Precommit(round), height, round, cstypes.RoundStepPrecommitWait)
+	cs.scheduleTimeout(cs.timeouts.Precommit(round), height, round, cstypes.RoundStepPrecommitWait)
 }
 
 // Enter: +2/3 precommits for block
@@ -1431,6 +1448,10 @@ func (cs *ConsensusState) finalizeCommit(height uint64) {
 		cmn.PanicConsensus(cmn.Fmt("+2/3 committed an invalid block: %v", err))
 		panic("Block validation failed")
 	}
+	if cs.dryRun {
+		cs.Logger.Info("Dry run, not committing block", "height", block.Height(), "hash", block.Hash())
+		return
+	}
 
 	cs.Logger.Info("Finalizing commit of block", "tx number", block.NumTxs(),
 		"height", block.Height(), "hash", block.Hash().String())
@@ -1471,6 +1492,8 @@ func (cs *ConsensusState) finalizeCommit(height uint64) {
 			endMsg, err))
 	}
 
+	cs.trimWAL(height)
+
 	fail.Fail() // XXX
 
 	// Create a copy of the state for staging and an event cache for txs.
@@ -1493,6 +1516,8 @@ func (cs *ConsensusState) finalizeCommit(height uint64) {
 		return
 	}
 
+	cs.recordMetrics(height, block)
+
 	fail.Fail() // XXX
 	// NewHeightStep!
 	cs.updateToState(stateCopy)
@@ -1509,6 +1534,19 @@ func (cs *ConsensusState) finalizeCommit(height uint64) {
 	// - cs.StartTime is set to when we will start round0.
 }
 
+// trimWAL removes the WAL files that only hold heights out of the retention
+// window ending at height, the one just committed.
+func (cs *ConsensusState) trimWAL(height uint64) {
+	retain := cs.config.WalRetainHeights
+	wal, ok := cs.wal.(*BaseWAL)
+	if retain == 0 || height <= retain || !ok {
+		return
+	}
+	if err := wal.TrimBelow(int64(height - retain)); err != nil {
+		cs.Logger.Error("Failed to trim consensus WAL", "err", err)
+	}
+}
+
 // Creates the next block to propose and returns it. Returns nil block upon
 // error.
 func (cs *ConsensusState) createProposalBlock() (*types.Block, *types.PartSet) {
@@ -19,7 +19,9 @@
 package types
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
 
 	cmn "github.com/kardiachain/go-kardia/lib/common"
@@ -194,3 +196,38 @@ func (hvs *HeightVoteSet) Precommits(round uint32) *types.VoteSet {
 	defer hvs.mtx.Unlock()
 	return hvs.getVoteSet(round, kproto.PrecommitType)
 }
+
+// roundVoteSetJSON is the JSON form of the votes of a round.
+type roundVoteSetJSON struct {
+	Round              uint32         `json:"round"`
+	Prevotes           *types.VoteSet `json:"prevotes"`
+	PrevotesBitArray   string         `json:"prevotes_bit_array"`
+	Precommits         *types.VoteSet `json:"precommits"`
+	PrecommitsBitArray string         `json:"precommits_bit_array"`
+}
+
+// MarshalJSON marshals the votes of every tracked round, including the
+// catchup rounds of the peers, in order.
+func (hvs *HeightVoteSet) MarshalJSON() ([]byte, error) {
+	hvs.mtx.Lock()
+	defer hvs.mtx.Unlock()
+
+	rounds := make([]uint32, 0, len(hvs.roundVoteSets))
+	for round := range hvs.roundVoteSets {
+		rounds = append(rounds, round)
+	}
+	sort.Slice(rounds, func(i, j int) bool { return rounds[i] < rounds[j] })
+
+	votes := make([]roundVoteSetJSON, len(rounds))
+	for i, round := range rounds {
+		rvs := hvs.roundVoteSets[round]
+		votes[i] = roundVoteSetJSON{
+			Round:              round,
+			Prevotes:           rvs.Prevotes,
+			PrevotesBitArray:   rvs.Prevotes.BitArrayString(),
+			Precommits:         rvs.Precommits,
+			PrecommitsBitArray: rvs.Precommits.BitArrayString(),
+		}
+	}
+	return json.Marshal(votes)
+}
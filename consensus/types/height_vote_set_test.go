@@ -19,7 +19,9 @@
 package types
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -83,3 +85,58 @@ func makeVoteHR(t *testing.T, height uint64, valIndex uint32, round uint32, priv
 	vote.Signature = kv.Signature
 	return vote
 }
+
+func TestHeightVoteSetMarshalJSON(t *testing.T) {
+	valSet, privSet := types.RandValidatorSet(4, 1)
+	hvs := NewHeightVoteSet(log.New(), "kaicoin", 1, valSet)
+	hvs.SetRound(2)
+	if _, err := hvs.AddVote(makeVoteHR(t, 1, 2, 1, privSet), "peer1"); err != nil {
+		t.Fatal(err)
+	}
+
+	bz, err := json.Marshal(hvs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rounds []struct {
+		Round              uint32 `json:"round"`
+		PrecommitsBitArray string `json:"precommits_bit_array"`
+		Precommits         struct {
+			Votes []string `json:"votes"`
+		} `json:"precommits"`
+	}
+	if err := json.Unmarshal(bz, &rounds); err != nil {
+		t.Fatal(err)
+	}
+	if len(rounds) != 3 || rounds[1].Round != 1 || rounds[2].Round != 2 {
+		t.Fatalf("unexpected rounds %s", bz)
+	}
+	if !strings.HasPrefix(rounds[1].PrecommitsBitArray, "BA{4:__x_}") {
+		t.Errorf("unexpected precommits bit array %q", rounds[1].PrecommitsBitArray)
+	}
+	if votes := rounds[1].Precommits.Votes; len(votes) != 4 || votes[0] != "nil-Vote" || votes[2] == "nil-Vote" {
+		t.Errorf("unexpected precommits %v", votes)
+	}
+}
+
+func TestRoundStateSummaries(t *testing.T) {
+	valSet, _ := types.RandValidatorSet(4, 1)
+	rs := &RoundState{
+		Height:     1,
+		Round:      1,
+		Step:       RoundStepPropose,
+		Validators: valSet,
+		Votes:      NewHeightVoteSet(log.New(), "kaicoin", 1, valSet),
+	}
+
+	simple := rs.Simple()
+	if simple.Step != "RoundStepPropose" || !simple.Proposer.Address.Equal(valSet.GetProposer().Address) {
+		t.Errorf("unexpected summary %+v", simple)
+	}
+	if !strings.HasPrefix(simple.Prevotes, "BA{4:____}") {
+		t.Errorf("unexpected prevotes %q", simple.Prevotes)
+	}
+	if _, err := json.Marshal(rs.Dump()); err != nil {
+		t.Fatal(err)
+	}
+}
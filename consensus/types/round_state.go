@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/kardiachain/go-kardia/lib/common"
 	"github.com/kardiachain/go-kardia/types"
 )
 
@@ -149,12 +150,126 @@ func (rs *RoundState) String() string {
 		rs.LastValidators)
 }
 
+// RoundStateSimple is a compact summary of the RoundState.
+type RoundStateSimple struct {
+	Height            uint64              `json:"height"`
+	Round             uint32              `json:"round"`
+	Step              string              `json:"step"`
+	StartTime         time.Time           `json:"start_time"`
+	Proposer          types.ValidatorInfo `json:"proposer"`
+	ProposalBlockHash common.Hash         `json:"proposal_block_hash"`
+	LockedRound       uint32              `json:"locked_round"`
+	LockedBlockHash   common.Hash         `json:"locked_block_hash"`
+	ValidRound        uint32              `json:"valid_round"`
+	ValidBlockHash    common.Hash         `json:"valid_block_hash"`
+	Prevotes          string              `json:"prevotes"`   // bit array of the current round
+	Precommits        string              `json:"precommits"` // bit array of the current round
+	LastCommit        string              `json:"last_commit"`
+}
+
+// Simple returns the summary of the RoundState.
+func (rs *RoundState) Simple() *RoundStateSimple {
+	simple := &RoundStateSimple{
+		Height:            rs.Height,
+		Round:             rs.Round,
+		Step:              rs.Step.String(),
+		StartTime:         rs.StartTime,
+		ProposalBlockHash: blockHash(rs.ProposalBlock),
+		LockedRound:       rs.LockedRound,
+		LockedBlockHash:   blockHash(rs.LockedBlock),
+		ValidRound:        rs.ValidRound,
+		ValidBlockHash:    blockHash(rs.ValidBlock),
+		LastCommit:        rs.LastCommit.BitArrayString(),
+	}
+	if rs.Validators != nil && rs.Validators.GetProposer() != nil {
+		addr := rs.Validators.GetProposer().Address
+		idx, _ := rs.Validators.GetByAddress(addr)
+		simple.Proposer = types.ValidatorInfo{Address: addr, Index: int32(idx)}
+	}
+	if rs.Votes != nil {
+		simple.Prevotes = rs.Votes.Prevotes(rs.Round).BitArrayString()
+		simple.Precommits = rs.Votes.Precommits(rs.Round).BitArrayString()
+	}
+	return simple
+}
+
+func blockHash(block *types.Block) common.Hash {
+	if block == nil {
+		return common.Hash{}
+	}
+	return block.Hash()
+}
+
+// BlockSummary stands for a block in the dump of a RoundState.
+type BlockSummary struct {
+	Hash   common.Hash   `json:"hash"`
+	Header *types.Header `json:"header"`
+}
+
+func newBlockSummary(block *types.Block) *BlockSummary {
+	if block == nil {
+		return nil
+	}
+	return &BlockSummary{Hash: block.Hash(), Header: block.Header()}
+}
+
+// RoundStateDump is the RoundState with its blocks summed up by their header
+// and its vote and part sets by their bit arrays.
+type RoundStateDump struct {
+	Height                    uint64              `json:"height"`
+	Round                     uint32              `json:"round"`
+	Step                      string              `json:"step"`
+	StartTime                 time.Time           `json:"start_time"`
+	CommitTime                time.Time           `json:"commit_time"`
+	Validators                *types.ValidatorSet `json:"validators"`
+	Proposal                  *types.Proposal     `json:"proposal"`
+	ProposalBlock             *BlockSummary       `json:"proposal_block"`
+	ProposalBlockParts        *types.PartSet      `json:"proposal_block_parts"`
+	LockedRound               uint32              `json:"locked_round"`
+	LockedBlock               *BlockSummary       `json:"locked_block"`
+	LockedBlockParts          *types.PartSet      `json:"locked_block_parts"`
+	ValidRound                uint32              `json:"valid_round"`
+	ValidBlock                *BlockSummary       `json:"valid_block"`
+	ValidBlockParts           *types.PartSet      `json:"valid_block_parts"`
+	Votes                     *HeightVoteSet      `json:"votes"`
+	CommitRound               uint32              `json:"commit_round"`
+	LastCommit                *types.VoteSet      `json:"last_commit"`
+	LastValidators            *types.ValidatorSet `json:"last_validators"`
+	TriggeredTimeoutPrecommit bool                `json:"triggered_timeout_precommit"`
+}
+
+// Dump returns the RoundState in a form that can be marshalled to JSON.
+func (rs *RoundState) Dump() *RoundStateDump {
+	return &RoundStateDump{
+		Height:                    rs.Height,
+		Round:                     rs.Round,
+		Step:                      rs.Step.String(),
+		StartTime:                 rs.StartTime,
+		CommitTime:                rs.CommitTime,
+		Validators:                rs.Validators,
+		Proposal:                  rs.Proposal,
+		ProposalBlock:             newBlockSummary(rs.ProposalBlock),
+		ProposalBlockParts:        rs.ProposalBlockParts,
+		LockedRound:               rs.LockedRound,
+		LockedBlock:               newBlockSummary(rs.LockedBlock),
+		LockedBlockParts:          rs.LockedBlockParts,
+		ValidRound:                rs.ValidRound,
+		ValidBlock:                newBlockSummary(rs.ValidBlock),
+		ValidBlockParts:           rs.ValidBlockParts,
+		Votes:                     rs.Votes,
+		CommitRound:               rs.CommitRound,
+		LastCommit:                rs.LastCommit,
+		LastValidators:            rs.LastValidators,
+		TriggeredTimeoutPrecommit: rs.TriggeredTimeoutPrecommit,
+	}
+}
+
 // CompleteProposalEvent returns information about a proposed block as an event.
 func (rs *RoundState) CompleteProposalEvent() types.EventDataCompleteProposal {
 	// We must construct BlockID from ProposalBlock and ProposalBlockParts
 	// cs.Proposal is not guaranteed to be set when this function is called
 	blockId := types.BlockID{
-		Hash:        rs.ProposalBlock.Hash(),
+		Hash:        blockHash(rs.ProposalBlock),
 		PartsHeader: rs.ProposalBlockParts.Header(),
 	}
 
@@ -6,15 +6,22 @@ import (
 	"fmt"
 	"hash/crc32"
 	"io"
+	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
 	auto "github.com/kardiachain/go-kardia/lib/autofile"
+	"github.com/kardiachain/go-kardia/lib/common"
 	"github.com/kardiachain/go-kardia/lib/log"
 	kos "github.com/kardiachain/go-kardia/lib/os"
+	"github.com/kardiachain/go-kardia/lib/p2p"
 	"github.com/kardiachain/go-kardia/lib/service"
 	kcons "github.com/kardiachain/go-kardia/proto/kardiachain/consensus"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+	"github.com/kardiachain/go-kardia/types"
 	ktime "github.com/kardiachain/go-kardia/types/time"
 )
 
@@ -74,6 +81,9 @@ type BaseWAL struct {
 
 	flushTicker   *time.Ticker
 	flushInterval time.Duration
+
+	// first #ENDHEIGHT of the rotated files, -1 if there's none
+	firstEndHeights map[int]int64
 }
 
 var _ WAL = &BaseWAL{}
@@ -91,9 +101,10 @@ func NewWAL(walFile string, groupOptions ...func(*auto.Group)) (*BaseWAL, error)
 		return nil, err
 	}
 	wal := &BaseWAL{
-		group:         group,
-		enc:           NewWALEncoder(group),
-		flushInterval: walDefaultFlushInterval,
+		group:           group,
+		enc:             NewWALEncoder(group),
+		flushInterval:   walDefaultFlushInterval,
+		firstEndHeights: make(map[int]int64),
 	}
 	wal.BaseService = *service.NewBaseService(nil, "baseWAL", wal)
 	return wal, nil
@@ -274,6 +285,101 @@ func (wal *BaseWAL) SearchForEndHeight(
 	return nil, false, nil
 }
 
+// TrimBelow removes the files of the group that only hold messages of heights
+// below height. The #ENDHEIGHT marker of height-1, the replay of height starts
+// from, is kept along with everything after it.
+func (wal *BaseWAL) TrimBelow(height int64) error {
+	min, max := wal.group.MinIndex(), wal.group.MaxIndex()
+	keep := min
+	for index := min + 1; index <= max; index++ {
+		first, err := wal.firstEndHeight(index, max)
+		if err != nil {
+			return err
+		}
+		if first >= height {
+			break
+		}
+		if first >= 0 {
+			// The marker of height-1 is in this file or a later one.
+			keep = index
+		}
+	}
+	if keep == min {
+		return nil
+	}
+	wal.Logger.Info("Trimming WAL", "below", height, "files", keep-min)
+	if err := wal.group.RemoveFilesBefore(keep); err != nil {
+		return err
+	}
+	for index := min; index < keep; index++ {
+		delete(wal.firstEndHeights, index)
+	}
+	return nil
+}
+
+// firstEndHeight returns the height of the first #ENDHEIGHT marker of the file
+// at index, -1 if it has none. The result is cached once the file is rotated.
+func (wal *BaseWAL) firstEndHeight(index, max int) (int64, error) {
+	if height, ok := wal.firstEndHeights[index]; ok {
+		return height, nil
+	}
+	gr, err := wal.group.NewReader(index)
+	if err != nil {
+		return 0, err
+	}
+	defer gr.Close()
+
+	height := int64(-1)
+	dec := NewWALDecoder(gr)
+	for {
+		msg, err := dec.Decode()
+		if err == io.EOF || IsDataCorruptionError(err) || gr.CurIndex() != index {
+			// The end of the file, or a message of the next one.
+			break
+		} else if err != nil {
+			return 0, err
+		}
+		if m, ok := msg.Msg.(EndHeightMessage); ok {
+			height = m.Height
+			break
+		}
+	}
+	if height >= 0 || index < max {
+		wal.firstEndHeights[index] = height
+	}
+	return height, nil
+}
+
+// RollbackWAL moves the WAL group of walFile aside and starts a new one that
+// only contains the #ENDHEIGHT marker of height. It must be used together with
+// a rollback of the chain to height, so that the next start catches up from
+// height+1 instead of tripping on markers written for the discarded heights.
+// The previous WAL directory is kept with a ".rollback-<height>" suffix, whose
+// path is returned (empty if there was no WAL to move).
+func RollbackWAL(walFile string, height uint64) (string, error) {
+	walDir := filepath.Dir(walFile)
+	if _, err := os.Stat(walDir); os.IsNotExist(err) {
+		return "", nil
+	}
+	backup := fmt.Sprintf("%s.rollback-%d", walDir, height)
+	if err := os.Rename(walDir, backup); err != nil {
+		return "", fmt.Errorf("failed to move WAL aside: %w", err)
+	}
+	if err := kos.EnsureDir(walDir, 0700); err != nil {
+		return backup, fmt.Errorf("failed to ensure WAL directory is in place: %w", err)
+	}
+	out, err := os.OpenFile(walFile, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return backup, err
+	}
+	defer out.Close()
+
+	if err := NewWALEncoder(out).Encode(&TimedWALMessage{ktime.Now(), EndHeightMessage{int64(height)}}); err != nil {
+		return backup, err
+	}
+	return backup, out.Sync()
+}
+
 // A WALEncoder writes custom-encoded WAL messages to an output stream.
 //
 // Format: 4 bytes CRC sum + 4 bytes length + arbitrary-length value
@@ -409,6 +515,130 @@ func (dec *WALDecoder) Decode() (*TimedWALMessage, error) {
 	return tMsgWal, err
 }
 
+// WALEntry is a message of a WAL in a form fit for humans and JSON.
+type WALEntry struct {
+	Index int         `json:"index"` // of the file of the group
+	Time  time.Time   `json:"time"`
+	Type  string      `json:"type"`
+	Peer  p2p.ID      `json:"peer,omitempty"` // empty for our own messages
+	Msg   interface{} `json:"msg"`
+}
+
+// NewWALEntry returns the entry of msg, read from the file at index.
+func NewWALEntry(index int, msg *TimedWALMessage) *WALEntry {
+	entry := &WALEntry{Index: index, Time: msg.Time, Msg: msg.Msg}
+	switch m := msg.Msg.(type) {
+	case EndHeightMessage:
+		entry.Type = "EndHeight"
+	case types.EventDataRoundState:
+		entry.Type = "NewRoundStep"
+	case msgInfo:
+		entry.Type = strings.TrimSuffix(reflect.Indirect(reflect.ValueOf(m.Msg)).Type().Name(), "Message")
+		entry.Peer = m.PeerID
+		entry.Msg = m.Msg
+	case timeoutInfo:
+		entry.Type = "Timeout"
+		entry.Msg = struct {
+			Duration string `json:"duration"`
+			Height   uint64 `json:"height"`
+			Round    uint32 `json:"round"`
+			Step     string `json:"step"`
+		}{m.Duration.String(), m.Height, m.Round, m.Step.String()}
+	default:
+		entry.Type = reflect.TypeOf(msg.Msg).String()
+	}
+	return entry
+}
+
+// IterateWAL calls fn with the messages of the WAL group of walFile, oldest
+// first, along with the index of the file they're read from. It stops at the
+// first error, including a corrupted message.
+func IterateWAL(walFile string, fn func(index int, msg *TimedWALMessage) error) error {
+	if _, err := os.Stat(walFile); err != nil {
+		return err
+	}
+	group, err := auto.OpenGroup(walFile)
+	if err != nil {
+		return err
+	}
+	defer group.Close()
+
+	gr, err := group.NewReader(group.MinIndex())
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	dec := NewWALDecoder(gr)
+	for {
+		msg, err := dec.Decode()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("file #%d: %w", gr.CurIndex(), err)
+		}
+		if err := fn(gr.CurIndex(), msg); err != nil {
+			return err
+		}
+	}
+}
+
+// LastSignedInWAL returns the height, round and type of the last vote or
+// proposal signed by address among the messages of the WAL group of
+// walFile. found is false if there is none, or no WAL. A corrupted tail, as
+// left by a crash, ends the search.
+func LastSignedInWAL(walFile string, address common.Address) (height uint64, round uint32, msgType kproto.SignedMsgType, found bool, err error) {
+	if _, err := os.Stat(walFile); os.IsNotExist(err) {
+		return 0, 0, 0, false, nil
+	}
+	// The steps of a round, in the order they are signed.
+	step := func(t kproto.SignedMsgType) int {
+		switch t {
+		case kproto.ProposalType:
+			return 1
+		case kproto.PrevoteType:
+			return 2
+		default:
+			return 3
+		}
+	}
+	err = IterateWAL(walFile, func(_ int, msg *TimedWALMessage) error {
+		mi, ok := msg.Msg.(msgInfo)
+		if !ok {
+			return nil
+		}
+		var (
+			h uint64
+			r uint32
+			t kproto.SignedMsgType
+		)
+		switch m := mi.Msg.(type) {
+		case *ProposalMessage:
+			// Only our own proposals are recorded without a peer.
+			if mi.PeerID != "" {
+				return nil
+			}
+			h, r, t = m.Proposal.Height, m.Proposal.Round, kproto.ProposalType
+		case *VoteMessage:
+			if !m.Vote.ValidatorAddress.Equal(address) {
+				return nil
+			}
+			h, r, t = m.Vote.Height, m.Vote.Round, m.Vote.Type
+		default:
+			return nil
+		}
+		if !found || h > height || (h == height && (r > round || (r == round && step(t) > step(msgType)))) {
+			height, round, msgType, found = h, r, t, true
+		}
+		return nil
+	})
+	var corrupted DataCorruptionError
+	if errors.As(err, &corrupted) {
+		err = nil
+	}
+	return height, round, msgType, found, err
+}
+
 type nilWAL struct{}
 
 var _ WAL = nilWAL{}
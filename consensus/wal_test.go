@@ -10,8 +10,13 @@ import (
 
 	"github.com/kardiachain/go-kardia/consensus/types"
 	"github.com/kardiachain/go-kardia/lib/autofile"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/crypto"
 	"github.com/kardiachain/go-kardia/lib/log"
 	"github.com/kardiachain/go-kardia/lib/merkle"
+	"github.com/kardiachain/go-kardia/lib/p2p"
+	"github.com/kardiachain/go-kardia/privval"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
 	ktime "github.com/kardiachain/go-kardia/types/time"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -172,6 +177,199 @@ func TestWALSearchForEndHeight(t *testing.T) {
 	assert.Equal(t, rs.Height, uint64(h+1), "wrong height")
 }
 
+func TestRollbackWAL(t *testing.T) {
+	walDir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	defer os.RemoveAll(walDir)
+	walFile := filepath.Join(walDir, "cs.wal", "wal")
+
+	wal, err := NewWAL(walFile)
+	require.NoError(t, err)
+	wal.SetLogger(log.TestingLogger())
+	require.NoError(t, wal.Start())
+	for h := int64(1); h <= 5; h++ {
+		require.NoError(t, wal.WriteSync(EndHeightMessage{h}))
+	}
+	require.NoError(t, wal.Stop())
+	wal.Wait()
+
+	backup, err := RollbackWAL(walFile, 3)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(backup, filepath.Base(walFile)))
+
+	wal, err = NewWAL(walFile)
+	require.NoError(t, err)
+	wal.SetLogger(log.TestingLogger())
+
+	gr, found, err := wal.SearchForEndHeight(3, &WALSearchOptions{})
+	require.NoError(t, err)
+	assert.True(t, found, "expected to find end height for 3")
+	gr.Close()
+
+	_, found, err = wal.SearchForEndHeight(4, &WALSearchOptions{})
+	require.NoError(t, err)
+	assert.False(t, found, "expected end height for 4 to be rolled back")
+}
+
+// writeWALFiles writes a file per element of heights to the group of wal,
+// each holding the #ENDHEIGHT markers of its heights, the last one being the
+// head.
+func writeWALFiles(t *testing.T, wal *BaseWAL, heights [][]int64) {
+	for i, markers := range heights {
+		require.NoError(t, wal.Write(ktypes.EventDataRoundState{Height: 1, Step: "RoundStepPropose"}))
+		for _, h := range markers {
+			require.NoError(t, wal.Write(EndHeightMessage{h}))
+		}
+		require.NoError(t, wal.FlushAndSync())
+		if i < len(heights)-1 {
+			wal.Group().RotateFile()
+		}
+	}
+}
+
+func TestWALTrimBelow(t *testing.T) {
+	walDir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	defer os.RemoveAll(walDir)
+	walFile := filepath.Join(walDir, "wal")
+
+	wal, err := NewWAL(walFile)
+	require.NoError(t, err)
+	wal.SetLogger(log.TestingLogger())
+	writeWALFiles(t, wal, [][]int64{{0}, {1, 2}, {}, {3}, {4}})
+
+	// Nothing below the first file holding the marker of 0.
+	require.NoError(t, wal.TrimBelow(1))
+	assert.Equal(t, 0, wal.Group().MinIndex())
+
+	require.NoError(t, wal.TrimBelow(3))
+	assert.Equal(t, 1, wal.Group().MinIndex())
+	gr, found, err := wal.SearchForEndHeight(2, &WALSearchOptions{})
+	require.NoError(t, err)
+	assert.True(t, found, "expected to find end height for 2")
+	gr.Close()
+
+	require.NoError(t, wal.TrimBelow(5))
+	assert.Equal(t, 4, wal.Group().MinIndex())
+	gr, found, err = wal.SearchForEndHeight(4, &WALSearchOptions{})
+	require.NoError(t, err)
+	assert.True(t, found, "expected to find end height for 4")
+	gr.Close()
+}
+
+func TestIterateWAL(t *testing.T) {
+	walDir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	defer os.RemoveAll(walDir)
+	walFile := filepath.Join(walDir, "wal")
+
+	wal, err := NewWAL(walFile)
+	require.NoError(t, err)
+	wal.SetLogger(log.TestingLogger())
+	writeWALFiles(t, wal, [][]int64{{0}, {1}})
+
+	var entries []*WALEntry
+	require.NoError(t, IterateWAL(walFile, func(index int, msg *TimedWALMessage) error {
+		entries = append(entries, NewWALEntry(index, msg))
+		return nil
+	}))
+	require.Len(t, entries, 4)
+	for i, want := range []struct {
+		index int
+		typ   string
+	}{{0, "NewRoundStep"}, {0, "EndHeight"}, {1, "NewRoundStep"}, {1, "EndHeight"}} {
+		assert.Equal(t, want.index, entries[i].Index, "entry %d", i)
+		assert.Equal(t, want.typ, entries[i].Type, "entry %d", i)
+	}
+	assert.Equal(t, EndHeightMessage{1}, entries[3].Msg)
+
+	assert.Error(t, IterateWAL(filepath.Join(walDir, "missing"), func(int, *TimedWALMessage) error { return nil }))
+}
+
+func TestLastSignedInWAL(t *testing.T) {
+	walDir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	defer os.RemoveAll(walDir)
+	walFile := filepath.Join(walDir, "wal")
+
+	me, other := common.BytesToAddress([]byte("me")), common.BytesToAddress([]byte("other"))
+	_, _, _, found, err := LastSignedInWAL(walFile, me)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	wal, err := NewWAL(walFile)
+	require.NoError(t, err)
+	wal.SetLogger(log.TestingLogger())
+	vote := func(addr common.Address, height uint64, round uint32, typ kproto.SignedMsgType, peer p2p.ID) msgInfo {
+		return msgInfo{&VoteMessage{&ktypes.Vote{
+			Type: typ, Height: height, Round: round, ValidatorAddress: addr, Timestamp: ktime.Now(), Signature: []byte{1},
+		}}, peer}
+	}
+	for _, msg := range []WALMessage{
+		EndHeightMessage{0},
+		vote(me, 1, 0, kproto.PrevoteType, ""),
+		vote(me, 1, 0, kproto.PrecommitType, ""),
+		EndHeightMessage{1},
+		vote(me, 2, 1, kproto.PrevoteType, ""),
+		// Votes of others, and ours gossiped back, come after.
+		vote(other, 2, 1, kproto.PrecommitType, "peer"),
+		vote(me, 2, 0, kproto.PrecommitType, "peer"),
+	} {
+		require.NoError(t, wal.Write(msg))
+	}
+	require.NoError(t, wal.FlushAndSync())
+
+	height, round, msgType, found, err := LastSignedInWAL(walFile, me)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.EqualValues(t, 2, height)
+	assert.EqualValues(t, 1, round)
+	assert.Equal(t, kproto.PrevoteType, msgType)
+
+	_, _, _, found, err = LastSignedInWAL(walFile, common.BytesToAddress([]byte("nobody")))
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+// TestLastSignedInWALNewFilePV starts a validator that signed before, as
+// recorded in its WAL, without a state file.
+func TestLastSignedInWALNewFilePV(t *testing.T) {
+	dir := t.TempDir()
+	walFile := filepath.Join(dir, "wal")
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	me := crypto.PubkeyToAddress(key.PublicKey)
+
+	wal, err := NewWAL(walFile)
+	require.NoError(t, err)
+	wal.SetLogger(log.TestingLogger())
+	require.NoError(t, wal.Write(EndHeightMessage{4}))
+	require.NoError(t, wal.Write(msgInfo{&VoteMessage{&ktypes.Vote{
+		Type: kproto.PrecommitType, Height: 5, Round: 1, ValidatorAddress: me, Timestamp: ktime.Now(), Signature: []byte{1},
+	}}, ""}))
+	require.NoError(t, wal.FlushAndSync())
+
+	// As on every start: load the state, then check it against the WAL.
+	for i := 0; i < 2; i++ {
+		pv, err := privval.LoadFilePV(key, filepath.Join(dir, privval.StateFileName))
+		require.NoError(t, err)
+		height, round, msgType, found, err := LastSignedInWAL(walFile, me)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.NoError(t, pv.LastSignState.CheckWAL(height, round, msgType), "start %d", i)
+
+		vote := func(height uint64, round uint32, typ kproto.SignedMsgType) *kproto.Vote {
+			return &kproto.Vote{Type: typ, Height: height, Round: round, Timestamp: time.Unix(1600000000, 0),
+				BlockID: kproto.BlockID{Hash: common.BytesToHash([]byte("block")).Bytes()}}
+		}
+		assert.Error(t, pv.SignVote("test", vote(5, 1, kproto.PrevoteType)), "below the WAL")
+		assert.Error(t, pv.SignVote("test", vote(5, 1, kproto.PrecommitType)), "the WAL vote itself")
+	}
+	pv, err := privval.LoadFilePV(key, filepath.Join(dir, privval.StateFileName))
+	require.NoError(t, err)
+	assert.NoError(t, pv.SignVote("test", &kproto.Vote{Type: kproto.PrevoteType, Height: 6, Timestamp: time.Unix(1600000000, 0)}))
+}
+
 func TestWALPeriodicSync(t *testing.T) {
 	walDir, err := ioutil.TempDir("", "wal")
 	require.NoError(t, err)
@@ -33,6 +33,7 @@ import (
 	"github.com/kardiachain/go-kardia/types"
 
 	stypes "github.com/kardiachain/go-kardia/mainchain/staking/types"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
 )
 
 var (
@@ -105,10 +106,8 @@ func (dbo *DualBlockOperations) CreateProposalBlock(height uint64, lastState cst
 	// Tx execution can happen in parallel with voting or precommitted.
 	// For simplicity, this code executes & commits txs before sending proposal,
 	// so statedb of proposal node already contains the new state and txs receipts of this proposal block.
-	maxBytes := lastState.ConsensusParams.Block.MaxBytes
 	// Fetch a limited amount of valid evidence
-	maxNumEvidence, _ := types.MaxEvidencePerBlock(int64(maxBytes))
-	evidence, _ := dbo.evpool.PendingEvidence(maxNumEvidence)
+	evidence, _ := dbo.evpool.PendingEvidence(lastState.ConsensusParams.Evidence.MaxBytes)
 
 	// Gets all dual's events in pending pools and them to the new block.
 	// TODO(namdoh@): Since there may be a small latency for other dual peers to see the same set of
@@ -152,10 +151,10 @@ func (dbo *DualBlockOperations) CreateProposalBlock(height uint64, lastState cst
 
 // Executes and commits the new state from events in the given block.
 // This also validate the new state root against the block root.
-func (dbo *DualBlockOperations) CommitAndValidateBlockTxs(block *types.Block, lastCommit stypes.LastCommitInfo, byzVals []stypes.Evidence) ([]*types.Validator, common.Hash, error) {
+func (dbo *DualBlockOperations) CommitAndValidateBlockTxs(block *types.Block, lastCommit stypes.LastCommitInfo, byzVals []stypes.Evidence) ([]*types.Validator, *kproto.ConsensusParams, common.Hash, error) {
 	root, err := dbo.commitDualEvents(block.DualEvents())
 	rawdb.WriteAppHash(dbo.blockchain.db.DB(), block.Height(), root)
-	return nil, root, err
+	return nil, nil, root, err
 }
 
 // CommitBlockTxsIfNotFound executes and commits block txs if the block state root is not found in storage.
@@ -164,7 +163,8 @@ func (dbo *DualBlockOperations) CommitBlockTxsIfNotFound(block *types.Block, las
 	root := dbo.blockchain.DB().ReadAppHash(block.Height())
 	if !dbo.blockchain.CheckCommittedStateRoot(root) {
 		dbo.logger.Trace("Block has unseen state root, execute & commit block txs", "height", block.Height())
-		return dbo.CommitAndValidateBlockTxs(block, lastCommit, byzVals)
+		vals, _, root, err := dbo.CommitAndValidateBlockTxs(block, lastCommit, byzVals)
+		return vals, root, err
 	}
 
 	return nil, common.Hash{}, nil
@@ -0,0 +1,159 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package simnet
+
+import (
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/kardiachain/go-kardia/consensus"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/crypto"
+	"github.com/kardiachain/go-kardia/lib/p2p"
+	ksync "github.com/kardiachain/go-kardia/lib/sync"
+	kcons "github.com/kardiachain/go-kardia/proto/kardiachain/consensus"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// byzantines holds the byzantine behaviours of the nodes of a network. They
+// are applied to the messages the nodes send, so the nodes themselves run
+// unmodified.
+type byzantines struct {
+	net *Network
+
+	mtx ksync.Mutex
+	// Nodes which never send their own votes
+	withholding map[p2p.ID]bool
+	// Nodes which send a conflicting proposal to some of their peers
+	equivocating map[p2p.ID]map[p2p.ID]bool
+}
+
+func newByzantines(net *Network) *byzantines {
+	return &byzantines{
+		net:          net,
+		withholding:  make(map[p2p.ID]bool),
+		equivocating: make(map[p2p.ID]map[p2p.ID]bool),
+	}
+}
+
+// WithholdVotes makes the node keep its votes to itself. Votes of the other
+// validators are still relayed by the node.
+func (net *Network) WithholdVotes(node int) {
+	b := net.byzantines
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.withholding[net.Nodes[node].ID()] = true
+}
+
+// EquivocateProposals makes the node, whenever it proposes, send the victims
+// another proposal for a different block. Both proposals are signed by the
+// node's validator.
+func (net *Network) EquivocateProposals(node int, victims ...int) {
+	b := net.byzantines
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	ids := make(map[p2p.ID]bool, len(victims))
+	for _, victim := range victims {
+		ids[net.Nodes[victim].ID()] = true
+	}
+	b.equivocating[net.Nodes[node].ID()] = ids
+}
+
+// SetHonest removes the byzantine behaviours of the node.
+func (net *Network) SetHonest(node int) {
+	b := net.byzantines
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	id := net.Nodes[node].ID()
+	delete(b.withholding, id)
+	delete(b.equivocating, id)
+}
+
+// intercept implements p2p.MessageInterceptor.
+func (b *byzantines) intercept(from, to p2p.ID, chID byte, msgBytes []byte) ([]byte, bool) {
+	if chID != consensus.VoteChannel && chID != consensus.DataChannel {
+		return msgBytes, true
+	}
+
+	b.mtx.Lock()
+	withholding := b.withholding[from]
+	equivocating := b.equivocating[from][to]
+	b.mtx.Unlock()
+	if !withholding && !equivocating {
+		return msgBytes, true
+	}
+
+	node := b.net.node(from)
+	if node == nil {
+		return msgBytes, true
+	}
+	msg, err := decodeMsg(msgBytes)
+	if err != nil {
+		return msgBytes, true
+	}
+
+	switch msg := msg.(type) {
+	case *consensus.VoteMessage:
+		if withholding && msg.Vote.ValidatorAddress.Equal(node.PrivVal.GetAddress()) {
+			return nil, false
+		}
+	case *consensus.ProposalMessage:
+		if equivocating {
+			if conflicting := conflictingProposal(node.PrivVal, msg.Proposal); conflicting != nil {
+				return consensus.MustEncode(&consensus.ProposalMessage{Proposal: conflicting}), true
+			}
+		}
+	}
+	return msgBytes, true
+}
+
+// conflictingProposal returns a proposal for another block at the same height
+// and round, signed by privVal.
+func conflictingProposal(privVal types.PrivValidator, proposal *types.Proposal) *types.Proposal {
+	blockID := proposal.POLBlockID
+	blockID.Hash = common.BytesToHash(crypto.Keccak256(blockID.Hash.Bytes()))
+	blockID.PartsHeader.Hash = common.BytesToHash(crypto.Keccak256(blockID.PartsHeader.Hash.Bytes()))
+
+	conflicting := types.NewProposal(proposal.Height, proposal.Round, proposal.POLRound, blockID)
+	conflicting.Timestamp = proposal.Timestamp
+	p := conflicting.ToProto()
+	if err := privVal.SignProposal(chainID, p); err != nil {
+		return nil
+	}
+	conflicting.Signature = p.Signature
+	return conflicting
+}
+
+func (net *Network) node(id p2p.ID) *Node {
+	for _, node := range net.Nodes {
+		if node.ID() == id {
+			return node
+		}
+	}
+	return nil
+}
+
+func decodeMsg(bz []byte) (consensus.Message, error) {
+	pb := &kcons.Message{}
+	if err := proto.Unmarshal(bz, pb); err != nil {
+		return nil, err
+	}
+	return consensus.MsgFromProto(pb)
+}
@@ -0,0 +1,391 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package simnet runs networks of full nodes in a single process, connected
+// over an in-memory p2p network. Links between nodes can be delayed, made
+// lossy or partitioned, and nodes can be made byzantine, so liveness and
+// safety issues are reproducible in tests.
+package simnet
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	bcReactor "github.com/kardiachain/go-kardia/blockchain"
+	"github.com/kardiachain/go-kardia/configs"
+	"github.com/kardiachain/go-kardia/consensus"
+	"github.com/kardiachain/go-kardia/kai/kaidb"
+	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
+	"github.com/kardiachain/go-kardia/kai/state/cstate"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/crypto"
+	"github.com/kardiachain/go-kardia/lib/log"
+	"github.com/kardiachain/go-kardia/lib/p2p"
+	"github.com/kardiachain/go-kardia/mainchain/blockchain"
+	"github.com/kardiachain/go-kardia/mainchain/genesis"
+	"github.com/kardiachain/go-kardia/mainchain/staking"
+	"github.com/kardiachain/go-kardia/mainchain/tx_pool"
+	"github.com/kardiachain/go-kardia/types"
+	"github.com/kardiachain/go-kardia/types/evidence"
+	kaitime "github.com/kardiachain/go-kardia/types/time"
+)
+
+const (
+	chainID        = "simnet"
+	minStake       = "12500000000000000000000000"
+	genesisBalance = "500000000000000000000000000"
+)
+
+// Config configures a simulated network.
+type Config struct {
+	// Number of validators, each run by its own node.
+	Validators int
+	// Directory of the consensus WALs. A temporary directory is used, and
+	// removed on Stop, if it is empty.
+	RootDir string
+	// Seed of the messages dropped by the link rules.
+	Seed int64
+	// Consensus configuration of all nodes, TestConsensusConfig if nil.
+	Consensus *configs.ConsensusConfig
+	// Logger of the nodes, a discarding logger if nil.
+	Logger log.Logger
+	// Whether the nodes propagate proposal blocks as compact blocks.
+	CompactBlocks bool
+}
+
+// Node is a full node of a simulated network.
+type Node struct {
+	Index   int
+	PrivVal types.PrivValidator
+
+	DB           kaidb.Database
+	Switch       *p2p.Switch
+	BlockChain   *blockchain.BlockChain
+	BlockOper    *blockchain.BlockOperations
+	TxPool       *tx_pool.TxPool
+	EvidencePool *evidence.Pool
+	Consensus    *consensus.ConsensusManager
+	EventBus     *types.EventBus
+}
+
+// ID returns the p2p ID of the node.
+func (n *Node) ID() p2p.ID {
+	return n.Switch.NodeInfo().ID()
+}
+
+// Height returns the height of the last block committed by the node.
+func (n *Node) Height() uint64 {
+	return n.BlockOper.Height()
+}
+
+// Network is a set of full nodes connected over a p2p.MemoryNetwork.
+type Network struct {
+	Nodes   []*Node
+	Genesis *genesis.Genesis
+	Links   *p2p.MemoryNetwork
+	// Account funded in the genesis, to send transactions from
+	Account *ecdsa.PrivateKey
+
+	rootDir    string
+	tempDir    bool
+	byzantines *byzantines
+}
+
+// NewNetwork creates the nodes of a network, with a validator each. The
+// nodes are connected to each other on Start.
+func NewNetwork(cfg Config) (*Network, error) {
+	if cfg.Validators < 1 {
+		return nil, fmt.Errorf("a network needs at least one validator, got %d", cfg.Validators)
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+	csConfig := cfg.Consensus
+	if csConfig == nil {
+		csConfig = configs.TestConsensusConfig()
+	}
+
+	net := &Network{
+		Links:   p2p.NewMemoryNetwork(cfg.Seed),
+		rootDir: cfg.RootDir,
+	}
+	if net.rootDir == "" {
+		dir, err := os.MkdirTemp("", "simnet")
+		if err != nil {
+			return nil, err
+		}
+		net.rootDir, net.tempDir = dir, true
+	}
+	net.byzantines = newByzantines(net)
+	net.Links.SetInterceptor(net.byzantines.intercept)
+
+	configs.AddDefaultContract()
+	configs.AddDefaultStakingContractAddress()
+	var privVals []types.PrivValidator
+	net.Account, _ = crypto.GenerateKey()
+	net.Genesis, privVals = genesisDoc(cfg.Validators, crypto.PubkeyToAddress(net.Account.PublicKey))
+
+	for i, privVal := range privVals {
+		nodeConfig := *csConfig
+		nodeConfig.RootDir = filepath.Join(net.rootDir, fmt.Sprintf("node%d", i))
+		node, err := newNode(i, net, privVal, &nodeConfig, cfg.CompactBlocks, logger.New("node", i))
+		if err != nil {
+			net.cleanup()
+			return nil, fmt.Errorf("node %d: %w", i, err)
+		}
+		net.Nodes = append(net.Nodes, node)
+	}
+	return net, nil
+}
+
+func genesisDoc(numValidators int, account common.Address) (*genesis.Genesis, []types.PrivValidator) {
+	validators := make([]*genesis.GenesisValidator, numValidators)
+	privVals := make([]types.PrivValidator, numValidators)
+	alloc := make(map[common.Address]genesis.GenesisAccount)
+	balance, _ := new(big.Int).SetString(genesisBalance, 10)
+	for i := 0; i < numValidators; i++ {
+		val, privVal := types.RandValidator(false, 1)
+		validators[i] = &genesis.GenesisValidator{
+			Address:          val.Address.String(),
+			StartWithGenesis: true,
+			SelfDelegate:     minStake,
+			// The staking contract reads 32 bytes of the name
+			Name:           fmt.Sprintf("%-32s", fmt.Sprintf("simnet%d", i)),
+			CommissionRate: "5",
+			MaxRate:        "20",
+			MaxChangeRate:  "5",
+		}
+		privVals[i] = privVal
+		alloc[val.Address] = genesis.GenesisAccount{
+			Balance: balance,
+		}
+	}
+	alloc[account] = genesis.GenesisAccount{
+		Balance: balance,
+	}
+	sort.Sort(types.PrivValidatorsByAddress(privVals))
+	return &genesis.Genesis{
+		InitialHeight:   1,
+		Timestamp:       kaitime.Now(),
+		ChainID:         chainID,
+		Validators:      validators,
+		ConsensusParams: configs.TestConsensusParams(),
+		Config:          configs.TestChainConfig,
+		Alloc:           alloc,
+	}, privVals
+}
+
+// newNode wires a full node the way the Kardiachain backend does, on a
+// memory database.
+func newNode(i int, net *Network, privVal types.PrivValidator, csConfig *configs.ConsensusConfig, compactBlocks bool,
+	logger log.Logger) (*Node, error) {
+	db := memorydb.New()
+	chainConfig, _, err := genesis.SetupGenesisBlock(db, net.Genesis)
+	if err != nil {
+		return nil, err
+	}
+	bc, err := blockchain.NewBlockChain(db, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	stakingUtil, err := staking.NewSmcStakingUtil()
+	if err != nil {
+		return nil, err
+	}
+	stateDB := cstate.NewStore(db)
+	evPool, err := evidence.NewPool(stateDB, db, bc)
+	if err != nil {
+		return nil, err
+	}
+	evPool.SetLogger(logger)
+	txConfig := tx_pool.DefaultTxPoolConfig
+	txConfig.Journal = "" // nodes aren't restarted
+	txPool := tx_pool.NewTxPool(txConfig, chainConfig, bc)
+	txpoolR := tx_pool.NewReactor(txConfig, txPool)
+	txpoolR.SetLogger(logger)
+	evR := evidence.NewReactor(evPool)
+	evR.SetLogger(logger)
+
+	bOper := blockchain.NewBlockOperations(logger, bc, txPool, evPool, stakingUtil)
+	blockExec := cstate.NewBlockExecutor(stateDB, logger, evPool, bOper)
+	state, err := stateDB.LoadStateFromDBOrGenesisDoc(net.Genesis)
+	if err != nil {
+		return nil, err
+	}
+
+	// All validators start from genesis, none of them has to catch up
+	fastSync := *configs.DefaultFastSyncConfig()
+	fastSync.Enable = false
+	bcR := bcReactor.NewBlockchainReactor(state, blockExec, bOper, &fastSync)
+	bcR.SetLogger(logger)
+
+	eventBus := types.NewEventBus()
+	eventBus.SetLogger(logger.New("module", "events"))
+	if err := eventBus.Start(); err != nil {
+		return nil, err
+	}
+	cs := consensus.NewConsensusState(logger, csConfig, state, bOper, blockExec, evPool)
+	cs.SetLogger(logger.New("module", "consensus"))
+	csR := consensus.NewConsensusManager(cs, &fastSync)
+	csR.SetLogger(logger)
+	csR.SetPrivValidator(privVal)
+	csR.SetEventBus(eventBus)
+	csR.SetTxSource(txPool)
+
+	sw := p2p.MakeMemorySwitch(configs.DefaultP2PConfig(), i, net.Links, func(_ int, sw *p2p.Switch) *p2p.Switch {
+		sw.SetAddrBook(&p2p.AddrBookMock{
+			Addrs:    make(map[string]struct{}),
+			OurAddrs: make(map[string]struct{}),
+		})
+		sw.AddReactor("BLOCKCHAIN", bcR)
+		sw.AddReactor("CONSENSUS", csR)
+		sw.AddReactor("TXPOOL", txpoolR)
+		sw.AddReactor("EVIDENCE", evR)
+		if compactBlocks {
+			sw.SetNodeInfo(p2p.DefaultNodeInfo{Capabilities: []string{consensus.CompactBlocksCapability}})
+		}
+		return sw
+	})
+	sw.SetLogger(logger.New("module", "p2p"))
+
+	return &Node{
+		Index:        i,
+		PrivVal:      privVal,
+		DB:           db,
+		Switch:       sw,
+		BlockChain:   bc,
+		BlockOper:    bOper,
+		TxPool:       txPool,
+		EvidencePool: evPool,
+		Consensus:    csR,
+		EventBus:     eventBus,
+	}, nil
+}
+
+// Start starts all nodes and connects each pair of them.
+func (net *Network) Start() error {
+	for _, node := range net.Nodes {
+		if err := node.Switch.Start(); err != nil {
+			return fmt.Errorf("node %d: %w", node.Index, err)
+		}
+	}
+	return net.connect()
+}
+
+// connect connects the pairs of nodes which are not connected yet.
+func (net *Network) connect() error {
+	for i, node := range net.Nodes {
+		for _, peer := range net.Nodes[i+1:] {
+			if node.Switch.Peers().Has(peer.ID()) {
+				continue
+			}
+			addr := net.Links.NetAddress(peer.ID())
+			if err := node.Switch.DialPeerWithAddress(&addr); err != nil {
+				return fmt.Errorf("node %d dialing node %d: %w", node.Index, peer.Index, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Stop stops all nodes, and removes the temporary directory of the network.
+func (net *Network) Stop() {
+	for _, node := range net.Nodes {
+		if node.Switch.IsRunning() {
+			_ = node.Switch.Stop()
+		}
+		_ = node.EventBus.Stop()
+	}
+	net.cleanup()
+}
+
+func (net *Network) cleanup() {
+	if net.tempDir {
+		_ = os.RemoveAll(net.rootDir)
+	}
+}
+
+// WaitForHeight waits until all nodes committed the block at the height.
+func (net *Network) WaitForHeight(height uint64, timeout time.Duration) error {
+	return net.waitFor(timeout, func() error {
+		for _, node := range net.Nodes {
+			if node.Height() < height {
+				return fmt.Errorf("node %d is at height %d, expected %d", node.Index, node.Height(), height)
+			}
+		}
+		return nil
+	})
+}
+
+func (net *Network) waitFor(timeout time.Duration, check func() error) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := check()
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// SetLinkRule sets the rule of the link from one node to another.
+func (net *Network) SetLinkRule(from, to int, rule p2p.LinkRule) {
+	net.Links.SetLinkRule(net.Nodes[from].ID(), net.Nodes[to].ID(), rule)
+}
+
+// Partition splits the nodes, given by index, into groups which can't reach
+// each other. The nodes of different groups are disconnected.
+func (net *Network) Partition(groups ...[]int) {
+	idGroups := make([][]p2p.ID, len(groups))
+	for i, group := range groups {
+		for _, index := range group {
+			idGroups[i] = append(idGroups[i], net.Nodes[index].ID())
+		}
+	}
+	net.Links.Partition(idGroups...)
+}
+
+// Heal removes all partitions and reconnects the nodes.
+func (net *Network) Heal() error {
+	net.Links.Heal()
+
+	// Wait for the switches to drop the connections broken by the partitions,
+	// dialing a peer they still have would be refused.
+	if err := net.waitFor(5*time.Second, func() error {
+		for _, node := range net.Nodes {
+			for _, p := range node.Switch.Peers().List() {
+				if !p.IsRunning() {
+					return fmt.Errorf("node %d is still disconnecting from %v", node.Index, p.ID())
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return net.connect()
+}
@@ -0,0 +1,325 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package simnet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/configs"
+	"github.com/kardiachain/go-kardia/consensus"
+	cstypes "github.com/kardiachain/go-kardia/consensus/types"
+	"github.com/kardiachain/go-kardia/kai/state/cstate"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/log"
+	"github.com/kardiachain/go-kardia/lib/p2p"
+	"github.com/kardiachain/go-kardia/mainchain/tx_pool"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+func startNetwork(t *testing.T, validators int) *Network {
+	net, err := NewNetwork(Config{Validators: validators, Seed: 1})
+	require.NoError(t, err)
+	require.NoError(t, net.Start())
+	t.Cleanup(net.Stop)
+	return net
+}
+
+func TestNetworkCommitsBlocks(t *testing.T) {
+	net := startNetwork(t, 4)
+	require.NoError(t, net.WaitForHeight(3, 30*time.Second))
+}
+
+func TestNetworkPartitionHalts(t *testing.T) {
+	net := startNetwork(t, 4)
+	require.NoError(t, net.WaitForHeight(2, 30*time.Second))
+
+	subs := make([]types.Subscription, len(net.Nodes))
+	for i, node := range net.Nodes {
+		sub, err := node.EventBus.Subscribe(context.Background(), "partition", types.EventQueryNewRoundStep, 100)
+		require.NoError(t, err)
+		subs[i] = sub
+	}
+
+	// Neither half has more than 2/3 of the voting power, the nodes can't
+	// leave the step they end up in
+	net.Partition([]int{0, 1}, []int{2, 3})
+	for i, sub := range subs {
+		rs := waitForHalt(t, net.Nodes[i], sub)
+		assert.Equal(t, rs.Height-1, net.Nodes[i].Height(), "node %d", i)
+		require.NoError(t, net.Nodes[i].EventBus.UnsubscribeAll(context.Background(), "partition"))
+	}
+	halted := maxHeight(net)
+
+	require.NoError(t, net.Heal())
+	require.NoError(t, net.WaitForHeight(halted+2, 30*time.Second))
+}
+
+// waitForHalt waits, step after step, for the node to be in a vote step
+// without +2/3 of any votes, which only the votes of other nodes can get it
+// out of. It returns the round state of the node then.
+func waitForHalt(t *testing.T, node *Node, sub types.Subscription) *cstypes.RoundState {
+	timeout := time.After(30 * time.Second)
+	for {
+		rs := node.Consensus.GetRoundState()
+		switch {
+		case rs.Step == cstypes.RoundStepPrevote && !rs.Votes.Prevotes(rs.Round).HasTwoThirdsAny(),
+			rs.Step == cstypes.RoundStepPrecommit && !rs.Votes.Precommits(rs.Round).HasTwoThirdsAny():
+			return rs
+		}
+		select {
+		case <-sub.Out():
+		case <-sub.Cancelled():
+			t.Fatalf("node %d: subscription cancelled: %v", node.Index, sub.Err())
+		case <-timeout:
+			t.Fatalf("node %d: still at %d/%d/%v", node.Index, rs.Height, rs.Round, rs.Step)
+		}
+	}
+}
+
+func TestNetworkLossyLinks(t *testing.T) {
+	net := startNetwork(t, 4)
+	net.Links.SetDefaultRule(p2p.LinkRule{Latency: 5 * time.Millisecond, DropRate: 0.05})
+	require.NoError(t, net.WaitForHeight(3, 60*time.Second))
+}
+
+func TestNetworkWithheldVotes(t *testing.T) {
+	net := startNetwork(t, 4)
+	net.WithholdVotes(0)
+	byzantine := net.Nodes[0].PrivVal.GetAddress()
+
+	// The other validators have more than 2/3 of the voting power
+	require.NoError(t, net.WaitForHeight(4, 30*time.Second))
+	for height := uint64(2); height <= 4; height++ {
+		commit := net.Nodes[1].BlockOper.LoadSeenCommit(height)
+		require.NotNil(t, commit)
+		for _, sig := range commit.Signatures {
+			assert.False(t, sig.ForBlock() && sig.ValidatorAddress.Equal(byzantine),
+				"vote of the withholding validator seen at height %d", height)
+		}
+	}
+}
+
+func TestNetworkEquivocatingProposer(t *testing.T) {
+	net := startNetwork(t, 4)
+	net.EquivocateProposals(0, 2, 3)
+
+	var mtx sync.Mutex
+	var equivocations int
+	net.Links.SetInterceptor(func(from, to p2p.ID, chID byte, msgBytes []byte) ([]byte, bool) {
+		intercepted, ok := net.byzantines.intercept(from, to, chID, msgBytes)
+		if ok && !bytes.Equal(intercepted, msgBytes) {
+			mtx.Lock()
+			equivocations++
+			mtx.Unlock()
+		}
+		return intercepted, ok
+	})
+
+	// Depending on whether the victims get the proposal of the byzantine
+	// validator from it or from their honest peers first, its heights take one
+	// or more rounds. All nodes commit the same blocks either way.
+	require.NoError(t, net.WaitForHeight(6, 60*time.Second))
+	for height := uint64(1); height <= 6; height++ {
+		hash := net.Nodes[0].BlockOper.LoadBlock(height).Hash()
+		for _, node := range net.Nodes[1:] {
+			assert.Equal(t, hash, node.BlockOper.LoadBlock(height).Hash(),
+				"node %d committed another block at height %d", node.Index, height)
+		}
+	}
+	mtx.Lock()
+	defer mtx.Unlock()
+	assert.NotZero(t, equivocations, "expected conflicting proposals to be sent")
+}
+
+func TestNetworkCompactBlocks(t *testing.T) {
+	net, err := NewNetwork(Config{Validators: 4, Seed: 1, CompactBlocks: true})
+	require.NoError(t, err)
+	t.Cleanup(net.Stop)
+
+	// Txs aren't gossiped, the first proposer has the last ones to itself
+	// The interceptor runs on the goroutines of the links, decoding errors
+	// are checked once the network is done
+	var mtx sync.Mutex
+	var requests, rebuilt int
+	var decodeErr error
+	net.Links.SetInterceptor(func(from, to p2p.ID, chID byte, msgBytes []byte) ([]byte, bool) {
+		if chID == tx_pool.TxpoolChannel {
+			return nil, false
+		}
+		if chID == consensus.DataChannel {
+			msg, err := decodeMsg(msgBytes)
+			mtx.Lock()
+			if err != nil && decodeErr == nil {
+				decodeErr = err
+			}
+			switch msg := msg.(type) {
+			case *consensus.BlockTxsRequestMessage:
+				requests++
+			case *consensus.CompactBlockAckMessage:
+				if msg.Rebuilt {
+					rebuilt++
+				}
+			}
+			mtx.Unlock()
+		}
+		return msgBytes, true
+	})
+
+	txs := make([]*types.Transaction, 8)
+	to := common.HexToAddress("0x1")
+	for i := range txs {
+		tx := types.NewTransaction(uint64(i), to, big.NewInt(1), 100000, big.NewInt(1), nil)
+		txs[i], err = types.SignTx(types.HomesteadSigner{}, tx, net.Account)
+		require.NoError(t, err)
+	}
+	proposer := net.Nodes[0].Consensus.GetRoundState().Validators.GetProposer().Address
+	for _, node := range net.Nodes {
+		nodeTxs := txs[:6]
+		if node.PrivVal.GetAddress().Equal(proposer) {
+			nodeTxs = txs
+		}
+		for _, err := range node.TxPool.AddRemotesSync(nodeTxs) {
+			require.NoError(t, err)
+		}
+	}
+
+	require.NoError(t, net.Start())
+	require.NoError(t, net.waitFor(30*time.Second, func() error {
+		for _, node := range net.Nodes {
+			committed := 0
+			for height := uint64(1); height <= node.Height(); height++ {
+				committed += len(node.BlockOper.LoadBlock(height).Transactions())
+			}
+			if committed != len(txs) {
+				return fmt.Errorf("node %d committed %d txs, expected %d", node.Index, committed, len(txs))
+			}
+		}
+		return nil
+	}))
+	mtx.Lock()
+	defer mtx.Unlock()
+	require.NoError(t, decodeErr)
+	assert.NotZero(t, requests, "expected missing txs to be requested")
+	assert.NotZero(t, rebuilt, "expected compact blocks to be rebuilt")
+}
+
+func maxHeight(net *Network) uint64 {
+	var height uint64
+	for _, node := range net.Nodes {
+		if h := node.Height(); h > height {
+			height = h
+		}
+	}
+	return height
+}
+
+func TestNetworkWALReplay(t *testing.T) {
+	rootDir := t.TempDir()
+	net, err := NewNetwork(Config{Validators: 2, Seed: 1, RootDir: rootDir})
+	require.NoError(t, err)
+	require.NoError(t, net.Start())
+	t.Cleanup(net.Stop)
+	require.NoError(t, net.WaitForHeight(6, 30*time.Second))
+
+	// Halt node 0 in the middle of a height, so that the WAL holds the
+	// messages of the height in progress.
+	node := net.Nodes[0]
+	sub, err := node.EventBus.Subscribe(context.Background(), "replay", types.EventQueryNewRoundStep, 100)
+	require.NoError(t, err)
+	net.Partition([]int{0}, []int{1})
+	halted := waitForHalt(t, node, sub)
+	net.Stop()
+
+	stateStore := cstate.NewStore(node.DB)
+	state := stateStore.Load()
+	height := state.LastBlockHeight
+	require.Equal(t, halted.Height, height+1)
+
+	// Split the WAL written by the node into a file per height, as the group
+	// rotates on a long running node.
+	walFile := filepath.Join(t.TempDir(), "wal")
+	wal, err := consensus.NewWAL(walFile)
+	require.NoError(t, err)
+	require.NoError(t, consensus.IterateWAL(filepath.Join(rootDir, "node0", "cs.wal", "wal"),
+		func(_ int, msg *consensus.TimedWALMessage) error {
+			if err := wal.Write(msg.Msg); err != nil {
+				return err
+			}
+			if _, ok := msg.Msg.(consensus.EndHeightMessage); ok {
+				if err := wal.FlushAndSync(); err != nil {
+					return err
+				}
+				wal.Group().RotateFile()
+			}
+			return nil
+		}))
+	require.NoError(t, wal.FlushAndSync())
+
+	// Retaining 2 heights keeps the #ENDHEIGHT marker the replay of the
+	// oldest one starts from, and nothing before it.
+	below := int64(height) - 2
+	require.NoError(t, wal.TrimBelow(below))
+	gr, found, err := wal.SearchForEndHeight(below-1, &consensus.WALSearchOptions{})
+	require.NoError(t, err)
+	require.True(t, found, "expected to find end height for %d", below-1)
+	gr.Close()
+	_, found, err = wal.SearchForEndHeight(below-2, &consensus.WALSearchOptions{})
+	require.NoError(t, err)
+	require.False(t, found, "expected end height for %d to be trimmed", below-2)
+	wal.Group().Close()
+
+	// The height in progress is replayed from the trimmed WAL.
+	logger := log.NewNopLogger()
+	blockExec := cstate.NewBlockExecutor(stateStore, logger, node.EvidencePool, node.BlockOper)
+	cs := consensus.NewConsensusState(logger, configs.TestConsensusConfig(), state, node.BlockOper, blockExec, node.EvidencePool)
+	eventBus := types.NewEventBus()
+	require.NoError(t, eventBus.Start())
+	defer eventBus.Stop()
+	cs.SetEventBus(eventBus)
+
+	player, err := consensus.NewWALPlayer(cs, walFile)
+	require.NoError(t, err)
+	defer player.Close()
+	require.Equal(t, height+1, player.Height())
+	played := 0
+	for {
+		_, err := player.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		played++
+	}
+	assert.NotZero(t, played)
+	rs := player.RoundState()
+	assert.Equal(t, halted.Height, rs.Height)
+	assert.Equal(t, halted.Round, rs.Round)
+}
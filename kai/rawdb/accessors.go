@@ -708,6 +708,9 @@ func DeleteBlockMeta(db kaidb.KeyValueWriter, height uint64) {
 
 func DeleteBlockPart(db kaidb.Database, height uint64) error {
 	blockMeta := ReadBlockMeta(db, height)
+	if blockMeta == nil {
+		return nil
+	}
 	for i := 0; i < int(blockMeta.BlockID.PartsHeader.Total); i++ {
 		if err := db.Delete(blockPartKey(height, i)); err != nil {
 			return err
@@ -716,6 +719,28 @@ func DeleteBlockPart(db kaidb.Database, height uint64) error {
 	return nil
 }
 
+// DeleteBlockInfo removes the block info (receipts, gas used, rewards) of a block.
+func DeleteBlockInfo(db kaidb.KeyValueWriter, hash common.Hash, height uint64) {
+	if err := db.Delete(blockInfoKey(height, hash)); err != nil {
+		log.Crit("Failed to delete block info", "err", err)
+	}
+}
+
+// DeleteCommit removes the commit for the block at height, which is stored
+// along with the block at height+1.
+func DeleteCommit(db kaidb.KeyValueWriter, height uint64) {
+	if err := db.Delete(commitKey(height)); err != nil {
+		log.Crit("Failed to delete block commit", "err", err)
+	}
+}
+
+// DeleteSeenCommit removes the locally seen commit for the block at height.
+func DeleteSeenCommit(db kaidb.KeyValueWriter, height uint64) {
+	if err := db.Delete(seenCommitKey(height)); err != nil {
+		log.Crit("Failed to delete seen commit", "err", err)
+	}
+}
+
 // ReadAppHash ...
 func ReadAppHash(db kaidb.KeyValueReader, height uint64) common.Hash {
 	b, _ := db.Get(calcAppHashKey(height))
@@ -730,6 +755,13 @@ func WriteAppHash(db kaidb.KeyValueWriter, height uint64, hash common.Hash) {
 	_ = db.Put(calcAppHashKey(height), hash.Bytes())
 }
 
+// DeleteAppHash removes the app hash recorded for height.
+func DeleteAppHash(db kaidb.KeyValueWriter, height uint64) {
+	if err := db.Delete(calcAppHashKey(height)); err != nil {
+		log.Crit("Failed to delete app hash", "err", err)
+	}
+}
+
 // mustEncode proto encodes a proto.message and panics if fails
 func mustEncode(pb proto.Message) []byte {
 	bz, err := proto.Marshal(pb)
@@ -90,3 +90,18 @@ func WriteConsensusValidatorsInfo(db kaidb.KeyValueWriter, hash common.Hash, val
 func DeleteConsensusValidatorsInfo(db kaidb.KeyValueWriter, hash common.Hash) error {
 	return db.Delete(calcConsensusValidatorsInfoKey(hash))
 }
+
+// ReadConsensusUptime retrieves the encoded signatures of the commit for the
+// given height, nil if they weren't indexed.
+func ReadConsensusUptime(db kaidb.Reader, height uint64) []byte {
+	data, _ := db.Get(calcConsensusUptimeKey(height))
+	return data
+}
+
+func WriteConsensusUptime(db kaidb.KeyValueWriter, height uint64, data []byte) error {
+	return db.Put(calcConsensusUptimeKey(height), data)
+}
+
+func DeleteConsensusUptime(db kaidb.KeyValueWriter, height uint64) error {
+	return db.Delete(calcConsensusUptimeKey(height))
+}
@@ -1,6 +1,7 @@
 package rawdb
 
 import (
+	"bytes"
 	"fmt"
 	"sync"
 
@@ -133,6 +134,18 @@ func ReadLegacyTrieNode(db kaidb.KeyValueReader, hash common.Hash) []byte {
 	return data
 }
 
+// isLegacyTrieNode reports whether the key-value pair is a legacy trie node,
+// keyed by the hash of its value. Path scheme and other keys may be 32 bytes
+// long as well.
+func isLegacyTrieNode(key, value []byte) bool {
+	if len(key) != common.HashLength {
+		return false
+	}
+	hasher := newNodeHasher()
+	defer returnHasherToPool(hasher)
+	return bytes.Equal(key, hasher.hashData(value).Bytes())
+}
+
 // HasLegacyTrieNode checks if the trie node with the provided hash is present in db.
 func HasLegacyTrieNode(db kaidb.KeyValueReader, hash common.Hash) bool {
 	ok, _ := db.Has(hash.Bytes())
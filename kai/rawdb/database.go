@@ -19,9 +19,18 @@
 package rawdb
 
 import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kardiachain/go-kardia/kai/kaidb"
 	"github.com/kardiachain/go-kardia/kai/kaidb/leveldb"
 	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/log"
 	"github.com/kardiachain/go-kardia/types"
+	"github.com/olekukonko/tablewriter"
 )
 
 // DbInfo is used to start new database
@@ -71,3 +80,224 @@ func NewLevelDBDatabase(file string, cache int, handles int, namespace string) (
 	}
 	return NewStoreDB(db), nil
 }
+
+// counter counts the number of database entries.
+type counter uint64
+
+func (c counter) String() string {
+	return fmt.Sprintf("%d", c)
+}
+
+// stat stores sizes and count for a parameter
+type stat struct {
+	size  common.StorageSize
+	count counter
+}
+
+// Add size to the stat and increase the counter by 1
+func (s *stat) Add(size common.StorageSize) {
+	s.size += size
+	s.count++
+}
+
+func (s *stat) Size() string {
+	return s.size.String()
+}
+
+func (s *stat) Count() string {
+	return s.count.String()
+}
+
+// inspection holds the statistics of the entries of a database, by
+// category.
+type inspection struct {
+	// Key-value store statistics
+	headers         stat
+	canonicalHashes stat
+	headerHeights   stat
+	bodies          stat
+	blockInfos      stat
+	blockParts      stat
+	blockMetas      stat
+	commits         stat
+	seenCommits     stat
+	appHashes       stat
+	txLookups       stat
+	bloomBits       stat
+	accountSnaps    stat
+	storageSnaps    stat
+	codes           stat
+	contractAbis    stat
+	accountTries    stat
+	storageTries    stat
+	legacyTries     stat
+	preimages       stat
+	events          stat
+	dualActions     stat
+	dualLookups     stat
+	cstates         stat
+	cvalidators     stat
+	cparams         stat
+	cuptimes        stat
+
+	// Meta- and unaccounted data
+	metadata    stat
+	unaccounted stat
+
+	// Totals
+	total common.StorageSize
+}
+
+// add accounts for a database entry.
+func (ins *inspection) add(key, value []byte) {
+	size := common.StorageSize(len(key) + len(value))
+	ins.total += size
+	switch {
+	case bytes.HasPrefix(key, consensusStatePrefix) && len(key) == len(consensusStatePrefix)+8:
+		ins.cstates.Add(size)
+	case bytes.HasPrefix(key, consensusValidatorsInfoPrefix) && len(key) == len(consensusValidatorsInfoPrefix)+common.HashLength:
+		ins.cvalidators.Add(size)
+	case bytes.HasPrefix(key, consensusParamsInfoPrefix) && len(key) == len(consensusParamsInfoPrefix)+common.HashLength:
+		ins.cparams.Add(size)
+	case bytes.HasPrefix(key, consensusUptimePrefix) && len(key) == len(consensusUptimePrefix)+8:
+		ins.cuptimes.Add(size)
+	case bytes.HasPrefix(key, headerPrefix) && len(key) == len(headerPrefix)+8+common.HashLength:
+		ins.headers.Add(size)
+	case bytes.HasPrefix(key, headerPrefix) && len(key) == len(headerPrefix)+8+len(headerHashSuffix):
+		ins.canonicalHashes.Add(size)
+	case bytes.HasPrefix(key, headerHeightPrefix) && len(key) == len(headerHeightPrefix)+common.HashLength:
+		ins.headerHeights.Add(size)
+	case bytes.HasPrefix(key, blockBodyPrefix) && len(key) == len(blockBodyPrefix)+8+common.HashLength:
+		ins.bodies.Add(size)
+	case bytes.HasPrefix(key, blockInfoPrefix) && len(key) == len(blockInfoPrefix)+8+common.HashLength:
+		ins.blockInfos.Add(size)
+	case bytes.HasPrefix(key, blockPartPrefix) && len(key) == len(blockPartPrefix)+8+4:
+		ins.blockParts.Add(size)
+	case bytes.HasPrefix(key, blockMetaPrefix) && len(key) == len(blockMetaPrefix)+8:
+		ins.blockMetas.Add(size)
+	case bytes.HasPrefix(key, commitPrefix) && len(key) == len(commitPrefix)+8:
+		ins.commits.Add(size)
+	case bytes.HasPrefix(key, seenCommitPrefix) && len(key) == len(seenCommitPrefix)+8:
+		ins.seenCommits.Add(size)
+	case bytes.HasPrefix(key, appHashPrefix) && len(key) == len(appHashPrefix)+8:
+		ins.appHashes.Add(size)
+	case bytes.HasPrefix(key, txLookupPrefix) && len(key) == len(txLookupPrefix)+common.HashLength:
+		ins.txLookups.Add(size)
+	case bytes.HasPrefix(key, bloomBitsPrefix) && len(key) == len(bloomBitsPrefix)+10+common.HashLength:
+		ins.bloomBits.Add(size)
+	case bytes.HasPrefix(key, BloomBitsIndexPrefix):
+		ins.bloomBits.Add(size)
+	case bytes.HasPrefix(key, SnapshotAccountPrefix) && len(key) == len(SnapshotAccountPrefix)+common.HashLength:
+		ins.accountSnaps.Add(size)
+	case bytes.HasPrefix(key, SnapshotStoragePrefix) && len(key) == len(SnapshotStoragePrefix)+2*common.HashLength:
+		ins.storageSnaps.Add(size)
+	case isLegacyTrieNode(key, value):
+		ins.legacyTries.Add(size)
+	case bytes.HasPrefix(key, contractAbiPrefix) && len(key) == len(contractAbiPrefix)+common.HashLength:
+		ins.codes.Add(size)
+	case bytes.HasPrefix(key, contractAbiPrefix):
+		ins.contractAbis.Add(size)
+	case bytes.HasPrefix(key, trieNodeAccountPrefix):
+		ins.accountTries.Add(size)
+	case bytes.HasPrefix(key, trieNodeStoragePrefix) && len(key) >= len(trieNodeStoragePrefix)+common.HashLength:
+		ins.storageTries.Add(size)
+	case bytes.HasPrefix(key, PreimagePrefix) && len(key) == len(PreimagePrefix)+common.HashLength:
+		ins.preimages.Add(size)
+	case bytes.HasPrefix(key, eventPrefix):
+		ins.events.Add(size)
+	case bytes.HasPrefix(key, dualActionPrefix):
+		ins.dualActions.Add(size)
+	case bytes.HasPrefix(key, dualEventLookupPrefix) && len(key) == len(dualEventLookupPrefix)+common.HashLength:
+		ins.dualLookups.Add(size)
+	case bytes.HasPrefix(key, configPrefix) && len(key) == len(configPrefix)+common.HashLength:
+		ins.metadata.Add(size)
+	case bytes.HasPrefix(key, genesisPrefix) && len(key) == len(genesisPrefix)+common.HashLength:
+		ins.metadata.Add(size)
+	default:
+		var accounted bool
+		for _, meta := range [][]byte{
+			databaseVersionKey, headBlockKey, lastPivotKey, snapshotDisabledKey, SnapshotRootKey,
+			snapshotJournalKey, snapshotGeneratorKey, snapshotRecoveryKey, snapshotSyncStatusKey,
+		} {
+			if bytes.Equal(key, meta) {
+				ins.metadata.Add(size)
+				accounted = true
+				break
+			}
+		}
+		if !accounted {
+			ins.unaccounted.Add(size)
+		}
+	}
+}
+
+// rows returns the statistics of the key-value store as table rows.
+func (ins *inspection) rows() [][]string {
+	return [][]string{
+		{"Key-Value store", "Headers", ins.headers.Size(), ins.headers.Count()},
+		{"Key-Value store", "Canonical hashes", ins.canonicalHashes.Size(), ins.canonicalHashes.Count()},
+		{"Key-Value store", "Header height index", ins.headerHeights.Size(), ins.headerHeights.Count()},
+		{"Key-Value store", "Bodies", ins.bodies.Size(), ins.bodies.Count()},
+		{"Key-Value store", "Block infos", ins.blockInfos.Size(), ins.blockInfos.Count()},
+		{"Key-Value store", "Block parts", ins.blockParts.Size(), ins.blockParts.Count()},
+		{"Key-Value store", "Block metas", ins.blockMetas.Size(), ins.blockMetas.Count()},
+		{"Key-Value store", "Commits", ins.commits.Size(), ins.commits.Count()},
+		{"Key-Value store", "Seen commits", ins.seenCommits.Size(), ins.seenCommits.Count()},
+		{"Key-Value store", "App hashes", ins.appHashes.Size(), ins.appHashes.Count()},
+		{"Key-Value store", "Transaction index", ins.txLookups.Size(), ins.txLookups.Count()},
+		{"Key-Value store", "Bloombit index", ins.bloomBits.Size(), ins.bloomBits.Count()},
+		{"Key-Value store", "Contract codes", ins.codes.Size(), ins.codes.Count()},
+		{"Key-Value store", "Contract ABIs", ins.contractAbis.Size(), ins.contractAbis.Count()},
+		{"Key-Value store", "Smart contract events", ins.events.Size(), ins.events.Count()},
+		{"Key-Value store", "Dual actions", ins.dualActions.Size(), ins.dualActions.Count()},
+		{"Key-Value store", "Dual event index", ins.dualLookups.Size(), ins.dualLookups.Count()},
+		{"Key-Value store", "Hash trie nodes", ins.legacyTries.Size(), ins.legacyTries.Count()},
+		{"Key-Value store", "Path trie account nodes", ins.accountTries.Size(), ins.accountTries.Count()},
+		{"Key-Value store", "Path trie storage nodes", ins.storageTries.Size(), ins.storageTries.Count()},
+		{"Key-Value store", "Trie preimages", ins.preimages.Size(), ins.preimages.Count()},
+		{"Key-Value store", "Account snapshot", ins.accountSnaps.Size(), ins.accountSnaps.Count()},
+		{"Key-Value store", "Storage snapshot", ins.storageSnaps.Size(), ins.storageSnaps.Count()},
+		{"Key-Value store", "Consensus states", ins.cstates.Size(), ins.cstates.Count()},
+		{"Key-Value store", "Consensus validators info", ins.cvalidators.Size(), ins.cvalidators.Count()},
+		{"Key-Value store", "Consensus params info", ins.cparams.Size(), ins.cparams.Count()},
+		{"Key-Value store", "Consensus uptime", ins.cuptimes.Size(), ins.cuptimes.Count()},
+		{"Key-Value store", "Singleton metadata", ins.metadata.Size(), ins.metadata.Count()},
+	}
+}
+
+// InspectDatabase traverses the entire database and checks the size
+// of all different categories of data.
+func InspectDatabase(db kaidb.Database, keyPrefix, keyStart []byte) error {
+	it := db.NewIterator(keyPrefix, keyStart)
+	defer it.Release()
+
+	var (
+		count  int64
+		start  = time.Now()
+		logged = time.Now()
+		ins    inspection
+	)
+	// Inspect key-value database first.
+	for it.Next() {
+		ins.add(it.Key(), it.Value())
+		count++
+		if count%1000 == 0 && time.Since(logged) > 8*time.Second {
+			log.Info("Inspecting database", "count", count, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	// Display the database statistic of key-value store.
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Database", "Category", "Size", "Items"})
+	table.SetFooter([]string{"", "Total", ins.total.String(), " "})
+	table.AppendBulk(ins.rows())
+	table.Render()
+
+	if ins.unaccounted.size > 0 {
+		log.Error("Database contains unaccounted data", "size", ins.unaccounted.size, "count", ins.unaccounted.count)
+	}
+	return nil
+}
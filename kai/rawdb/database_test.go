@@ -0,0 +1,65 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rawdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/crypto"
+)
+
+// legacyNodeWithPrefix returns a value whose hash, its key as a legacy trie
+// node, starts with prefix.
+func legacyNodeWithPrefix(prefix byte) (common.Hash, []byte) {
+	for i := 0; ; i++ {
+		value := []byte{byte(i), byte(i >> 8), byte(i >> 16)}
+		if hash := crypto.Keccak256Hash(value); hash[0] == prefix {
+			return hash, value
+		}
+	}
+}
+
+func TestInspectionTrieNodes(t *testing.T) {
+	var ins inspection
+
+	// Legacy trie nodes whose hash starts like other keys.
+	for _, prefix := range []byte{contractAbiPrefix[0], trieNodeAccountPrefix[0], trieNodeStoragePrefix[0], 0x00} {
+		hash, value := legacyNodeWithPrefix(prefix)
+		ins.add(hash.Bytes(), value)
+	}
+	assert.EqualValues(t, 4, ins.legacyTries.count)
+
+	// A path scheme account node as long as a hash.
+	ins.add(accountTrieNodeKey(make([]byte, common.HashLength-len(trieNodeAccountPrefix))), []byte{0x01})
+	ins.add(accountTrieNodeKey([]byte{0x01, 0x02}), []byte{0x02})
+	assert.EqualValues(t, 2, ins.accountTries.count)
+	ins.add(storageTrieNodeKey(common.Hash{0x01}, []byte{0x03}), []byte{0x03})
+	assert.EqualValues(t, 1, ins.storageTries.count)
+
+	ins.add(codeKey(common.Hash{0x02}), []byte{0x60})
+	assert.EqualValues(t, 1, ins.codes.count)
+	ins.add(contractAbiKey("0x0000000000000000000000000000000000000001"), []byte("[]"))
+	assert.EqualValues(t, 1, ins.contractAbis.count)
+
+	assert.EqualValues(t, 4, ins.legacyTries.count)
+	assert.Zero(t, ins.unaccounted.count)
+}
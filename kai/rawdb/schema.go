@@ -60,6 +60,7 @@ var (
 	consensusStatePrefix          = []byte("ConsensusState")          // consensusStatePrefix + num (uint64 big endian) -> consensus state
 	consensusValidatorsInfoPrefix = []byte("ConsensusValidatorsInfo") // consensusValidatorsInfoPrefix + hash (consensus params hash) -> consensus params info
 	consensusParamsInfoPrefix     = []byte("ConsensusParamsInfo")     // consensusParamsInfoPrefix + hash (validators hash) -> consensus validators info
+	consensusUptimePrefix         = []byte("ConsensusUptime")         // consensusUptimePrefix + num (uint64 big endian) -> signatures of the commit
 
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
@@ -271,6 +272,11 @@ func calcConsensusStateKey(height uint64) []byte {
 	return append(consensusStatePrefix, encodeBlockHeight(height)...)
 }
 
+// consensusUptimeKey = consensusUptimePrefix + num (uint64 big endian)
+func calcConsensusUptimeKey(height uint64) []byte {
+	return append(consensusUptimePrefix, encodeBlockHeight(height)...)
+}
+
 // consensusValidatorsInfoKey = consensusValidatorsInfoPrefix + hash (validators hash)
 func calcConsensusValidatorsInfoKey(hash common.Hash) []byte {
 	return append(consensusValidatorsInfoPrefix, hash.Bytes()...)
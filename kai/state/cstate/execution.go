@@ -28,6 +28,7 @@ import (
 	"github.com/kardiachain/go-kardia/configs"
 	"github.com/kardiachain/go-kardia/lib/common"
 	"github.com/kardiachain/go-kardia/lib/log"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
 	"github.com/kardiachain/go-kardia/types"
 
 	stypes "github.com/kardiachain/go-kardia/mainchain/staking/types"
@@ -41,7 +42,7 @@ type EvidencePool interface {
 
 // BlockStore ...
 type BlockStore interface {
-	CommitAndValidateBlockTxs(*types.Block, stypes.LastCommitInfo, []stypes.Evidence) ([]*types.Validator, common.Hash, error)
+	CommitAndValidateBlockTxs(*types.Block, stypes.LastCommitInfo, []stypes.Evidence) ([]*types.Validator, *kproto.ConsensusParams, common.Hash, error)
 	Config() *configs.ChainConfig
 }
 
@@ -56,6 +57,7 @@ type BlockExecutor struct {
 	bc       BlockStore // block operations abstraction
 	store    Store
 	eventBus *types.EventBus
+	uptime   *UptimeStore
 
 	logger log.Logger
 
@@ -86,6 +88,11 @@ func (blockExec *BlockExecutor) SetEventBus(b *types.EventBus) {
 	blockExec.eventBus = b
 }
 
+// SetUptimeStore sets the index the signers of every commit are recorded in.
+func (blockExec *BlockExecutor) SetUptimeStore(s *UptimeStore) {
+	blockExec.uptime = s
+}
+
 // ValidateBlock validates the given block against the given state.
 // If the block is invalid, it returns an error.
 // Validation does not mutate state, but does require historical information from the stateDB,
@@ -96,7 +103,7 @@ func (blockExec *BlockExecutor) ValidateBlock(state LatestBlockState, block *typ
 		return nil
 	}
 
-	if err := validateBlock(blockExec.evpool, blockExec.store, state, block); err != nil {
+	if err := validateBlock(blockExec.evpool, blockExec.store, blockExec.bc.Config(), state, block); err != nil {
 		return err
 	}
 	blockExec.cache[hash] = struct{}{}
@@ -128,19 +135,20 @@ func (blockExec *BlockExecutor) ApplyBlock(state LatestBlockState, blockID types
 
 	commitInfo := getBeginBlockValidatorInfo(blockExec.bc.Config(), block, blockExec.store)
 
-	valUpdates, appHash, err := blockExec.bc.CommitAndValidateBlockTxs(block, commitInfo, byzVals)
+	valUpdates, paramsUpdate, appHash, err := blockExec.bc.CommitAndValidateBlockTxs(block, commitInfo, byzVals)
 	if err != nil {
 		return state, block.Height(), fmt.Errorf("commit failed for application: %v", err)
 	}
 
 	valUpdates = calculateValidatorSetUpdates(state.NextValidators.Validators, valUpdates)
 	// update the state with the block and responses
-	state, err = updateState(blockExec.logger, state, blockID, block.Header(), valUpdates)
+	state, err = updateState(blockExec.logger, state, blockID, block.Header(), valUpdates, paramsUpdate)
 	if err != nil {
 		return state, block.Height(), fmt.Errorf("commit failed for application: %v", err)
 	}
 	state.AppHash = appHash
 	blockExec.store.Save(state)
+	blockExec.saveUptime(block)
 
 	// Update evpool with the block and state.
 	blockExec.evpool.Update(state, block.Evidence().Evidence)
@@ -157,6 +165,21 @@ func (blockExec *BlockExecutor) ApplyBlock(state LatestBlockState, blockID types
 	return state, block.Height(), nil
 }
 
+// saveUptime indexes the signers of the last commit of the block.
+func (blockExec *BlockExecutor) saveUptime(block *types.Block) {
+	if blockExec.uptime == nil || block.Height() <= 1 {
+		return
+	}
+	height := block.Height() - 1
+	vals, err := blockExec.store.LoadValidators(height)
+	if err == nil {
+		err = blockExec.uptime.Save(height, vals, block.LastCommit())
+	}
+	if err != nil {
+		blockExec.logger.Error("Failed to index commit signers", "height", height, "err", err)
+	}
+}
+
 func (blockExec *BlockExecutor) interruptProc() {
 	blockExec.procInterrupt.Store(true)
 }
@@ -181,7 +204,8 @@ func (blockExec *BlockExecutor) Stop() {
 }
 
 // updateState returns a new State updated according to the header and responses.
-func updateState(logger log.Logger, state LatestBlockState, blockID types.BlockID, header *types.Header, validatorUpdates []*types.Validator) (LatestBlockState, error) {
+func updateState(logger log.Logger, state LatestBlockState, blockID types.BlockID, header *types.Header, validatorUpdates []*types.Validator,
+	paramsUpdate *kproto.ConsensusParams) (LatestBlockState, error) {
 	logger.Trace("updateState", "state", state, "blockID", blockID, "header", header)
 	// Copy the valset so we can apply changes from EndBlock
 	// and update s.LastValidators and s.Validators.
@@ -200,6 +224,15 @@ func updateState(logger log.Logger, state LatestBlockState, blockID types.BlockI
 
 	}
 	nValSet.IncrementProposerPriority(1)
+
+	// Update the params with the ones set through governance.
+	nextParams, changed := NextConsensusParams(logger, state.ConsensusParams, paramsUpdate)
+	lastHeightParamsChanged := state.LastHeightConsensusParamsChanged
+	if changed {
+		// Change results from this height but only applies to the next height.
+		lastHeightParamsChanged = header.Height + 1
+	}
+
 	return LatestBlockState{
 		ChainID:                     state.ChainID,
 		InitialHeight:               state.InitialHeight,
@@ -210,10 +243,33 @@ func updateState(logger log.Logger, state LatestBlockState, blockID types.BlockI
 		Validators:                  state.NextValidators.Copy(),
 		LastValidators:              state.Validators.Copy(),
 		LastHeightValidatorsChanged: lastHeightValsChanged,
-		ConsensusParams:             state.ConsensusParams,
+
+		ConsensusParams:                  nextParams,
+		LastHeightConsensusParamsChanged: lastHeightParamsChanged,
 	}, nil
 }
 
+// NextConsensusParams returns params updated with the ones set through
+// governance, and whether they changed. Every node ignores the same invalid
+// update.
+func NextConsensusParams(logger log.Logger, params kproto.ConsensusParams, update *kproto.ConsensusParams) (kproto.ConsensusParams, bool) {
+	next := types.UpdateConsensusParams(params, update)
+	if next.Equal(params) {
+		return params, false
+	}
+	if err := types.ValidateConsensusParams(next); err != nil {
+		logger.Error("Ignored invalid consensus params update", "err", err)
+		return params, false
+	}
+	return next, true
+}
+
+// BlockCommitInfo returns the signing information of the last commit that is
+// handed to the application when the given block is executed.
+func BlockCommitInfo(cfg *configs.ChainConfig, b *types.Block, store Store) stypes.LastCommitInfo {
+	return getBeginBlockValidatorInfo(cfg, b, store)
+}
+
 func getBeginBlockValidatorInfo(cfg *configs.ChainConfig, b *types.Block, store Store) stypes.LastCommitInfo {
 	lastCommit := b.LastCommit()
 	voteInfos := make([]stypes.VoteInfo, lastCommit.Size())
@@ -0,0 +1,75 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cstate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/lib/log"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+func TestUpdateStateConsensusParams(t *testing.T) {
+	vals, _ := types.RandValidatorSet(2, 10)
+	state := LatestBlockState{
+		ChainID:                          "test",
+		InitialHeight:                    1,
+		Validators:                       vals,
+		NextValidators:                   vals.Copy(),
+		LastValidators:                   vals.Copy(),
+		ConsensusParams:                  *types.DefaultConsensusParams(),
+		LastHeightConsensusParamsChanged: 1,
+	}
+	logger := log.New()
+
+	// Nothing set through governance keeps the params.
+	next, err := updateState(logger, state, types.BlockID{}, &types.Header{Height: 5}, nil, &kproto.ConsensusParams{})
+	require.NoError(t, err)
+	require.Equal(t, state.ConsensusParams, next.ConsensusParams)
+	require.EqualValues(t, 1, next.LastHeightConsensusParamsChanged)
+
+	// A valid update applies from the next height.
+	update := &kproto.ConsensusParams{
+		Block:   kproto.BlockParams{MaxGas: 30000000},
+		Timeout: kproto.TimeoutParams{Commit: 2 * time.Second},
+	}
+	next, err = updateState(logger, state, types.BlockID{}, &types.Header{Height: 5}, nil, update)
+	require.NoError(t, err)
+	require.EqualValues(t, 30000000, next.ConsensusParams.Block.MaxGas)
+	require.Equal(t, 2*time.Second, next.ConsensusParams.Timeout.Commit)
+	require.Equal(t, state.ConsensusParams.Block.MaxBytes, next.ConsensusParams.Block.MaxBytes)
+	require.EqualValues(t, 6, next.LastHeightConsensusParamsChanged)
+
+	// The same update at a later height is not a change.
+	later, err := updateState(logger, next, types.BlockID{}, &types.Header{Height: 9}, nil, update)
+	require.NoError(t, err)
+	require.Equal(t, next.ConsensusParams, later.ConsensusParams)
+	require.EqualValues(t, 6, later.LastHeightConsensusParamsChanged)
+
+	// An invalid update is ignored.
+	invalid := &kproto.ConsensusParams{Block: kproto.BlockParams{MaxBytes: types.MaxBlockSizeBytes + 1}}
+	next, err = updateState(logger, state, types.BlockID{}, &types.Header{Height: 5}, nil, invalid)
+	require.NoError(t, err)
+	require.Equal(t, state.ConsensusParams, next.ConsensusParams)
+	require.EqualValues(t, 1, next.LastHeightConsensusParamsChanged)
+}
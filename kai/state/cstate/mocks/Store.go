@@ -18,6 +18,20 @@ type Store struct {
 	mock.Mock
 }
 
+// Bootstrap provides a mock function with given fields: _a0
+func (_m *Store) Bootstrap(_a0 cstate.LatestBlockState) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(cstate.LatestBlockState) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Load provides a mock function with given fields:
 func (_m *Store) Load() cstate.LatestBlockState {
 	ret := _m.Called()
@@ -137,6 +151,30 @@ func (_m *Store) PruneState(from uint64, to uint64) (uint64, uint64, uint64) {
 	return r0, r1, r2
 }
 
+// Rollback provides a mock function with given fields: height
+func (_m *Store) Rollback(height uint64) (uint64, error) {
+	ret := _m.Called(height)
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint64) (uint64, error)); ok {
+		return rf(height)
+	}
+	if rf, ok := ret.Get(0).(func(uint64) uint64); ok {
+		r0 = rf(height)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint64) error); ok {
+		r1 = rf(height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Save provides a mock function with given fields: _a0
 func (_m *Store) Save(_a0 cstate.LatestBlockState) {
 	_m.Called(_a0)
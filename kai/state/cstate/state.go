@@ -86,7 +86,9 @@ func (state LatestBlockState) Copy() LatestBlockState {
 		LastValidators:              state.LastValidators.Copy(),
 		LastHeightValidatorsChanged: state.LastHeightValidatorsChanged,
 		AppHash:                     state.AppHash,
-		ConsensusParams:             state.ConsensusParams,
+
+		ConsensusParams:                  state.ConsensusParams,
+		LastHeightConsensusParamsChanged: state.LastHeightConsensusParamsChanged,
 	}
 }
 
@@ -178,3 +180,46 @@ func MedianTime(commit *types.Commit, validators *types.ValidatorSet) time.Time
 
 	return ktime.WeightedMedian(weightedTimes, totalVotingPower)
 }
+
+// ProposerSlot is the proposer of a round.
+type ProposerSlot struct {
+	Height   uint64         `json:"height"`
+	Round    uint32         `json:"round"`
+	Proposer common.Address `json:"proposer"`
+}
+
+// ProposerSchedule predicts the proposers of the first rounds of the next
+// heights, incrementing the proposer priorities as the consensus does. The
+// validators of the height after the next one and beyond are assumed not to
+// change.
+func (state LatestBlockState) ProposerSchedule(heights uint64, rounds uint32) []ProposerSlot {
+	height := state.LastBlockHeight + 1
+	if state.LastBlockHeight == 0 {
+		height = state.InitialHeight
+	}
+	var (
+		slots = make([]ProposerSlot, 0, heights*uint64(rounds))
+		vals  = state.Validators
+	)
+	for i := uint64(0); i < heights; i++ {
+		switch i {
+		case 0:
+		case 1:
+			vals = state.NextValidators
+		default:
+			vals = vals.CopyIncrementProposerPriority(1)
+		}
+		if vals.IsNilOrEmpty() {
+			break
+		}
+		// Rounds start at 1, every round after it moves the priorities once.
+		for round := uint32(1); round <= rounds; round++ {
+			roundVals := vals
+			if round > 1 {
+				roundVals = vals.CopyIncrementProposerPriority(int64(round - 1))
+			}
+			slots = append(slots, ProposerSlot{Height: height + i, Round: round, Proposer: roundVals.GetProposer().Address})
+		}
+	}
+	return slots
+}
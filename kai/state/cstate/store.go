@@ -43,9 +43,11 @@ type Store interface {
 	LoadStateFromDBOrGenesisDoc(genesisDoc *genesis.Genesis) (LatestBlockState, error)
 	Load() LatestBlockState
 	Save(LatestBlockState)
+	Bootstrap(LatestBlockState) error
 	LoadValidators(height uint64) (*types.ValidatorSet, error)
 	LoadConsensusParams(height uint64) (kproto.ConsensusParams, error)
 	PruneState(from, to uint64) (uint64, uint64, uint64)
+	Rollback(height uint64) (uint64, error)
 }
 
 type dbStore struct {
@@ -79,6 +81,23 @@ func (s *dbStore) Save(state LatestBlockState) {
 	saveState(s.db, state)
 }
 
+// Bootstrap saves a state restored by state sync. Unlike Save, it also writes the
+// validator sets of the current and the last height, which a node that didn't
+// process the previous blocks doesn't have.
+func (s *dbStore) Bootstrap(state LatestBlockState) error {
+	if state.LastValidators == nil || state.Validators == nil || state.NextValidators == nil {
+		return fmt.Errorf("incomplete validator sets in state at height %d", state.LastBlockHeight)
+	}
+	batch := s.db.NewBatch()
+	saveValidatorsInfo(batch, state.LastHeightValidatorsChanged, state.LastValidators)
+	saveValidatorsInfo(batch, state.LastHeightValidatorsChanged, state.Validators)
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	saveState(s.db, state)
+	return nil
+}
+
 func saveState(db kaidb.KeyValueStore, state LatestBlockState) {
 	sp, err := state.ToProto()
 	if err != nil {
@@ -109,7 +128,7 @@ func saveState(db kaidb.KeyValueStore, state LatestBlockState) {
 	batch.Write()
 }
 
-// PruneState prunes consensus state height in range of [from, to)
+// PruneState prunes consensus state and uptime index height in range of [from, to)
 func (s *dbStore) PruneState(from, to uint64) (uint64, uint64, uint64) {
 	if from == 0 { // do not prune state at height #0
 		from = 1
@@ -132,6 +151,14 @@ func (s *dbStore) PruneState(from, to uint64) (uint64, uint64, uint64) {
 				prunedBytes += uint64(len(bz))
 			}
 		}
+		// the uptime index goes along with the state of the same height
+		if data := rawdb.ReadConsensusUptime(s.db, i); data != nil {
+			if err := rawdb.DeleteConsensusUptime(s.db, i); err != nil {
+				log.Error("Failed to prune consensus uptime", "height", i)
+			} else {
+				prunedBytes += uint64(len(data))
+			}
+		}
 	}
 
 	// discards pruning validator infos which are used by genesis state
@@ -166,6 +193,29 @@ func (s *dbStore) PruneState(from, to uint64) (uint64, uint64, uint64) {
 	return prunedStates, prunedValInfos, prunedBytes
 }
 
+// Rollback deletes the consensus states above height, so that the state at
+// height becomes the latest one again, along with the uptime index above height.
+// It returns the number of removed states.
+// Validator and consensus params infos are shared between heights and kept.
+func (s *dbStore) Rollback(height uint64) (uint64, error) {
+	if rawdb.ReadConsensusStateHeight(s.db, height) == nil {
+		return 0, fmt.Errorf("consensus state at height %d not found", height)
+	}
+	var removed uint64
+	for h := height + 1; rawdb.ReadConsensusStateHeight(s.db, h) != nil; h++ {
+		if err := rawdb.DeleteConsensusStateHeight(s.db, h); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	for h := height + 1; rawdb.ReadConsensusUptime(s.db, h) != nil; h++ {
+		if err := rawdb.DeleteConsensusUptime(s.db, h); err != nil {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
 // LoadState loads the State from the database.
 func (s *dbStore) Load() LatestBlockState {
 	head := rawdb.ReadHeadBlock(s.db)
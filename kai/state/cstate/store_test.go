@@ -165,6 +165,11 @@ func TestPruneState(t *testing.T) {
 	}
 	stateStore.Save(state)
 
+	// Uptime index of blocks #1 to #5
+	for height := uint64(1); height <= 5; height++ {
+		assert.NoError(t, rawdb.WriteConsensusUptime(db, height, []byte{byte(height)}))
+	}
+
 	// perform pruningƒ
 	prunedStates, prunedValInfos, _ := stateStore.PruneState(0, 5)
 	assert.Equal(t, uint64(2), prunedStates)
@@ -182,6 +187,10 @@ func TestPruneState(t *testing.T) {
 	assert.Nil(t, valsInfo)
 	nValsInfo := rawdb.ReadConsensusValidatorsInfo(db, nvals.Hash())
 	assert.NotNil(t, nValsInfo)
+	for height := uint64(1); height < 5; height++ {
+		assert.Nil(t, rawdb.ReadConsensusUptime(db, height))
+	}
+	assert.NotNil(t, rawdb.ReadConsensusUptime(db, 5))
 }
 
 func TestLoadValidators(t *testing.T) {
@@ -239,3 +248,40 @@ func TestLoadValidators(t *testing.T) {
 	assert.NotNil(t, vs3)
 	assert.Equal(t, vs3.Hash(), vals.Hash())
 }
+
+func TestRollbackState(t *testing.T) {
+	db := memorydb.New()
+	stateStore := cstate.NewStore(db)
+	val, _ := types.RandValidator(true, 10)
+	vals := types.NewValidatorSet([]*types.Validator{val})
+	cparams := configs.DefaultConsensusParams()
+
+	for height := uint64(0); height <= 5; height++ {
+		stateStore.Save(cstate.LatestBlockState{
+			LastBlockHeight:             height,
+			LastValidators:              vals,
+			Validators:                  vals,
+			NextValidators:              vals,
+			LastHeightValidatorsChanged: 1,
+			ConsensusParams:             *cparams,
+		})
+		assert.NoError(t, rawdb.WriteConsensusUptime(db, height, []byte{byte(height)}))
+	}
+
+	removed, err := stateStore.Rollback(3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), removed)
+	for height := uint64(0); height <= 3; height++ {
+		assert.NotNil(t, rawdb.ReadConsensusStateHeight(db, height))
+	}
+	assert.Nil(t, rawdb.ReadConsensusStateHeight(db, 4))
+	assert.Nil(t, rawdb.ReadConsensusStateHeight(db, 5))
+	assert.NotNil(t, rawdb.ReadConsensusValidatorsInfo(db, vals.Hash()))
+	assert.NotNil(t, rawdb.ReadConsensusUptime(db, 3))
+	assert.Nil(t, rawdb.ReadConsensusUptime(db, 4))
+	assert.Nil(t, rawdb.ReadConsensusUptime(db, 5))
+
+	// Rolling back to a height without state must fail
+	_, err = stateStore.Rollback(4)
+	assert.Error(t, err)
+}
@@ -0,0 +1,108 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cstate
+
+import (
+	"github.com/kardiachain/go-kardia/kai/kaidb"
+	"github.com/kardiachain/go-kardia/kai/rawdb"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/rlp"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// ValidatorSignature records whether a validator signed a commit.
+type ValidatorSignature struct {
+	Address common.Address `json:"address"`
+	Signed  bool           `json:"signed"`
+}
+
+// ValidatorUptime is the number of blocks a validator was expected to sign
+// over a window and the number of them it missed.
+type ValidatorUptime struct {
+	Address common.Address `json:"address"`
+	Blocks  uint64         `json:"blocks"`
+	Missed  uint64         `json:"missed"`
+}
+
+// UptimeStore indexes the signers of every commit.
+type UptimeStore struct {
+	db kaidb.Database
+}
+
+// NewUptimeStore creates an uptime index over the given database.
+func NewUptimeStore(db kaidb.Database) *UptimeStore {
+	return &UptimeStore{db: db}
+}
+
+// Save records which validators of vals signed the commit for height.
+func (s *UptimeStore) Save(height uint64, vals *types.ValidatorSet, commit *types.Commit) error {
+	if commit.Size() != vals.Size() {
+		return types.NewErrInvalidCommitSignatures(uint64(vals.Size()), uint64(commit.Size()))
+	}
+	sigs := make([]ValidatorSignature, vals.Size())
+	for i, val := range vals.Validators {
+		sigs[i] = ValidatorSignature{
+			Address: val.Address,
+			Signed:  commit.Signatures[i].Signature != nil,
+		}
+	}
+	data, err := rlp.EncodeToBytes(sigs)
+	if err != nil {
+		return err
+	}
+	return rawdb.WriteConsensusUptime(s.db, height, data)
+}
+
+// Load returns the signers of the commit for height, nil if it wasn't indexed.
+func (s *UptimeStore) Load(height uint64) []ValidatorSignature {
+	data := rawdb.ReadConsensusUptime(s.db, height)
+	if len(data) == 0 {
+		return nil
+	}
+	var sigs []ValidatorSignature
+	if err := rlp.DecodeBytes(data, &sigs); err != nil {
+		return nil
+	}
+	return sigs
+}
+
+// Uptime counts the blocks each validator missed over the window of heights
+// ending at height. Heights that weren't indexed are skipped; validators are
+// listed in the order they first appear.
+func (s *UptimeStore) Uptime(height, window uint64) []*ValidatorUptime {
+	var (
+		uptimes []*ValidatorUptime
+		index   = make(map[common.Address]*ValidatorUptime)
+	)
+	for h := height; h > 0 && height-h < window; h-- {
+		for _, sig := range s.Load(h) {
+			uptime, ok := index[sig.Address]
+			if !ok {
+				uptime = &ValidatorUptime{Address: sig.Address}
+				index[sig.Address] = uptime
+				uptimes = append(uptimes, uptime)
+			}
+			uptime.Blocks++
+			if !sig.Signed {
+				uptime.Missed++
+			}
+		}
+	}
+	return uptimes
+}
@@ -0,0 +1,106 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cstate_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
+	"github.com/kardiachain/go-kardia/kai/state/cstate"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+func TestProposerSchedule(t *testing.T) {
+	validators := make([]*types.Validator, 4)
+	for i := range validators {
+		validators[i], _ = types.RandValidator(false, int64(10*(i+1)))
+	}
+	vals := types.NewValidatorSet(validators)
+	state := cstate.LatestBlockState{
+		LastBlockHeight: 5,
+		Validators:      vals.Copy(),
+		NextValidators:  vals.CopyIncrementProposerPriority(1),
+	}
+
+	slots := state.ProposerSchedule(4, 3)
+	require.Len(t, slots, 12)
+
+	// Walk the priorities the way the consensus does.
+	next := state.Validators.Copy()
+	for i, slot := range slots {
+		height := uint64(6 + i/3)
+		round := uint32(1 + i%3)
+		assert.Equal(t, height, slot.Height)
+		assert.Equal(t, round, slot.Round)
+
+		if round == 1 && height > 6 {
+			next.IncrementProposerPriority(1)
+		}
+		roundVals := next.Copy()
+		if round > 1 {
+			roundVals.IncrementProposerPriority(int64(round - 1))
+		}
+		assert.Equal(t, roundVals.GetProposer().Address, slot.Proposer, "height %d round %d", height, round)
+	}
+
+	assert.Empty(t, cstate.LatestBlockState{}.ProposerSchedule(2, 2))
+}
+
+func TestUptimeStore(t *testing.T) {
+	store := cstate.NewUptimeStore(memorydb.New())
+	vals, _ := types.RandValidatorSet(3, 10)
+
+	// The last validator misses every other block.
+	for h := uint64(1); h <= 10; h++ {
+		sigs := make([]types.CommitSig, vals.Size())
+		for i, val := range vals.Validators {
+			sigs[i] = types.NewCommitSigForBlock([]byte("signature"), val.Address, time.Now())
+		}
+		if h%2 == 0 {
+			sigs[2] = types.NewCommitSigAbsent()
+		}
+		require.NoError(t, store.Save(h, vals, types.NewCommit(h, 1, types.BlockID{}, sigs)))
+	}
+
+	loaded := store.Load(4)
+	require.Len(t, loaded, 3)
+	assert.True(t, loaded[0].Signed)
+	assert.False(t, loaded[2].Signed)
+	assert.Nil(t, store.Load(11))
+
+	uptimes := store.Uptime(10, 5)
+	require.Len(t, uptimes, 3)
+	for i, uptime := range uptimes {
+		assert.Equal(t, vals.Validators[i].Address, uptime.Address)
+		assert.EqualValues(t, 5, uptime.Blocks)
+	}
+	assert.EqualValues(t, 0, uptimes[0].Missed)
+	assert.EqualValues(t, 3, uptimes[2].Missed)
+
+	// Windows reaching before the first height only count the indexed ones.
+	uptimes = store.Uptime(10, 100)
+	assert.EqualValues(t, 10, uptimes[2].Blocks)
+	assert.EqualValues(t, 5, uptimes[2].Missed)
+
+	assert.Error(t, store.Save(11, vals, types.NewCommit(11, 1, types.BlockID{}, nil)))
+}
@@ -21,15 +21,41 @@ package cstate
 import (
 	"fmt"
 
+	"github.com/kardiachain/go-kardia/configs"
 	"github.com/kardiachain/go-kardia/trie"
 	"github.com/kardiachain/go-kardia/types"
 )
 
-func validateBlock(evidencePool EvidencePool, store Store, state LatestBlockState, block *types.Block) error {
+// BlockGasLimit returns the gas limit of the block following state. From the
+// Params upgrade fork, it is the Block.MaxGas of the consensus params, which
+// governance sets. Before, it only depends on the chain config.
+func BlockGasLimit(config *configs.ChainConfig, state LatestBlockState) uint64 {
+	height := state.LastBlockHeight + 1
+	if config.IsParamsUpgrade(&height) {
+		return state.ConsensusParams.Block.MaxGas
+	}
+	if config.IsGalaxias(&state.LastBlockHeight) {
+		return configs.BlockGasLimitGalaxias
+	}
+	return configs.BlockGasLimit
+}
+
+func validateBlock(evidencePool EvidencePool, store Store, config *configs.ChainConfig, state LatestBlockState, block *types.Block) error {
 	// Validate internal consistency
 	if err := block.ValidateBasic(trie.NewStackTrie(nil)); err != nil {
 		return err
 	}
+	if maxBytes := state.ConsensusParams.Block.MaxBytes; maxBytes > 0 && block.ByteSize() > maxBytes {
+		return fmt.Errorf("block size %d exceeds Block.MaxBytes %d", block.ByteSize(), maxBytes)
+	}
+	// The gas limit of the blocks before the fork was never checked, and
+	// differs from the params of the chain.
+	height := block.Height()
+	if config != nil && config.IsParamsUpgrade(&height) {
+		if want := BlockGasLimit(config, state); block.GasLimit() != want {
+			return fmt.Errorf("wrong Block.Header.GasLimit. Expected %v, got %v", want, block.GasLimit())
+		}
+	}
 
 	// Validate basic info
 	if block.Height() != state.LastBlockHeight+1 {
@@ -116,11 +142,9 @@ func validateBlock(evidencePool EvidencePool, store Store, state LatestBlockStat
 	}
 
 	// Limit the amount of evidence
-	maxNumEvidence, _ := types.MaxEvidencePerBlock(int64(state.ConsensusParams.Block.MaxBytes))
-	numEvidence := int64(len(block.Evidence().Evidence))
-	if numEvidence > maxNumEvidence {
-		return types.NewErrEvidenceOverflow(maxNumEvidence, numEvidence)
-
+	maxEvidenceBytes := state.ConsensusParams.Evidence.MaxBytes
+	if evidenceBytes := block.Evidence().Evidence.ByteSize(); evidenceBytes > maxEvidenceBytes {
+		return types.NewErrEvidenceOverflow(maxEvidenceBytes, evidenceBytes)
 	}
 
 	// Validate proposer is a known validator
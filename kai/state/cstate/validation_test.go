@@ -18,7 +18,81 @@
 
 package cstate
 
-import "testing"
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/configs"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/trie"
+	"github.com/kardiachain/go-kardia/types"
+)
 
 func TestValidateBlockEvidence(t *testing.T) {
 }
+
+func TestValidateBlockMaxBytes(t *testing.T) {
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 100000, big.NewInt(1), make([]byte, 2048))
+	block := types.NewBlock(&types.Header{Height: 1}, []*types.Transaction{tx}, nil, nil, trie.NewStackTrie(nil))
+
+	state := LatestBlockState{ConsensusParams: *types.DefaultConsensusParams()}
+	state.ConsensusParams.Block.MaxBytes = 1024
+	err := validateBlock(nil, nil, nil, state, block)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "exceeds Block.MaxBytes"), err.Error())
+
+	// A block within the limit goes on to the next checks.
+	state.ConsensusParams.Block.MaxBytes = block.ByteSize()
+	err = validateBlock(nil, nil, nil, state, block)
+	require.Error(t, err)
+	require.False(t, strings.Contains(err.Error(), "exceeds Block.MaxBytes"), err.Error())
+}
+
+func TestBlockGasLimit(t *testing.T) {
+	galaxias, upgrade := uint64(10), uint64(20)
+	config := &configs.ChainConfig{GalaxiasBlock: &galaxias, ParamsUpgradeBlock: &upgrade}
+	state := LatestBlockState{ConsensusParams: *types.DefaultConsensusParams()}
+	state.ConsensusParams.Block.MaxGas = 15000000
+
+	for _, tc := range []struct {
+		lastHeight uint64
+		want       uint64
+	}{
+		{9, configs.BlockGasLimit},
+		{10, configs.BlockGasLimitGalaxias},
+		{18, configs.BlockGasLimitGalaxias},
+		{19, 15000000},
+		{100, 15000000},
+	} {
+		state.LastBlockHeight = tc.lastHeight
+		require.Equal(t, tc.want, BlockGasLimit(config, state), "last height %d", tc.lastHeight)
+	}
+}
+
+func TestValidateBlockGasLimit(t *testing.T) {
+	upgrade := uint64(1)
+	config := &configs.ChainConfig{ParamsUpgradeBlock: &upgrade}
+	state := LatestBlockState{ConsensusParams: *types.DefaultConsensusParams()}
+	state.ConsensusParams.Block.MaxGas = 15000000
+	newBlock := func(gasLimit uint64) *types.Block {
+		return types.NewBlock(&types.Header{Height: 1, GasLimit: gasLimit}, nil, nil, nil, trie.NewStackTrie(nil))
+	}
+
+	err := validateBlock(nil, nil, config, state, newBlock(configs.BlockGasLimit))
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "wrong Block.Header.GasLimit"), err.Error())
+
+	// A block with the governed gas limit goes on to the next checks.
+	err = validateBlock(nil, nil, config, state, newBlock(15000000))
+	require.Error(t, err)
+	require.False(t, strings.Contains(err.Error(), "wrong Block.Header.GasLimit"), err.Error())
+
+	// The gas limit of the blocks before the fork isn't checked.
+	upgrade = 2
+	err = validateBlock(nil, nil, config, state, newBlock(configs.BlockGasLimit))
+	require.Error(t, err)
+	require.False(t, strings.Contains(err.Error(), "wrong Block.Header.GasLimit"), err.Error())
+}
@@ -0,0 +1,383 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kardiachain/go-kardia/kai/kaidb"
+	"github.com/kardiachain/go-kardia/kai/rawdb"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/crypto"
+	"github.com/kardiachain/go-kardia/lib/log"
+	"github.com/kardiachain/go-kardia/lib/rlp"
+	"github.com/kardiachain/go-kardia/trie"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+const (
+	// StateExportVersion is the version of the state export format.
+	StateExportVersion = 1
+
+	// stateChunkSize is the amount of entry data after which a chunk is cut.
+	stateChunkSize = 4 * 1024 * 1024
+)
+
+// errChunkChecksum is returned if a chunk doesn't match its checksum.
+var errChunkChecksum = errors.New("state chunk checksum mismatch")
+
+// StateExportHeader is written at the start of a state export.
+type StateExportHeader struct {
+	Version uint64
+	Root    common.Hash
+	Height  uint64
+}
+
+// stateEntry is a single flat state entry: an account if Storage is false,
+// one of the storage slots of Account otherwise.
+type stateEntry struct {
+	Account common.Hash
+	Slot    common.Hash
+	Storage bool
+	Value   []byte
+}
+
+// stateChunk is the unit of a state export. Entries are in snapshot iteration
+// order, the storage slots of an account right after it, and an account's
+// storage may continue in the next chunk. Codes holds the contract codes first
+// referenced by the accounts of the chunk.
+type stateChunk struct {
+	Entries []stateEntry
+	Codes   [][]byte
+}
+
+// chunkEnvelope carries an encoded chunk along with its keccak256 hash.
+type chunkEnvelope struct {
+	Payload  []byte
+	Checksum common.Hash
+}
+
+// ExportStateChunks iterates the flat state of root in the snapshot tree and
+// hands it out as a sequence of encoded chunks to fn. Contract codes are read
+// from db.
+func ExportStateChunks(snaptree *Tree, db kaidb.KeyValueReader, root common.Hash, fn func(payload []byte) error) error {
+	acctIt, err := snaptree.AccountIterator(root, common.Hash{})
+	if err != nil {
+		return err
+	}
+	defer acctIt.Release()
+
+	var (
+		chunk stateChunk
+		size  int
+		codes = make(map[common.Hash]struct{})
+
+		accounts, slots uint64
+		start           = time.Now()
+		logged          = time.Now()
+	)
+	flush := func(force bool) error {
+		if size < stateChunkSize && !(force && size > 0) {
+			return nil
+		}
+		payload, err := rlp.EncodeToBytes(&chunk)
+		if err != nil {
+			return err
+		}
+		chunk, size = stateChunk{}, 0
+		return fn(payload)
+	}
+	for acctIt.Next() {
+		hash, data := acctIt.Hash(), common.CopyBytes(acctIt.Account())
+		account, err := types.FullAccount(data)
+		if err != nil {
+			return err
+		}
+		chunk.Entries = append(chunk.Entries, stateEntry{Account: hash, Value: data})
+		size += common.HashLength + len(data)
+		accounts++
+
+		if codeHash := common.BytesToHash(account.CodeHash); codeHash != types.EmptyCodeHash {
+			if _, ok := codes[codeHash]; !ok {
+				code := rawdb.ReadCode(db, codeHash)
+				if len(code) == 0 {
+					return fmt.Errorf("missing code %x of account %x", codeHash, hash)
+				}
+				codes[codeHash] = struct{}{}
+				chunk.Codes = append(chunk.Codes, code)
+				size += len(code)
+			}
+		}
+		if account.Root != types.EmptyRootHash {
+			storageIt, err := snaptree.StorageIterator(root, hash, common.Hash{})
+			if err != nil {
+				return err
+			}
+			for storageIt.Next() {
+				slot := common.CopyBytes(storageIt.Slot())
+				chunk.Entries = append(chunk.Entries, stateEntry{Account: hash, Slot: storageIt.Hash(), Storage: true, Value: slot})
+				size += 2*common.HashLength + len(slot)
+				slots++
+
+				if err := flush(false); err != nil {
+					storageIt.Release()
+					return err
+				}
+			}
+			err = storageIt.Error()
+			storageIt.Release()
+			if err != nil {
+				return err
+			}
+		}
+		if err := flush(false); err != nil {
+			return err
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Exporting state", "at", hash, "accounts", accounts, "slots", slots, "codes", len(codes),
+				"elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := acctIt.Error(); err != nil {
+		return err
+	}
+	if err := flush(true); err != nil {
+		return err
+	}
+	log.Info("Exported state", "root", root, "accounts", accounts, "slots", slots, "codes", len(codes),
+		"elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// ExportState writes the flat state of root into w: a StateExportHeader
+// followed by checksummed chunks of accounts, storage slots and codes.
+func ExportState(snaptree *Tree, db kaidb.KeyValueReader, root common.Hash, height uint64, w io.Writer) error {
+	header := &StateExportHeader{Version: StateExportVersion, Root: root, Height: height}
+	if err := rlp.Encode(w, header); err != nil {
+		return err
+	}
+	return ExportStateChunks(snaptree, db, root, func(payload []byte) error {
+		return rlp.Encode(w, &chunkEnvelope{Payload: payload, Checksum: crypto.Keccak256Hash(payload)})
+	})
+}
+
+// StateImporter rebuilds a state trie from exported chunks, which must be
+// applied in order. Trie nodes and codes are written to the database as they
+// are produced, so an import that fails midway leaves dangling data behind.
+type StateImporter struct {
+	db     kaidb.Database
+	batch  kaidb.Batch
+	writer trie.NodeWriteFunc
+
+	accountTrie *trie.StackTrie
+	storageTrie *trie.StackTrie
+	account     *types.StateAccount // Account whose storage is being imported
+	accountHash common.Hash
+	lastSlot    common.Hash
+
+	accounts, slots, codes uint64
+}
+
+// NewStateImporter creates an importer writing the trie nodes into db with
+// the given state scheme.
+func NewStateImporter(db kaidb.Database, scheme string) *StateImporter {
+	batch := db.NewBatch()
+	writer := func(owner common.Hash, path []byte, hash common.Hash, blob []byte) {
+		rawdb.WriteTrieNode(batch, owner, path, hash, blob, scheme)
+	}
+	return &StateImporter{
+		db:          db,
+		batch:       batch,
+		writer:      writer,
+		accountTrie: trie.NewStackTrie(writer),
+	}
+}
+
+// ApplyChunk imports an encoded chunk produced by ExportStateChunks.
+func (imp *StateImporter) ApplyChunk(payload []byte) error {
+	var chunk stateChunk
+	if err := rlp.DecodeBytes(payload, &chunk); err != nil {
+		return fmt.Errorf("invalid state chunk: %w", err)
+	}
+	for _, code := range chunk.Codes {
+		imp.AddCode(code)
+	}
+	for _, entry := range chunk.Entries {
+		var err error
+		if entry.Storage {
+			err = imp.AddStorage(entry.Account, entry.Slot, entry.Value)
+		} else {
+			err = imp.AddAccount(entry.Account, entry.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return imp.Flush()
+}
+
+// AddAccount imports the next account, in slim or full RLP format. Accounts
+// must be added in hash order, each followed by all of its storage slots.
+func (imp *StateImporter) AddAccount(hash common.Hash, data []byte) error {
+	if imp.account != nil && bytes.Compare(hash[:], imp.accountHash[:]) <= 0 {
+		return fmt.Errorf("account %x out of order", hash)
+	}
+	if err := imp.finishAccount(); err != nil {
+		return err
+	}
+	account, err := types.FullAccount(data)
+	if err != nil {
+		return fmt.Errorf("invalid account %x: %w", hash, err)
+	}
+	imp.account, imp.accountHash = account, hash
+	imp.accounts++
+	return nil
+}
+
+// AddStorage imports the next storage slot of the last added account.
+func (imp *StateImporter) AddStorage(account, slot common.Hash, value []byte) error {
+	if imp.account == nil || account != imp.accountHash {
+		return fmt.Errorf("storage slot %x of account %x out of order", slot, account)
+	}
+	if imp.storageTrie == nil {
+		imp.storageTrie = trie.NewStackTrieWithOwner(imp.writer, imp.accountHash)
+	} else if bytes.Compare(slot[:], imp.lastSlot[:]) <= 0 {
+		return fmt.Errorf("storage slot %x of account %x out of order", slot, account)
+	}
+	if err := imp.storageTrie.Update(slot[:], value); err != nil {
+		return err
+	}
+	imp.lastSlot = slot
+	imp.slots++
+	return nil
+}
+
+// AddCode imports a contract code.
+func (imp *StateImporter) AddCode(code []byte) {
+	rawdb.WriteCode(imp.batch, crypto.Keccak256Hash(code), code)
+	imp.codes++
+}
+
+// Flush writes the pending data out once the batch has grown large enough.
+// ApplyChunk calls it after each chunk, users of the Add methods should call
+// it periodically.
+func (imp *StateImporter) Flush() error {
+	if imp.batch.ValueSize() > kaidb.IdealBatchSize {
+		if err := imp.batch.Write(); err != nil {
+			return err
+		}
+		imp.batch.Reset()
+	}
+	return nil
+}
+
+// finishAccount completes the storage trie of the pending account, checks it
+// against the storage root of the account and inserts it into the account trie.
+func (imp *StateImporter) finishAccount() error {
+	if imp.account == nil {
+		return nil
+	}
+	root := types.EmptyRootHash
+	if imp.storageTrie != nil {
+		var err error
+		if root, err = imp.storageTrie.Commit(); err != nil {
+			return err
+		}
+	}
+	if root != imp.account.Root {
+		return fmt.Errorf("storage root mismatch of account %x: have %x, want %x", imp.accountHash, root, imp.account.Root)
+	}
+	data, err := rlp.EncodeToBytes(imp.account)
+	if err != nil {
+		return err
+	}
+	if err := imp.accountTrie.Update(imp.accountHash[:], data); err != nil {
+		return err
+	}
+	imp.account, imp.storageTrie = nil, nil
+	return nil
+}
+
+// Finish completes the import, flushes the remaining data and checks that the
+// rebuilt state matches root.
+func (imp *StateImporter) Finish(root common.Hash) error {
+	if err := imp.finishAccount(); err != nil {
+		return err
+	}
+	have, err := imp.accountTrie.Commit()
+	if err != nil {
+		return err
+	}
+	if err := imp.batch.Write(); err != nil {
+		return err
+	}
+	imp.batch.Reset()
+	if have != root {
+		return fmt.Errorf("state root mismatch: have %x, want %x", have, root)
+	}
+	log.Info("Imported state", "root", root, "accounts", imp.accounts, "slots", imp.slots, "codes", imp.codes)
+	return nil
+}
+
+// ImportState rebuilds into db the state exported by ExportState, verifying
+// each chunk checksum, each storage root and finally the state root.
+func ImportState(db kaidb.Database, scheme string, r io.Reader) (*StateExportHeader, error) {
+	stream := rlp.NewStream(r, 0)
+
+	var header StateExportHeader
+	if err := stream.Decode(&header); err != nil {
+		return nil, fmt.Errorf("invalid state export header: %w", err)
+	}
+	if header.Version != StateExportVersion {
+		return nil, fmt.Errorf("unsupported state export version %d", header.Version)
+	}
+	var (
+		imp    = NewStateImporter(db, scheme)
+		start  = time.Now()
+		logged = time.Now()
+	)
+	for index := 0; ; index++ {
+		var env chunkEnvelope
+		if err := stream.Decode(&env); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read chunk %d: %w", index, err)
+		}
+		if crypto.Keccak256Hash(env.Payload) != env.Checksum {
+			return nil, fmt.Errorf("chunk %d: %w", index, errChunkChecksum)
+		}
+		if err := imp.ApplyChunk(env.Payload); err != nil {
+			return nil, fmt.Errorf("chunk %d: %w", index, err)
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Importing state", "chunks", index+1, "accounts", imp.accounts, "slots", imp.slots,
+				"elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := imp.Finish(header.Root); err != nil {
+		return nil, err
+	}
+	return &header, nil
+}
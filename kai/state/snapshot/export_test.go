@@ -0,0 +1,170 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
+	"github.com/kardiachain/go-kardia/kai/rawdb"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/crypto"
+	"github.com/kardiachain/go-kardia/lib/rlp"
+	"github.com/kardiachain/go-kardia/trie"
+	"github.com/kardiachain/go-kardia/trie/trienode"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// makeExportState commits a state of accounts, some of them contracts with
+// storage, into a fresh database and returns its snapshot tree.
+func makeExportState(t *testing.T) (*memorydb.Database, *Tree, common.Hash) {
+	db := memorydb.New()
+	triedb := trie.NewDatabase(db)
+	accTrie := trie.NewEmpty(triedb)
+	nodes := trienode.NewMergedNodeSet()
+
+	for i := 0; i < 20; i++ {
+		hash := crypto.Keccak256Hash([]byte{byte(i)})
+		account := &types.StateAccount{Nonce: uint64(i), Balance: big.NewInt(int64(i)), Root: types.EmptyRootHash, CodeHash: types.EmptyCodeHash.Bytes()}
+		if i%4 == 0 {
+			code := []byte{byte(i), 0x60, 0x00}
+			rawdb.WriteCode(db, crypto.Keccak256Hash(code), code)
+			account.CodeHash = crypto.Keccak256(code)
+
+			stTrie, err := trie.New(trie.StorageTrieID(types.EmptyRootHash, hash, types.EmptyRootHash), triedb)
+			require.NoError(t, err)
+			for j := 0; j <= i; j++ {
+				value, err := rlp.EncodeToBytes([]byte{byte(i), byte(j + 1)})
+				require.NoError(t, err)
+				require.NoError(t, stTrie.Update(crypto.Keccak256([]byte{byte(j)}), value))
+			}
+			root, set := stTrie.Commit(false)
+			require.NoError(t, nodes.Merge(set))
+			account.Root = root
+		}
+		data, err := rlp.EncodeToBytes(account)
+		require.NoError(t, err)
+		require.NoError(t, accTrie.Update(hash[:], data))
+	}
+	root, set := accTrie.Commit(false)
+	require.NoError(t, nodes.Merge(set))
+	require.NoError(t, triedb.Update(root, types.EmptyRootHash, nodes))
+	require.NoError(t, triedb.Commit(root, false))
+
+	snaptree, err := New(Config{CacheSize: 16}, db, triedb, root)
+	require.NoError(t, err)
+	return db, snaptree, root
+}
+
+func TestStateExportImport(t *testing.T) {
+	db, snaptree, root := makeExportState(t)
+	var buf bytes.Buffer
+	require.NoError(t, ExportState(snaptree, db, root, 7, &buf))
+
+	imported := memorydb.New()
+	header, err := ImportState(imported, trie.NewDatabase(imported).Scheme(), &buf)
+	require.NoError(t, err)
+	assert.Equal(t, root, header.Root)
+	assert.EqualValues(t, 7, header.Height)
+
+	// The imported state reads the same as the exported one.
+	want, err := trie.New(trie.StateTrieID(root), trie.NewDatabase(db))
+	require.NoError(t, err)
+	have, err := trie.New(trie.StateTrieID(root), trie.NewDatabase(imported))
+	require.NoError(t, err)
+	for i := 0; i < 20; i++ {
+		key := crypto.Keccak256([]byte{byte(i)})
+		data, err := have.Get(key)
+		require.NoError(t, err)
+		wantData, err := want.Get(key)
+		require.NoError(t, err)
+		assert.Equal(t, wantData, data)
+
+		account, err := types.FullAccount(data)
+		require.NoError(t, err)
+		if codeHash := common.BytesToHash(account.CodeHash); codeHash != types.EmptyCodeHash {
+			assert.NotEmpty(t, rawdb.ReadCode(imported, codeHash), "code of account %d", i)
+		}
+	}
+}
+
+// exportChunks returns the header and the chunks of the export of root, the
+// entries split in two chunks.
+func exportChunks(t *testing.T) (*StateExportHeader, []*chunkEnvelope) {
+	db, snaptree, root := makeExportState(t)
+	var payloads [][]byte
+	require.NoError(t, ExportStateChunks(snaptree, db, root, func(payload []byte) error {
+		payloads = append(payloads, payload)
+		return nil
+	}))
+	require.Len(t, payloads, 1)
+
+	var chunk stateChunk
+	require.NoError(t, rlp.DecodeBytes(payloads[0], &chunk))
+	half := len(chunk.Entries) / 2
+	for chunk.Entries[half].Storage {
+		half++
+	}
+	var envs []*chunkEnvelope
+	for _, c := range []stateChunk{{Entries: chunk.Entries[:half], Codes: chunk.Codes}, {Entries: chunk.Entries[half:]}} {
+		payload, err := rlp.EncodeToBytes(&c)
+		require.NoError(t, err)
+		envs = append(envs, &chunkEnvelope{Payload: payload, Checksum: crypto.Keccak256Hash(payload)})
+	}
+	return &StateExportHeader{Version: StateExportVersion, Root: root, Height: 7}, envs
+}
+
+func encodeExport(t *testing.T, header *StateExportHeader, envs ...*chunkEnvelope) *bytes.Buffer {
+	var buf bytes.Buffer
+	require.NoError(t, rlp.Encode(&buf, header))
+	for _, env := range envs {
+		require.NoError(t, rlp.Encode(&buf, env))
+	}
+	return &buf
+}
+
+func TestStateImportChunks(t *testing.T) {
+	header, envs := exportChunks(t)
+	db := memorydb.New()
+	_, err := ImportState(db, trie.NewDatabase(db).Scheme(), encodeExport(t, header, envs...))
+	require.NoError(t, err)
+
+	// A chunk not matching its checksum.
+	corrupted := *envs[1]
+	corrupted.Checksum[0] ^= 0xff
+	db = memorydb.New()
+	_, err = ImportState(db, trie.NewDatabase(db).Scheme(), encodeExport(t, header, envs[0], &corrupted))
+	assert.True(t, errors.Is(err, errChunkChecksum), "have %v", err)
+
+	// Chunks out of order.
+	db = memorydb.New()
+	_, err = ImportState(db, trie.NewDatabase(db).Scheme(), encodeExport(t, header, envs[1], envs[0]))
+	assert.Error(t, err)
+
+	// A missing chunk.
+	db = memorydb.New()
+	_, err = ImportState(db, trie.NewDatabase(db).Scheme(), encodeExport(t, header, envs[0]))
+	assert.Error(t, err)
+}
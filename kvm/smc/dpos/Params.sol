@@ -46,7 +46,21 @@ contract Params is Ownable {
 
         // Proposal
         Deposit,
-        VotingPeriod
+        VotingPeriod,
+
+        // consensus params : 20, unset (zero) keeps the value of the chain
+        blockMaxBytes,
+        blockMaxGas,
+        evidenceMaxAgeNumBlocks,
+        evidenceMaxAgeDuration, // seconds
+        evidenceMaxBytes,
+        timeoutPropose, // milliseconds
+        timeoutProposeDelta,
+        timeoutPrevote,
+        timeoutPrevoteDelta,
+        timeoutPrecommit,
+        timeoutPrecommitDelta,
+        timeoutCommit
     }
 
     using SafeMath for uint256;
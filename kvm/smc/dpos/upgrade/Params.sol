@@ -0,0 +1,307 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+/**
+ * @dev ../Params.sol ported to solidity 0.8, with the getProposalDetails of
+ * the Galaxias Params. Its runtime code replaces the code of the running
+ * Params contract at the ParamsUpgrade fork, keeping the storage, so the
+ * storage layout must stay the one of ../Params.sol. The file is
+ * self-contained, paramsupgrade.ParamsUpgradeSMCBytecode is built with solc 0.8.21:
+ *
+ *   solc --optimize --evm-version istanbul --bin-runtime Params.sol
+ */
+contract Ownable {
+    address private _owner;
+
+    event OwnershipTransferred(address indexed previousOwner, address indexed newOwner);
+
+    function owner() public view returns (address) {
+        return _owner;
+    }
+
+    modifier onlyOwner() {
+        require(_owner == address(0x0) || _owner == msg.sender, "Ownable: caller is not the owner");
+        _;
+    }
+
+    function renounceOwnership() public onlyOwner {
+        emit OwnershipTransferred(_owner, address(0));
+        _owner = address(0);
+    }
+
+    function transferOwnership(address newOwner) public onlyOwner {
+        require(newOwner != address(0), "Ownable: new owner is the zero address");
+        emit OwnershipTransferred(_owner, newOwner);
+        _owner = newOwner;
+    }
+}
+
+interface IStaking {
+    function getValidatorSets() external view returns (address[] memory, uint256[] memory);
+    function treasury() external view returns(address);
+}
+
+contract Params is Ownable {
+
+    enum ProposalStatus {
+        Pending,
+        Passed,
+        Rejected
+    }
+
+    enum VoteOption {
+        Abstain,
+        Yes,
+        No
+    }
+
+    enum ParamKey {
+        // staking params
+        baseProposerReward,
+        bonusProposerReward,
+        maxProposers,
+
+        // validator params :3
+        downtimeJailDuration,
+        slashFractionDowntime,
+        unbondingTime,
+        slashFractionDoubleSign,
+        signedBlockWindow,
+        minSignedPerWindow,
+        minStake,
+        minValidatorStake,
+        minAmountChangeName,
+        minSelfDelegation,
+
+        // minter params : 13
+        inflationRateChange,
+        goalBonded,
+        blocksPerYear,
+        inflationMax,
+        inflationMin,
+
+        // Proposal
+        Deposit,
+        VotingPeriod,
+
+        // consensus params : 20, unset (zero) keeps the value of the chain
+        blockMaxBytes,
+        blockMaxGas,
+        evidenceMaxAgeNumBlocks,
+        evidenceMaxAgeDuration, // seconds
+        evidenceMaxBytes,
+        timeoutPropose, // milliseconds
+        timeoutProposeDelta,
+        timeoutPrevote,
+        timeoutPrevoteDelta,
+        timeoutPrecommit,
+        timeoutPrecommitDelta,
+        timeoutCommit
+    }
+
+    struct Proposal {
+        address payable proposer;
+        ParamKey[] keys;
+        uint256[] values;
+        uint256 startTime;
+        uint256 endTime;
+        mapping(address => VoteOption) votes;
+        uint256 deposit;
+        ProposalStatus status;
+        mapping (uint=>uint256) results;
+    }
+
+    IStaking private _staking;
+    mapping(uint256 => uint256) public params;
+    Proposal[] public proposals;
+
+    // The constructor doesn't run at the fork, the params are kept.
+    constructor() {
+        _staking = IStaking(msg.sender);
+    }
+
+    function _setParam(ParamKey key, uint256 value) internal {
+        params[uint256(key)] = value;
+    }
+
+    function _getParam(ParamKey key) private view returns (uint256) {
+        return params[uint256(key)];
+    }
+
+    function getParam(ParamKey key) public view returns (uint256) {
+        return _getParam(key);
+    }
+
+    function allProposal() public view returns (uint) {
+        return proposals.length;
+    }
+
+    function addProposal(ParamKey[] memory keys, uint256[] memory values) public payable returns (uint) {
+        require(msg.value >= _getParam(ParamKey.Deposit), "min deposit");
+        Proposal storage proposal = proposals.push();
+        proposal.keys = keys;
+        proposal.values = values;
+        proposal.proposer = payable(msg.sender);
+        proposal.deposit = msg.value;
+        proposal.startTime = block.timestamp;
+        proposal.endTime = block.timestamp + _getParam(ParamKey.VotingPeriod);
+        proposal.status = ProposalStatus.Pending;
+        return proposals.length - 1;
+    }
+
+    function addVote(uint proposalId, VoteOption option) public {
+        require(proposalId < proposals.length, "proposal not found");
+        require(proposals[proposalId].endTime > block.timestamp, "inactive proposal");
+        proposals[proposalId].votes[msg.sender] = option;
+    }
+    function confirmProposal(uint proposalId) public {
+        require(proposalId < proposals.length, "proposal not found");
+        require(proposals[proposalId].status == ProposalStatus.Pending, "proposal status pending");
+        require(proposals[proposalId].endTime < block.timestamp, "Inactive proposal");
+
+        Proposal storage proposal = proposals[proposalId];
+        uint256 voteYes;
+        uint256 voteNo;
+        uint256 voteAbsent;
+        (voteYes,  voteNo, voteAbsent) = _getProposalPendingResults(proposalId);
+        // update result
+        proposal.results[uint(VoteOption.Yes)] = voteYes;
+        proposal.results[uint(VoteOption.No)] = voteNo;
+        proposal.results[uint(VoteOption.Abstain)] = voteAbsent;
+        uint256 totalVotingPowers = voteYes + voteNo + voteAbsent;
+        uint256 quorum = totalVotingPowers * 2 / 3 + 1;
+        if (voteYes < quorum) {
+            proposal.status = ProposalStatus.Rejected;
+            payable(_staking.treasury()).transfer(proposal.deposit);
+            return;
+        }
+
+        // update params
+        for (uint i = 0; i < proposal.keys.length; i ++) {
+            _setParam(proposal.keys[i], proposal.values[i]);
+        }
+        // refund deposit
+        proposal.proposer.transfer(proposals[proposalId].deposit);
+        proposal.status = ProposalStatus.Passed;
+    }
+
+    function _getProposalPendingResults(uint proposalId) private view returns (uint256, uint256, uint256) {
+        address[] memory signers;
+        uint256[] memory votingPowers;
+        (signers, votingPowers) = _staking.getValidatorSets();
+        uint256 totalPowerVoteYes;
+        uint256 totalPowerVoteNo;
+        uint256 totalPowerVoteAbsent;
+        for (uint i = 0; i < signers.length; i ++) {
+            VoteOption voteOption = proposals[proposalId].votes[signers[i]];
+            if (voteOption == VoteOption.Yes) {
+                totalPowerVoteYes = totalPowerVoteYes + votingPowers[i];
+            } else if (voteOption == VoteOption.No) {
+                totalPowerVoteNo = totalPowerVoteNo + votingPowers[i];
+            } else {
+                totalPowerVoteAbsent = totalPowerVoteAbsent + votingPowers[i];
+            }
+        }
+        return (totalPowerVoteYes, totalPowerVoteNo, totalPowerVoteAbsent);
+    }
+
+    function getProposalResults(uint proposalId) public view returns (uint256, uint256, uint256) {
+        require(proposalId < proposals.length, "proposal not found");
+        if (proposals[proposalId].status == ProposalStatus.Pending) {
+            return _getProposalPendingResults(proposalId);
+        }
+        uint256 voteYes = proposals[proposalId].results[uint(VoteOption.Yes)];
+        uint256 voteNo = proposals[proposalId].results[uint(VoteOption.No)];
+        uint256 voteAbstain = proposals[proposalId].results[uint(VoteOption.Abstain)];
+        return (
+            voteYes,
+            voteNo,
+            voteAbstain
+        );
+    }
+
+    // getProposalDetails of the Galaxias Params contract.
+    function getProposalDetails(uint proposalId) external view returns (uint256, uint256, uint256, ParamKey[] memory, uint256[] memory) {
+        (uint256 voteYes, uint256 voteNo, uint256 voteAbstain) = getProposalResults(proposalId);
+        return (
+            voteYes,
+            voteNo,
+            voteAbstain,
+            proposals[proposalId].keys,
+            proposals[proposalId].values
+        );
+    }
+
+
+    function getBaseProposerReward() external view returns (uint256) {
+        return _getParam(ParamKey.baseProposerReward);
+    }
+
+    function getBonusProposerReward() external view returns (uint256) {
+        return _getParam(ParamKey.bonusProposerReward);
+    }
+
+    function getMaxProposers() external view returns (uint256) {
+        return _getParam(ParamKey.maxProposers);
+    }
+
+    function getInflationRateChange() external view returns (uint256) {
+        return _getParam(ParamKey.inflationRateChange);
+    }
+
+    function getGoalBonded() external view returns (uint256) {
+        return _getParam(ParamKey.goalBonded);
+    }
+
+    function getBlocksPerYear() external view returns (uint256) {
+        return _getParam(ParamKey.blocksPerYear);
+    }
+
+    function getInflationMax() external view returns (uint256) {
+        return _getParam(ParamKey.inflationMax);
+    }
+
+    function getInflationMin() external view returns (uint256) {
+        return _getParam(ParamKey.inflationMin);
+    }
+
+    function getDowntimeJailDuration() external view returns (uint256) {
+        return _getParam(ParamKey.downtimeJailDuration);
+    }
+
+    function getSlashFractionDowntime() external view returns (uint256) {
+        return _getParam(ParamKey.slashFractionDowntime);
+    }
+
+    function getUnbondingTime() external view returns (uint256) {
+       return _getParam(ParamKey.unbondingTime);
+    }
+
+    function getSlashFractionDoubleSign() external view returns (uint256) {
+        return _getParam(ParamKey.slashFractionDoubleSign);
+    }
+
+    function getSignedBlockWindow() external view returns (uint256) {
+        return _getParam(ParamKey.signedBlockWindow);
+    }
+
+    function getMinSignedPerWindow() external view returns (uint256) {
+       return _getParam(ParamKey.minSignedPerWindow);
+    }
+
+    function getMinStake() external view returns (uint256) {
+       return _getParam(ParamKey.minStake);
+    }
+
+    function getMinValidatorStake() external view returns (uint256) {
+        return _getParam(ParamKey.minValidatorStake);
+    }
+
+    function getMinAmountChangeName() external view returns (uint256) {
+        return _getParam(ParamKey.minAmountChangeName);
+    }
+
+    function getMinSelfDelegation() external view returns (uint256) {
+        return _getParam(ParamKey.minSelfDelegation);
+    }
+}
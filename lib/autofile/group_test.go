@@ -173,6 +173,32 @@ func TestRotateFile(t *testing.T) {
 	destroyTestGroup(t, g)
 }
 
+func TestRemoveFilesBefore(t *testing.T) {
+	g := createTestGroupWithHeadSizeLimit(t, 0)
+	defer destroyTestGroup(t, g)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, g.WriteLine("Line"))
+		require.NoError(t, g.FlushAndSync())
+		g.RotateFile()
+	}
+	require.NoError(t, g.WriteLine("Head"))
+	require.NoError(t, g.FlushAndSync())
+
+	require.NoError(t, g.RemoveFilesBefore(2))
+	assert.Equal(t, 2, g.MinIndex())
+	assertGroupInfo(t, g.ReadGroupInfo(), 2, 3, 10, 5)
+	_, err := os.Stat(g.Head.Path + ".001")
+	assert.True(t, os.IsNotExist(err))
+
+	// The head is kept.
+	require.NoError(t, g.RemoveFilesBefore(3))
+	assert.Equal(t, 3, g.MinIndex())
+	_, err = os.Stat(g.Head.Path)
+	assert.NoError(t, err)
+	assert.Error(t, g.RemoveFilesBefore(4))
+}
+
 func TestWrite(t *testing.T) {
 	g := createTestGroupWithHeadSizeLimit(t, 0)
 
@@ -30,19 +30,25 @@ var (
 	typeCounterTpl         = "# TYPE %s counter\n"
 	typeSummaryTpl         = "# TYPE %s summary\n"
 	keyValueTpl            = "%s %v\n\n"
-	keyQuantileTagValueTpl = "%s {quantile=\"%s\"} %v\n"
+	keyQuantileTagValueTpl = "%s {%squantile=\"%s\"} %v\n"
 )
 
 // collector is a collection of byte buffers that aggregate Prometheus reports
 // for different metric types.
+//
+// Metric names may end with Prometheus labels, e.g. `p2p/send{channel="state"}`.
+// The metrics of a family are then reported under a single TYPE line, which
+// relies on the names being sorted so the family is listed contiguously.
 type collector struct {
-	buff *bytes.Buffer
+	buff  *bytes.Buffer
+	typed map[string]bool // families whose TYPE line was written
 }
 
 // newCollector creates a new Prometheus metric aggregator.
 func newCollector() *collector {
 	return &collector{
-		buff: &bytes.Buffer{},
+		buff:  &bytes.Buffer{},
+		typed: make(map[string]bool),
 	}
 }
 
@@ -62,7 +68,7 @@ func (c *collector) addHistogram(name string, m metrics.Histogram) {
 	pv := []float64{0.5, 0.75, 0.95, 0.99, 0.999, 0.9999}
 	ps := m.Percentiles(pv)
 	c.writeSummaryCounter(name, m.Count())
-	c.buff.WriteString(fmt.Sprintf(typeSummaryTpl, mutateKey(name)))
+	c.writeType(typeSummaryTpl, name)
 	for i := range pv {
 		c.writeSummaryPercentile(name, strconv.FormatFloat(pv[i], 'f', -1, 64), ps[i])
 	}
@@ -77,7 +83,7 @@ func (c *collector) addTimer(name string, m metrics.Timer) {
 	pv := []float64{0.5, 0.75, 0.95, 0.99, 0.999, 0.9999}
 	ps := m.Percentiles(pv)
 	c.writeSummaryCounter(name, m.Count())
-	c.buff.WriteString(fmt.Sprintf(typeSummaryTpl, mutateKey(name)))
+	c.writeType(typeSummaryTpl, name)
 	for i := range pv {
 		c.writeSummaryPercentile(name, strconv.FormatFloat(pv[i], 'f', -1, 64), ps[i])
 	}
@@ -91,7 +97,7 @@ func (c *collector) addResettingTimer(name string, m metrics.ResettingTimer) {
 	ps := m.Percentiles([]float64{50, 95, 99})
 	val := m.Values()
 	c.writeSummaryCounter(name, len(val))
-	c.buff.WriteString(fmt.Sprintf(typeSummaryTpl, mutateKey(name)))
+	c.writeType(typeSummaryTpl, name)
 	c.writeSummaryPercentile(name, "0.50", ps[0])
 	c.writeSummaryPercentile(name, "0.95", ps[1])
 	c.writeSummaryPercentile(name, "0.99", ps[2])
@@ -99,22 +105,47 @@ func (c *collector) addResettingTimer(name string, m metrics.ResettingTimer) {
 }
 
 func (c *collector) writeGaugeCounter(name string, value interface{}) {
-	name = mutateKey(name)
-	c.buff.WriteString(fmt.Sprintf(typeGaugeTpl, name))
-	c.buff.WriteString(fmt.Sprintf(keyValueTpl, name, value))
+	c.writeType(typeGaugeTpl, name)
+	c.buff.WriteString(fmt.Sprintf(keyValueTpl, mutateKey(name), value))
 }
 
 func (c *collector) writeSummaryCounter(name string, value interface{}) {
-	name = mutateKey(name + "_count")
-	c.buff.WriteString(fmt.Sprintf(typeCounterTpl, name))
-	c.buff.WriteString(fmt.Sprintf(keyValueTpl, name, value))
+	family, labels := splitLabels(name)
+	name = family + "_count" + labels
+	c.writeType(typeCounterTpl, name)
+	c.buff.WriteString(fmt.Sprintf(keyValueTpl, mutateKey(name), value))
 }
 
 func (c *collector) writeSummaryPercentile(name, p string, value interface{}) {
-	name = mutateKey(name)
-	c.buff.WriteString(fmt.Sprintf(keyQuantileTagValueTpl, name, p, value))
+	family, labels := splitLabels(name)
+	if labels != "" {
+		labels = labels[1:len(labels)-1] + ","
+	}
+	c.buff.WriteString(fmt.Sprintf(keyQuantileTagValueTpl, mutateKey(family), labels, p, value))
+}
+
+// writeType writes the TYPE line of the family of the named metric, unless it
+// was already written.
+func (c *collector) writeType(tpl, name string) {
+	family, _ := splitLabels(name)
+	family = mutateKey(family)
+	if c.typed[family] {
+		return
+	}
+	c.typed[family] = true
+	c.buff.WriteString(fmt.Sprintf(tpl, family))
+}
+
+// splitLabels splits a metric name into its family and its labels, including
+// the braces.
+func splitLabels(name string) (string, string) {
+	if i := strings.IndexByte(name, '{'); i >= 0 && strings.HasSuffix(name, "}") {
+		return name[:i], name[i:]
+	}
+	return name, ""
 }
 
 func mutateKey(key string) string {
-	return strings.ReplaceAll(key, "/", "_")
+	family, labels := splitLabels(key)
+	return strings.ReplaceAll(family, "/", "_") + labels
 }
@@ -108,3 +108,40 @@ test_resetting_timer {quantile="0.99"} 120000000
 		t.Fatal("unexpected collector output")
 	}
 }
+
+func TestCollectorLabels(t *testing.T) {
+	c := newCollector()
+
+	for i, channel := range []string{"block", "state"} {
+		gauge := metrics.NewGauge()
+		gauge.Update(int64(i + 1))
+		c.addGauge(`test/gauge{channel="`+channel+`"}`, gauge)
+	}
+	timer := metrics.NewTimer()
+	defer timer.Stop()
+	timer.Update(10 * time.Millisecond)
+	c.addTimer(`test/timer{peer="a/b"}`, timer)
+
+	const expectedOutput = `# TYPE test_gauge gauge
+test_gauge{channel="block"} 1
+
+test_gauge{channel="state"} 2
+
+# TYPE test_timer_count counter
+test_timer_count{peer="a/b"} 1
+
+# TYPE test_timer summary
+test_timer {peer="a/b",quantile="0.5"} 1e+07
+test_timer {peer="a/b",quantile="0.75"} 1e+07
+test_timer {peer="a/b",quantile="0.95"} 1e+07
+test_timer {peer="a/b",quantile="0.99"} 1e+07
+test_timer {peer="a/b",quantile="0.999"} 1e+07
+test_timer {peer="a/b",quantile="0.9999"} 1e+07
+
+`
+	if exp := c.buff.String(); exp != expectedOutput {
+		t.Log("Expected Output:\n", expectedOutput)
+		t.Log("Actual Output:\n", exp)
+		t.Fatal("unexpected collector output")
+	}
+}
@@ -82,6 +82,7 @@ type MConnection struct {
 	bufConnWriter *bufio.Writer
 	sendMonitor   *flow.Monitor
 	recvMonitor   *flow.Monitor
+	recvStats     *flow.Monitor // received bytes, kept apart from the throttle of recvMonitor
 	send          chan struct{}
 	pong          chan struct{}
 	channels      []*Channel
@@ -180,6 +181,7 @@ func NewMConnectionWithConfig(
 		bufConnWriter: bufio.NewWriterSize(conn, minWriteBufferSize),
 		sendMonitor:   flow.New(0, 0),
 		recvMonitor:   flow.New(0, 0),
+		recvStats:     flow.New(0, 0),
 		send:          make(chan struct{}, 1),
 		pong:          make(chan struct{}, 1),
 		onReceive:     onReceive,
@@ -584,6 +586,12 @@ FOR_LOOP:
 		var packet kp2p.Packet
 
 		err := protoReader.ReadMsg(&packet)
+		if err == nil {
+			// Only measured: updating recvMonitor would start throttling
+			// the connection to RecvRate.
+			n := packet.Size()
+			c.recvStats.Update(n + proto.SizeVarint(uint64(n)))
+		}
 		if err != nil {
 			// stopServices was invoked and we are shutting down
 			// receiving is excpected to fail since we will close the connection
@@ -688,25 +696,42 @@ type ConnectionStatus struct {
 
 type ChannelStatus struct {
 	ID                byte
+	Name              string
 	SendQueueCapacity int
 	SendQueueSize     int
 	Priority          int
 	RecentlySent      int64
+
+	// Current rates, in bytes per second, and totals of the message bytes
+	// sent and received on the channel.
+	SendRate  int64
+	RecvRate  int64
+	SentBytes int64
+	RecvBytes int64
+	SentMsgs  int64
+	RecvMsgs  int64
 }
 
 func (c *MConnection) Status() ConnectionStatus {
 	var status ConnectionStatus
 	status.Duration = time.Since(c.created)
 	status.SendMonitor = c.sendMonitor.Status()
-	status.RecvMonitor = c.recvMonitor.Status()
+	status.RecvMonitor = c.recvStats.Status()
 	status.Channels = make([]ChannelStatus, len(c.channels))
 	for i, channel := range c.channels {
 		status.Channels[i] = ChannelStatus{
 			ID:                channel.desc.ID,
+			Name:              channel.name(),
 			SendQueueCapacity: cap(channel.sendQueue),
 			SendQueueSize:     int(atomic.LoadInt32(&channel.sendQueueSize)),
 			Priority:          channel.desc.Priority,
 			RecentlySent:      atomic.LoadInt64(&channel.recentlySent),
+			SendRate:          channel.sendMonitor.Status().CurRate,
+			RecvRate:          channel.recvMonitor.Status().CurRate,
+			SentBytes:         atomic.LoadInt64(&channel.sentBytes),
+			RecvBytes:         atomic.LoadInt64(&channel.recvBytes),
+			SentMsgs:          atomic.LoadInt64(&channel.sentMsgs),
+			RecvMsgs:          atomic.LoadInt64(&channel.recvMsgs),
 		}
 	}
 	return status
@@ -718,6 +743,10 @@ type ChannelDescriptor struct {
 	ID       byte
 	Priority int
 
+	// Name identifies the channel in statistics and metrics. It defaults to
+	// the hex encoded ID.
+	Name string
+
 	SendQueueCapacity   int
 	RecvMessageCapacity int
 
@@ -751,6 +780,15 @@ type Channel struct {
 	sending       []byte
 	recentlySent  int64 // exponential moving average
 
+	// message bytes and counts, the meters aggregate them over all peers
+	sendMonitor *flow.Monitor
+	recvMonitor *flow.Monitor
+	sentBytes   int64 // atomic.
+	recvBytes   int64 // atomic.
+	sentMsgs    int64 // atomic.
+	recvMsgs    int64 // atomic.
+	meters      *channelMeters
+
 	maxPacketMsgPayloadSize int
 
 	Logger log.Logger
@@ -761,13 +799,25 @@ func newChannel(conn *MConnection, desc ChannelDescriptor) *Channel {
 	if desc.Priority <= 0 {
 		panic("Channel default priority must be a positive integer")
 	}
-	return &Channel{
+	ch := &Channel{
 		conn:                    conn,
 		desc:                    desc,
 		sendQueue:               make(chan []byte, desc.SendQueueCapacity),
 		recving:                 make([]byte, 0, desc.RecvBufferCapacity),
+		sendMonitor:             flow.New(0, 0),
+		recvMonitor:             flow.New(0, 0),
 		maxPacketMsgPayloadSize: conn.config.MaxPacketMsgPayloadSize,
 	}
+	ch.meters = getChannelMeters(ch.name())
+	return ch
+}
+
+// name returns the name of the channel in statistics and metrics.
+func (ch *Channel) name() string {
+	if ch.desc.Name != "" {
+		return ch.desc.Name
+	}
+	return fmt.Sprintf("%#x", ch.desc.ID)
 }
 
 func (ch *Channel) SetLogger(l log.Logger) {
@@ -830,10 +880,15 @@ func (ch *Channel) nextPacketMsg() kp2p.PacketMsg {
 	packet := kp2p.PacketMsg{ChannelID: int32(ch.desc.ID)}
 	maxSize := ch.maxPacketMsgPayloadSize
 	packet.Data = ch.sending[:kmath.MinInt(maxSize, len(ch.sending))]
+	ch.sendMonitor.Update(len(packet.Data))
+	atomic.AddInt64(&ch.sentBytes, int64(len(packet.Data)))
+	ch.meters.sentBytes.Mark(int64(len(packet.Data)))
 	if len(ch.sending) <= maxSize {
 		packet.EOF = true
 		ch.sending = nil
 		atomic.AddInt32(&ch.sendQueueSize, -1) // decrement sendQueueSize
+		atomic.AddInt64(&ch.sentMsgs, 1)
+		ch.meters.sentMsgs.Mark(1)
 	} else {
 		packet.EOF = false
 		ch.sending = ch.sending[kmath.MinInt(maxSize, len(ch.sending)):]
@@ -860,7 +915,12 @@ func (ch *Channel) recvPacketMsg(packet kp2p.PacketMsg) ([]byte, error) {
 		return nil, fmt.Errorf("received message exceeds available capacity: %v < %v", recvCap, recvReceived)
 	}
 	ch.recving = append(ch.recving, packet.Data...)
+	ch.recvMonitor.Update(len(packet.Data))
+	atomic.AddInt64(&ch.recvBytes, int64(len(packet.Data)))
+	ch.meters.recvBytes.Mark(int64(len(packet.Data)))
 	if packet.EOF {
+		atomic.AddInt64(&ch.recvMsgs, 1)
+		ch.meters.recvMsgs.Mark(1)
 		msgBytes := ch.recving
 		ch.recving = make([]byte, 0, ch.desc.RecvBufferCapacity)
 		return msgBytes, nil
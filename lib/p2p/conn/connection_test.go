@@ -163,6 +163,85 @@ func TestMConnectionStatus(t *testing.T) {
 	assert.Zero(t, status.Channels[0].SendQueueSize)
 }
 
+func TestMConnectionChannelStats(t *testing.T) {
+	server, client := NetPipe()
+	defer server.Close()
+	defer client.Close()
+
+	receivedCh := make(chan []byte)
+	onReceive := func(chID byte, msgBytes []byte) {
+		receivedCh <- msgBytes
+	}
+	mconn1 := createMConnectionWithCallbacks(client, onReceive, func(r interface{}) {})
+	err := mconn1.Start()
+	require.Nil(t, err)
+	defer mconn1.Stop() // nolint:errcheck // ignore for tests
+
+	mconn2 := createTestMConnection(server)
+	err = mconn2.Start()
+	require.Nil(t, err)
+	defer mconn2.Stop() // nolint:errcheck // ignore for tests
+
+	// Spans several packets
+	msg := make([]byte, 2500)
+	assert.True(t, mconn2.Send(0x01, msg))
+	select {
+	case <-receivedCh:
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("Did not receive the message in 500ms")
+	}
+
+	sent := mconn2.Status().Channels[0]
+	assert.Equal(t, "0x1", sent.Name)
+	assert.EqualValues(t, 1, sent.SentMsgs)
+	assert.EqualValues(t, len(msg), sent.SentBytes)
+	assert.Zero(t, sent.RecvMsgs)
+
+	recv := mconn1.Status().Channels[0]
+	assert.EqualValues(t, 1, recv.RecvMsgs)
+	assert.EqualValues(t, len(msg), recv.RecvBytes)
+	assert.Zero(t, recv.SentMsgs)
+
+	// The monitors only count complete samples, the packets are counted with
+	// their overhead
+	assert.Eventually(t, func() bool {
+		return mconn1.Status().RecvMonitor.Bytes > int64(len(msg))
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestMConnectionStatsDontThrottle(t *testing.T) {
+	server, client := NetPipe()
+	defer server.Close()
+	defer client.Close()
+
+	receivedCh := make(chan []byte)
+	onReceive := func(chID byte, msgBytes []byte) {
+		receivedCh <- msgBytes
+	}
+	// Reading the message at RecvRate would take 100s
+	cfg := DefaulKAIConnConfig()
+	cfg.RecvRate = 1000
+	chDescs := []*ChannelDescriptor{{ID: 0x01, Priority: 1, SendQueueCapacity: 1}}
+	mconn1 := NewMConnectionWithConfig(client, chDescs, onReceive, func(r interface{}) {}, cfg)
+	mconn1.SetLogger(log.TestingLogger())
+	err := mconn1.Start()
+	require.Nil(t, err)
+	defer mconn1.Stop() // nolint:errcheck // ignore for tests
+
+	mconn2 := createTestMConnection(server)
+	err = mconn2.Start()
+	require.Nil(t, err)
+	defer mconn2.Stop() // nolint:errcheck // ignore for tests
+
+	msg := make([]byte, 100000)
+	assert.True(t, mconn2.Send(0x01, msg))
+	select {
+	case <-receivedCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Did not receive the message in 2s")
+	}
+}
+
 func TestMConnectionPongTimeoutResultsInError(t *testing.T) {
 	server, client := net.Pipe()
 	defer server.Close()
@@ -0,0 +1,41 @@
+package conn
+
+import (
+	"fmt"
+
+	"github.com/kardiachain/go-kardia/lib/metrics"
+	ksync "github.com/kardiachain/go-kardia/lib/sync"
+)
+
+// channelMeters aggregate the traffic of a channel over all connections.
+type channelMeters struct {
+	sentBytes metrics.Meter
+	recvBytes metrics.Meter
+	sentMsgs  metrics.Meter
+	recvMsgs  metrics.Meter
+}
+
+var (
+	channelMetersMtx ksync.Mutex
+	channelMetersMap = make(map[string]*channelMeters)
+)
+
+// getChannelMeters returns the meters of the named channel, registering them
+// on first use.
+func getChannelMeters(name string) *channelMeters {
+	channelMetersMtx.Lock()
+	defer channelMetersMtx.Unlock()
+
+	if m, ok := channelMetersMap[name]; ok {
+		return m
+	}
+	label := fmt.Sprintf("{channel=%q}", name)
+	m := &channelMeters{
+		sentBytes: metrics.NewRegisteredMeter("p2p/channel/send/bytes"+label, nil),
+		recvBytes: metrics.NewRegisteredMeter("p2p/channel/recv/bytes"+label, nil),
+		sentMsgs:  metrics.NewRegisteredMeter("p2p/channel/send/msgs"+label, nil),
+		recvMsgs:  metrics.NewRegisteredMeter("p2p/channel/recv/msgs"+label, nil),
+	}
+	channelMetersMap[name] = m
+	return m
+}
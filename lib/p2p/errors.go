@@ -20,9 +20,11 @@ type ErrRejected struct {
 	err               error
 	id                ID
 	isAuthFailure     bool
+	isBanned          bool
 	isDuplicate       bool
 	isFiltered        bool
 	isIncompatible    bool
+	isLimited         bool
 	isNodeInfoInvalid bool
 	isSelf            bool
 }
@@ -37,6 +39,10 @@ func (e ErrRejected) Error() string {
 		return fmt.Sprintf("auth failure: %s", e.err)
 	}
 
+	if e.isBanned {
+		return fmt.Sprintf("banned ID<%v>: %s", e.id, e.err)
+	}
+
 	if e.isDuplicate {
 		if e.conn != nil {
 			return fmt.Sprintf(
@@ -67,6 +73,10 @@ func (e ErrRejected) Error() string {
 		return fmt.Sprintf("incompatible: %s", e.err)
 	}
 
+	if e.isLimited {
+		return fmt.Sprintf("limited CONN<%s>: %s", e.conn.RemoteAddr().String(), e.err)
+	}
+
 	if e.isNodeInfoInvalid {
 		return fmt.Sprintf("invalid NodeInfo: %s", e.err)
 	}
@@ -81,6 +91,9 @@ func (e ErrRejected) Error() string {
 // IsAuthFailure when Peer authentication was unsuccessful.
 func (e ErrRejected) IsAuthFailure() bool { return e.isAuthFailure }
 
+// IsBanned when the Peer is banned for misbehaving.
+func (e ErrRejected) IsBanned() bool { return e.isBanned }
+
 // IsDuplicate when Peer ID or IP are present already.
 func (e ErrRejected) IsDuplicate() bool { return e.isDuplicate }
 
@@ -90,6 +103,9 @@ func (e ErrRejected) IsFiltered() bool { return e.isFiltered }
 // IsIncompatible when Peer NodeInfo is not compatible with our own.
 func (e ErrRejected) IsIncompatible() bool { return e.isIncompatible }
 
+// IsLimited when the connection exceeds the inbound limits.
+func (e ErrRejected) IsLimited() bool { return e.isLimited }
+
 // IsNodeInfoInvalid when the sent NodeInfo is not valid.
 func (e ErrRejected) IsNodeInfoInvalid() bool { return e.isNodeInfoInvalid }
 
@@ -0,0 +1,238 @@
+package p2p
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/kardiachain/go-kardia/configs"
+	"github.com/kardiachain/go-kardia/lib/metrics"
+	ksync "github.com/kardiachain/go-kardia/lib/sync"
+)
+
+// sources tracked by the handshake rate limit before idle ones are pruned.
+const maxRateLimitedSources = 4096
+
+var (
+	inboundAdmittedMeter = metrics.NewRegisteredMeter("p2p/inbound/admitted", nil)
+
+	// Inbound connections refused by the admission control, by reason.
+	inboundDeniedMeter      = metrics.NewRegisteredMeter("p2p/inbound/rejected/denied", nil)
+	inboundRateLimitMeter   = metrics.NewRegisteredMeter("p2p/inbound/rejected/rate", nil)
+	inboundIPLimitMeter     = metrics.NewRegisteredMeter("p2p/inbound/rejected/ip", nil)
+	inboundSubnetLimitMeter = metrics.NewRegisteredMeter("p2p/inbound/rejected/subnet", nil)
+)
+
+// InboundLimits configures the admission control of inbound connections.
+// Zero values disable the corresponding limit.
+type InboundLimits struct {
+	// Maximum number of connections from a single IP.
+	MaxPerIP int
+	// Maximum number of connections from a single /24 (IPv4) or /64 (IPv6)
+	// subnet.
+	MaxPerSubnet int
+	// Handshakes per second allowed from a single IP, and the number of
+	// handshakes it may burst above that rate.
+	HandshakeRate  float64
+	HandshakeBurst int
+	// Connections from Allow are never limited, connections from Deny are
+	// always refused. Deny takes precedence.
+	Allow []*net.IPNet
+	Deny  []*net.IPNet
+}
+
+// InboundLimitsFromConfig returns the InboundLimits set in the P2PConfig.
+func InboundLimitsFromConfig(cfg *configs.P2PConfig) (InboundLimits, error) {
+	allow, err := parseCIDRs(cfg.InboundAllowCIDRs)
+	if err != nil {
+		return InboundLimits{}, fmt.Errorf("p2p.inbound_allow_cidrs is incorrect: %w", err)
+	}
+	deny, err := parseCIDRs(cfg.InboundDenyCIDRs)
+	if err != nil {
+		return InboundLimits{}, fmt.Errorf("p2p.inbound_deny_cidrs is incorrect: %w", err)
+	}
+	return InboundLimits{
+		MaxPerIP:       cfg.MaxInboundPerIP,
+		MaxPerSubnet:   cfg.MaxInboundPerSubnet,
+		HandshakeRate:  cfg.InboundHandshakeRate,
+		HandshakeBurst: cfg.InboundHandshakeBurst,
+		Allow:          allow,
+		Deny:           deny,
+	}, nil
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// tokenBucket rate limits the handshakes of a single source.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// inboundLimiter counts the admitted inbound connections per IP and subnet.
+// Connections are admitted before the handshake and released when the
+// transport cleans them up.
+type inboundLimiter struct {
+	limits InboundLimits
+
+	mtx     ksync.Mutex
+	conns   map[string]net.IP // remote address -> ip of admitted connections
+	ips     map[string]int
+	subnets map[string]int
+	buckets map[string]*tokenBucket
+}
+
+func newInboundLimiter(limits InboundLimits) *inboundLimiter {
+	return &inboundLimiter{
+		limits:  limits,
+		conns:   make(map[string]net.IP),
+		ips:     make(map[string]int),
+		subnets: make(map[string]int),
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// admit returns an error if a new connection from addr exceeds the limits,
+// otherwise counts it until it is released.
+func (l *inboundLimiter) admit(addr net.Addr) error {
+	ip := addrIP(addr)
+	if ip == nil {
+		// Not an IP connection, nothing to limit on
+		return nil
+	}
+	if containsIP(l.limits.Deny, ip) {
+		inboundDeniedMeter.Mark(1)
+		return fmt.Errorf("ip<%v> is denied", ip)
+	}
+	if containsIP(l.limits.Allow, ip) {
+		inboundAdmittedMeter.Mark(1)
+		return nil
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if !l.takeToken(ip, time.Now()) {
+		inboundRateLimitMeter.Mark(1)
+		return fmt.Errorf("too many handshakes from ip<%v>", ip)
+	}
+	key, subnet := ip.String(), subnetKey(ip)
+	if l.limits.MaxPerIP > 0 && l.ips[key] >= l.limits.MaxPerIP {
+		inboundIPLimitMeter.Mark(1)
+		return fmt.Errorf("too many connections from ip<%v>", ip)
+	}
+	if l.limits.MaxPerSubnet > 0 && l.subnets[subnet] >= l.limits.MaxPerSubnet {
+		inboundSubnetLimitMeter.Mark(1)
+		return fmt.Errorf("too many connections from subnet<%v>", subnet)
+	}
+
+	l.conns[addr.String()] = ip
+	l.ips[key]++
+	l.subnets[subnet]++
+	inboundAdmittedMeter.Mark(1)
+	return nil
+}
+
+// release stops counting the connection from addr. It is a no-op for
+// connections which were not admitted or already released.
+func (l *inboundLimiter) release(addr net.Addr) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	ip, ok := l.conns[addr.String()]
+	if !ok {
+		return
+	}
+	delete(l.conns, addr.String())
+
+	key, subnet := ip.String(), subnetKey(ip)
+	if l.ips[key]--; l.ips[key] <= 0 {
+		delete(l.ips, key)
+	}
+	if l.subnets[subnet]--; l.subnets[subnet] <= 0 {
+		delete(l.subnets, subnet)
+	}
+}
+
+// takeToken consumes a handshake token of the ip, returning false if it has
+// none left. It must be called with the mutex held.
+func (l *inboundLimiter) takeToken(ip net.IP, now time.Time) bool {
+	if l.limits.HandshakeRate <= 0 {
+		return true
+	}
+	burst := float64(l.limits.HandshakeBurst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	key := ip.String()
+	bucket, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxRateLimitedSources {
+			l.pruneBuckets(now, burst)
+		}
+		bucket = &tokenBucket{tokens: burst, last: now}
+		l.buckets[key] = bucket
+	}
+
+	bucket.tokens += now.Sub(bucket.last).Seconds() * l.limits.HandshakeRate
+	if bucket.tokens > burst {
+		bucket.tokens = burst
+	}
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// pruneBuckets drops the buckets which have refilled, as they would be
+// recreated in the same state.
+func (l *inboundLimiter) pruneBuckets(now time.Time, burst float64) {
+	for key, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.limits.HandshakeRate >= burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func addrIP(addr net.Addr) net.IP {
+	if tcpAddr, ok := addr.(*net.TCPAddr); ok {
+		return tcpAddr.IP
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(host)
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// subnetKey returns the /24 subnet of an IPv4 address, or the /64 subnet of
+// an IPv6 one.
+func subnetKey(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return fmt.Sprintf("%v/24", ip4.Mask(net.CIDRMask(24, 32)))
+	}
+	return fmt.Sprintf("%v/64", ip.Mask(net.CIDRMask(64, 128)))
+}
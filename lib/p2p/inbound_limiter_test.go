@@ -0,0 +1,110 @@
+package p2p
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/configs"
+	"github.com/kardiachain/go-kardia/lib/crypto"
+)
+
+func tcpAddr(ip string, port int) net.Addr {
+	return &net.TCPAddr{IP: net.ParseIP(ip), Port: port}
+}
+
+func TestInboundLimiterPerIPAndSubnet(t *testing.T) {
+	l := newInboundLimiter(InboundLimits{MaxPerIP: 2, MaxPerSubnet: 3})
+
+	require.NoError(t, l.admit(tcpAddr("10.0.0.1", 1)))
+	require.NoError(t, l.admit(tcpAddr("10.0.0.1", 2)))
+	assert.Error(t, l.admit(tcpAddr("10.0.0.1", 3)), "expected the ip limit to be hit")
+
+	require.NoError(t, l.admit(tcpAddr("10.0.0.2", 1)))
+	assert.Error(t, l.admit(tcpAddr("10.0.0.3", 1)), "expected the subnet limit to be hit")
+	require.NoError(t, l.admit(tcpAddr("10.0.1.1", 1)), "other subnets are not limited")
+
+	// Releasing a connection frees its slots, releasing twice is a no-op
+	l.release(tcpAddr("10.0.0.1", 1))
+	l.release(tcpAddr("10.0.0.1", 1))
+	require.NoError(t, l.admit(tcpAddr("10.0.0.1", 3)))
+	assert.Error(t, l.admit(tcpAddr("10.0.0.4", 1)))
+
+	// IPv6 addresses are grouped by /64
+	l = newInboundLimiter(InboundLimits{MaxPerSubnet: 1})
+	require.NoError(t, l.admit(tcpAddr("2001:db8::1", 1)))
+	assert.Error(t, l.admit(tcpAddr("2001:db8::2", 1)))
+	require.NoError(t, l.admit(tcpAddr("2001:db8:0:1::1", 1)))
+}
+
+func TestInboundLimiterHandshakeRate(t *testing.T) {
+	l := newInboundLimiter(InboundLimits{HandshakeRate: 1, HandshakeBurst: 2})
+	ip := net.ParseIP("10.0.0.1")
+	now := time.Now()
+
+	assert.True(t, l.takeToken(ip, now))
+	assert.True(t, l.takeToken(ip, now))
+	assert.False(t, l.takeToken(ip, now), "expected the burst to be used up")
+	assert.True(t, l.takeToken(net.ParseIP("10.0.0.2"), now), "other sources have their own tokens")
+
+	// Tokens refill at the rate, up to the burst
+	assert.True(t, l.takeToken(ip, now.Add(time.Second)))
+	assert.False(t, l.takeToken(ip, now.Add(time.Second)))
+	assert.True(t, l.takeToken(ip, now.Add(time.Hour)))
+	assert.True(t, l.takeToken(ip, now.Add(time.Hour)))
+	assert.False(t, l.takeToken(ip, now.Add(time.Hour)))
+}
+
+func TestInboundLimiterAllowDeny(t *testing.T) {
+	cfg := configs.DefaultP2PConfig()
+	cfg.MaxInboundPerIP = 1
+	cfg.InboundAllowCIDRs = []string{"10.0.0.0/8"}
+	cfg.InboundDenyCIDRs = []string{"10.1.0.0/16", "192.168.1.1/32"}
+	limits, err := InboundLimitsFromConfig(cfg)
+	require.NoError(t, err)
+	l := newInboundLimiter(limits)
+
+	// Allowed sources are never limited, unless they are also denied
+	for i := 0; i < 3; i++ {
+		require.NoError(t, l.admit(tcpAddr("10.0.0.1", i)))
+	}
+	assert.Error(t, l.admit(tcpAddr("10.1.0.1", 1)))
+	assert.Error(t, l.admit(tcpAddr("192.168.1.1", 1)))
+	require.NoError(t, l.admit(tcpAddr("192.168.1.2", 1)))
+
+	cfg.InboundDenyCIDRs = []string{"not a cidr"}
+	_, err = InboundLimitsFromConfig(cfg)
+	assert.Error(t, err)
+}
+
+func TestTransportMultiplexInboundLimits(t *testing.T) {
+	priv1, _ := crypto.GenerateKey()
+	mt := newMultiplexTransport(
+		emptyNodeInfo(),
+		NodeKey{
+			PrivKey: priv1,
+		},
+	)
+	id := mt.nodeKey.ID()
+
+	MultiplexTransportInboundLimits(InboundLimits{
+		Deny: []*net.IPNet{{IP: net.IPv4(127, 0, 0, 0), Mask: net.CIDRMask(8, 32)}},
+	})(mt)
+
+	addr, err := NewNetAddressString(IDAddressString(id, "127.0.0.1:0"))
+	require.NoError(t, err)
+	require.NoError(t, mt.Listen(*addr))
+
+	go func() {
+		addr := NewNetAddress(id, mt.listener.Addr())
+		_, _ = addr.Dial()
+	}()
+
+	_, err = mt.Accept(peerConfig{})
+	e, ok := err.(ErrRejected)
+	require.True(t, ok, "expected ErrRejected, got %v", err)
+	assert.True(t, e.IsLimited())
+}
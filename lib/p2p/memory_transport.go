@@ -0,0 +1,622 @@
+package p2p
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/kardiachain/go-kardia/lib/cmap"
+	"github.com/kardiachain/go-kardia/lib/log"
+	"github.com/kardiachain/go-kardia/lib/p2p/conn"
+	"github.com/kardiachain/go-kardia/lib/service"
+	ksync "github.com/kardiachain/go-kardia/lib/sync"
+)
+
+const (
+	memoryPort          = 26656
+	memorySendQueueSize = 1024
+	memorySendTimeout   = 10 * time.Second
+)
+
+var (
+	errMemoryConnClosed  = errors.New("memory connection closed")
+	errMemoryPartitioned = errors.New("memory connection partitioned")
+)
+
+// LinkRule sets how messages travel from one node to another over a
+// MemoryNetwork.
+type LinkRule struct {
+	// Delay before a message is delivered. Messages on a link are always
+	// delivered in order.
+	Latency time.Duration
+	// Probability in [0, 1] that a message is dropped.
+	DropRate float64
+	// Connections over a partitioned link are closed, and no new ones can
+	// be made until the partition is removed.
+	Partitioned bool
+}
+
+// MessageInterceptor is called for every message sent over a MemoryNetwork,
+// before the link rules apply. It returns the message to deliver instead,
+// and false to drop it.
+type MessageInterceptor func(from, to ID, chID byte, msgBytes []byte) ([]byte, bool)
+
+type memoryLink struct {
+	from, to ID
+}
+
+// MemoryNetwork connects MemoryTransports in the same process. It is used to
+// simulate networks of nodes in tests: every link between two nodes has its
+// own latency, drop and partition rule, and messages can be intercepted.
+//
+// Reactors expect the messages they send to be delivered while the peer is
+// connected, so dropped messages may stall them like a faulty connection
+// would. Partitions close the connections instead, as a real network does.
+type MemoryNetwork struct {
+	mtx         ksync.Mutex
+	rng         *rand.Rand
+	transports  map[ID]*MemoryTransport
+	addrs       map[ID]NetAddress
+	rules       map[memoryLink]LinkRule
+	defaultRule LinkRule
+	interceptor MessageInterceptor
+	conns       map[*memoryPeer]struct{} // dialing ends of the open connections
+}
+
+// NewMemoryNetwork returns an empty MemoryNetwork. The seed makes the
+// messages dropped by the link rules reproducible.
+func NewMemoryNetwork(seed int64) *MemoryNetwork {
+	return &MemoryNetwork{
+		rng:        rand.New(rand.NewSource(seed)),
+		transports: make(map[ID]*MemoryTransport),
+		addrs:      make(map[ID]NetAddress),
+		rules:      make(map[memoryLink]LinkRule),
+		conns:      make(map[*memoryPeer]struct{}),
+	}
+}
+
+// NetAddress returns the address of the node with the given ID. Nodes get
+// distinct IPs in the order they are first seen.
+func (n *MemoryNetwork) NetAddress(id ID) NetAddress {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if addr, ok := n.addrs[id]; ok {
+		return addr
+	}
+	i := len(n.addrs) + 1
+	addr := NewNetAddressIPPort(net.IPv4(10, byte(i>>16), byte(i>>8), byte(i)), memoryPort)
+	addr.ID = id
+	n.addrs[id] = *addr
+	return *addr
+}
+
+// SetDefaultRule sets the rule of the links without their own rule.
+func (n *MemoryNetwork) SetDefaultRule(rule LinkRule) {
+	n.mtx.Lock()
+	n.defaultRule = rule
+	n.mtx.Unlock()
+	n.breakPartitioned()
+}
+
+// SetLinkRule sets the rule of the link from one node to another. Links are
+// directional, so the link back keeps its own rule.
+func (n *MemoryNetwork) SetLinkRule(from, to ID, rule LinkRule) {
+	n.mtx.Lock()
+	n.rules[memoryLink{from, to}] = rule
+	n.mtx.Unlock()
+	n.breakPartitioned()
+}
+
+// ResetLinkRule makes the link from one node to another follow the default
+// rule again.
+func (n *MemoryNetwork) ResetLinkRule(from, to ID) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	delete(n.rules, memoryLink{from, to})
+}
+
+// Partition splits the nodes into groups which can't reach each other, in
+// both directions. Links inside a group are left as they are.
+func (n *MemoryNetwork) Partition(groups ...[]ID) {
+	defer n.breakPartitioned()
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	for i, group := range groups {
+		for j, other := range groups {
+			if i == j {
+				continue
+			}
+			for _, from := range group {
+				for _, to := range other {
+					link := memoryLink{from, to}
+					rule, ok := n.rules[link]
+					if !ok {
+						rule = n.defaultRule
+					}
+					rule.Partitioned = true
+					n.rules[link] = rule
+				}
+			}
+		}
+	}
+}
+
+// Heal removes all partitions, keeping the other link rules. The connections
+// closed by the partitions are not reopened.
+func (n *MemoryNetwork) Heal() {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	for link, rule := range n.rules {
+		rule.Partitioned = false
+		n.rules[link] = rule
+	}
+	n.defaultRule.Partitioned = false
+}
+
+// SetInterceptor sets the interceptor of all messages, nil to remove it.
+func (n *MemoryNetwork) SetInterceptor(interceptor MessageInterceptor) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.interceptor = interceptor
+}
+
+// rule returns the rule of the link from one node to another. It must be
+// called with the mutex held.
+func (n *MemoryNetwork) rule(from, to ID) LinkRule {
+	if rule, ok := n.rules[memoryLink{from, to}]; ok {
+		return rule
+	}
+	return n.defaultRule
+}
+
+// partitioned returns whether either direction of the link between two nodes
+// is partitioned. It must be called with the mutex held.
+func (n *MemoryNetwork) partitioned(a, b ID) bool {
+	return n.rule(a, b).Partitioned || n.rule(b, a).Partitioned
+}
+
+// breakPartitioned closes the connections over partitioned links.
+func (n *MemoryNetwork) breakPartitioned() {
+	n.mtx.Lock()
+	var broken []*memoryPeer
+	for p := range n.conns {
+		if n.partitioned(p.localID, p.ID()) {
+			broken = append(broken, p)
+			delete(n.conns, p)
+		}
+	}
+	n.mtx.Unlock()
+
+	for _, p := range broken {
+		p.breakConn(errMemoryPartitioned)
+	}
+}
+
+// connect registers the connection dialed by p, unless the link was
+// partitioned in the meantime.
+func (n *MemoryNetwork) connect(p *memoryPeer) error {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if n.partitioned(p.localID, p.ID()) {
+		return errMemoryPartitioned
+	}
+	n.conns[p] = struct{}{}
+	return nil
+}
+
+func (n *MemoryNetwork) disconnect(p *memoryPeer) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	delete(n.conns, p)
+}
+
+// route applies the interceptor and the link rule to a message. It returns
+// the message to deliver, its delay and false if the message is dropped.
+func (n *MemoryNetwork) route(from, to ID, chID byte, msgBytes []byte) ([]byte, time.Duration, bool) {
+	n.mtx.Lock()
+	interceptor := n.interceptor
+	rule := n.rule(from, to)
+	dropped := rule.Partitioned || (rule.DropRate > 0 && n.rng.Float64() < rule.DropRate)
+	n.mtx.Unlock()
+
+	if dropped {
+		return nil, 0, false
+	}
+	if interceptor != nil {
+		var ok bool
+		if msgBytes, ok = interceptor(from, to, chID, msgBytes); !ok {
+			return nil, 0, false
+		}
+	}
+	return msgBytes, rule.Latency, true
+}
+
+func (n *MemoryNetwork) listen(mt *MemoryTransport) error {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	id := mt.nodeKey.ID()
+	if _, ok := n.transports[id]; ok {
+		return fmt.Errorf("memory transport for %v is already listening", id)
+	}
+	n.transports[id] = mt
+	return nil
+}
+
+func (n *MemoryNetwork) close(mt *MemoryTransport) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	id := mt.nodeKey.ID()
+	if n.transports[id] == mt {
+		delete(n.transports, id)
+	}
+}
+
+func (n *MemoryNetwork) transport(id ID) *MemoryTransport {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	return n.transports[id]
+}
+
+// MemoryTransport is a Transport connecting peers over a MemoryNetwork
+// instead of sockets. Peers exchange whole messages, there is no connection
+// multiplexing or encryption involved.
+type MemoryTransport struct {
+	network  *MemoryNetwork
+	netAddr  NetAddress
+	nodeInfo NodeInfo
+	nodeKey  NodeKey
+
+	acceptc chan *memoryPeer
+	closec  chan struct{}
+	closeMu ksync.Mutex
+	closed  bool
+
+	dialTimeout time.Duration
+}
+
+// Test MemoryTransport for interface completeness.
+var _ Transport = (*MemoryTransport)(nil)
+var _ transportLifecycle = (*MemoryTransport)(nil)
+
+// NewMemoryTransport returns a transport on the given network. The node is
+// reachable at network.NetAddress(nodeKey.ID()) once Listen is called.
+func NewMemoryTransport(network *MemoryNetwork, nodeInfo NodeInfo, nodeKey NodeKey) *MemoryTransport {
+	return &MemoryTransport{
+		network:     network,
+		netAddr:     network.NetAddress(nodeKey.ID()),
+		nodeInfo:    nodeInfo,
+		nodeKey:     nodeKey,
+		acceptc:     make(chan *memoryPeer),
+		closec:      make(chan struct{}),
+		dialTimeout: defaultDialTimeout,
+	}
+}
+
+// NetAddress implements Transport.
+func (mt *MemoryTransport) NetAddress() NetAddress {
+	return mt.netAddr
+}
+
+// SetNodeInfo sets the NodeInfo exchanged with peers. It must be called
+// before Listen.
+func (mt *MemoryTransport) SetNodeInfo(nodeInfo NodeInfo) {
+	mt.nodeInfo = nodeInfo
+}
+
+// Listen implements transportLifecycle.
+func (mt *MemoryTransport) Listen(addr NetAddress) error {
+	if addr.ID != mt.nodeKey.ID() {
+		return fmt.Errorf("can't listen on %v with the key of %v", addr, mt.nodeKey.ID())
+	}
+	return mt.network.listen(mt)
+}
+
+// Close implements transportLifecycle.
+func (mt *MemoryTransport) Close() error {
+	mt.closeMu.Lock()
+	defer mt.closeMu.Unlock()
+
+	if !mt.closed {
+		mt.closed = true
+		close(mt.closec)
+		mt.network.close(mt)
+	}
+	return nil
+}
+
+// Accept implements Transport.
+func (mt *MemoryTransport) Accept(cfg peerConfig) (Peer, error) {
+	select {
+	case p := <-mt.acceptc:
+		cfg.outbound = false
+		p.configure(cfg)
+		return p, nil
+	case <-mt.closec:
+		return nil, ErrTransportClosed{}
+	}
+}
+
+// Dial implements Transport.
+func (mt *MemoryTransport) Dial(addr NetAddress, cfg peerConfig) (Peer, error) {
+	remote := mt.network.transport(addr.ID)
+	if remote == nil {
+		return nil, fmt.Errorf("no memory transport listening on %v", addr)
+	}
+	if remote == mt {
+		return nil, ErrRejected{addr: addr, id: addr.ID, isSelf: true}
+	}
+	if err := mt.nodeInfo.CompatibleWith(remote.nodeInfo); err != nil {
+		return nil, ErrRejected{id: addr.ID, err: err, isIncompatible: true}
+	}
+
+	local := newMemoryPeer(mt.network, mt.nodeKey.ID(), remote.nodeInfo, &addr)
+	if err := mt.network.connect(local); err != nil {
+		return nil, fmt.Errorf("dial %v: %w", addr, err)
+	}
+	remoteAddr := mt.netAddr
+	inbound := newMemoryPeer(mt.network, addr.ID, mt.nodeInfo, &remoteAddr)
+	local.remote, inbound.remote = inbound, local
+
+	select {
+	case remote.acceptc <- inbound:
+	case <-remote.closec:
+		mt.network.disconnect(local)
+		return nil, fmt.Errorf("memory transport on %v is closed", addr)
+	case <-mt.closec:
+		mt.network.disconnect(local)
+		return nil, ErrTransportClosed{}
+	case <-time.After(mt.dialTimeout):
+		mt.network.disconnect(local)
+		return nil, fmt.Errorf("dial %v timed out", addr)
+	}
+
+	cfg.outbound = true
+	local.configure(cfg)
+	return local, nil
+}
+
+// Cleanup implements Transport.
+func (mt *MemoryTransport) Cleanup(p Peer) {
+	_ = p.CloseConn()
+}
+
+type memoryPacket struct {
+	chID     byte
+	msgBytes []byte
+	sentAt   time.Time
+}
+
+// memoryPeer is one end of a connection over a MemoryNetwork. Messages sent
+// on it are routed by the network and received by the other end, once that
+// end is started.
+type memoryPeer struct {
+	service.BaseService
+
+	network    *MemoryNetwork
+	localID    ID
+	nodeInfo   NodeInfo // of the remote node
+	channels   []byte
+	socketAddr *NetAddress
+	outbound   bool
+	persistent bool
+
+	reactorsByCh map[byte]Reactor
+	onPeerError  func(Peer, interface{})
+
+	remote  *memoryPeer
+	sendc   chan memoryPacket
+	started chan struct{}
+	closec  chan struct{}
+	closeMu ksync.Mutex
+	closed  bool
+
+	Data *cmap.CMap
+}
+
+var _ Peer = (*memoryPeer)(nil)
+
+func newMemoryPeer(network *MemoryNetwork, localID ID, nodeInfo NodeInfo, socketAddr *NetAddress) *memoryPeer {
+	p := &memoryPeer{
+		network:    network,
+		localID:    localID,
+		nodeInfo:   nodeInfo,
+		socketAddr: socketAddr,
+		sendc:      make(chan memoryPacket, memorySendQueueSize),
+		started:    make(chan struct{}),
+		closec:     make(chan struct{}),
+		Data:       cmap.NewCMap(),
+	}
+	if ni, ok := nodeInfo.(DefaultNodeInfo); ok {
+		p.channels = ni.Channels
+	}
+	p.BaseService = *service.NewBaseService(nil, "MemoryPeer", p)
+	return p
+}
+
+// configure sets what the switch passed to Accept or Dial.
+func (p *memoryPeer) configure(cfg peerConfig) {
+	p.outbound = cfg.outbound
+	p.reactorsByCh = cfg.reactorsByCh
+	p.onPeerError = cfg.onPeerError
+	if cfg.isPersistent != nil {
+		if cfg.outbound {
+			p.persistent = cfg.isPersistent(p.socketAddr)
+		} else if addr, err := p.nodeInfo.NetAddress(); err == nil {
+			p.persistent = cfg.isPersistent(addr)
+		}
+	}
+}
+
+// String representation.
+func (p *memoryPeer) String() string {
+	if p.outbound {
+		return fmt.Sprintf("MemoryPeer{%v out}", p.ID())
+	}
+	return fmt.Sprintf("MemoryPeer{%v in}", p.ID())
+}
+
+// SetLogger implements BaseService.
+func (p *memoryPeer) SetLogger(l log.Logger) {
+	p.Logger = l
+}
+
+// OnStart implements BaseService.
+func (p *memoryPeer) OnStart() error {
+	if err := p.BaseService.OnStart(); err != nil {
+		return err
+	}
+	close(p.started)
+	go p.sendRoutine()
+	return nil
+}
+
+// OnStop implements BaseService.
+func (p *memoryPeer) OnStop() {
+	p.BaseService.OnStop()
+	_ = p.CloseConn()
+}
+
+// FlushStop implements Peer. Messages still queued are dropped.
+func (p *memoryPeer) FlushStop() {
+	_ = p.Stop()
+}
+
+// CloseConn implements Peer. The remote end is stopped for error, as it
+// would be on a broken connection.
+func (p *memoryPeer) CloseConn() error {
+	p.closeMu.Lock()
+	if p.closed {
+		p.closeMu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.closec)
+	p.closeMu.Unlock()
+
+	p.network.disconnect(p)
+	p.remote.disconnected(errMemoryConnClosed)
+	return nil
+}
+
+// breakConn closes the connection as if it broke, both ends are stopped for
+// error.
+func (p *memoryPeer) breakConn(err error) {
+	p.disconnected(err)
+	p.remote.disconnected(err)
+}
+
+// disconnected is called when the connection is closed from the other end,
+// or broken.
+func (p *memoryPeer) disconnected(err error) {
+	if p.IsRunning() && p.onPeerError != nil {
+		go p.onPeerError(p, err)
+		return
+	}
+	_ = p.CloseConn()
+}
+
+func (p *memoryPeer) ID() ID                            { return p.nodeInfo.ID() }
+func (p *memoryPeer) RemoteIP() net.IP                  { return p.socketAddr.IP }
+func (p *memoryPeer) RemoteAddr() net.Addr              { return &net.TCPAddr{IP: p.RemoteIP(), Port: memoryPort} }
+func (p *memoryPeer) IsOutbound() bool                  { return p.outbound }
+func (p *memoryPeer) IsPersistent() bool                { return p.persistent }
+func (p *memoryPeer) NodeInfo() NodeInfo                { return p.nodeInfo }
+func (p *memoryPeer) Status() conn.ConnectionStatus     { return conn.ConnectionStatus{} }
+func (p *memoryPeer) SocketAddr() *NetAddress           { return p.socketAddr }
+func (p *memoryPeer) Get(key string) interface{}        { return p.Data.Get(key) }
+func (p *memoryPeer) Set(key string, value interface{}) { p.Data.Set(key, value) }
+
+// Send implements Peer. It blocks while the send queue is full, up to a
+// timeout.
+func (p *memoryPeer) Send(chID byte, msgBytes []byte) bool {
+	if !p.IsRunning() || !p.hasChannel(chID) {
+		return false
+	}
+	select {
+	case p.sendc <- memoryPacket{chID, msgBytes, time.Now()}:
+		return true
+	case <-p.closec:
+		return false
+	case <-time.After(memorySendTimeout):
+		return false
+	}
+}
+
+// TrySend implements Peer. It returns false at once if the send queue is
+// full.
+func (p *memoryPeer) TrySend(chID byte, msgBytes []byte) bool {
+	if !p.IsRunning() || !p.hasChannel(chID) {
+		return false
+	}
+	select {
+	case p.sendc <- memoryPacket{chID, msgBytes, time.Now()}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *memoryPeer) hasChannel(chID byte) bool {
+	for _, ch := range p.channels {
+		if ch == chID {
+			return true
+		}
+	}
+	return false
+}
+
+// sendRoutine delivers the queued messages in order, each after the latency
+// of the link.
+func (p *memoryPeer) sendRoutine() {
+	for {
+		select {
+		case pkt := <-p.sendc:
+			msgBytes, latency, ok := p.network.route(p.localID, p.ID(), pkt.chID, pkt.msgBytes)
+			if !ok {
+				continue
+			}
+			if wait := time.Until(pkt.sentAt.Add(latency)); wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-p.closec:
+					return
+				}
+			}
+			// The remote end reads nothing until the remote switch starts it
+			select {
+			case <-p.remote.started:
+			case <-p.remote.closec:
+				p.disconnected(errMemoryConnClosed)
+				return
+			case <-p.closec:
+				return
+			}
+			p.remote.receive(pkt.chID, msgBytes)
+		case <-p.closec:
+			return
+		}
+	}
+}
+
+// receive hands a message from the remote end to the reactor of its channel.
+func (p *memoryPeer) receive(chID byte, msgBytes []byte) {
+	if !p.IsRunning() {
+		return
+	}
+	reactor := p.reactorsByCh[chID]
+	if reactor == nil {
+		if p.onPeerError != nil {
+			p.onPeerError(p, fmt.Errorf("unknown channel %X", chID))
+		}
+		return
+	}
+	reactor.Receive(chID, p, msgBytes)
+}
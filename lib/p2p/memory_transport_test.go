@@ -0,0 +1,125 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeMemorySwitchPair(t *testing.T, network *MemoryNetwork) (*Switch, *Switch) {
+	s1 := MakeMemorySwitch(cfg, 1, network, initSwitchFunc)
+	s2 := MakeMemorySwitch(cfg, 2, network, initSwitchFunc)
+	require.NoError(t, StartSwitches([]*Switch{s1, s2}))
+	t.Cleanup(func() {
+		_ = s1.Stop()
+		_ = s2.Stop()
+	})
+
+	addr := network.NetAddress(s2.NodeInfo().ID())
+	require.NoError(t, s1.DialPeerWithAddress(&addr))
+	assert.Eventually(t, func() bool {
+		return s1.Peers().Size() == 1 && s2.Peers().Size() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	return s1, s2
+}
+
+func TestMemoryTransportSwitches(t *testing.T) {
+	network := NewMemoryNetwork(1)
+	s1, s2 := makeMemorySwitchPair(t, network)
+
+	p := s1.Peers().Get(s2.NodeInfo().ID())
+	require.NotNil(t, p)
+	assert.True(t, p.IsOutbound())
+	assert.Equal(t, network.NetAddress(s2.NodeInfo().ID()).IP, p.RemoteIP())
+	assert.False(t, s2.Peers().Get(s1.NodeInfo().ID()).IsOutbound())
+
+	s1.Broadcast(byte(0x00), []byte("channel zero"))
+	s1.Broadcast(byte(0x02), []byte("channel two"))
+	assertMsgReceivedWithTimeout(t, []byte("channel zero"), byte(0x00),
+		s2.Reactor("foo").(*TestReactor), 10*time.Millisecond, 5*time.Second)
+	assertMsgReceivedWithTimeout(t, []byte("channel two"), byte(0x02),
+		s2.Reactor("bar").(*TestReactor), 10*time.Millisecond, 5*time.Second)
+
+	// Channels the remote node doesn't have are refused
+	assert.False(t, p.Send(byte(0x09), []byte("unknown")))
+
+	// Closing one end disconnects the other
+	s1.StopPeerGracefully(p)
+	assert.Eventually(t, func() bool {
+		return s2.Peers().Size() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestMemoryTransportLinkRules(t *testing.T) {
+	network := NewMemoryNetwork(1)
+	s1, s2 := makeMemorySwitchPair(t, network)
+	id1, id2 := s1.NodeInfo().ID(), s2.NodeInfo().ID()
+	r1, r2 := s1.Reactor("foo").(*TestReactor), s2.Reactor("foo").(*TestReactor)
+
+	// Partitions close the connections, in both directions, and refuse new
+	// ones until healed
+	network.Partition([]ID{id1}, []ID{id2})
+	assert.Eventually(t, func() bool {
+		return s1.Peers().Size() == 0 && s2.Peers().Size() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	addr := network.NetAddress(id1)
+	assert.Error(t, s2.DialPeerWithAddress(&addr))
+
+	network.Heal()
+	require.NoError(t, s2.DialPeerWithAddress(&addr))
+	assert.Eventually(t, func() bool {
+		return s1.Peers().Size() == 1 && s2.Peers().Size() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Links are directional
+	network.SetLinkRule(id1, id2, LinkRule{DropRate: 1})
+	s1.Broadcast(byte(0x01), []byte("dropped"))
+	s2.Broadcast(byte(0x01), []byte("delivered"))
+	assertMsgReceivedWithTimeout(t, []byte("delivered"), byte(0x01), r1, 10*time.Millisecond, 5*time.Second)
+	assert.Empty(t, r2.getMsgs(byte(0x01)))
+
+	network.SetLinkRule(id1, id2, LinkRule{Latency: 200 * time.Millisecond})
+	start := time.Now()
+	s1.Broadcast(byte(0x00), []byte("delayed"))
+	assertMsgReceivedWithTimeout(t, []byte("delayed"), byte(0x00), r2, 10*time.Millisecond, 5*time.Second)
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestMemoryTransportInterceptor(t *testing.T) {
+	network := NewMemoryNetwork(1)
+	s1, s2 := makeMemorySwitchPair(t, network)
+	id1 := s1.NodeInfo().ID()
+
+	network.SetInterceptor(func(from, to ID, chID byte, msgBytes []byte) ([]byte, bool) {
+		if from != id1 {
+			return msgBytes, true
+		}
+		if chID == byte(0x01) {
+			return nil, false
+		}
+		return []byte("tampered"), true
+	})
+	s1.Broadcast(byte(0x01), []byte("withheld"))
+	s1.Broadcast(byte(0x00), []byte("original"))
+	r2 := s2.Reactor("foo").(*TestReactor)
+	assertMsgReceivedWithTimeout(t, []byte("tampered"), byte(0x00), r2, 10*time.Millisecond, 5*time.Second)
+	assert.Empty(t, r2.getMsgs(byte(0x01)))
+}
+
+func TestMemoryTransportDialErrors(t *testing.T) {
+	network := NewMemoryNetwork(1)
+	s1 := MakeMemorySwitch(cfg, 1, network, initSwitchFunc)
+	require.NoError(t, s1.Start())
+	t.Cleanup(func() { _ = s1.Stop() })
+
+	addr := network.NetAddress(s1.NodeInfo().ID())
+	err := s1.DialPeerWithAddress(&addr)
+	e, ok := err.(ErrRejected)
+	require.True(t, ok, "expected ErrRejected, got %v", err)
+	assert.True(t, e.IsSelf())
+
+	unknown := network.NetAddress(ID("0123456789abcdef0123456789abcdef01234567"))
+	assert.Error(t, s1.DialPeerWithAddress(&unknown))
+}
@@ -13,6 +13,9 @@ import (
 const (
 	maxNodeInfoSize = 10240 // 10KB
 	maxNumChannels  = 16    // plenty of room for upgrades, for now
+
+	maxNumCapabilities = 16
+	maxCapabilityLen   = 32
 )
 
 // Max size of the NodeInfo struct
@@ -91,6 +94,10 @@ type DefaultNodeInfo struct {
 	// ASCIIText fields
 	Moniker string               `json:"moniker"` // arbitrary moniker
 	Other   DefaultNodeInfoOther `json:"other"`   // other application specific data
+
+	// Optional protocol features this node supports, which reactors use with
+	// the peers supporting them too.
+	Capabilities []string `json:"capabilities,omitempty"`
 }
 
 // DefaultNodeInfoOther is the misc. applcation specific data
@@ -104,6 +111,16 @@ func (info DefaultNodeInfo) ID() ID {
 	return info.DefaultNodeID
 }
 
+// HasCapability returns true if the node supports the capability.
+func (info DefaultNodeInfo) HasCapability(capability string) bool {
+	for _, c := range info.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate checks the self-reported DefaultNodeInfo is safe.
 // It returns an error if there
 // are too many Channels, if there are any duplicate Channels,
@@ -168,6 +185,16 @@ func (info DefaultNodeInfo) Validate() error {
 		return fmt.Errorf("info.Other.RPCAddress=%v must be valid ASCII text without tabs", rpcAddr)
 	}
 
+	// Validate Capabilities.
+	if len(info.Capabilities) > maxNumCapabilities {
+		return fmt.Errorf("info.Capabilities is too long (%v). Max is %v", len(info.Capabilities), maxNumCapabilities)
+	}
+	for _, c := range info.Capabilities {
+		if c == "" || len(c) > maxCapabilityLen || !kstrings.IsASCIIText(c) || kstrings.ASCIITrim(c) != c {
+			return fmt.Errorf("info.Capabilities contains invalid capability %q", c)
+		}
+	}
+
 	return nil
 }
 
@@ -240,6 +267,7 @@ func (info DefaultNodeInfo) ToProto() *kp2p.DefaultNodeInfo {
 		TxIndex:    info.Other.TxIndex,
 		RPCAddress: info.Other.RPCAddress,
 	}
+	dni.Capabilities = info.Capabilities
 
 	return dni
 }
@@ -264,6 +292,7 @@ func DefaultNodeInfoFromToProto(pb *kp2p.DefaultNodeInfo) (DefaultNodeInfo, erro
 			TxIndex:    pb.Other.TxIndex,
 			RPCAddress: pb.Other.RPCAddress,
 		},
+		Capabilities: pb.Capabilities,
 	}
 
 	return dni, nil
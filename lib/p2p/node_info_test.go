@@ -63,6 +63,11 @@ func TestNodeInfoValidate(t *testing.T) {
 		{"Empty space RPCAddress", func(ni *DefaultNodeInfo) { ni.Other.RPCAddress = emptySpace }, true},
 		{"Empty RPCAddress", func(ni *DefaultNodeInfo) { ni.Other.RPCAddress = "" }, false},
 		{"Good RPCAddress", func(ni *DefaultNodeInfo) { ni.Other.RPCAddress = "0.0.0.0:26657" }, false},
+
+		{"Too Many Capabilities", func(ni *DefaultNodeInfo) { ni.Capabilities = make([]string, maxNumCapabilities+1) }, true},
+		{"Non-ASCII Capability", func(ni *DefaultNodeInfo) { ni.Capabilities = []string{nonASCII} }, true},
+		{"Empty space Capability", func(ni *DefaultNodeInfo) { ni.Capabilities = []string{emptySpace} }, true},
+		{"Good Capabilities", func(ni *DefaultNodeInfo) { ni.Capabilities = []string{"compact_blocks"} }, false},
 	}
 
 	priv1, _ := crypto.GenerateKey()
@@ -345,10 +345,12 @@ func PeerMetrics(metrics *Metrics) PeerOption {
 }
 
 func (p *peer) metricsReporter() {
+	gauges := newPeerGauges(p.ID())
 	for {
 		select {
 		case <-p.metricsTicker.C:
 			status := p.mconn.Status()
+			gauges.update(status)
 			var sendQueueSize float64
 			for _, chStatus := range status.Channels {
 				sendQueueSize += float64(chStatus.SendQueueSize)
@@ -356,6 +358,7 @@ func (p *peer) metricsReporter() {
 
 			p.metrics.PeerPendingSendBytes.With("peer_id", string(p.ID())).Set(sendQueueSize)
 		case <-p.Quit():
+			gauges.unregister()
 			return
 		}
 	}
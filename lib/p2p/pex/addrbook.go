@@ -42,6 +42,9 @@ type AddrBook interface {
 
 	AddPrivateIDs([]string)
 
+	// Set the source of peer trust scores used to prioritise addresses
+	SetPeerScorer(PeerScorer)
+
 	// Add and remove an address
 	AddAddress(addr *p2p.NetAddress, src *p2p.NetAddress) error
 	RemoveAddress(*p2p.NetAddress)
@@ -81,6 +84,12 @@ type AddrBook interface {
 
 var _ AddrBook = (*addrBook)(nil)
 
+// PeerScorer reports the trust score (0-100) of a peer, and false if the peer
+// has not been scored yet. It is implemented by p2p.Switch.
+type PeerScorer interface {
+	PeerTrustScore(p2p.ID) (int, bool)
+}
+
 // addrBook - concurrency safe peer address manager.
 // Implements AddrBook.
 type addrBook struct {
@@ -97,6 +106,7 @@ type addrBook struct {
 	bucketsNew []map[string]*knownAddress
 	nOld       int
 	nNew       int
+	scorer     PeerScorer
 
 	// immutable after creation
 	filePath          string
@@ -204,6 +214,14 @@ func (a *addrBook) AddPrivateIDs(ids []string) {
 	}
 }
 
+// SetPeerScorer implements AddrBook. Once set, PickAddress prefers the
+// addresses of peers with higher trust scores.
+func (a *addrBook) SetPeerScorer(scorer PeerScorer) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.scorer = scorer
+}
+
 // AddAddress implements AddrBook
 // Add address to a "new" bucket. If it's already in one, only add it probabilistically.
 // Returns error if the addr is non-routable. Does not add self.
@@ -304,15 +322,25 @@ func (a *addrBook) PickAddress(biasTowardsNewAddrs int) *p2p.NetAddress {
 			bucket = a.bucketsNew[a.rand.Intn(len(a.bucketsNew))]
 		}
 	}
-	// pick a random index and loop over the map to return that index
-	randIndex := a.rand.Intn(len(bucket))
-	for _, ka := range bucket {
-		if randIndex == 0 {
-			return ka.Addr
+	// pick a few random addresses from the bucket and keep the most trusted
+	candidates := 1
+	if a.scorer != nil {
+		candidates = pickAddressCandidates
+	}
+	var (
+		picked    *knownAddress
+		bestScore = -1
+	)
+	for i := 0; i < candidates; i++ {
+		ka := a.randomFromBucket(bucket)
+		if score := a.peerScore(ka.ID()); score > bestScore {
+			picked, bestScore = ka, score
 		}
-		randIndex--
 	}
-	return nil
+	if picked == nil {
+		return nil
+	}
+	return picked.Addr
 }
 
 // MarkGood implements AddrBook - it marks the peer as good and
@@ -795,6 +823,31 @@ func (a *addrBook) moveToOld(ka *knownAddress) error {
 	return nil
 }
 
+// randomFromBucket picks a random address from a non-empty bucket.
+func (a *addrBook) randomFromBucket(bucket map[string]*knownAddress) *knownAddress {
+	// pick a random index and loop over the map to return that index
+	randIndex := a.rand.Intn(len(bucket))
+	for _, ka := range bucket {
+		if randIndex == 0 {
+			return ka
+		}
+		randIndex--
+	}
+	return nil
+}
+
+// peerScore returns the trust score of the peer, or defaultPeerScore if
+// there is no scorer or it has not seen the peer yet.
+func (a *addrBook) peerScore(id p2p.ID) int {
+	if a.scorer == nil {
+		return defaultPeerScore
+	}
+	if score, ok := a.scorer.PeerTrustScore(id); ok {
+		return score
+	}
+	return defaultPeerScore
+}
+
 func (a *addrBook) removeAddress(addr *p2p.NetAddress) {
 	ka := a.addrLookup[addr.ID]
 	if ka == nil {
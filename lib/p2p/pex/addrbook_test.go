@@ -58,6 +58,42 @@ func TestAddrBookPickAddress(t *testing.T) {
 	assert.Nil(t, addr, "did not expected an address")
 }
 
+type mockPeerScorer map[p2p.ID]int
+
+func (s mockPeerScorer) PeerTrustScore(id p2p.ID) (int, bool) {
+	score, ok := s[id]
+	return score, ok
+}
+
+func TestAddrBookPickAddressPrefersTrustedPeers(t *testing.T) {
+	fname := createTempFileName("addrbook_test")
+	defer deleteTempFile(fname)
+
+	book := NewAddrBook(fname, true).(*addrBook)
+	book.SetLogger(log.TestingLogger())
+
+	// put both addresses in the same bucket so only the score tells them apart
+	randAddrs := randNetAddressPairs(t, 2)
+	trusted, untrusted := randAddrs[0].addr, randAddrs[1].addr
+	for _, pair := range randAddrs {
+		require.NoError(t, book.addToNewBucket(newKnownAddress(pair.addr, pair.src), 0))
+	}
+	book.SetPeerScorer(mockPeerScorer{trusted.ID: 90, untrusted.ID: 10})
+
+	const picks = 200
+	var trustedPicks int
+	for i := 0; i < picks; i++ {
+		addr := book.PickAddress(100)
+		require.NotNil(t, addr)
+		if addr.Equals(trusted) {
+			trustedPicks++
+		}
+	}
+	// a uniform pick would return the trusted address about half of the time,
+	// picking the best of three returns it about 7/8 of the time
+	assert.Greater(t, trustedPicks, picks*3/4)
+}
+
 func TestAddrBookSaveLoad(t *testing.T) {
 	fname := createTempFileName("addrbook_test")
 	defer deleteTempFile(fname)
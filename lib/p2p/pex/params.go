@@ -49,6 +49,13 @@ const (
 	// min addresses that must be returned by GetSelection. Useful for bootstrapping.
 	minGetSelection = 32
 
+	// addresses sampled from a bucket by PickAddress when a PeerScorer is set;
+	// the one with the highest trust score is returned.
+	pickAddressCandidates = 3
+
+	// trust score assumed for addresses the PeerScorer has not seen yet.
+	defaultPeerScore = 50
+
 	// max addresses returned by GetSelection
 	// NOTE: this must match "maxMsgSize"
 	maxGetSelection = 250
@@ -9,6 +9,7 @@ import (
 	"github.com/kardiachain/go-kardia/configs"
 	"github.com/kardiachain/go-kardia/lib/cmap"
 	"github.com/kardiachain/go-kardia/lib/p2p/conn"
+	"github.com/kardiachain/go-kardia/lib/p2p/trust"
 	"github.com/kardiachain/go-kardia/lib/rand"
 	"github.com/kardiachain/go-kardia/lib/service"
 )
@@ -50,6 +51,7 @@ type AddrBook interface {
 	AddOurAddress(*NetAddress)
 	OurAddress(*NetAddress) bool
 	MarkGood(ID)
+	MarkBad(*NetAddress, time.Duration)
 	RemoveAddress(*NetAddress)
 	HasAddress(*NetAddress) bool
 	Save()
@@ -90,6 +92,12 @@ type Switch struct {
 	rng *rand.Rand // seed for randomizing dial times and orders
 
 	metrics *Metrics
+
+	// scores peers from the behaviour reported by reactors, nil if disabled
+	trustStore *trust.MetricStore
+
+	badEventsMtx sync.Mutex
+	badEvents    map[ID]int // bad events reported per peer since its last ban
 }
 
 // NetAddress returns the address the switch is listening on.
@@ -120,6 +128,7 @@ func NewSwitch(
 		filterTimeout:        defaultFilterTimeout,
 		persistentPeersAddrs: make([]*NetAddress, 0),
 		unconditionalPeerIDs: make(map[ID]struct{}),
+		badEvents:            make(map[ID]int),
 	}
 
 	// Ensure we have a completely undeterministic PRNG.
@@ -149,6 +158,12 @@ func WithMetrics(metrics *Metrics) SwitchOption {
 	return func(sw *Switch) { sw.metrics = metrics }
 }
 
+// SwitchTrustMetricStore sets the store used to score peers. The switch
+// starts and stops the store along with itself.
+func SwitchTrustMetricStore(store *trust.MetricStore) SwitchOption {
+	return func(sw *Switch) { sw.trustStore = store }
+}
+
 //---------------------------------------------------------------------
 // Switch setup
 
@@ -221,6 +236,14 @@ func (sw *Switch) SetNodeKey(nodeKey *NodeKey) {
 
 // OnStart implements BaseService. It starts all the reactors and peers.
 func (sw *Switch) OnStart() error {
+	// Load the peer scores and bans before accepting any peer
+	if sw.trustStore != nil {
+		sw.trustStore.SetLogger(sw.Logger.New("module", "trust"))
+		if err := sw.trustStore.Start(); err != nil {
+			return fmt.Errorf("failed to start trust metric store: %w", err)
+		}
+	}
+
 	// Start reactors
 	for _, reactor := range sw.reactors {
 		err := reactor.Start()
@@ -249,6 +272,12 @@ func (sw *Switch) OnStop() {
 			sw.Logger.Error("error while stopped reactor", "reactor", reactor, "error", err)
 		}
 	}
+
+	if sw.trustStore != nil {
+		if err := sw.trustStore.Stop(); err != nil {
+			sw.Logger.Error("error while stopping trust metric store", "error", err)
+		}
+	}
 }
 
 //---------------------------------------------------------------------
@@ -320,7 +349,8 @@ func (sw *Switch) Peers() IPeerSet {
 
 // StopPeerForError disconnects from a peer due to external error.
 // If the peer is persistent, it will attempt to reconnect.
-// TODO: make record depending on reason.
+// It doesn't lower the trust score of the peer, reactors stopping a peer for
+// misbehaving should call MarkPeerAsBad instead.
 func (sw *Switch) StopPeerForError(peer Peer, reason interface{}) {
 	if !peer.IsRunning() {
 		return
@@ -329,7 +359,7 @@ func (sw *Switch) StopPeerForError(peer Peer, reason interface{}) {
 	sw.Logger.Error("Stopping peer for error", "peer", peer, "err", reason)
 	sw.stopAndRemovePeer(peer, reason)
 
-	if peer.IsPersistent() {
+	if peer.IsPersistent() && !sw.IsPeerBanned(peer.ID()) {
 		var addr *NetAddress
 		if peer.IsOutbound() { // socket address for outbound peers
 			addr = peer.SocketAddr()
@@ -369,6 +399,10 @@ func (sw *Switch) stopAndRemovePeer(peer Peer, reason interface{}) {
 	if sw.peers.Remove(peer) {
 		sw.metrics.Peers.Add(float64(-1))
 	}
+
+	if sw.trustStore != nil {
+		sw.trustStore.PeerDisconnected(string(peer.ID()))
+	}
 }
 
 // reconnectToPeer tries to reconnect to the addr, first repeatedly
@@ -389,7 +423,7 @@ func (sw *Switch) reconnectToPeer(addr *NetAddress) {
 	start := time.Now()
 	sw.Logger.Info("Reconnecting to peer", "addr", addr)
 	for i := 0; i < reconnectAttempts; i++ {
-		if !sw.IsRunning() {
+		if !sw.IsRunning() || sw.IsPeerBanned(addr.ID) {
 			return
 		}
 
@@ -409,7 +443,7 @@ func (sw *Switch) reconnectToPeer(addr *NetAddress) {
 	sw.Logger.Error("Failed to reconnect to peer. Beginning exponential backoff",
 		"addr", addr, "elapsed", time.Since(start))
 	for i := 0; i < reconnectBackOffAttempts; i++ {
-		if !sw.IsRunning() {
+		if !sw.IsRunning() || sw.IsPeerBanned(addr.ID) {
 			return
 		}
 
@@ -439,6 +473,81 @@ func (sw *Switch) MarkPeerAsGood(peer Peer) {
 	if sw.addrBook != nil {
 		sw.addrBook.MarkGood(peer.ID())
 	}
+	if sw.trustStore != nil {
+		sw.trustStore.GetPeerTrustMetric(string(peer.ID())).GoodEvents(1)
+	}
+}
+
+// MarkPeerAsBad lowers the trust score of the given peer and disconnects it.
+// If the peer was reported at least PeerBanMinBadEvents times and the score
+// falls below the ban threshold the peer is banned, so that it is neither
+// redialed nor accepted again until the ban expires.
+func (sw *Switch) MarkPeerAsBad(peer Peer, reason interface{}) {
+	if sw.trustStore != nil {
+		tm := sw.trustStore.GetPeerTrustMetric(string(peer.ID()))
+		tm.BadEvents(1)
+		if banTime, banned := sw.banUntrustedPeer(peer.ID(), sw.addBadEvent(peer.ID()), tm.TrustScore()); banned && sw.addrBook != nil {
+			addr := peer.SocketAddr()
+			if !peer.IsOutbound() {
+				// The book knows inbound peers by the address they listen on,
+				// as added by the PEX reactor.
+				if listenAddr, err := peer.NodeInfo().NetAddress(); err == nil {
+					addr = listenAddr
+					if !sw.addrBook.HasAddress(addr) {
+						_ = sw.addrBook.AddAddress(addr, addr)
+					}
+				}
+			}
+			sw.addrBook.MarkBad(addr, banTime)
+		}
+	}
+	sw.StopPeerForError(peer, reason)
+}
+
+// PeerTrustScore returns the trust score (0-100) of the peer with the given
+// ID, and false if the peer has not been scored yet.
+func (sw *Switch) PeerTrustScore(id ID) (int, bool) {
+	if sw.trustStore == nil {
+		return 0, false
+	}
+	return sw.trustStore.PeerTrustScore(string(id))
+}
+
+// IsPeerBanned returns true if the peer with the given ID is currently banned.
+func (sw *Switch) IsPeerBanned(id ID) bool {
+	if sw.trustStore == nil {
+		return false
+	}
+	_, banned := sw.trustStore.PeerBannedUntil(string(id))
+	return banned
+}
+
+// addBadEvent counts a bad event of the peer and returns the number of bad
+// events reported since its last ban.
+func (sw *Switch) addBadEvent(id ID) int {
+	sw.badEventsMtx.Lock()
+	defer sw.badEventsMtx.Unlock()
+	sw.badEvents[id]++
+	return sw.badEvents[id]
+}
+
+// banUntrustedPeer bans the peer if it was reported at least
+// PeerBanMinBadEvents times and its score is below the configured threshold.
+// The ban lasts PeerBanDuration for a score of 0 and shrinks linearly as the
+// score approaches the threshold. Unconditional peers are never banned.
+func (sw *Switch) banUntrustedPeer(id ID, badEvents, score int) (time.Duration, bool) {
+	threshold := sw.config.PeerBanThreshold
+	if threshold <= 0 || score >= threshold || badEvents < sw.config.PeerBanMinBadEvents || sw.IsPeerUnconditional(id) {
+		return 0, false
+	}
+
+	banTime := sw.config.PeerBanDuration * time.Duration(threshold-score) / time.Duration(threshold)
+	sw.trustStore.BanPeer(string(id), time.Now().Add(banTime))
+	sw.badEventsMtx.Lock()
+	delete(sw.badEvents, id)
+	sw.badEventsMtx.Unlock()
+	sw.Logger.Info("Banning peer", "peer", id, "score", score, "duration", banTime)
+	return banTime, true
 }
 
 //---------------------------------------------------------------------
@@ -634,6 +743,13 @@ func (sw *Switch) acceptRoutine() {
 					sw.addrBook.AddOurAddress(&addr)
 				}
 
+				// Connections over the inbound limits are counted by the
+				// transport metrics, logging each one would flood the log.
+				if err.IsLimited() {
+					sw.Logger.Debug("Inbound connection limited", "err", err)
+					continue
+				}
+
 				sw.Logger.Info(
 					"Inbound Peer rejected",
 					"err", err,
@@ -764,6 +880,14 @@ func (sw *Switch) filterPeer(p Peer) error {
 		return ErrRejected{id: p.ID(), isDuplicate: true}
 	}
 
+	// Reject banned peers. A peer whose ban expired is accepted again, its
+	// low score only makes it banned sooner if it misbehaves again.
+	if sw.trustStore != nil {
+		if until, banned := sw.trustStore.PeerBannedUntil(string(p.ID())); banned {
+			return ErrRejected{id: p.ID(), err: fmt.Errorf("until %v", until), isBanned: true}
+		}
+	}
+
 	errc := make(chan error, len(sw.peerFilters))
 
 	for _, f := range sw.peerFilters {
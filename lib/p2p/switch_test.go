@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/kardiachain/go-kardia/configs"
+	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
 	"github.com/kardiachain/go-kardia/lib/crypto"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -20,6 +21,7 @@ import (
 
 	"github.com/kardiachain/go-kardia/lib/log"
 	"github.com/kardiachain/go-kardia/lib/p2p/conn"
+	"github.com/kardiachain/go-kardia/lib/p2p/trust"
 	ksync "github.com/kardiachain/go-kardia/lib/sync"
 )
 
@@ -528,6 +530,121 @@ func TestSwitchReconnectsToInboundPersistentPeer(t *testing.T) {
 	assert.Equal(t, 1, sw.Peers().Size())
 }
 
+func TestSwitchBansMisbehavingPersistentPeer(t *testing.T) {
+	store := trust.NewTrustMetricStore(memorydb.New(), trust.DefaultConfig())
+	sw := MakeSwitch(cfg, 1, "testing", "123.123.123", initSwitchFunc, SwitchTrustMetricStore(store))
+	err := sw.Start()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := sw.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	priv1, _ := crypto.GenerateKey()
+	rp := &remotePeer{PrivKey: priv1, Config: cfg}
+	rp.Start()
+	defer rp.Stop()
+
+	err = sw.AddPersistentPeers([]string{rp.Addr().String()})
+	require.NoError(t, err)
+
+	err = sw.DialPeerWithAddress(rp.Addr())
+	require.NoError(t, err)
+	p := sw.Peers().Get(rp.ID())
+	require.NotNil(t, p)
+
+	// a single report disconnects the peer without banning it
+	sw.MarkPeerAsBad(p, errors.New("bad message"))
+	assert.False(t, sw.IsPeerBanned(rp.ID()))
+
+	// the reconnected peer keeps misbehaving and is banned
+	for i := 1; i < cfg.PeerBanMinBadEvents; i++ {
+		require.Eventually(t, func() bool { return sw.Peers().Get(rp.ID()) != nil }, 5*time.Second, 10*time.Millisecond)
+		sw.MarkPeerAsBad(sw.Peers().Get(rp.ID()), errors.New("bad message"))
+	}
+	assert.True(t, sw.IsPeerBanned(rp.ID()))
+	score, ok := sw.PeerTrustScore(rp.ID())
+	require.True(t, ok)
+	assert.Less(t, score, cfg.PeerBanThreshold)
+
+	// the persistent peer is not reconnected, and can't be dialed again
+	assertNoPeersAfterTimeout(t, sw, 100*time.Millisecond)
+	err = sw.DialPeerWithAddress(rp.Addr())
+	require.Error(t, err)
+	e, ok := err.(ErrRejected)
+	require.True(t, ok)
+	assert.True(t, e.IsBanned())
+}
+
+func TestSwitchAcceptsPeerAfterBanExpires(t *testing.T) {
+	banCfg := *cfg
+	banCfg.PeerBanDuration = 300 * time.Millisecond
+	store := trust.NewTrustMetricStore(memorydb.New(), trust.DefaultConfig())
+	sw := MakeSwitch(&banCfg, 1, "testing", "123.123.123", initSwitchFunc, SwitchTrustMetricStore(store))
+	require.NoError(t, sw.Start())
+	t.Cleanup(func() {
+		if err := sw.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	priv1, _ := crypto.GenerateKey()
+	rp := &remotePeer{PrivKey: priv1, Config: cfg}
+	rp.Start()
+	defer rp.Stop()
+
+	require.NoError(t, sw.DialPeerWithAddress(rp.Addr()))
+	p := sw.Peers().Get(rp.ID())
+	require.NotNil(t, p)
+	for i := 0; i < banCfg.PeerBanMinBadEvents; i++ {
+		sw.MarkPeerAsBad(p, errors.New("bad message"))
+	}
+	require.True(t, sw.IsPeerBanned(rp.ID()))
+	require.Error(t, sw.DialPeerWithAddress(rp.Addr()))
+
+	// the score is still below the threshold, the expired ban isn't renewed
+	require.Eventually(t, func() bool { return !sw.IsPeerBanned(rp.ID()) }, 5*time.Second, 10*time.Millisecond)
+	score, _ := sw.PeerTrustScore(rp.ID())
+	assert.Less(t, score, banCfg.PeerBanThreshold)
+	require.NoError(t, sw.DialPeerWithAddress(rp.Addr()))
+	assert.NotNil(t, sw.Peers().Get(rp.ID()))
+}
+
+func TestSwitchMarksInboundPeerBadByListenAddr(t *testing.T) {
+	store := trust.NewTrustMetricStore(memorydb.New(), trust.DefaultConfig())
+	sw := MakeSwitch(cfg, 1, "testing", "123.123.123", initSwitchFunc, SwitchTrustMetricStore(store))
+	book := &AddrBookMock{
+		Addrs:    make(map[string]struct{}),
+		OurAddrs: make(map[string]struct{}),
+		BadAddrs: make(map[string]struct{}),
+	}
+	sw.SetAddrBook(book)
+	require.NoError(t, sw.Start())
+	t.Cleanup(func() {
+		if err := sw.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	priv1, _ := crypto.GenerateKey()
+	rp := &remotePeer{PrivKey: priv1, Config: cfg}
+	rp.Start()
+	defer rp.Stop()
+
+	conn, err := rp.Dial(sw.NetAddress())
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Eventually(t, func() bool { return sw.Peers().Get(rp.ID()) != nil }, 5*time.Second, 10*time.Millisecond)
+	p := sw.Peers().Get(rp.ID())
+	require.False(t, p.IsOutbound())
+
+	for i := 0; i < cfg.PeerBanMinBadEvents; i++ {
+		sw.MarkPeerAsBad(p, errors.New("bad message"))
+	}
+	listenAddr, err := p.NodeInfo().NetAddress()
+	require.NoError(t, err)
+	assert.Contains(t, book.BadAddrs, listenAddr.String())
+	assert.NotContains(t, book.BadAddrs, p.SocketAddr().String())
+}
+
 func TestSwitchDialPeersAsync(t *testing.T) {
 	if testing.Short() {
 		return
@@ -218,6 +218,45 @@ func MakeSwitch(
 	return sw
 }
 
+// MakeMemorySwitch is like MakeSwitch, with the switch listening on a
+// MemoryTransport of the network.
+func MakeMemorySwitch(
+	cfg *configs.P2PConfig,
+	i int,
+	network *MemoryNetwork,
+	initSwitch func(int, *Switch) *Switch,
+	opts ...SwitchOption,
+) *Switch {
+	priv, _ := crypto.GenerateKey()
+	nodeKey := NodeKey{
+		PrivKey: priv,
+	}
+	addr := network.NetAddress(nodeKey.ID())
+	ni := testNodeInfo(nodeKey.ID(), fmt.Sprintf("node%d", i)).(DefaultNodeInfo)
+	ni.ListenAddr = addr.DialString()
+
+	t := NewMemoryTransport(network, ni, nodeKey)
+	if err := t.Listen(addr); err != nil {
+		panic(err)
+	}
+
+	sw := initSwitch(i, NewSwitch(cfg, t, opts...))
+	sw.SetLogger(log.TestingLogger())
+	sw.SetNodeKey(&nodeKey)
+
+	for ch := range sw.reactorsByCh {
+		ni.Channels = append(ni.Channels, ch)
+	}
+	// Keep the capabilities initSwitch advertised
+	if advertised, ok := sw.NodeInfo().(DefaultNodeInfo); ok {
+		ni.Capabilities = advertised.Capabilities
+	}
+	t.SetNodeInfo(ni)
+	sw.SetNodeInfo(ni)
+
+	return sw
+}
+
 func testInboundPeerConn(
 	conn net.Conn,
 	config *configs.P2PConfig,
@@ -286,6 +325,7 @@ type AddrBookMock struct {
 	Addrs        map[string]struct{}
 	OurAddrs     map[string]struct{}
 	PrivateAddrs map[string]struct{}
+	BadAddrs     map[string]struct{} // recorded if not nil
 }
 
 var _ AddrBook = (*AddrBookMock)(nil)
@@ -300,6 +340,11 @@ func (book *AddrBookMock) OurAddress(addr *NetAddress) bool {
 	return ok
 }
 func (book *AddrBookMock) MarkGood(ID) {}
+func (book *AddrBookMock) MarkBad(addr *NetAddress, _ time.Duration) {
+	if book.BadAddrs != nil {
+		book.BadAddrs[addr.String()] = struct{}{}
+	}
+}
 func (book *AddrBookMock) HasAddress(addr *NetAddress) bool {
 	_, ok := book.Addrs[addr.String()]
 	return ok
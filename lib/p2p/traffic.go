@@ -0,0 +1,97 @@
+package p2p
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kardiachain/go-kardia/lib/metrics"
+)
+
+// PeerTraffic is the current traffic with a peer.
+type PeerTraffic struct {
+	ID ID `json:"id"`
+	// Current rates, in bytes per second.
+	SendRate int64 `json:"send_rate"`
+	RecvRate int64 `json:"recv_rate"`
+	// Pending messages in the send queues.
+	SendQueueSize int `json:"send_queue_size"`
+	// Name of the channel with the highest current rate.
+	TopChannel string `json:"top_channel"`
+}
+
+func peerTraffic(p Peer) PeerTraffic {
+	status := p.Status()
+	traffic := PeerTraffic{
+		ID:       p.ID(),
+		SendRate: status.SendMonitor.CurRate,
+		RecvRate: status.RecvMonitor.CurRate,
+	}
+	var topRate int64
+	for _, ch := range status.Channels {
+		traffic.SendQueueSize += ch.SendQueueSize
+		if rate := ch.SendRate + ch.RecvRate; rate > topRate {
+			topRate = rate
+			traffic.TopChannel = ch.Name
+		}
+	}
+	return traffic
+}
+
+// TopTalkers returns the traffic of the n peers with the highest current
+// send and receive rate, highest first. The rates are moving averages over
+// the last second. All peers are returned if n is not positive.
+func (sw *Switch) TopTalkers(n int) []PeerTraffic {
+	peers := sw.peers.List()
+	talkers := make([]PeerTraffic, 0, len(peers))
+	for _, p := range peers {
+		talkers = append(talkers, peerTraffic(p))
+	}
+	sort.Slice(talkers, func(i, j int) bool {
+		ri := talkers[i].SendRate + talkers[i].RecvRate
+		rj := talkers[j].SendRate + talkers[j].RecvRate
+		if ri != rj {
+			return ri > rj
+		}
+		return talkers[i].ID < talkers[j].ID
+	})
+	if n > 0 && len(talkers) > n {
+		talkers = talkers[:n]
+	}
+	return talkers
+}
+
+// peerGauges reports the traffic of a peer as gauges labelled with its ID,
+// which are unregistered when the peer goes away.
+type peerGauges struct {
+	peer  ID
+	names map[string]struct{}
+}
+
+func newPeerGauges(peer ID) *peerGauges {
+	return &peerGauges{peer: peer, names: make(map[string]struct{})}
+}
+
+func (g *peerGauges) update(status ConnectionStatus) {
+	g.set("p2p/peer/send/rate", "", status.SendMonitor.CurRate)
+	g.set("p2p/peer/recv/rate", "", status.RecvMonitor.CurRate)
+	for _, ch := range status.Channels {
+		g.set("p2p/peer/send/queue", ch.Name, int64(ch.SendQueueSize))
+	}
+}
+
+func (g *peerGauges) set(name, channel string, value int64) {
+	if channel != "" {
+		name += fmt.Sprintf("{peer=%q,channel=%q}", g.peer, channel)
+	} else {
+		name += fmt.Sprintf("{peer=%q}", g.peer)
+	}
+	g.names[name] = struct{}{}
+	metrics.GetOrRegisterGauge(name, nil).Update(value)
+}
+
+func (g *peerGauges) unregister() {
+	for name := range g.names {
+		metrics.DefaultRegistry.Unregister(name)
+	}
+	g.names = make(map[string]struct{})
+}
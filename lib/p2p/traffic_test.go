@@ -0,0 +1,77 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/lib/metrics"
+	"github.com/kardiachain/go-kardia/lib/p2p/conn"
+)
+
+func TestSwitchTopTalkers(t *testing.T) {
+	s1, s2 := MakeSwitchPair(t, func(i int, sw *Switch) *Switch {
+		sw.SetAddrBook(&AddrBookMock{
+			Addrs:    make(map[string]struct{}),
+			OurAddrs: make(map[string]struct{})})
+		sw.AddReactor("foo", NewTestReactor([]*conn.ChannelDescriptor{
+			{ID: byte(0x00), Priority: 10, Name: "quiet"},
+			{ID: byte(0x01), Priority: 10, Name: "chatty"},
+		}, false))
+		return sw
+	})
+	t.Cleanup(func() {
+		_ = s1.Stop()
+		_ = s2.Stop()
+	})
+
+	msg := make([]byte, 10000)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			s1.Broadcast(byte(0x01), msg)
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	assert.Eventually(t, func() bool {
+		talkers := s1.TopTalkers(1)
+		return len(talkers) == 1 && talkers[0].SendRate > 0 && talkers[0].TopChannel == "chatty"
+	}, 5*time.Second, 50*time.Millisecond)
+	talkers := s1.TopTalkers(0)
+	require.Len(t, talkers, 1)
+	assert.Equal(t, s2.NodeInfo().ID(), talkers[0].ID)
+
+	assert.Eventually(t, func() bool {
+		talkers := s2.TopTalkers(1)
+		return len(talkers) == 1 && talkers[0].RecvRate > 0 && talkers[0].TopChannel == "chatty"
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestPeerGauges(t *testing.T) {
+	g := newPeerGauges(ID("abc"))
+	g.update(ConnectionStatus{
+		Channels: []conn.ChannelStatus{{Name: "txpool", SendQueueSize: 3}},
+	})
+
+	names := []string{
+		`p2p/peer/send/rate{peer="abc"}`,
+		`p2p/peer/recv/rate{peer="abc"}`,
+		`p2p/peer/send/queue{peer="abc",channel="txpool"}`,
+	}
+	for _, name := range names {
+		assert.NotNil(t, metrics.DefaultRegistry.Get(name), name)
+	}
+	g.unregister()
+	for _, name := range names {
+		assert.Nil(t, metrics.DefaultRegistry.Get(name), name)
+	}
+}
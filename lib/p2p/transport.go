@@ -130,6 +130,12 @@ func MultiplexTransportMaxIncomingConnections(n int) MultiplexTransportOption {
 	return func(mt *MultiplexTransport) { mt.maxIncomingConnections = n }
 }
 
+// MultiplexTransportInboundLimits sets the limits inbound connections are
+// admitted under, before any handshake. Default: no limits.
+func MultiplexTransportInboundLimits(limits InboundLimits) MultiplexTransportOption {
+	return func(mt *MultiplexTransport) { mt.limiter = newInboundLimiter(limits) }
+}
+
 // MultiplexTransport accepts and dials tcp connections and upgrades them to
 // multiplexed peers.
 type MultiplexTransport struct {
@@ -144,6 +150,9 @@ type MultiplexTransport struct {
 	conns       ConnSet
 	connFilters []ConnFilterFunc
 
+	// Admission control for inbound connections, nil if unlimited.
+	limiter *inboundLimiter
+
 	dialTimeout      time.Duration
 	filterTimeout    time.Duration
 	handshakeTimeout time.Duration
@@ -307,7 +316,10 @@ func (mt *MultiplexTransport) acceptPeers() {
 				netAddr    *NetAddress
 			)
 
-			err := mt.filterConn(c)
+			err := mt.admitConn(c)
+			if err == nil {
+				err = mt.filterConn(c)
+			}
 			if err == nil {
 				secretConn, nodeInfo, err = mt.upgrade(c, nil)
 				if err == nil {
@@ -333,18 +345,41 @@ func (mt *MultiplexTransport) acceptPeers() {
 // closes the connection.
 func (mt *MultiplexTransport) Cleanup(p Peer) {
 	mt.conns.RemoveAddr(p.RemoteAddr())
+	mt.releaseConn(p.RemoteAddr())
 	_ = p.CloseConn()
 }
 
 func (mt *MultiplexTransport) cleanup(c net.Conn) error {
 	mt.conns.Remove(c)
+	mt.releaseConn(c.RemoteAddr())
 
 	return c.Close()
 }
 
+// admitConn applies the inbound limits to an accepted connection, closing it
+// if it exceeds them.
+func (mt *MultiplexTransport) admitConn(c net.Conn) error {
+	if mt.limiter == nil {
+		return nil
+	}
+	if err := mt.limiter.admit(c.RemoteAddr()); err != nil {
+		_ = c.Close()
+		return ErrRejected{conn: c, err: err, isLimited: true}
+	}
+	return nil
+}
+
+// releaseConn stops counting the connection against the inbound limits.
+func (mt *MultiplexTransport) releaseConn(addr net.Addr) {
+	if mt.limiter != nil {
+		mt.limiter.release(addr)
+	}
+}
+
 func (mt *MultiplexTransport) filterConn(c net.Conn) (err error) {
 	defer func() {
 		if err != nil {
+			mt.releaseConn(c.RemoteAddr())
 			_ = c.Close()
 		}
 	}()
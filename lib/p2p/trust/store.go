@@ -15,7 +15,10 @@ import (
 
 const defaultStorePeriodicSaveInterval = 1 * time.Minute
 
-var trustMetricKey = []byte("trustMetricStore")
+var (
+	trustMetricKey = []byte("trustMetricStore")
+	trustBanKey    = []byte("trustMetricBans")
+)
 
 // MetricStore - Manages all trust metrics for peers
 type MetricStore struct {
@@ -24,6 +27,9 @@ type MetricStore struct {
 	// Maps a Peer.Key to that peer's TrustMetric
 	peerMetrics map[string]*Metric
 
+	// Maps a Peer.Key to the time its ban expires
+	peerBans map[string]time.Time
+
 	// Mutex that protects the map and history data file
 	mtx ksync.Mutex
 
@@ -40,6 +46,7 @@ type MetricStore struct {
 func NewTrustMetricStore(db kaidb.Database, tmc MetricConfig) *MetricStore {
 	tms := &MetricStore{
 		peerMetrics: make(map[string]*Metric),
+		peerBans:    make(map[string]time.Time),
 		db:          db,
 		config:      tmc,
 	}
@@ -118,6 +125,51 @@ func (tms *MetricStore) GetPeerTrustMetric(key string) *Metric {
 	return tm
 }
 
+// PeerTrustScore returns the trust score of the peer identified by the key,
+// without creating a metric for peers the store has not seen yet
+func (tms *MetricStore) PeerTrustScore(key string) (int, bool) {
+	tms.mtx.Lock()
+	tm, ok := tms.peerMetrics[key]
+	tms.mtx.Unlock()
+
+	if !ok {
+		return 0, false
+	}
+	return tm.TrustScore(), true
+}
+
+// BanPeer bans the peer identified by the key until the given time.
+// An existing ban is only ever extended
+func (tms *MetricStore) BanPeer(key string, until time.Time) {
+	tms.mtx.Lock()
+	defer tms.mtx.Unlock()
+
+	if key == "" {
+		return
+	}
+	if cur, ok := tms.peerBans[key]; ok && cur.After(until) {
+		return
+	}
+	tms.peerBans[key] = until
+}
+
+// PeerBannedUntil returns the time the ban of the peer identified by the key
+// expires, and false if the peer is not currently banned
+func (tms *MetricStore) PeerBannedUntil(key string) (time.Time, bool) {
+	tms.mtx.Lock()
+	defer tms.mtx.Unlock()
+
+	until, ok := tms.peerBans[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	if !until.After(time.Now()) {
+		delete(tms.peerBans, key)
+		return time.Time{}, false
+	}
+	return until, true
+}
+
 // PeerDisconnected pauses the trust metric associated with the peer identified by the key
 func (tms *MetricStore) PeerDisconnected(key string) {
 	tms.mtx.Lock()
@@ -175,6 +227,20 @@ func (tms *MetricStore) loadFromDB() bool {
 		// Load the peer trust metric into the store
 		tms.peerMetrics[key] = tm
 	}
+
+	// Restore the bans that have not expired yet
+	if bytes, _ := tms.db.Get(trustBanKey); bytes != nil {
+		bans := make(map[string]time.Time)
+		if err := json.Unmarshal(bytes, &bans); err != nil {
+			panic(fmt.Sprintf("Could not unmarshal Trust Metric Store ban data: %v", err))
+		}
+		now := time.Now()
+		for key, until := range bans {
+			if until.After(now) {
+				tms.peerBans[key] = until
+			}
+		}
+	}
 	return true
 }
 
@@ -198,6 +264,23 @@ func (tms *MetricStore) saveToDB() {
 	if err := tms.db.Put(trustMetricKey, bytes); err != nil {
 		tms.Logger.Error("failed to flush data to disk", "error", err)
 	}
+
+	// Expired bans are dropped rather than written back
+	now := time.Now()
+	bans := make(map[string]time.Time, len(tms.peerBans))
+	for key, until := range tms.peerBans {
+		if until.After(now) {
+			bans[key] = until
+		}
+	}
+	bytes, err = json.Marshal(bans)
+	if err != nil {
+		tms.Logger.Error("Failed to encode the peer bans", "err", err)
+		return
+	}
+	if err := tms.db.Put(trustBanKey, bytes); err != nil {
+		tms.Logger.Error("failed to flush data to disk", "error", err)
+	}
 }
 
 // Periodically saves the trust history data to the DB
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/kardiachain/go-kardia/kai/kaidb/leveldb"
 	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
@@ -163,3 +164,48 @@ func TestTrustMetricStorePeerScore(t *testing.T) {
 	err = store.Stop()
 	require.NoError(t, err)
 }
+
+func TestTrustMetricStoreBans(t *testing.T) {
+	historyDB := memorydb.New()
+
+	store := NewTrustMetricStore(historyDB, DefaultConfig())
+	store.SetLogger(log.TestingLogger())
+	err := store.Start()
+	require.NoError(t, err)
+
+	until := time.Now().Add(time.Hour)
+	store.BanPeer("banned", until)
+	store.BanPeer("expired", time.Now().Add(-time.Second))
+	// A shorter ban does not shorten an existing one
+	store.BanPeer("banned", time.Now().Add(time.Minute))
+
+	got, ok := store.PeerBannedUntil("banned")
+	assert.True(t, ok)
+	assert.True(t, until.Equal(got))
+	_, ok = store.PeerBannedUntil("expired")
+	assert.False(t, ok)
+
+	// Scores are only reported for peers the store has seen
+	_, ok = store.PeerTrustScore("unknown")
+	assert.False(t, ok)
+	store.GetPeerTrustMetric("known").BadEvents(1)
+	score, ok := store.PeerTrustScore("known")
+	assert.True(t, ok)
+	assert.Less(t, score, 100)
+
+	err = store.Stop()
+	require.NoError(t, err)
+
+	// Bans survive a restart
+	store = NewTrustMetricStore(historyDB, DefaultConfig())
+	store.SetLogger(log.TestingLogger())
+	err = store.Start()
+	require.NoError(t, err)
+
+	got, ok = store.PeerBannedUntil("banned")
+	assert.True(t, ok)
+	assert.True(t, until.Equal(got))
+
+	err = store.Stop()
+	require.NoError(t, err)
+}
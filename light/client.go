@@ -0,0 +1,942 @@
+package light
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kardiachain/go-kardia/lib/log"
+	kmath "github.com/kardiachain/go-kardia/lib/math"
+	"github.com/kardiachain/go-kardia/light/provider"
+	"github.com/kardiachain/go-kardia/light/store"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+type mode byte
+
+const (
+	sequential mode = iota + 1
+	skipping
+
+	defaultPruningSize = 1000
+	// 10s should cover most of the clients.
+	// References:
+	// - http://vancouver-webpages.com/time/web.html
+	// - https://blog.codinghorror.com/keeping-time-on-the-pc/
+	defaultMaxClockDrift = 10 * time.Second
+)
+
+// Option sets a parameter for the light client.
+type Option func(*Client)
+
+// SequentialVerification option configures the light client to sequentially
+// check the blocks (every block, in ascending height order). Note this is
+// much slower than SkippingVerification, albeit more secure.
+func SequentialVerification() Option {
+	return func(c *Client) {
+		c.verificationMode = sequential
+	}
+}
+
+// SkippingVerification option configures the light client to skip blocks as
+// long as {trustLevel} of the old validator set signed the new header. The
+// verifySkipping algorithm from the specification is used for finding the
+// minimal "trust path".
+//
+// trustLevel - fraction of the old validator set (in terms of voting power),
+// which must sign the new header in order for us to trust it. NOTE this only
+// applies to non-adjacent headers. For adjacent headers, sequential
+// verification is used.
+func SkippingVerification(trustLevel kmath.Fraction) Option {
+	return func(c *Client) {
+		c.verificationMode = skipping
+		c.trustLevel = trustLevel
+	}
+}
+
+// PruningSize option sets the maximum amount of light blocks that the light
+// client stores. When Prune() is run, all light blocks that are earlier than
+// the h amount of light blocks will be removed from the store.
+// Default: 1000. A pruning size of 0 will not prune the light client at all.
+func PruningSize(h uint16) Option {
+	return func(c *Client) {
+		c.pruningSize = h
+	}
+}
+
+// Logger option can be used to set a logger for the client.
+func Logger(l log.Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// MaxClockDrift defines how much new header's time can drift into
+// the future relative to the light clients local time. Default: 10s.
+func MaxClockDrift(d time.Duration) Option {
+	return func(c *Client) {
+		c.maxClockDrift = d
+	}
+}
+
+// Client represents a light client, connected to a single chain, which gets
+// light blocks from a primary provider, verifies them either sequentially or by
+// skipping some and stores them in a trusted store (usually, a local DB).
+//
+// Default verification: SkippingVerification(DefaultTrustLevel)
+//
+// Every verified header is cross-checked with the witnesses. When one of them
+// serves a conflicting header that it can prove from the same trusted header,
+// the client returns ErrLightClientAttack carrying the evidence and stops
+// trusting new headers.
+type Client struct {
+	chainID          string
+	trustingPeriod   time.Duration // see TrustOptions.Period
+	verificationMode mode
+	trustLevel       kmath.Fraction
+	maxClockDrift    time.Duration
+
+	// Mutex for locking during changes of the light clients providers
+	providerMutex sync.Mutex
+	// Primary provides us with headers and validator sets
+	primary provider.Provider
+	// Providers used to "witness" new headers.
+	witnesses []provider.Provider
+
+	// Where trusted light blocks are stored.
+	trustedStore store.Store
+	// Highest trusted light block from the store (height=H).
+	latestTrustedBlock *types.LightBlock
+
+	// See PruningSize option
+	pruningSize uint16
+
+	logger log.Logger
+}
+
+// NewClient returns a new light client. It returns an error if it fails to
+// obtain the light block from the primary or they are invalid (e.g. trust
+// hash does not match with the one from the headers).
+//
+// Witnesses are providers, which will be used for cross-checking the primary
+// provider. At least one witness must be given. If no witness is available
+// the light client will return ErrNoWitnesses.
+//
+// See all Option(s) for the additional configuration.
+func NewClient(
+	ctx context.Context,
+	chainID string,
+	trustOptions TrustOptions,
+	primary provider.Provider,
+	witnesses []provider.Provider,
+	trustedStore store.Store,
+	options ...Option) (*Client, error) {
+
+	if err := trustOptions.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid TrustOptions: %w", err)
+	}
+
+	c, err := NewClientFromTrustedStore(chainID, trustOptions.Period, primary, witnesses, trustedStore, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.latestTrustedBlock != nil {
+		c.logger.Info("Checking trusted light block using options")
+		if err := c.checkTrustedHeaderUsingOptions(trustOptions); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.latestTrustedBlock == nil || c.latestTrustedBlock.Height < trustOptions.Height {
+		c.logger.Info("Downloading trusted light block using options")
+		if err := c.initializeWithTrustOptions(ctx, trustOptions); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, err
+}
+
+// NewClientFromTrustedStore initializes existing client from the trusted store.
+//
+// See NewClient
+func NewClientFromTrustedStore(
+	chainID string,
+	trustingPeriod time.Duration,
+	primary provider.Provider,
+	witnesses []provider.Provider,
+	trustedStore store.Store,
+	options ...Option) (*Client, error) {
+
+	c := &Client{
+		chainID:          chainID,
+		trustingPeriod:   trustingPeriod,
+		verificationMode: skipping,
+		trustLevel:       DefaultTrustLevel,
+		maxClockDrift:    defaultMaxClockDrift,
+		primary:          primary,
+		witnesses:        witnesses,
+		trustedStore:     trustedStore,
+		pruningSize:      defaultPruningSize,
+		logger:           log.New(),
+	}
+
+	for _, o := range options {
+		o(c)
+	}
+
+	// Validate the number of witnesses.
+	if len(c.witnesses) < 1 {
+		return nil, ErrNoWitnesses
+	}
+
+	// Verify witnesses are all on the same chain.
+	for i, w := range witnesses {
+		if w.ChainID() != chainID {
+			return nil, fmt.Errorf("witness #%d: %v is on another chain %s, expected %s",
+				i, w, w.ChainID(), chainID)
+		}
+	}
+
+	// Validate trust level.
+	if err := ValidateTrustLevel(c.trustLevel); err != nil {
+		return nil, err
+	}
+
+	if err := c.restoreTrustedLightBlock(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// restoreTrustedLightBlock loads the latest trusted light block from the store
+func (c *Client) restoreTrustedLightBlock() error {
+	lastHeight, err := c.trustedStore.LastLightBlockHeight()
+	if err != nil {
+		return fmt.Errorf("can't get last trusted light block height: %w", err)
+	}
+
+	if lastHeight > 0 {
+		trustedBlock, err := c.trustedStore.LightBlock(lastHeight)
+		if err != nil {
+			return fmt.Errorf("can't get last trusted light block: %w", err)
+		}
+		c.latestTrustedBlock = trustedBlock
+		c.logger.Info("Restored trusted light block", "height", lastHeight)
+	}
+
+	return nil
+}
+
+// checkTrustedHeaderUsingOptions makes sure the light block the store has at
+// the trusted height is the one the options trust. The client refuses to
+// start rather than silently keep a chain the user no longer trusts.
+func (c *Client) checkTrustedHeaderUsingOptions(options TrustOptions) error {
+	var stored *types.LightBlock
+	switch {
+	case options.Height > c.latestTrustedBlock.Height:
+		// The options move the root of trust forward: it's verified when the
+		// new trusted light block is downloaded.
+		return nil
+	case options.Height == c.latestTrustedBlock.Height:
+		stored = c.latestTrustedBlock
+	default:
+		lb, err := c.trustedStore.LightBlock(options.Height)
+		if err != nil {
+			// The trusted height was pruned, or the store started above it:
+			// nothing to compare against.
+			return nil
+		}
+		stored = lb
+	}
+
+	if !stored.Hash().Equal(options.Hash) {
+		return fmt.Errorf("trusted hash %v at height %d differs from the stored one %v; "+
+			"delete the trusted store to reset the light client", options.Hash, options.Height, stored.Hash())
+	}
+	return nil
+}
+
+// initializeWithTrustOptions fetches the weakly-trusted light block from
+// primary provider.
+func (c *Client) initializeWithTrustOptions(ctx context.Context, options TrustOptions) error {
+	// 1) Fetch and verify the light block.
+	l, err := c.lightBlockFromPrimary(ctx, options.Height)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: - Verify func will check if it's expired or not.
+	//       - h.Time is not being checked against time.Now() because we don't
+	//         want to add yet another argument to NewClient* functions.
+	if err := l.ValidateBasic(); err != nil {
+		return err
+	}
+
+	if !l.Hash().Equal(options.Hash) {
+		return fmt.Errorf("expected header's hash %v, but got %v", options.Hash, l.Hash())
+	}
+
+	// 2) Ensure that +2/3 of validators signed correctly.
+	err = l.ValidatorSet.VerifyCommit(c.chainID, l.Commit.BlockID, l.Height, l.Commit)
+	if err != nil {
+		return fmt.Errorf("invalid commit: %w", err)
+	}
+
+	// 3) Cross-verify with witnesses to ensure everybody has the same state.
+	if err := c.compareFirstHeaderWithWitnesses(ctx, l.SignedHeader); err != nil {
+		return err
+	}
+
+	// 4) Persist both of them and continue.
+	return c.updateTrustedLightBlock(l)
+}
+
+// ChainID returns the chain ID the light client was configured with.
+func (c *Client) ChainID() string {
+	return c.chainID
+}
+
+// Primary returns the primary provider.
+//
+// NOTE: provider may be not safe for concurrent access.
+func (c *Client) Primary() provider.Provider {
+	c.providerMutex.Lock()
+	defer c.providerMutex.Unlock()
+	return c.primary
+}
+
+// Witnesses returns the witness providers.
+//
+// NOTE: providers may be not safe for concurrent access.
+func (c *Client) Witnesses() []provider.Provider {
+	c.providerMutex.Lock()
+	defer c.providerMutex.Unlock()
+	return c.witnesses
+}
+
+// TrustedLightBlock returns a trusted light block at the given height (0 -
+// the latest).
+//
+// It returns an error if:
+//   - there are some issues with the trusted store, although that should not
+//     happen normally;
+//   - height is greater than the highest trusted light block height;
+//   - there's no light block at the given height.
+func (c *Client) TrustedLightBlock(height uint64) (*types.LightBlock, error) {
+	if height == 0 {
+		var err error
+		height, err = c.LastTrustedHeight()
+		if err != nil {
+			return nil, err
+		}
+		if height == 0 {
+			return nil, errors.New("no light blocks exist")
+		}
+	}
+	return c.trustedStore.LightBlock(height)
+}
+
+// LastTrustedHeight returns a last trusted height. 0 and nil are returned if
+// there are no trusted headers.
+func (c *Client) LastTrustedHeight() (uint64, error) {
+	return c.trustedStore.LastLightBlockHeight()
+}
+
+// FirstTrustedHeight returns a first trusted height. 0 and nil are returned
+// if there are no trusted headers.
+func (c *Client) FirstTrustedHeight() (uint64, error) {
+	return c.trustedStore.FirstLightBlockHeight()
+}
+
+// Update attempts to advance the state by downloading the latest light block
+// and comparing it with the existing one. It returns a new light block on a
+// successful update. Otherwise, it returns nil (plus an error, if any).
+func (c *Client) Update(ctx context.Context, now time.Time) (*types.LightBlock, error) {
+	lastTrustedHeight, err := c.LastTrustedHeight()
+	if err != nil {
+		return nil, fmt.Errorf("can't get last trusted height: %w", err)
+	}
+
+	if lastTrustedHeight == 0 {
+		// no light blocks yet => wait
+		return nil, nil
+	}
+
+	latestBlock, err := c.lightBlockFromPrimary(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if latestBlock.Height > lastTrustedHeight {
+		err = c.verifyLightBlock(ctx, latestBlock, now)
+		if err != nil {
+			return nil, err
+		}
+		c.logger.Info("Advanced to new state", "height", latestBlock.Height, "hash", latestBlock.Hash())
+		return latestBlock, nil
+	}
+
+	return nil, nil
+}
+
+// VerifyLightBlockAtHeight fetches the light block at the given height and
+// verifies it. It returns the block immediately if it exists in the
+// trustedStore (no verification is needed).
+//
+// height must be > 0.
+//
+// It returns provider.ErrLightBlockNotFound if light block is not found by
+// primary.
+func (c *Client) VerifyLightBlockAtHeight(ctx context.Context, height uint64, now time.Time) (*types.LightBlock, error) {
+	if height == 0 {
+		return nil, errors.New("zero height")
+	}
+
+	// Check if the light block is already verified.
+	h, err := c.TrustedLightBlock(height)
+	if err == nil {
+		c.logger.Debug("Header has already been verified", "height", height, "hash", h.Hash())
+		// Return already trusted light block
+		return h, nil
+	}
+
+	// Request the light block from primary
+	l, err := c.lightBlockFromPrimary(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return l, c.verifyLightBlock(ctx, l, now)
+}
+
+// VerifyHeader verifies a new header against the trusted state. It returns
+// immediately if newHeader exists in trustedStore (no verification is
+// needed). Else it performs one of the two types of verification:
+//
+// SequentialVerification: verifies that 2/3 of the trusted validator set has
+// signed the new header. If the headers are not adjacent, **all** intermediate
+// headers will be requested. Intermediate headers are not saved to database.
+//
+// SkippingVerification(trustLevel): verifies that {trustLevel} of the trusted
+// validator set has signed the new header. If it's not the case and the
+// headers are not adjacent, verifySkipping is performed and necessary (not all)
+// intermediate headers will be requested. See the specification for details.
+// Intermediate headers are not saved to database.
+//
+// If the header, which is older than the currently trusted header, is
+// requested and the light client does not have it, VerifyHeader will perform:
+//
+//	a) verifySkipping verification if nearest trusted header is found & not
+//	   expired
+//	b) backwards verification in all other cases
+//
+// It returns ErrOldHeaderExpired if the latest trusted header expired.
+//
+// If the primary provides an invalid header (ErrInvalidHeader), it is
+// rejected and replaced by another provider until all are exhausted.
+func (c *Client) VerifyHeader(ctx context.Context, newHeader *types.Header, now time.Time) error {
+	if newHeader == nil {
+		return errors.New("nil header")
+	}
+	if newHeader.Height == 0 {
+		return errors.New("zero height")
+	}
+
+	// Check if newHeader already verified.
+	l, err := c.TrustedLightBlock(newHeader.Height)
+	if err == nil {
+		// Make sure it's the same header.
+		if !l.Hash().Equal(newHeader.Hash()) {
+			return fmt.Errorf("existing trusted header %v does not match newHeader %v", l.Hash(), newHeader.Hash())
+		}
+		c.logger.Debug("Header has already been verified",
+			"height", newHeader.Height, "hash", newHeader.Hash())
+		return nil
+	}
+
+	// Request the header and the vals.
+	l, err = c.lightBlockFromPrimary(ctx, newHeader.Height)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve light block from primary to verify against: %w", err)
+	}
+
+	if !l.Hash().Equal(newHeader.Hash()) {
+		return fmt.Errorf("light block header %v does not match newHeader %v", l.Hash(), newHeader.Hash())
+	}
+
+	return c.verifyLightBlock(ctx, l, now)
+}
+
+func (c *Client) verifyLightBlock(ctx context.Context, newLightBlock *types.LightBlock, now time.Time) error {
+	c.logger.Info("VerifyHeader", "height", newLightBlock.Height, "hash", newLightBlock.Hash())
+
+	var (
+		verifyFunc func(ctx context.Context, trusted *types.LightBlock, new *types.LightBlock, now time.Time) error
+		err        error
+	)
+
+	switch c.verificationMode {
+	case sequential:
+		verifyFunc = c.verifySequential
+	case skipping:
+		verifyFunc = c.verifySkippingAgainstPrimary
+	default:
+		panic(fmt.Sprintf("Unknown verification mode: %b", c.verificationMode))
+	}
+
+	firstBlockHeight, err := c.FirstTrustedHeight()
+	if err != nil {
+		return fmt.Errorf("can't get first light block height: %w", err)
+	}
+
+	switch {
+	// Verifying forwards
+	case newLightBlock.Height >= c.latestTrustedBlock.Height:
+		err = verifyFunc(ctx, c.latestTrustedBlock, newLightBlock, now)
+
+	// Verifying backwards
+	case newLightBlock.Height < firstBlockHeight:
+		var firstBlock *types.LightBlock
+		firstBlock, err = c.trustedStore.LightBlock(firstBlockHeight)
+		if err != nil {
+			return fmt.Errorf("can't get first light block: %w", err)
+		}
+		err = c.backwards(ctx, firstBlock.Header, newLightBlock.Header)
+
+	// Verifying between first and last trusted light block
+	default:
+		var closestBlock *types.LightBlock
+		closestBlock, err = c.trustedStore.LightBlockBefore(newLightBlock.Height)
+		if err != nil {
+			return fmt.Errorf("can't get signed header before height %d: %w", newLightBlock.Height, err)
+		}
+		err = verifyFunc(ctx, closestBlock, newLightBlock, now)
+	}
+	if err != nil {
+		c.logger.Error("Can't verify", "err", err)
+		return err
+	}
+
+	// Once verified, save and return
+	return c.updateTrustedLightBlock(newLightBlock)
+}
+
+// see VerifyHeader
+func (c *Client) verifySequential(
+	ctx context.Context,
+	trustedBlock *types.LightBlock,
+	newLightBlock *types.LightBlock,
+	now time.Time) error {
+
+	var (
+		verifiedBlock = trustedBlock
+		interimBlock  *types.LightBlock
+		err           error
+		trace         = []*types.LightBlock{trustedBlock}
+	)
+
+	for height := trustedBlock.Height + 1; height <= newLightBlock.Height; height++ {
+		// 1) Fetch interim light block if needed.
+		if height == newLightBlock.Height { // last light block
+			interimBlock = newLightBlock
+		} else { // intermediate light blocks
+			interimBlock, err = c.lightBlockFromPrimary(ctx, height)
+			if err != nil {
+				return ErrVerificationFailed{From: verifiedBlock.Height, To: height, Reason: err}
+			}
+		}
+
+		// 2) Verify them
+		c.logger.Debug("Verify adjacent newLightBlock against verifiedBlock",
+			"trustedHeight", verifiedBlock.Height,
+			"trustedHash", verifiedBlock.Hash(),
+			"newHeight", interimBlock.Height,
+			"newHash", interimBlock.Hash())
+
+		err = VerifyAdjacent(c.chainID, verifiedBlock.SignedHeader, interimBlock.SignedHeader, interimBlock.ValidatorSet,
+			c.trustingPeriod, now, c.maxClockDrift)
+		if err != nil {
+			err := ErrVerificationFailed{From: verifiedBlock.Height, To: interimBlock.Height, Reason: err}
+
+			var errInvalidHeader ErrInvalidHeader
+			if !errors.As(err, &errInvalidHeader) {
+				return err
+			}
+			// If the primary sent an invalid header, replace it with a
+			// witness and try again from the last verified block.
+			c.logger.Error("primary sent invalid header -> replacing", "err", err, "primary", c.primary)
+			replacementBlock, removeErr := c.findNewPrimary(ctx, newLightBlock.Height, true)
+			if removeErr != nil {
+				c.logger.Debug("failed to replace primary. Returning original error", "err", removeErr)
+				return err
+			}
+			if !replacementBlock.Hash().Equal(newLightBlock.Hash()) {
+				c.logger.Error("Replacement provider has a different light block",
+					"newHash", newLightBlock.Hash(),
+					"replHash", replacementBlock.Hash())
+				// return original error
+				return err
+			}
+
+			// attempt to verify header again
+			height--
+			continue
+		}
+
+		// 3) Update verifiedBlock
+		verifiedBlock = interimBlock
+
+		// 4) Add the light block to the trace
+		trace = append(trace, interimBlock)
+	}
+
+	// Compare header with the witnesses to ensure it's not a fork.
+	// More witnesses we have, more chance to notice one.
+	//
+	// CORRECTNESS ASSUMPTION: there's at least 1 correct full node
+	// (primary or one of the witnesses).
+	return c.detectDivergence(ctx, trace, now)
+}
+
+// verifySkipping verifies the newLightBlock against the trustedBlock using
+// the source provider for the intermediate blocks it needs, see
+// VerifySkipping.
+func (c *Client) verifySkipping(
+	ctx context.Context,
+	source provider.Provider,
+	trustedBlock *types.LightBlock,
+	newLightBlock *types.LightBlock,
+	now time.Time) ([]*types.LightBlock, error) {
+
+	c.logger.Debug("Verify non-adjacent newHeader against trustedBlock",
+		"trustedHeight", trustedBlock.Height,
+		"trustedHash", trustedBlock.Hash(),
+		"newHeight", newLightBlock.Height,
+		"newHash", newLightBlock.Hash())
+
+	fetch := func(ctx context.Context, height uint64) (*types.LightBlock, error) {
+		return c.lightBlockFrom(ctx, source, height)
+	}
+	return VerifySkipping(ctx, c.chainID, fetch, trustedBlock, newLightBlock,
+		c.trustingPeriod, now, c.maxClockDrift, c.trustLevel)
+}
+
+// verifySkippingAgainstPrimary does verifySkipping plus it compares new header
+// with witnesses and replaces primary if it sends the light client an invalid
+// header.
+func (c *Client) verifySkippingAgainstPrimary(
+	ctx context.Context,
+	trustedBlock *types.LightBlock,
+	newLightBlock *types.LightBlock,
+	now time.Time) error {
+
+	trace, err := c.verifySkipping(ctx, c.Primary(), trustedBlock, newLightBlock, now)
+	if err != nil {
+		var errInvalidHeader ErrInvalidHeader
+		if !errors.As(err, &errInvalidHeader) {
+			return err
+		}
+		// If the primary sent an invalid header, replace it with a witness
+		// and try again.
+		c.logger.Error("primary sent invalid header -> replacing", "err", err, "primary", c.Primary())
+		replacementBlock, removeErr := c.findNewPrimary(ctx, newLightBlock.Height, true)
+		if removeErr != nil {
+			c.logger.Error("failed to replace primary. Returning original error", "err", removeErr)
+			return err
+		}
+		if !replacementBlock.Hash().Equal(newLightBlock.Hash()) {
+			c.logger.Error("Replacement provider has a different light block",
+				"newHash", newLightBlock.Hash(),
+				"replHash", replacementBlock.Hash())
+			// return original error
+			return err
+		}
+		// attempt to verify the header again
+		return c.verifySkippingAgainstPrimary(ctx, trustedBlock, replacementBlock, now)
+	}
+
+	// Compare header with the witnesses to ensure it's not a fork.
+	// More witnesses we have, more chance to notice one.
+	//
+	// CORRECTNESS ASSUMPTION: there's at least 1 correct full node
+	// (primary or one of the witnesses).
+	return c.detectDivergence(ctx, trace, now)
+}
+
+// backwards verifies the newHeader by walking the hash links down from the
+// trusted header. Intermediate headers are not stored.
+func (c *Client) backwards(ctx context.Context, trustedHeader *types.Header, newHeader *types.Header) error {
+	var (
+		verifiedHeader = trustedHeader
+		interimHeader  *types.Header
+	)
+
+	for verifiedHeader.Height > newHeader.Height {
+		interimBlock, err := c.lightBlockFromPrimary(ctx, verifiedHeader.Height-1)
+		if err != nil {
+			return fmt.Errorf("failed to obtain the header at height #%d: %w", verifiedHeader.Height-1, err)
+		}
+		interimHeader = interimBlock.Header
+		c.logger.Debug("Verify newHeader against verifiedHeader",
+			"trustedHeight", verifiedHeader.Height,
+			"trustedHash", verifiedHeader.Hash(),
+			"newHeight", interimHeader.Height,
+			"newHash", interimHeader.Hash())
+		if err := VerifyBackwards(interimHeader, verifiedHeader); err != nil {
+			c.logger.Error("primary sent invalid header -> replacing", "err", err, "primary", c.Primary())
+			if _, replaceErr := c.findNewPrimary(ctx, newHeader.Height, true); replaceErr != nil {
+				c.logger.Error("Can't replace primary", "err", replaceErr)
+				// return original error
+				return fmt.Errorf("verify backwards from %d to %d failed: %w",
+					verifiedHeader.Height, interimHeader.Height, err)
+			}
+			// we need to verify the header at the same height again
+			continue
+		}
+		verifiedHeader = interimHeader
+	}
+
+	// The last fetched header is the one asked for, make sure it's not just
+	// a header at the same height.
+	if !verifiedHeader.Hash().Equal(newHeader.Hash()) {
+		return fmt.Errorf("header at height %d (%v) does not match the one verified backwards (%v)",
+			newHeader.Height, newHeader.Hash(), verifiedHeader.Hash())
+	}
+	return nil
+}
+
+// updateTrustedLightBlock saves the light block and prunes the store.
+func (c *Client) updateTrustedLightBlock(l *types.LightBlock) error {
+	c.logger.Debug("Updating trusted light block", "light_block", l)
+
+	if err := c.trustedStore.SaveLightBlock(l); err != nil {
+		return fmt.Errorf("failed to save trusted header: %w", err)
+	}
+
+	if c.pruningSize > 0 {
+		if err := c.trustedStore.Prune(c.pruningSize); err != nil {
+			return fmt.Errorf("prune: %w", err)
+		}
+	}
+
+	if c.latestTrustedBlock == nil || l.Height > c.latestTrustedBlock.Height {
+		c.latestTrustedBlock = l
+	}
+
+	return nil
+}
+
+// lightBlockFromPrimary retrieves the lightBlock from the primary provider
+// at the specified height. If the primary is unresponsive or sends an
+// invalid light block, it is replaced by a witness.
+func (c *Client) lightBlockFromPrimary(ctx context.Context, height uint64) (*types.LightBlock, error) {
+	c.providerMutex.Lock()
+	l, err := c.lightBlockFrom(ctx, c.primary, height)
+	c.providerMutex.Unlock()
+
+	switch {
+	case err == nil:
+		return l, nil
+
+	case errors.Is(err, provider.ErrLightBlockNotFound), errors.Is(err, provider.ErrHeightTooHigh):
+		// The primary doesn't have the block: it's up to the caller to try
+		// later, the primary isn't faulty.
+		return nil, err
+
+	case errors.Is(err, provider.ErrNoResponse):
+		// The primary is unresponsive, swap it with a witness but keep it
+		// around: it may come back.
+		c.logger.Info("Primary is unresponsive, replacing...", "height", height, "primary", c.Primary())
+		return c.findNewPrimary(ctx, height, false)
+
+	default:
+		// The primary sent a bad light block, replace it for good.
+		c.logger.Info("Error from light block request from primary, replacing...",
+			"error", err, "height", height, "primary", c.Primary())
+		return c.findNewPrimary(ctx, height, true)
+	}
+}
+
+// lightBlockFrom fetches a light block from the provider and checks it's
+// well formed and at the requested height.
+func (c *Client) lightBlockFrom(ctx context.Context, p provider.Provider, height uint64) (*types.LightBlock, error) {
+	l, err := p.LightBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.ValidateBasic(); err != nil {
+		return nil, provider.ErrBadLightBlock{Reason: err}
+	}
+	if height != 0 && l.Height != height {
+		return nil, provider.ErrBadLightBlock{
+			Reason: fmt.Errorf("height %d responded doesn't match height %d requested", l.Height, height)}
+	}
+	return l, nil
+}
+
+// findNewPrimary concurrently sends a light block request, promoting the
+// first witness to return a valid light block as the new primary. The
+// remove option indicates whether the primary should be entirely removed or
+// just appended to the back of the witnesses list.
+func (c *Client) findNewPrimary(ctx context.Context, height uint64, remove bool) (*types.LightBlock, error) {
+	c.providerMutex.Lock()
+	defer c.providerMutex.Unlock()
+
+	if len(c.witnesses) == 0 {
+		return nil, ErrNoWitnesses
+	}
+
+	type witnessResponse struct {
+		lb           *types.LightBlock
+		witnessIndex int
+		err          error
+	}
+
+	var (
+		witnessResponsesC = make(chan witnessResponse, len(c.witnesses))
+		witnessesToRemove []int
+		lastError         error
+		wg                sync.WaitGroup
+	)
+
+	subctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// send out a light block request to all witnesses
+	for index := range c.witnesses {
+		wg.Add(1)
+		go func(witnessIndex int, witnessResponsesC chan witnessResponse) {
+			defer wg.Done()
+
+			lb, err := c.lightBlockFrom(subctx, c.witnesses[witnessIndex], height)
+			witnessResponsesC <- witnessResponse{lb, witnessIndex, err}
+		}(index, witnessResponsesC)
+	}
+
+	// process all the responses as they come in
+	for i := 0; i < cap(witnessResponsesC); i++ {
+		response := <-witnessResponsesC
+		switch {
+		// successfully found a new primary
+		case response.err == nil:
+			// stop the other witnesses from responding
+			cancel()
+			wg.Wait()
+
+			// if we are not intending on removing the primary then append the old primary to the end of the witness slice
+			if !remove {
+				c.witnesses = append(c.witnesses, c.primary)
+			}
+
+			// promote respondent as the new primary
+			c.logger.Debug("found new primary", "primary", c.witnesses[response.witnessIndex])
+			c.primary = c.witnesses[response.witnessIndex]
+
+			// add promoted witness to the list of witnesses to be removed
+			witnessesToRemove = append(witnessesToRemove, response.witnessIndex)
+
+			// remove witnesses marked as bad (the lock is held)
+			c.removeWitnesses(witnessesToRemove)
+
+			return response.lb, nil
+
+		// process benign errors by logging them only
+		case errors.Is(response.err, provider.ErrNoResponse), errors.Is(response.err, provider.ErrLightBlockNotFound),
+			errors.Is(response.err, provider.ErrHeightTooHigh):
+			lastError = response.err
+			c.logger.Debug("error on light block request from witness",
+				"error", response.err, "primary", c.witnesses[response.witnessIndex])
+			continue
+
+		// all other errors such as ErrBadLightBlock or ErrUnreliableProvider are seen as malevolent and the
+		// provider is removed
+		default:
+			witnessesToRemove = append(witnessesToRemove, response.witnessIndex)
+			c.logger.Debug("error on light block request from witness, removing...",
+				"error", response.err, "primary", c.witnesses[response.witnessIndex])
+		}
+	}
+
+	// remove witnesses marked as bad (the lock is held)
+	c.removeWitnesses(witnessesToRemove)
+
+	if lastError == nil {
+		lastError = ErrNoWitnesses
+	}
+	return nil, lastError
+}
+
+// compareFirstHeaderWithWitnesses compares h with all witnesses. If any
+// witness reports a different header than h, the function returns an error.
+func (c *Client) compareFirstHeaderWithWitnesses(ctx context.Context, h *types.SignedHeader) error {
+	c.providerMutex.Lock()
+	defer c.providerMutex.Unlock()
+
+	if len(c.witnesses) == 0 {
+		return ErrNoWitnesses
+	}
+
+	errc := make(chan error, len(c.witnesses))
+	for i, witness := range c.witnesses {
+		go c.compareNewHeaderWithWitness(ctx, errc, h, witness, i)
+	}
+
+	witnessesToRemove := make([]int, 0, len(c.witnesses))
+
+	// handle errors from the header comparisons as they come in
+	for i := 0; i < cap(errc); i++ {
+		err := <-errc
+
+		switch e := err.(type) {
+		case nil:
+			continue
+		case errConflictingHeaders:
+			c.logger.Error("Witness has a different header. Please check primary is correct and remove witness. "+
+				"Otherwise, use a different primary", "index", e.WitnessIndex, "witness", c.witnesses[e.WitnessIndex])
+			return err
+		case errBadWitness:
+			// If witness sent us an invalid header, then remove it
+			c.logger.Info("witness sent an invalid light block, removing...",
+				"witness", c.witnesses[e.WitnessIndex],
+				"err", err)
+			witnessesToRemove = append(witnessesToRemove, e.WitnessIndex)
+		default: // benign errors can be ignored with the exception of context errors
+			if errors.Is(e, context.Canceled) || errors.Is(e, context.DeadlineExceeded) {
+				return e
+			}
+
+			// the witness either didn't respond or didn't have the block. We ignore it.
+			c.logger.Debug("unable to compare first header with witness",
+				"err", err)
+		}
+	}
+
+	// remove all witnesses that misbehaved (the lock is held)
+	c.removeWitnesses(witnessesToRemove)
+	return nil
+}
+
+// removeWitnesses removes the witnesses at the given indexes.
+//
+// NOTE: requires a providerMutex lock.
+func (c *Client) removeWitnesses(indexes []int) {
+	if len(indexes) == 0 {
+		return
+	}
+	remove := make(map[int]bool, len(indexes))
+	for _, idx := range indexes {
+		remove[idx] = true
+	}
+	witnesses := make([]provider.Provider, 0, len(c.witnesses))
+	for idx, w := range c.witnesses {
+		if !remove[idx] {
+			witnesses = append(witnesses, w)
+		}
+	}
+	c.witnesses = witnesses
+}
@@ -0,0 +1,178 @@
+package light
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
+	kmath "github.com/kardiachain/go-kardia/lib/math"
+	"github.com/kardiachain/go-kardia/light/provider"
+	"github.com/kardiachain/go-kardia/light/provider/mock"
+	dbs "github.com/kardiachain/go-kardia/light/store/db"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+func trustOptions(lb *types.LightBlock) TrustOptions {
+	return TrustOptions{Period: trustingPeriod, Height: lb.Height, Hash: lb.Hash()}
+}
+
+func TestClientVerifyLightBlockAtHeight(t *testing.T) {
+	keys := genKeys(8)
+	blocks := genChain(t, keys, 12, changingVals(keys), 0)
+	now := bTime.Add(time.Hour)
+
+	testCases := []struct {
+		name string
+		opt  Option
+	}{
+		{"sequential", SequentialVerification()},
+		{"skipping", SkippingVerification(DefaultTrustLevel)},
+		{"skipping with high trust level", SkippingVerification(kmath.Fraction{Numerator: 2, Denominator: 3})},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewClient(context.Background(), chainID, trustOptions(blocks[1]),
+				mock.New(chainID, blocks), []provider.Provider{mock.New(chainID, blocks)},
+				dbs.New(memorydb.New()), tc.opt)
+			require.NoError(t, err)
+
+			lb, err := c.VerifyLightBlockAtHeight(context.Background(), 12, now)
+			require.NoError(t, err)
+			assert.Equal(t, blocks[12].Hash(), lb.Hash())
+
+			trusted, err := c.TrustedLightBlock(0)
+			require.NoError(t, err)
+			assert.Equal(t, blocks[12].Hash(), trusted.Hash())
+
+			// In between the trusted blocks.
+			lb, err = c.VerifyLightBlockAtHeight(context.Background(), 7, now)
+			require.NoError(t, err)
+			assert.Equal(t, blocks[7].Hash(), lb.Hash())
+		})
+	}
+}
+
+func TestClientUpdate(t *testing.T) {
+	keys := genKeys(8)
+	blocks := genChain(t, keys, 12, changingVals(keys), 0)
+	now := bTime.Add(time.Hour)
+
+	primary := mock.New(chainID, map[uint64]*types.LightBlock{1: blocks[1], 2: blocks[2]})
+	witness := mock.New(chainID, blocks)
+	c, err := NewClient(context.Background(), chainID, trustOptions(blocks[1]), primary,
+		[]provider.Provider{witness}, dbs.New(memorydb.New()))
+	require.NoError(t, err)
+
+	lb, err := c.Update(context.Background(), now)
+	require.NoError(t, err)
+	require.NotNil(t, lb)
+	assert.EqualValues(t, 2, lb.Height)
+
+	// Nothing new.
+	lb, err = c.Update(context.Background(), now)
+	require.NoError(t, err)
+	assert.Nil(t, lb)
+
+	for height := uint64(3); height <= 12; height++ {
+		primary.AddLightBlock(blocks[height])
+	}
+	lb, err = c.Update(context.Background(), now)
+	require.NoError(t, err)
+	require.NotNil(t, lb)
+	assert.EqualValues(t, 12, lb.Height)
+}
+
+func TestClientBackwardsVerification(t *testing.T) {
+	keys := genKeys(8)
+	blocks := genChain(t, keys, 12, changingVals(keys), 0)
+	now := bTime.Add(time.Hour)
+
+	c, err := NewClient(context.Background(), chainID, trustOptions(blocks[8]),
+		mock.New(chainID, blocks), []provider.Provider{mock.New(chainID, blocks)}, dbs.New(memorydb.New()))
+	require.NoError(t, err)
+
+	lb, err := c.VerifyLightBlockAtHeight(context.Background(), 3, now)
+	require.NoError(t, err)
+	assert.Equal(t, blocks[3].Hash(), lb.Hash())
+
+	// A primary serving another chain below the trusted block can't be
+	// verified backwards, and there's no other provider to fall back to.
+	forked := genChain(t, keys, 12, changingVals(keys), 1)
+	for height := uint64(3); height <= 12; height++ {
+		forked[height] = blocks[height]
+	}
+	c, err = NewClient(context.Background(), chainID, trustOptions(blocks[3]),
+		mock.New(chainID, forked), []provider.Provider{mock.New(chainID, blocks)}, dbs.New(memorydb.New()))
+	require.NoError(t, err)
+	c.witnesses = nil
+	_, err = c.VerifyLightBlockAtHeight(context.Background(), 2, now)
+	assert.Error(t, err)
+}
+
+func TestClientTrustOptions(t *testing.T) {
+	keys := genKeys(8)
+	blocks := genChain(t, keys, 12, changingVals(keys), 0)
+	db := memorydb.New()
+
+	// Wrong hash.
+	opts := trustOptions(blocks[1])
+	opts.Hash = blocks[2].Hash()
+	_, err := NewClient(context.Background(), chainID, opts, mock.New(chainID, blocks),
+		[]provider.Provider{mock.New(chainID, blocks)}, dbs.New(db))
+	assert.Error(t, err)
+
+	// No witnesses.
+	_, err = NewClient(context.Background(), chainID, trustOptions(blocks[1]), mock.New(chainID, blocks),
+		nil, dbs.New(db))
+	assert.Equal(t, ErrNoWitnesses, err)
+
+	// Invalid trust level.
+	_, err = NewClient(context.Background(), chainID, trustOptions(blocks[1]), mock.New(chainID, blocks),
+		[]provider.Provider{mock.New(chainID, blocks)}, dbs.New(db),
+		SkippingVerification(kmath.Fraction{Numerator: 1, Denominator: 4}))
+	assert.Error(t, err)
+
+	// The store is reused, but with other trust options.
+	_, err = NewClient(context.Background(), chainID, trustOptions(blocks[1]), mock.New(chainID, blocks),
+		[]provider.Provider{mock.New(chainID, blocks)}, dbs.New(db))
+	require.NoError(t, err)
+	otherKeys := genKeys(8)
+	other := genChain(t, otherKeys, 1, changingVals(otherKeys), 0)
+	_, err = NewClient(context.Background(), chainID, trustOptions(other[1]), mock.New(chainID, blocks),
+		[]provider.Provider{mock.New(chainID, blocks)}, dbs.New(db))
+	assert.Error(t, err)
+}
+
+func TestClientExpiredTrustedBlock(t *testing.T) {
+	keys := genKeys(8)
+	blocks := genChain(t, keys, 12, changingVals(keys), 0)
+
+	c, err := NewClient(context.Background(), chainID, trustOptions(blocks[1]),
+		mock.New(chainID, blocks), []provider.Provider{mock.New(chainID, blocks)}, dbs.New(memorydb.New()))
+	require.NoError(t, err)
+
+	_, err = c.VerifyLightBlockAtHeight(context.Background(), 12, bTime.Add(2*trustingPeriod))
+	var errExpired ErrOldHeaderExpired
+	assert.True(t, errors.As(err, &errExpired), "unexpected error: %v", err)
+}
+
+func TestClientReplacesUnresponsivePrimary(t *testing.T) {
+	keys := genKeys(8)
+	blocks := genChain(t, keys, 12, changingVals(keys), 0)
+	now := bTime.Add(time.Hour)
+
+	dead := mock.NewDeadMock(chainID)
+	c, err := NewClient(context.Background(), chainID, trustOptions(blocks[1]), dead,
+		[]provider.Provider{mock.New(chainID, blocks), mock.New(chainID, blocks)}, dbs.New(memorydb.New()))
+	require.NoError(t, err)
+	assert.NotEqual(t, dead, c.Primary())
+
+	_, err = c.VerifyLightBlockAtHeight(context.Background(), 12, now)
+	require.NoError(t, err)
+}
@@ -0,0 +1,338 @@
+package light
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kardiachain/go-kardia/light/provider"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// witnessLagWait is how long a witness that doesn't have the header yet is
+// given to catch up before it's considered faulty.
+const witnessLagWait = 5 * time.Second
+
+var errNoDivergence = errors.New(
+	"sanity check failed: no divergence between the original trace and the provider's new trace",
+)
+
+// detectDivergence is a second wall of defense for the light client.
+//
+// It takes the target verified header and compares it with the headers of a
+// set of witness providers that the light client is connected to. If a
+// conflicting header is returned it verifies and examines the conflicting
+// header against the verified trace that was produced from the primary. If
+// successful, it returns ErrLightClientAttack with the evidence against both
+// sides; otherwise, it removes the faulty witness and returns nil.
+//
+// If there are no conflicting headers, the light client deems the verified
+// target header trusted and saves it to the trusted store.
+func (c *Client) detectDivergence(ctx context.Context, primaryTrace []*types.LightBlock, now time.Time) error {
+	if len(primaryTrace) < 2 {
+		return errors.New("nil or single block primary trace")
+	}
+	var (
+		headerMatched      bool
+		lastVerifiedHeader = primaryTrace[len(primaryTrace)-1].SignedHeader
+		witnessesToRemove  = make([]int, 0)
+	)
+	c.logger.Debug("Running detector against trace", "endBlockHeight", lastVerifiedHeader.Height,
+		"endBlockHash", lastVerifiedHeader.Hash(), "length", len(primaryTrace))
+
+	c.providerMutex.Lock()
+	defer c.providerMutex.Unlock()
+
+	if len(c.witnesses) == 0 {
+		return ErrNoWitnesses
+	}
+
+	// launch one goroutine per witness to retrieve the light block of the
+	// target height and compare it with the header from the primary
+	errc := make(chan error, len(c.witnesses))
+	for i, witness := range c.witnesses {
+		go c.compareNewHeaderWithWitness(ctx, errc, lastVerifiedHeader, witness, i)
+	}
+
+	// handle errors from the header comparisons as they come in
+	for i := 0; i < cap(errc); i++ {
+		err := <-errc
+
+		switch e := err.(type) {
+		case nil: // at least one header matched
+			headerMatched = true
+		case errConflictingHeaders:
+			// We have conflicting headers. This could possibly imply an
+			// attack on the light client. First we need to verify the
+			// witness's header using the same skipping verification and then
+			// we need to find the point that the headers diverge and examine
+			// this for any evidence of an attack.
+			//
+			// We combine these actions together, verifying the witnesses
+			// headers and outputting the trace which captures the bifurcation
+			// point and if successful provides the information to create
+			// valid evidence.
+			err := c.handleConflictingHeaders(ctx, primaryTrace, e.Block, e.WitnessIndex, now)
+			if err != nil {
+				// return information of the attack
+				return err
+			}
+			// if attempt to generate conflicting headers failed then remove
+			// witness
+			witnessesToRemove = append(witnessesToRemove, e.WitnessIndex)
+
+		case errBadWitness:
+			c.logger.Info("Witness returned an error during header comparison", "witness", c.witnesses[e.WitnessIndex],
+				"err", err)
+			// if witness sent us an invalid header, then remove it
+			witnessesToRemove = append(witnessesToRemove, e.WitnessIndex)
+
+		default:
+			if errors.Is(e, context.Canceled) || errors.Is(e, context.DeadlineExceeded) {
+				return e
+			}
+			c.logger.Info("Error in light block request to witness", "err", err)
+		}
+	}
+
+	// remove witnesses that have misbehaved (the lock is held)
+	c.removeWitnesses(witnessesToRemove)
+
+	// 1. If we had at least one witness that returned the same header then we
+	// conclude that we can trust the header
+	if headerMatched {
+		return nil
+	}
+
+	// 2. Else all witnesses have either not responded, don't have the block
+	// or sent invalid blocks.
+	return ErrFailedHeaderCrossReferencing
+}
+
+// compareNewHeaderWithWitness takes the verified header from the primary and
+// compares it with the header from a specified witness. The function can
+// return one of three errors:
+//
+// 1: errConflictingHeaders -> there may have been an attack on this light
+// client
+// 2: errBadWitness -> the witness has either not responded, doesn't have the
+// header or has given us an invalid one
+// 3: nil -> the hashes of the two headers match
+func (c *Client) compareNewHeaderWithWitness(ctx context.Context, errc chan error, h *types.SignedHeader,
+	witness provider.Provider, witnessIndex int) {
+
+	lightBlock, err := c.lightBlockFrom(ctx, witness, h.Height)
+	if errors.Is(err, provider.ErrHeightTooHigh) {
+		// The witness may be lagging behind: give it some time to catch
+		// up before holding it as faulty.
+		select {
+		case <-time.After(witnessLagWait):
+		case <-ctx.Done():
+			errc <- ctx.Err()
+			return
+		}
+		lightBlock, err = c.lightBlockFrom(ctx, witness, h.Height)
+		if errors.Is(err, provider.ErrHeightTooHigh) {
+			errc <- errBadWitness{Reason: err, WitnessIndex: witnessIndex}
+			return
+		}
+	}
+
+	switch {
+	case errors.Is(err, provider.ErrNoResponse), errors.Is(err, provider.ErrLightBlockNotFound),
+		errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		errc <- err
+
+	case err != nil:
+		errc <- errBadWitness{Reason: err, WitnessIndex: witnessIndex}
+
+	case !lightBlock.Hash().Equal(h.Hash()):
+		errc <- errConflictingHeaders{Block: lightBlock, WitnessIndex: witnessIndex}
+
+	default:
+		c.logger.Debug("Matching header received by witness", "height", h.Height, "witness", witnessIndex)
+		errc <- nil
+	}
+}
+
+// handleConflictingHeaders handles the primary style of attack, which is
+// where a primary and witness have two headers of the same height but with
+// different hashes.
+//
+// NOTE: requires a providerMutex lock.
+func (c *Client) handleConflictingHeaders(
+	ctx context.Context,
+	primaryTrace []*types.LightBlock,
+	challengingBlock *types.LightBlock,
+	witnessIndex int,
+	now time.Time,
+) error {
+	supportingWitness := c.witnesses[witnessIndex]
+	witnessTrace, primaryBlock, err := c.examineConflictingHeaderAgainstTrace(
+		ctx,
+		primaryTrace,
+		challengingBlock,
+		supportingWitness,
+		now,
+	)
+	if err != nil {
+		c.logger.Info("Error validating witness's divergent header", "witness", supportingWitness, "err", err)
+		return nil
+	}
+
+	// We are suspecting that the primary is faulty, hence we hold the witness
+	// as the source of truth and generate evidence against the primary that
+	// we can send to the witness.
+	commonBlock, trustedBlock := witnessTrace[0], witnessTrace[len(witnessTrace)-1]
+	attack := ErrLightClientAttack{
+		EvidenceAgainstPrimary: types.NewLightClientAttackEvidence(primaryBlock, trustedBlock, commonBlock),
+		WitnessIndex:           witnessIndex,
+	}
+	c.logger.Error("ATTEMPTED ATTACK DETECTED. Primary and witness diverge",
+		"witness", supportingWitness, "height", primaryBlock.Height,
+		"primaryHash", primaryBlock.Hash(), "witnessHash", trustedBlock.Hash(),
+		"commonHeight", attack.EvidenceAgainstPrimary.CommonHeight)
+
+	if primaryBlock.Commit.Round != trustedBlock.Commit.Round {
+		c.logger.Info("The conflicting blocks were committed in different rounds: this is an attempted amnesia attack")
+	}
+	c.sendEvidence(ctx, attack.EvidenceAgainstPrimary, supportingWitness)
+
+	// This may not be valid because the witness itself is at fault. So now we
+	// reverse it, examining the trace provided by the witness and holding the
+	// primary as the source of truth. Note: primary may not respond but this
+	// is okay as we will halt anyway.
+	primaryTrace, witnessBlock, err := c.examineConflictingHeaderAgainstTrace(
+		ctx,
+		witnessTrace,
+		primaryBlock,
+		c.primary,
+		now,
+	)
+	if err != nil {
+		c.logger.Info("Error validating primary's divergent header", "primary", c.primary, "err", err)
+		return attack
+	}
+
+	// We now use the primary trace to create evidence against the witness.
+	commonBlock, trustedBlock = primaryTrace[0], primaryTrace[len(primaryTrace)-1]
+	attack.EvidenceAgainstWitness = types.NewLightClientAttackEvidence(witnessBlock, trustedBlock, commonBlock)
+	c.sendEvidence(ctx, attack.EvidenceAgainstWitness, c.primary)
+	return attack
+}
+
+// sendEvidence sends evidence to a provider on a best effort basis.
+func (c *Client) sendEvidence(ctx context.Context, ev *types.LightClientAttackEvidence, receiver provider.Provider) {
+	if err := receiver.ReportEvidence(ctx, ev); err != nil {
+		c.logger.Error("Failed to report evidence to provider", "ev", ev, "provider", receiver, "err", err)
+	}
+}
+
+// examineConflictingHeaderAgainstTrace takes a trace from one provider and a
+// divergent header that it has received from another and preforms verifySkipping
+// at the heights of each of the intermediate headers in the trace until it
+// reaches the divergentHeader. 1 of 2 things can happen.
+//
+//  1. The light client verifies a header that is different to the intermediate
+//     header in the trace. This is the bifurcation point and the light client
+//     can create evidence from it
+//  2. The source stops responding, doesn't have the block or sends an invalid
+//     header in which case we return the error and remove the witness
+//
+// CONTRACT:
+//  1. Trace can not be empty len(trace) > 0
+//  2. The last block in the trace can not be of a lower height than the
+//     target block trace[len(trace)-1].Height >= targetBlock.Height
+//  3. The first block in the trace must be trusted
+func (c *Client) examineConflictingHeaderAgainstTrace(
+	ctx context.Context,
+	trace []*types.LightBlock,
+	targetBlock *types.LightBlock,
+	source provider.Provider, now time.Time,
+) ([]*types.LightBlock, *types.LightBlock, error) {
+
+	var (
+		previouslyVerifiedBlock, sourceBlock *types.LightBlock
+		sourceTrace                          []*types.LightBlock
+		err                                  error
+	)
+
+	if targetBlock.Height < trace[0].Height {
+		return nil, nil, fmt.Errorf("target block has a height lower than the trusted height (%d < %d)",
+			targetBlock.Height, trace[0].Height)
+	}
+
+	for idx, traceBlock := range trace {
+		// this case only happens in a forward lunatic attack. We treat the
+		// block with the height directly after the targetBlock as the
+		// divergent block
+		if traceBlock.Height > targetBlock.Height {
+			// sanity check that the time of the traceBlock is indeed less
+			// than that of the targetBlock. If the trace was correctly
+			// verified we should expect monotonically increasing time. This
+			// means that if the block at the end of the trace has a lesser
+			// time than the target block then all blocks in the trace should
+			// have a lesser time
+			if traceBlock.Time.After(targetBlock.Time) {
+				return nil, nil,
+					errors.New("sanity check failed: expected traceblock to have a lesser time than the target block")
+			}
+
+			// before sending back the divergent block and trace we need to
+			// ensure we have verified the final gap between the
+			// previouslyVerifiedBlock and the targetBlock
+			if previouslyVerifiedBlock.Height != targetBlock.Height {
+				sourceTrace, err = c.verifySkipping(ctx, source, previouslyVerifiedBlock, targetBlock, now)
+				if err != nil {
+					return nil, nil, fmt.Errorf("verifySkipping of conflicting header failed: %w", err)
+				}
+			}
+			return sourceTrace, traceBlock, nil
+		}
+
+		// get the corresponding block from the source to verify and match up
+		// against the traceBlock
+		if traceBlock.Height == targetBlock.Height {
+			sourceBlock = targetBlock
+		} else {
+			sourceBlock, err = c.lightBlockFrom(ctx, source, traceBlock.Height)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to examine trace: %w", err)
+			}
+		}
+
+		// The first block in the trace MUST be the same to the light block
+		// that the source produces else we cannot continue with verification.
+		if idx == 0 {
+			if shash, thash := sourceBlock.Hash(), traceBlock.Hash(); !shash.Equal(thash) {
+				return nil, nil, fmt.Errorf("trusted block is different to the source's first block (%v = %v)",
+					thash, shash)
+			}
+			previouslyVerifiedBlock = sourceBlock
+			continue
+		}
+
+		// we check that the source provider can verify a block at the same
+		// height of the intermediate height
+		sourceTrace, err = c.verifySkipping(ctx, source, previouslyVerifiedBlock, sourceBlock, now)
+		if err != nil {
+			return nil, nil, fmt.Errorf("verifySkipping of conflicting header failed: %w", err)
+		}
+		// check if the headers verified by the source has diverged from the
+		// trace
+		if shash, thash := sourceBlock.Hash(), traceBlock.Hash(); !shash.Equal(thash) {
+			// Bifurcation point found!
+			return sourceTrace, traceBlock, nil
+		}
+
+		// headers are still the same. update the previouslyVerifiedBlock
+		previouslyVerifiedBlock = sourceBlock
+	}
+
+	// We have reached the end of the trace. This should never happen. This
+	// can only happen if one of the stated prerequisites to this function
+	// were not met. Namely that either trace[len(trace)-1].Height <
+	// targetBlock.Height or that trace[i].Hash() != targetBlock.Hash()
+	return nil, nil, errNoDivergence
+}
@@ -0,0 +1,81 @@
+package light
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
+	"github.com/kardiachain/go-kardia/light/provider"
+	"github.com/kardiachain/go-kardia/light/provider/mock"
+	dbs "github.com/kardiachain/go-kardia/light/store/db"
+)
+
+func TestClientDetectsFork(t *testing.T) {
+	keys := genKeys(8)
+	blocks := genChain(t, keys, 12, changingVals(keys), 0)
+	// The validators sign another chain from height 7.
+	forked := genChain(t, keys, 12, changingVals(keys), 6)
+	now := bTime.Add(time.Hour)
+
+	for _, opt := range []Option{SequentialVerification(), SkippingVerification(DefaultTrustLevel)} {
+		primary, witness := mock.New(chainID, forked), mock.New(chainID, blocks)
+		c, err := NewClient(context.Background(), chainID, trustOptions(blocks[1]), primary,
+			[]provider.Provider{witness}, dbs.New(memorydb.New()), opt)
+		require.NoError(t, err)
+
+		_, err = c.VerifyLightBlockAtHeight(context.Background(), 12, now)
+		var attack ErrLightClientAttack
+		require.True(t, errors.As(err, &attack), "unexpected error: %v", err)
+
+		ev := attack.EvidenceAgainstPrimary
+		require.NotNil(t, ev)
+		assert.Equal(t, forked[ev.ConflictingBlock.Height].Hash(), ev.ConflictingBlock.Hash())
+		assert.True(t, ev.ConflictingBlock.Height > 6)
+		assert.NotEmpty(t, ev.ByzantineValidators)
+		// Each side is reported the evidence against the other.
+		assert.True(t, witness.HasEvidence(ev))
+
+		ev = attack.EvidenceAgainstWitness
+		require.NotNil(t, ev)
+		assert.Equal(t, blocks[ev.ConflictingBlock.Height].Hash(), ev.ConflictingBlock.Hash())
+		assert.True(t, primary.HasEvidence(ev))
+
+		// The forked block isn't trusted.
+		height, err := c.LastTrustedHeight()
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, height)
+	}
+}
+
+func TestClientRemovesWitnessWithUnverifiableHeader(t *testing.T) {
+	keys := genKeys(8)
+	blocks := genChain(t, keys, 12, changingVals(keys), 0)
+	// A chain signed by other validators, which the trusted ones never
+	// committed to.
+	otherKeys := genKeys(8)
+	lunatic := genChain(t, otherKeys, 12, changingVals(otherKeys), 0)
+	for height := uint64(1); height < 12; height++ {
+		lunatic[height] = blocks[height]
+	}
+	now := bTime.Add(time.Hour)
+
+	c, err := NewClient(context.Background(), chainID, trustOptions(blocks[1]), mock.New(chainID, blocks),
+		[]provider.Provider{mock.New(chainID, lunatic), mock.New(chainID, blocks)}, dbs.New(memorydb.New()))
+	require.NoError(t, err)
+
+	_, err = c.VerifyLightBlockAtHeight(context.Background(), 12, now)
+	require.NoError(t, err)
+	assert.Len(t, c.Witnesses(), 1)
+
+	// Without the honest witness, the header can't be cross-checked.
+	c, err = NewClient(context.Background(), chainID, trustOptions(blocks[1]), mock.New(chainID, blocks),
+		[]provider.Provider{mock.New(chainID, lunatic)}, dbs.New(memorydb.New()))
+	require.NoError(t, err)
+	_, err = c.VerifyLightBlockAtHeight(context.Background(), 12, now)
+	assert.Equal(t, ErrFailedHeaderCrossReferencing, err)
+}
@@ -0,0 +1,117 @@
+package light
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kardiachain/go-kardia/types"
+)
+
+var (
+	// ErrNoWitnesses means that there are not enough witnesses connected to
+	// continue running the light client.
+	ErrNoWitnesses = errors.New("no witnesses connected. please reset light client")
+
+	// ErrFailedHeaderCrossReferencing is returned when the detector was not
+	// able to cross reference the header with any of the connected witnesses.
+	ErrFailedHeaderCrossReferencing = errors.New("all witnesses have either not responded, don't have the " +
+		"block or sent invalid blocks. You should look to change your witnesses " +
+		"or review the light client's logs for more information")
+)
+
+// ErrOldHeaderExpired means the old (trusted) header has expired according to
+// the given trustingPeriod and current time. If so, the light client must be
+// reset subjectively.
+type ErrOldHeaderExpired struct {
+	At  time.Time
+	Now time.Time
+}
+
+func (e ErrOldHeaderExpired) Error() string {
+	return fmt.Sprintf("old header has expired at %v (now: %v)", e.At, e.Now)
+}
+
+// ErrNewValSetCantBeTrusted means the new validator set cannot be trusted
+// because less than trustLevel of the old validator set has signed.
+type ErrNewValSetCantBeTrusted struct {
+	Reason types.ErrNotEnoughVotingPowerSigned
+}
+
+func (e ErrNewValSetCantBeTrusted) Error() string {
+	return fmt.Sprintf("can't trust new val set: %v", e.Reason)
+}
+
+// ErrInvalidHeader means the header either failed the basic validation or
+// commit is not signed by 2/3+.
+type ErrInvalidHeader struct {
+	Reason error
+}
+
+func (e ErrInvalidHeader) Error() string {
+	return fmt.Sprintf("invalid header: %v", e.Reason)
+}
+
+func (e ErrInvalidHeader) Unwrap() error {
+	return e.Reason
+}
+
+// ErrVerificationFailed means either sequential or skipping verification has
+// failed to verify from header #1 to header #2 due to some reason.
+type ErrVerificationFailed struct {
+	From   uint64
+	To     uint64
+	Reason error
+}
+
+func (e ErrVerificationFailed) Error() string {
+	return fmt.Sprintf("verify from #%d to #%d failed: %v", e.From, e.To, e.Reason)
+}
+
+func (e ErrVerificationFailed) Unwrap() error {
+	return e.Reason
+}
+
+// ErrLightClientAttack is returned when witnesses served a header conflicting
+// with the one verified from the primary, and both could be verified from the
+// same trusted header: one of the validator sets involved signed a fork. It
+// carries the evidence against each side; the light client stops updating.
+type ErrLightClientAttack struct {
+	EvidenceAgainstPrimary *types.LightClientAttackEvidence
+	EvidenceAgainstWitness *types.LightClientAttackEvidence
+	WitnessIndex           int
+}
+
+func (e ErrLightClientAttack) Error() string {
+	return fmt.Sprintf("attempted attack detected: primary and witness #%d diverge from common height %d",
+		e.WitnessIndex, e.EvidenceAgainstPrimary.CommonHeight)
+}
+
+// ----------------------------- INTERNAL ERRORS ---------------------------------
+
+// errConflictingHeaders is returned when two conflicting headers are
+// discovered.
+type errConflictingHeaders struct {
+	Block        *types.LightBlock
+	WitnessIndex int
+}
+
+func (e errConflictingHeaders) Error() string {
+	return fmt.Sprintf("header hash (%v) from witness (%d) does not match primary",
+		e.Block.Hash(), e.WitnessIndex)
+}
+
+// errBadWitness is returned when the witness either does not respond or
+// responds with an invalid header.
+type errBadWitness struct {
+	Reason       error
+	WitnessIndex int
+}
+
+func (e errBadWitness) Error() string {
+	return fmt.Sprintf("Witness %d returned error: %s", e.WitnessIndex, e.Reason.Error())
+}
+
+func (e errBadWitness) Unwrap() error {
+	return e.Reason
+}
@@ -0,0 +1,113 @@
+package light
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+const (
+	chainID        = "test-chain"
+	trustingPeriod = 4 * time.Hour
+)
+
+var bTime = time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+// testKeys are validator keys shared by the generated chains, so that a fork
+// can be signed by the validators of the original chain.
+type testKeys []types.PrivValidator
+
+func genKeys(n int) testKeys {
+	keys := make(testKeys, n)
+	for i := range keys {
+		keys[i] = types.NewMockPV()
+	}
+	return keys
+}
+
+// vals returns the validator set of the keys [from, to).
+func (keys testKeys) vals(from, to int) *types.ValidatorSet {
+	var vals []*types.Validator
+	for _, key := range keys[from:to] {
+		vals = append(vals, types.NewValidator(key.GetAddress(), 10))
+	}
+	return types.NewValidatorSet(vals)
+}
+
+// signCommit makes the commit of all the validators for the header.
+func (keys testKeys) signCommit(t *testing.T, header *types.Header, vals *types.ValidatorSet, round uint32) *types.Commit {
+	byAddress := make(map[common.Address]types.PrivValidator, len(keys))
+	for _, key := range keys {
+		byAddress[key.GetAddress()] = key
+	}
+	blockID := types.BlockID{Hash: header.Hash(), PartsHeader: types.PartSetHeader{Total: 1, Hash: header.Hash()}}
+	voteSet := types.NewVoteSet(chainID, header.Height, round, kproto.PrecommitType, vals)
+	for idx, val := range vals.Validators {
+		vote := &types.Vote{
+			ValidatorAddress: val.Address,
+			ValidatorIndex:   uint32(idx),
+			Height:           header.Height,
+			Round:            round,
+			Type:             kproto.PrecommitType,
+			BlockID:          blockID,
+			Timestamp:        header.Time,
+		}
+		v := vote.ToProto()
+		require.NoError(t, byAddress[val.Address].SignVote(chainID, v))
+		vote.Signature = v.Signature
+		_, err := voteSet.AddVote(vote)
+		require.NoError(t, err)
+	}
+	return voteSet.MakeCommit()
+}
+
+// genChain generates the light blocks of heights [1, n], valsAt giving the
+// validators of each height. Blocks after forkHeight, if not zero, have a
+// different app hash than the ones generated with forkHeight 0.
+func genChain(t *testing.T, keys testKeys, n uint64, valsAt func(height uint64) *types.ValidatorSet,
+	forkHeight uint64) map[uint64]*types.LightBlock {
+
+	var (
+		blocks = make(map[uint64]*types.LightBlock, n)
+		lastID types.BlockID
+	)
+	for height := uint64(1); height <= n; height++ {
+		vals := valsAt(height)
+		header := &types.Header{
+			Height:             height,
+			Time:               bTime.Add(time.Duration(height) * time.Minute),
+			LastBlockID:        lastID,
+			ProposerAddress:    vals.Validators[0].Address,
+			ValidatorsHash:     vals.Hash(),
+			NextValidatorsHash: valsAt(height + 1).Hash(),
+		}
+		if forkHeight != 0 && height > forkHeight {
+			header.AppHash = common.BytesToHash([]byte("fork"))
+		}
+		commit := keys.signCommit(t, header, vals, 0)
+		blocks[height] = &types.LightBlock{
+			SignedHeader: &types.SignedHeader{Header: header, Commit: commit},
+			ValidatorSet: vals,
+		}
+		lastID = commit.BlockID
+	}
+	return blocks
+}
+
+// changingVals rotates the validators: 4 of 8 keys sign each block and the
+// set shifts by 2 keys every 4 blocks, so that blocks 8 heights apart share no
+// validator.
+func changingVals(keys testKeys) func(uint64) *types.ValidatorSet {
+	return func(height uint64) *types.ValidatorSet {
+		from := int((height - 1) / 4 * 2)
+		if from+4 > len(keys) {
+			from = len(keys) - 4
+		}
+		return keys.vals(from, from+4)
+	}
+}
@@ -0,0 +1,114 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/light/provider"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// Mock is a provider serving light blocks from memory.
+type Mock struct {
+	chainID string
+
+	mtx          sync.Mutex
+	lightBlocks  map[uint64]*types.LightBlock
+	latestHeight uint64
+	evidence     map[common.Hash]types.Evidence
+}
+
+var _ provider.Provider = (*Mock)(nil)
+
+// New creates a mock provider with the given set of light blocks.
+func New(chainID string, lightBlocks map[uint64]*types.LightBlock) *Mock {
+	p := &Mock{
+		chainID:     chainID,
+		lightBlocks: make(map[uint64]*types.LightBlock),
+		evidence:    make(map[common.Hash]types.Evidence),
+	}
+	for _, lb := range lightBlocks {
+		p.addLightBlock(lb)
+	}
+	return p
+}
+
+// ChainID returns the chain ID the provider was created with.
+func (p *Mock) ChainID() string {
+	return p.chainID
+}
+
+func (p *Mock) String() string {
+	return fmt.Sprintf("Mock{%s}", p.chainID)
+}
+
+// LightBlock returns the light block at height, the latest one if height is 0.
+func (p *Mock) LightBlock(_ context.Context, height uint64) (*types.LightBlock, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if height == 0 {
+		height = p.latestHeight
+	}
+	if height > p.latestHeight {
+		return nil, provider.ErrHeightTooHigh
+	}
+	lb, ok := p.lightBlocks[height]
+	if !ok {
+		return nil, provider.ErrLightBlockNotFound
+	}
+	return lb, nil
+}
+
+// ReportEvidence records the evidence.
+func (p *Mock) ReportEvidence(_ context.Context, ev types.Evidence) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.evidence[ev.Hash()] = ev
+	return nil
+}
+
+// HasEvidence reports whether the evidence was reported to the provider.
+func (p *Mock) HasEvidence(ev types.Evidence) bool {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	_, ok := p.evidence[ev.Hash()]
+	return ok
+}
+
+// AddLightBlock adds a light block, e.g. to let the chain grow.
+func (p *Mock) AddLightBlock(lb *types.LightBlock) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.addLightBlock(lb)
+}
+
+func (p *Mock) addLightBlock(lb *types.LightBlock) {
+	p.lightBlocks[lb.Height] = lb
+	if lb.Height > p.latestHeight {
+		p.latestHeight = lb.Height
+	}
+}
+
+type deadMock struct {
+	chainID string
+}
+
+// NewDeadMock creates a mock provider that never responds.
+func NewDeadMock(chainID string) provider.Provider {
+	return &deadMock{chainID: chainID}
+}
+
+func (p *deadMock) ChainID() string { return p.chainID }
+
+func (p *deadMock) String() string { return "deadMock" }
+
+func (p *deadMock) LightBlock(_ context.Context, _ uint64) (*types.LightBlock, error) {
+	return nil, provider.ErrNoResponse
+}
+
+func (p *deadMock) ReportEvidence(_ context.Context, _ types.Evidence) error {
+	return provider.ErrNoResponse
+}
@@ -0,0 +1,26 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// Provider provides information for the light client to sync (verification
+// happens in the client).
+type Provider interface {
+	// ChainID returns the chain ID the provider serves.
+	ChainID() string
+
+	// LightBlock returns the LightBlock at the given height. If height is 0,
+	// the provider returns the latest one it has a commit for.
+	//
+	// If the provider fails to fetch the LightBlock due to the IO or other
+	// issues, an error will be returned. If there's no LightBlock for the
+	// given height, ErrLightBlockNotFound error is returned.
+	LightBlock(ctx context.Context, height uint64) (*types.LightBlock, error)
+
+	// ReportEvidence reports evidence of misbehavior to the provider, which
+	// verifies it and gossips it to the network.
+	ReportEvidence(ctx context.Context, ev types.Evidence) error
+}
@@ -0,0 +1,99 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/light/provider"
+	"github.com/kardiachain/go-kardia/rpc"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// http provider uses the kai RPC API of a full node to obtain the necessary
+// information.
+type http struct {
+	chainID string
+	client  *rpc.Client
+}
+
+// New creates a Provider that dials the node RPC at remote, e.g.
+// "http://localhost:8545".
+func New(chainID, remote string) (provider.Provider, error) {
+	client, err := rpc.Dial(remote)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithClient(chainID, client), nil
+}
+
+// NewWithClient allows you to provide a custom client.
+func NewWithClient(chainID string, client *rpc.Client) provider.Provider {
+	return &http{chainID: chainID, client: client}
+}
+
+// ChainID returns the chain ID the provider was created with.
+func (p *http) ChainID() string {
+	return p.chainID
+}
+
+func (p *http) String() string {
+	return fmt.Sprintf("http{%s}", p.chainID)
+}
+
+// LightBlock fetches the light block at the given height with kai_lightBlock,
+// which takes the header, the commit and the validators from the same view of
+// the chain. Height 0 means the latest block that has a commit, which is the
+// one before the head.
+func (p *http) LightBlock(ctx context.Context, height uint64) (*types.LightBlock, error) {
+	var arg interface{} = height
+	if height == 0 {
+		arg = "latest"
+	}
+	var lb *types.LightBlock
+	if err := p.call(ctx, &lb, "kai_lightBlock", arg); err != nil {
+		var rpcErr rpc.Error
+		if !errors.As(err, &rpcErr) {
+			return nil, err
+		}
+		// The node answered, it doesn't have the light block.
+		var latest uint64
+		if height > 0 && p.call(ctx, &latest, "kai_blockNumber") == nil && height >= latest {
+			return nil, provider.ErrHeightTooHigh
+		}
+		return nil, provider.ErrLightBlockNotFound
+	}
+	if lb == nil || lb.SignedHeader == nil {
+		return nil, provider.ErrLightBlockNotFound
+	}
+	if err := lb.ValidateBasic(); err != nil {
+		return nil, provider.ErrBadLightBlock{Reason: err}
+	}
+	if height != 0 && lb.Height != height {
+		return nil, provider.ErrBadLightBlock{Reason: fmt.Errorf("height %d responded doesn't match height %d requested", lb.Height, height)}
+	}
+	return lb, nil
+}
+
+// ReportEvidence submits the evidence with evidence_broadcastEvidence.
+func (p *http) ReportEvidence(ctx context.Context, ev types.Evidence) error {
+	evpb, err := types.EvidenceToProto(ev)
+	if err != nil {
+		return err
+	}
+	raw, err := evpb.Marshal()
+	if err != nil {
+		return err
+	}
+	var hash common.Hash
+	return p.call(ctx, &hash, "evidence_broadcastEvidence", common.Bytes(raw))
+}
+
+func (p *http) call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	err := p.client.CallContext(ctx, result, method, args...)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return provider.ErrNoResponse
+	}
+	return err
+}
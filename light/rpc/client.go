@@ -0,0 +1,161 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/rpc"
+	"github.com/kardiachain/go-kardia/trie"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// LightClient is the light client the responses are verified with.
+type LightClient interface {
+	ChainID() string
+	VerifyLightBlockAtHeight(ctx context.Context, height uint64, now time.Time) (*types.LightBlock, error)
+}
+
+// Client is an RPC client that verifies the responses of a node against the
+// headers verified by a light client, so that the node doesn't need to be
+// trusted.
+type Client struct {
+	next *rpc.Client
+	lc   LightClient
+}
+
+// NewClient returns a new client.
+func NewClient(next *rpc.Client, lc LightClient) *Client {
+	return &Client{next: next, lc: lc}
+}
+
+// LightBlock returns the light block at the given height (0 - the latest),
+// verified by the light client.
+func (c *Client) LightBlock(ctx context.Context, height uint64) (*types.LightBlock, error) {
+	var lb *types.LightBlock
+	if err := c.next.CallContext(ctx, &lb, "kai_lightBlock", heightArg(height)); err != nil {
+		return nil, err
+	}
+	return c.verifyLightBlock(ctx, lb)
+}
+
+// AccountProof returns the account and the given storage slots in the state
+// after the block at the given height (0 - the latest available). The proofs
+// are checked against the app hash of the next header, itself verified by
+// the light client.
+func (c *Client) AccountProof(ctx context.Context, address common.Address, storageKeys []common.Hash,
+	height uint64) (*AccountProof, error) {
+
+	keys := make([]string, len(storageKeys))
+	for i, key := range storageKeys {
+		keys[i] = key.Hex()
+	}
+	var bundle ProofBundle
+	if err := c.next.CallContext(ctx, &bundle, "kai_proofBundle", address, keys, heightArg(height)); err != nil {
+		return nil, err
+	}
+	if bundle.Proof == nil {
+		return nil, errors.New("missing account proof")
+	}
+	if bundle.Proof.Address != address {
+		return nil, fmt.Errorf("proof of account %v, requested %v", bundle.Proof.Address, address)
+	}
+	trusted, err := c.verifyLightBlock(ctx, bundle.LightBlock)
+	if err != nil {
+		return nil, err
+	}
+	if height != 0 && trusted.Height != height+1 {
+		return nil, fmt.Errorf("proof against header #%d, expected #%d", trusted.Height, height+1)
+	}
+	if err := VerifyProofBundle(trusted, &bundle); err != nil {
+		return nil, err
+	}
+	return bundle.Proof, nil
+}
+
+// TxProof returns the proof of the inclusion of the transaction with the given
+// hash, verified against the header the light client verified at its height.
+func (c *Client) TxProof(ctx context.Context, hash common.Hash) (*types.TxProof, error) {
+	var proof *types.TxProof
+	if err := c.next.CallContext(ctx, &proof, "kai_getTransactionProof", hash); err != nil {
+		return nil, err
+	}
+	if proof == nil {
+		return nil, errors.New("tx proof not found")
+	}
+	header, err := c.trustedTxHeader(ctx, hash, proof)
+	if err != nil {
+		return nil, err
+	}
+	if err := proof.Verify(header, trie.VerifyProof); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// ReceiptProof returns the proof of the receipt of the transaction with the
+// given hash. The transaction is verified against a trusted header, and the
+// receipt against receiptsRoot, the root of the receipts of the block the
+// caller trusts, see types.ReceiptProof.
+func (c *Client) ReceiptProof(ctx context.Context, hash common.Hash, receiptsRoot common.Hash) (*types.ReceiptProof, error) {
+	var proof *types.ReceiptProof
+	if err := c.next.CallContext(ctx, &proof, "kai_getReceiptProof", hash); err != nil {
+		return nil, err
+	}
+	if proof == nil || proof.TxProof == nil {
+		return nil, errors.New("receipt proof not found")
+	}
+	header, err := c.trustedTxHeader(ctx, hash, proof.TxProof)
+	if err != nil {
+		return nil, err
+	}
+	if err := proof.Verify(header, receiptsRoot, trie.VerifyProof); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// trustedTxHeader checks that proof is the one of the transaction with the
+// given hash and returns the header the light client verified at its height.
+func (c *Client) trustedTxHeader(ctx context.Context, hash common.Hash, proof *types.TxProof) (*types.Header, error) {
+	tx, err := proof.Transaction()
+	if err != nil {
+		return nil, fmt.Errorf("invalid tx: %w", err)
+	}
+	if !tx.Hash().Equal(hash) {
+		return nil, fmt.Errorf("proof of tx %v, requested %v", tx.Hash().Hex(), hash.Hex())
+	}
+	trusted, err := c.lc.VerifyLightBlockAtHeight(ctx, proof.BlockHeight, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("verify light block #%d: %w", proof.BlockHeight, err)
+	}
+	return trusted.Header, nil
+}
+
+// verifyLightBlock returns the light block the light client verified at the
+// height of lb, if it's the same.
+func (c *Client) verifyLightBlock(ctx context.Context, lb *types.LightBlock) (*types.LightBlock, error) {
+	if lb == nil {
+		return nil, errors.New("light block not found")
+	}
+	if err := lb.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid light block: %w", err)
+	}
+	trusted, err := c.lc.VerifyLightBlockAtHeight(ctx, lb.Height, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("verify light block #%d: %w", lb.Height, err)
+	}
+	if !trusted.Hash().Equal(lb.Hash()) {
+		return nil, fmt.Errorf("light block #%d %v doesn't match the trusted one %v", lb.Height, lb.Hash(), trusted.Hash())
+	}
+	return trusted, nil
+}
+
+func heightArg(height uint64) interface{} {
+	if height == 0 {
+		return "latest"
+	}
+	return height
+}
@@ -0,0 +1,127 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/kai/state"
+	"github.com/kardiachain/go-kardia/lib/common"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+	"github.com/kardiachain/go-kardia/rpc"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+const testChainID = "test-chain"
+
+func makeLightBlock(t *testing.T, height uint64, appHash common.Hash) *types.LightBlock {
+	vals, privVals := types.RandValidatorSet(4, 10)
+	header := &types.Header{
+		Height:             height,
+		Time:               time.Now().UTC(),
+		ValidatorsHash:     vals.Hash(),
+		NextValidatorsHash: vals.Hash(),
+		ProposerAddress:    vals.Validators[0].Address,
+		AppHash:            appHash,
+	}
+	blockID := types.BlockID{Hash: header.Hash(), PartsHeader: types.PartSetHeader{Total: 1, Hash: header.Hash()}}
+	voteSet := types.NewVoteSet(testChainID, height, 0, kproto.PrecommitType, vals)
+	commit, err := types.MakeCommit(blockID, height, 0, voteSet, privVals, time.Now())
+	require.NoError(t, err)
+	return &types.LightBlock{
+		SignedHeader: &types.SignedHeader{Header: header, Commit: commit},
+		ValidatorSet: vals,
+	}
+}
+
+// testLightClient trusts the given light blocks.
+type testLightClient map[uint64]*types.LightBlock
+
+func (lc testLightClient) ChainID() string { return testChainID }
+
+func (lc testLightClient) VerifyLightBlockAtHeight(_ context.Context, height uint64, _ time.Time) (*types.LightBlock, error) {
+	lb, ok := lc[height]
+	if !ok {
+		return nil, errors.New("can't verify")
+	}
+	return lb, nil
+}
+
+// testKaiAPI serves the light blocks and the proofs of a state.
+type testKaiAPI struct {
+	t       *testing.T
+	blocks  map[uint64]*types.LightBlock
+	statedb *state.StateDB
+}
+
+func (api *testKaiAPI) LightBlock(height rpc.BlockHeight) (*types.LightBlock, error) {
+	lb, ok := api.blocks[height.Uint64()]
+	if !ok {
+		return nil, errors.New("light block not found")
+	}
+	return lb, nil
+}
+
+func (api *testKaiAPI) ProofBundle(address common.Address, storageKeys []string, height rpc.BlockHeight) (*ProofBundle, error) {
+	keys := make([]common.Hash, len(storageKeys))
+	for i, key := range storageKeys {
+		keys[i] = common.HexToHash(key)
+	}
+	return &ProofBundle{
+		LightBlock: api.blocks[height.Uint64()+1],
+		Proof:      accountProof(api.t, api.statedb, address, keys...),
+	}, nil
+}
+
+func newTestClient(t *testing.T, api *testKaiAPI, lc LightClient) *Client {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("kai", api))
+	t.Cleanup(server.Stop)
+	client := rpc.DialInProc(server)
+	t.Cleanup(client.Close)
+	return NewClient(client, lc)
+}
+
+func TestClientLightBlock(t *testing.T) {
+	lb := makeLightBlock(t, 5, common.Hash{})
+	api := &testKaiAPI{t: t, blocks: map[uint64]*types.LightBlock{5: lb}}
+
+	c := newTestClient(t, api, testLightClient{5: lb})
+	got, err := c.LightBlock(context.Background(), 5)
+	require.NoError(t, err)
+	assert.Equal(t, lb.Hash(), got.Hash())
+	assert.Equal(t, lb.Commit.Hash(), got.Commit.Hash())
+
+	// The light client trusts another block.
+	c = newTestClient(t, api, testLightClient{5: makeLightBlock(t, 5, common.Hash{})})
+	_, err = c.LightBlock(context.Background(), 5)
+	assert.Error(t, err)
+
+	// The light client can't verify it.
+	c = newTestClient(t, api, testLightClient{})
+	_, err = c.LightBlock(context.Background(), 5)
+	assert.Error(t, err)
+}
+
+func TestClientAccountProof(t *testing.T) {
+	statedb, root := newTestState(t)
+	lb := makeLightBlock(t, 5, root)
+	api := &testKaiAPI{t: t, blocks: map[uint64]*types.LightBlock{5: lb}, statedb: statedb}
+
+	c := newTestClient(t, api, testLightClient{5: lb})
+	proof, err := c.AccountProof(context.Background(), testAccount, []common.Hash{testSlot}, 4)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1000, proof.Balance.ToInt().Int64())
+	assert.Equal(t, common.HexToHash("0x2a").Big(), proof.StorageProof[0].Value.ToInt())
+
+	// The bundled header doesn't commit to the state.
+	other := makeLightBlock(t, 5, common.HexToHash("0xbeef"))
+	api.blocks[5] = other
+	c = newTestClient(t, api, testLightClient{5: other})
+	_, err = c.AccountProof(context.Background(), testAccount, []common.Hash{testSlot}, 4)
+	assert.Error(t, err)
+}
@@ -0,0 +1,141 @@
+package rpc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/crypto"
+	"github.com/kardiachain/go-kardia/lib/rlp"
+	"github.com/kardiachain/go-kardia/trie"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// AccountProof is the Merkle-proof of an account and some of its storage
+// slots, as returned by kai_getProof.
+type AccountProof struct {
+	Address      common.Address `json:"address"`
+	AccountProof []string       `json:"accountProof"`
+	Balance      *common.Big    `json:"balance"`
+	CodeHash     common.Hash    `json:"codeHash"`
+	Nonce        common.Uint64  `json:"nonce"`
+	StorageHash  common.Hash    `json:"storageHash"`
+	StorageProof []StorageProof `json:"storageProof"`
+}
+
+// StorageProof is the Merkle-proof of a storage slot.
+type StorageProof struct {
+	Key   string      `json:"key"`
+	Value *common.Big `json:"value"`
+	Proof []string    `json:"proof"`
+}
+
+// ProofBundle is an account proof in the state after some block, together
+// with the light block of the next height, whose app hash is the root of that
+// state. It's returned by kai_proofBundle.
+type ProofBundle struct {
+	LightBlock *types.LightBlock `json:"lightBlock"`
+	Proof      *AccountProof     `json:"proof"`
+}
+
+// VerifyProofBundle checks that bundle carries the trusted light block and
+// that its account proof is valid against the trusted app hash.
+func VerifyProofBundle(trusted *types.LightBlock, bundle *ProofBundle) error {
+	if bundle.LightBlock == nil || bundle.Proof == nil {
+		return errors.New("incomplete proof bundle")
+	}
+	if !bundle.LightBlock.Hash().Equal(trusted.Hash()) {
+		return fmt.Errorf("bundled light block %v doesn't match the trusted one %v", bundle.LightBlock.Hash(), trusted.Hash())
+	}
+	return VerifyAccountProof(trusted.AppHash, bundle.Proof)
+}
+
+// VerifyAccountProof checks the account and the storage slots of proof
+// against the state root.
+func VerifyAccountProof(root common.Hash, proof *AccountProof) error {
+	db, err := proofDB(proof.AccountProof)
+	if err != nil {
+		return fmt.Errorf("account proof: %w", err)
+	}
+	value, err := trie.VerifyProof(root, crypto.Keccak256(proof.Address[:]), db)
+	if err != nil {
+		return fmt.Errorf("account proof: %w", err)
+	}
+
+	account := types.StateAccount{
+		Balance:  new(big.Int),
+		Root:     types.EmptyRootHash,
+		CodeHash: types.EmptyCodeHash[:],
+	}
+	if value != nil {
+		if err := rlp.DecodeBytes(value, &account); err != nil {
+			return fmt.Errorf("account proof: %w", err)
+		}
+	}
+	switch {
+	case proof.Balance == nil || account.Balance.Cmp(proof.Balance.ToInt()) != 0:
+		return fmt.Errorf("balance %v, proven %v", proof.Balance, account.Balance)
+	case uint64(proof.Nonce) != account.Nonce:
+		return fmt.Errorf("nonce %d, proven %d", proof.Nonce, account.Nonce)
+	case !bytes.Equal(proof.CodeHash[:], account.CodeHash):
+		return fmt.Errorf("code hash %v, proven %x", proof.CodeHash, account.CodeHash)
+	case proof.StorageHash != account.Root:
+		return fmt.Errorf("storage hash %v, proven %v", proof.StorageHash, account.Root)
+	}
+
+	for _, sp := range proof.StorageProof {
+		if err := verifyStorageProof(account.Root, sp); err != nil {
+			return fmt.Errorf("storage proof of %s: %w", sp.Key, err)
+		}
+	}
+	return nil
+}
+
+func verifyStorageProof(root common.Hash, proof StorageProof) error {
+	if proof.Value == nil {
+		return errors.New("missing value")
+	}
+	proven := new(big.Int)
+	// There are no nodes to prove anything in an empty trie.
+	if root != types.EmptyRootHash {
+		db, err := proofDB(proof.Proof)
+		if err != nil {
+			return err
+		}
+		key := common.HexToHash(proof.Key)
+		value, err := trie.VerifyProof(root, crypto.Keccak256(key[:]), db)
+		if err != nil {
+			return err
+		}
+		if value != nil {
+			_, content, _, err := rlp.Split(value)
+			if err != nil {
+				return err
+			}
+			proven.SetBytes(content)
+		}
+	}
+	if proven.Cmp(proof.Value.ToInt()) != 0 {
+		return fmt.Errorf("value %v, proven %v", proof.Value, proven)
+	}
+	return nil
+}
+
+// proofDB returns the hex encoded trie nodes keyed by their hash, the way
+// trie.VerifyProof looks them up.
+func proofDB(nodes []string) (*memorydb.Database, error) {
+	db := memorydb.New()
+	for _, node := range nodes {
+		blob, err := common.Decode(node)
+		if err != nil {
+			return nil, err
+		}
+		if err := db.Put(crypto.Keccak256(blob), blob); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
@@ -0,0 +1,115 @@
+package rpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
+	"github.com/kardiachain/go-kardia/kai/state"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+var (
+	testAccount = common.HexToAddress("0x1234")
+	testSlot    = common.HexToHash("0x01")
+)
+
+// newTestState returns the committed state holding testAccount, with code
+// and a storage slot.
+func newTestState(t *testing.T) (*state.StateDB, common.Hash) {
+	db := state.NewDatabase(memorydb.New())
+	statedb, err := state.New(common.Hash{}, db, nil)
+	require.NoError(t, err)
+	statedb.SetBalance(testAccount, big.NewInt(1000))
+	statedb.SetNonce(testAccount, 3)
+	statedb.SetCode(testAccount, []byte{0x60, 0x00})
+	statedb.SetState(testAccount, testSlot, common.HexToHash("0x2a"))
+	for i := byte(1); i < 20; i++ {
+		statedb.AddBalance(common.BytesToAddress([]byte{i}), big.NewInt(int64(i)))
+	}
+	root, err := statedb.Commit(true)
+	require.NoError(t, err)
+
+	statedb, err = state.New(root, db, nil)
+	require.NoError(t, err)
+	return statedb, root
+}
+
+// accountProof builds the proof the way kai_getProof does.
+func accountProof(t *testing.T, statedb *state.StateDB, address common.Address, keys ...common.Hash) *AccountProof {
+	proof := &AccountProof{
+		Address:     address,
+		Balance:     (*common.Big)(statedb.GetBalance(address)),
+		CodeHash:    statedb.GetCodeHash(address),
+		Nonce:       common.Uint64(statedb.GetNonce(address)),
+		StorageHash: types.EmptyRootHash,
+	}
+	storageTrie, err := statedb.StorageTrie(address)
+	require.NoError(t, err)
+	if storageTrie != nil {
+		proof.StorageHash = storageTrie.Hash()
+	} else {
+		proof.CodeHash = types.EmptyCodeHash
+	}
+	for _, key := range keys {
+		sp := StorageProof{Key: key.Hex(), Value: (*common.Big)(statedb.GetState(address, key).Big())}
+		if storageTrie != nil {
+			nodes, err := statedb.GetStorageProof(address, key)
+			require.NoError(t, err)
+			sp.Proof = hexSlice(nodes)
+		}
+		proof.StorageProof = append(proof.StorageProof, sp)
+	}
+	nodes, err := statedb.GetProof(address)
+	require.NoError(t, err)
+	proof.AccountProof = hexSlice(nodes)
+	return proof
+}
+
+func hexSlice(b [][]byte) []string {
+	r := make([]string, len(b))
+	for i := range b {
+		r[i] = common.Encode(b[i])
+	}
+	return r
+}
+
+func TestVerifyAccountProof(t *testing.T) {
+	statedb, root := newTestState(t)
+
+	proof := accountProof(t, statedb, testAccount, testSlot, common.HexToHash("0x02"))
+	require.NoError(t, VerifyAccountProof(root, proof))
+
+	// Absent account.
+	require.NoError(t, VerifyAccountProof(root, accountProof(t, statedb, common.HexToAddress("0xdead"), testSlot)))
+
+	testCases := []struct {
+		name   string
+		tamper func(p *AccountProof)
+	}{
+		{"balance", func(p *AccountProof) { p.Balance = (*common.Big)(big.NewInt(1001)) }},
+		{"nonce", func(p *AccountProof) { p.Nonce++ }},
+		{"code hash", func(p *AccountProof) { p.CodeHash = types.EmptyCodeHash }},
+		{"storage hash", func(p *AccountProof) { p.StorageHash = types.EmptyRootHash }},
+		{"storage value", func(p *AccountProof) { p.StorageProof[0].Value = (*common.Big)(big.NewInt(41)) }},
+		{"absent storage value", func(p *AccountProof) { p.StorageProof[1].Value = (*common.Big)(big.NewInt(1)) }},
+		{"missing account node", func(p *AccountProof) { p.AccountProof = p.AccountProof[1:] }},
+		{"missing storage node", func(p *AccountProof) { p.StorageProof[0].Proof = nil }},
+		{"other account", func(p *AccountProof) { p.Address = common.HexToAddress("0x01") }},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			p := accountProof(t, statedb, testAccount, testSlot, common.HexToHash("0x02"))
+			tc.tamper(p)
+			assert.Error(t, VerifyAccountProof(root, p))
+		})
+	}
+
+	// Another state.
+	assert.Error(t, VerifyAccountProof(common.HexToHash("0xbeef"), proof))
+}
@@ -0,0 +1,241 @@
+package db
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/kardiachain/go-kardia/kai/kaidb"
+	"github.com/kardiachain/go-kardia/light/store"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+var (
+	signedHeaderPrefix = []byte("lsh") // signedHeaderPrefix + height (uint64 big endian) -> signed header
+	validatorSetPrefix = []byte("lvs") // validatorSetPrefix + height (uint64 big endian) -> validator set
+)
+
+type dbs struct {
+	db kaidb.Database
+
+	mtx  sync.RWMutex
+	size uint16
+}
+
+// New returns a Store that wraps any kaidb.Database. Keys are prefixed, so
+// the database can be shared with other data.
+func New(db kaidb.Database) store.Store {
+	s := &dbs{db: db}
+	it := db.NewIterator(signedHeaderPrefix, nil)
+	defer it.Release()
+	for it.Next() {
+		s.size++
+	}
+	return s
+}
+
+// SaveLightBlock persists the signed header and the validator set.
+func (s *dbs) SaveLightBlock(lb *types.LightBlock) error {
+	if lb.Height == 0 {
+		panic("negative or zero height")
+	}
+	shBz, err := proto.Marshal(lb.SignedHeader.ToProto())
+	if err != nil {
+		return fmt.Errorf("marshaling signed header: %w", err)
+	}
+	pbVals, err := lb.ValidatorSet.ToProto()
+	if err != nil {
+		return fmt.Errorf("converting validator set: %w", err)
+	}
+	valsBz, err := proto.Marshal(pbVals)
+	if err != nil {
+		return fmt.Errorf("marshaling validator set: %w", err)
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	has, err := s.db.Has(signedHeaderKey(lb.Height))
+	if err != nil {
+		return err
+	}
+	batch := s.db.NewBatch()
+	if err := batch.Put(signedHeaderKey(lb.Height), shBz); err != nil {
+		return err
+	}
+	if err := batch.Put(validatorSetKey(lb.Height), valsBz); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	if !has {
+		s.size++
+	}
+	return nil
+}
+
+// DeleteLightBlock deletes the light block at the given height, if any.
+func (s *dbs) DeleteLightBlock(height uint64) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.deleteLightBlock(height)
+}
+
+func (s *dbs) deleteLightBlock(height uint64) error {
+	has, err := s.db.Has(signedHeaderKey(height))
+	if err != nil || !has {
+		return err
+	}
+	batch := s.db.NewBatch()
+	if err := batch.Delete(signedHeaderKey(height)); err != nil {
+		return err
+	}
+	if err := batch.Delete(validatorSetKey(height)); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	s.size--
+	return nil
+}
+
+// LightBlock retrieves the light block at the given height.
+func (s *dbs) LightBlock(height uint64) (*types.LightBlock, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	shBz, err := s.db.Get(signedHeaderKey(height))
+	if err != nil || len(shBz) == 0 {
+		return nil, store.ErrLightBlockNotFound
+	}
+	valsBz, err := s.db.Get(validatorSetKey(height))
+	if err != nil || len(valsBz) == 0 {
+		return nil, store.ErrLightBlockNotFound
+	}
+
+	var pbSh kproto.SignedHeader
+	if err := proto.Unmarshal(shBz, &pbSh); err != nil {
+		return nil, fmt.Errorf("unmarshal signed header #%d: %w", height, err)
+	}
+	sh, err := types.SignedHeaderFromProto(&pbSh)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signed header #%d: %w", height, err)
+	}
+	var pbVals kproto.ValidatorSet
+	if err := proto.Unmarshal(valsBz, &pbVals); err != nil {
+		return nil, fmt.Errorf("unmarshal validator set #%d: %w", height, err)
+	}
+	vals, err := types.ValidatorSetFromProto(&pbVals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid validator set #%d: %w", height, err)
+	}
+	return &types.LightBlock{SignedHeader: sh, ValidatorSet: vals}, nil
+}
+
+// LastLightBlockHeight returns the height of the last stored light block.
+func (s *dbs) LastLightBlockHeight() (uint64, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	var last uint64
+	it := s.db.NewIterator(signedHeaderPrefix, nil)
+	defer it.Release()
+	for it.Next() {
+		last = parseHeight(it.Key())
+	}
+	return last, it.Error()
+}
+
+// FirstLightBlockHeight returns the height of the first stored light block.
+func (s *dbs) FirstLightBlockHeight() (uint64, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	it := s.db.NewIterator(signedHeaderPrefix, nil)
+	defer it.Release()
+	if it.Next() {
+		return parseHeight(it.Key()), nil
+	}
+	return 0, it.Error()
+}
+
+// LightBlockBefore returns the light block right before the given height.
+func (s *dbs) LightBlockBefore(height uint64) (*types.LightBlock, error) {
+	s.mtx.RLock()
+	var before uint64
+	it := s.db.NewIterator(signedHeaderPrefix, nil)
+	for it.Next() {
+		h := parseHeight(it.Key())
+		if h >= height {
+			break
+		}
+		before = h
+	}
+	err := it.Error()
+	it.Release()
+	s.mtx.RUnlock()
+
+	if err != nil {
+		return nil, err
+	}
+	if before == 0 {
+		return nil, store.ErrLightBlockNotFound
+	}
+	return s.LightBlock(before)
+}
+
+// Prune deletes the oldest light blocks so that at most size are left.
+func (s *dbs) Prune(size uint16) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.size <= size {
+		return nil
+	}
+	var heights []uint64
+	it := s.db.NewIterator(signedHeaderPrefix, nil)
+	for uint16(len(heights)) < s.size-size && it.Next() {
+		heights = append(heights, parseHeight(it.Key()))
+	}
+	err := it.Error()
+	it.Release()
+	if err != nil {
+		return err
+	}
+	for _, height := range heights {
+		if err := s.deleteLightBlock(height); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Size returns the number of stored light blocks.
+func (s *dbs) Size() uint16 {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.size
+}
+
+func signedHeaderKey(height uint64) []byte {
+	return append(append([]byte{}, signedHeaderPrefix...), encodeHeight(height)...)
+}
+
+func validatorSetKey(height uint64) []byte {
+	return append(append([]byte{}, validatorSetPrefix...), encodeHeight(height)...)
+}
+
+func encodeHeight(height uint64) []byte {
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, height)
+	return enc
+}
+
+func parseHeight(key []byte) uint64 {
+	return binary.BigEndian.Uint64(key[len(key)-8:])
+}
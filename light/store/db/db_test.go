@@ -0,0 +1,87 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
+	"github.com/kardiachain/go-kardia/light/store"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+func randLightBlock(t *testing.T, height uint64) *types.LightBlock {
+	vals, privVals := types.RandValidatorSet(2, 10)
+	header := &types.Header{
+		Height:             height,
+		Time:               time.Now().UTC(),
+		ValidatorsHash:     vals.Hash(),
+		NextValidatorsHash: vals.Hash(),
+	}
+	blockID := types.BlockID{Hash: header.Hash(), PartsHeader: types.PartSetHeader{Total: 1, Hash: header.Hash()}}
+	voteSet := types.NewVoteSet("test-chain", height, 0, kproto.PrecommitType, vals)
+	commit, err := types.MakeCommit(blockID, height, 0, voteSet, privVals, time.Now())
+	require.NoError(t, err)
+	return &types.LightBlock{
+		SignedHeader: &types.SignedHeader{Header: header, Commit: commit},
+		ValidatorSet: vals,
+	}
+}
+
+func TestStore(t *testing.T) {
+	db := memorydb.New()
+	s := New(db)
+
+	height, err := s.LastLightBlockHeight()
+	require.NoError(t, err)
+	assert.Zero(t, height)
+	_, err = s.LightBlock(1)
+	assert.Equal(t, store.ErrLightBlockNotFound, err)
+
+	blocks := make(map[uint64]*types.LightBlock)
+	for _, height := range []uint64{1, 2, 5, 300} {
+		blocks[height] = randLightBlock(t, height)
+		require.NoError(t, s.SaveLightBlock(blocks[height]))
+	}
+	// Overwrites don't count.
+	require.NoError(t, s.SaveLightBlock(blocks[5]))
+	assert.EqualValues(t, 4, s.Size())
+
+	lb, err := s.LightBlock(5)
+	require.NoError(t, err)
+	assert.Equal(t, blocks[5].Hash(), lb.Hash())
+	assert.Equal(t, blocks[5].ValidatorSet.Hash(), lb.ValidatorSet.Hash())
+	require.NoError(t, lb.ValidateBasic())
+
+	height, err = s.FirstLightBlockHeight()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, height)
+	height, err = s.LastLightBlockHeight()
+	require.NoError(t, err)
+	assert.EqualValues(t, 300, height)
+
+	lb, err = s.LightBlockBefore(300)
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, lb.Height)
+	_, err = s.LightBlockBefore(1)
+	assert.Equal(t, store.ErrLightBlockNotFound, err)
+
+	// The size is restored with the store.
+	s = New(db)
+	assert.EqualValues(t, 4, s.Size())
+
+	require.NoError(t, s.Prune(2))
+	assert.EqualValues(t, 2, s.Size())
+	height, err = s.FirstLightBlockHeight()
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, height)
+
+	require.NoError(t, s.DeleteLightBlock(300))
+	require.NoError(t, s.DeleteLightBlock(300))
+	assert.EqualValues(t, 1, s.Size())
+	_, err = s.LightBlock(300)
+	assert.Equal(t, store.ErrLightBlockNotFound, err)
+}
@@ -0,0 +1,43 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// ErrLightBlockNotFound is returned when a store does not have the requested
+// light block.
+var ErrLightBlockNotFound = errors.New("light block not found")
+
+// Store is anything that can persistently store light blocks.
+type Store interface {
+	// SaveLightBlock saves a light block at its height, overwriting the one
+	// stored before if any.
+	SaveLightBlock(lb *types.LightBlock) error
+
+	// DeleteLightBlock deletes the light block at the given height.
+	DeleteLightBlock(height uint64) error
+
+	// LightBlock returns the light block at the given height, or
+	// ErrLightBlockNotFound.
+	LightBlock(height uint64) (*types.LightBlock, error)
+
+	// LastLightBlockHeight returns the height of the last stored light block,
+	// 0 if the store is empty.
+	LastLightBlockHeight() (uint64, error)
+
+	// FirstLightBlockHeight returns the height of the first stored light block,
+	// 0 if the store is empty.
+	FirstLightBlockHeight() (uint64, error)
+
+	// LightBlockBefore returns the light block with the largest height that is
+	// lower than the given one, or ErrLightBlockNotFound.
+	LightBlockBefore(height uint64) (*types.LightBlock, error)
+
+	// Prune removes the oldest light blocks until only size are left.
+	Prune(size uint16) error
+
+	// Size returns the number of stored light blocks.
+	Size() uint16
+}
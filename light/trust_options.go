@@ -0,0 +1,43 @@
+package light
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+)
+
+// TrustOptions are the trust parameters needed when a new light client
+// connects to the network or when an existing light client that has been
+// offline for longer than the trusting period connects to the network.
+//
+// The expectation is the user will get this information from a trusted source
+// like a validator, a friend, or a secure website. A more user friendly
+// solution with trust tradeoffs is that we establish an https based protocol
+// with a default end point that populates this information. Also an on-chain
+// registry of roots-of-trust (e.g. on the staking contract) can be used.
+type TrustOptions struct {
+	// Period is the time for which headers are trusted. It should be
+	// significantly less than the time validators stay bonded after they
+	// unbond, so that a fork signed by former validators can still be
+	// punished.
+	Period time.Duration
+
+	// Height and Hash of the header the client starts trusting.
+	Height uint64
+	Hash   common.Hash
+}
+
+// ValidateBasic performs basic validation.
+func (opts TrustOptions) ValidateBasic() error {
+	if opts.Period <= 0 {
+		return errors.New("negative or zero period")
+	}
+	if opts.Height == 0 {
+		return errors.New("zero height")
+	}
+	if opts.Hash.IsZero() {
+		return errors.New("empty hash")
+	}
+	return nil
+}
@@ -0,0 +1,307 @@
+package light
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	kmath "github.com/kardiachain/go-kardia/lib/math"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// DefaultTrustLevel - new header can be trusted if at least one correct
+// validator signed it.
+var DefaultTrustLevel = kmath.Fraction{Numerator: 1, Denominator: 3}
+
+// VerifyNonAdjacent verifies non-adjacent untrustedHeader against
+// trustedHeader. It ensures that:
+//
+//	a) trustedHeader can still be trusted (if not, ErrOldHeaderExpired is returned)
+//	b) untrustedHeader is valid (if not, ErrInvalidHeader is returned)
+//	c) trustLevel ([1/3, 1]) of trustedHeaderVals (or trustedHeaderNextVals)
+//	 signed correctly (if not, ErrNewValSetCantBeTrusted is returned)
+//	d) more than 2/3 of untrustedVals have signed h2
+//	  (otherwise, ErrInvalidHeader is returned)
+//	e) headers are non-adjacent.
+//
+// maxClockDrift defines how much untrustedHeader.Time can drift into the
+// future.
+func VerifyNonAdjacent(
+	chainID string,
+	trustedHeader *types.SignedHeader, // height=X
+	trustedVals *types.ValidatorSet, // height=X or height=X+1
+	untrustedHeader *types.SignedHeader, // height=Y
+	untrustedVals *types.ValidatorSet, // height=Y
+	trustingPeriod time.Duration,
+	now time.Time,
+	maxClockDrift time.Duration,
+	trustLevel kmath.Fraction) error {
+
+	if untrustedHeader.Height == trustedHeader.Height+1 {
+		return errors.New("headers must be non adjacent in height")
+	}
+
+	if HeaderExpired(trustedHeader, trustingPeriod, now) {
+		return ErrOldHeaderExpired{trustedHeader.Time.Add(trustingPeriod), now}
+	}
+
+	if err := verifyNewHeaderAndVals(untrustedHeader, untrustedVals, trustedHeader, now, maxClockDrift); err != nil {
+		return ErrInvalidHeader{err}
+	}
+
+	// Ensure that +`trustLevel` (default 1/3) or more of last trusted validators signed correctly.
+	err := trustedVals.VerifyCommitTrusting(chainID, untrustedHeader.Commit, trustLevel)
+	if err != nil {
+		var e types.ErrNotEnoughVotingPowerSigned
+		if errors.As(err, &e) {
+			return ErrNewValSetCantBeTrusted{e}
+		}
+		return ErrInvalidHeader{err}
+	}
+
+	// Ensure that +2/3 of new validators signed correctly.
+	//
+	// NOTE: this should always be the last check because untrustedVals can be
+	// intentionally made very large to DOS the light client. not the case for
+	// VerifyAdjacent, where validator set is known in advance.
+	if err := untrustedVals.VerifyCommit(chainID, untrustedHeader.Commit.BlockID, untrustedHeader.Height,
+		untrustedHeader.Commit); err != nil {
+		return ErrInvalidHeader{err}
+	}
+
+	return nil
+}
+
+// VerifyAdjacent verifies directly adjacent untrustedHeader against
+// trustedHeader. It ensures that:
+//
+//	a) trustedHeader can still be trusted (if not, ErrOldHeaderExpired is returned)
+//	b) untrustedHeader is valid (if not, ErrInvalidHeader is returned)
+//	c) untrustedHeader links to trustedHeader and its validators are the ones
+//	   trustedHeader committed to
+//	d) more than 2/3 of new validators (untrustedVals) have signed h2
+//	  (otherwise, ErrInvalidHeader is returned)
+//	e) headers are adjacent.
+//
+// maxClockDrift defines how much untrustedHeader.Time can drift into the
+// future.
+func VerifyAdjacent(
+	chainID string,
+	trustedHeader *types.SignedHeader, // height=X
+	untrustedHeader *types.SignedHeader, // height=X+1
+	untrustedVals *types.ValidatorSet, // height=X+1
+	trustingPeriod time.Duration,
+	now time.Time,
+	maxClockDrift time.Duration) error {
+
+	if untrustedHeader.Height != trustedHeader.Height+1 {
+		return errors.New("headers must be adjacent in height")
+	}
+
+	if HeaderExpired(trustedHeader, trustingPeriod, now) {
+		return ErrOldHeaderExpired{trustedHeader.Time.Add(trustingPeriod), now}
+	}
+
+	if err := verifyNewHeaderAndVals(untrustedHeader, untrustedVals, trustedHeader, now, maxClockDrift); err != nil {
+		return ErrInvalidHeader{err}
+	}
+
+	// Check the header links to the trusted one.
+	if hash := trustedHeader.Hash(); !untrustedHeader.LastBlockID.Hash.Equal(hash) {
+		err := fmt.Errorf("new header's last block (%v) does not match trusted header's hash (%v)",
+			untrustedHeader.LastBlockID.Hash, hash)
+		return ErrInvalidHeader{err}
+	}
+
+	// Check the validator hashes are the same
+	if !untrustedHeader.ValidatorsHash.Equal(trustedHeader.NextValidatorsHash) {
+		err := fmt.Errorf("expected old header next validators (%v) to match those from new header (%v)",
+			trustedHeader.NextValidatorsHash,
+			untrustedHeader.ValidatorsHash,
+		)
+		return ErrInvalidHeader{err}
+	}
+
+	// Ensure that +2/3 of new validators signed correctly.
+	if err := untrustedVals.VerifyCommit(chainID, untrustedHeader.Commit.BlockID, untrustedHeader.Height,
+		untrustedHeader.Commit); err != nil {
+		return ErrInvalidHeader{err}
+	}
+
+	return nil
+}
+
+// Verify combines both VerifyAdjacent and VerifyNonAdjacent functions.
+func Verify(
+	chainID string,
+	trustedHeader *types.SignedHeader, // height=X
+	trustedVals *types.ValidatorSet, // height=X or height=X+1
+	untrustedHeader *types.SignedHeader, // height=Y
+	untrustedVals *types.ValidatorSet, // height=Y
+	trustingPeriod time.Duration,
+	now time.Time,
+	maxClockDrift time.Duration,
+	trustLevel kmath.Fraction) error {
+
+	if untrustedHeader.Height != trustedHeader.Height+1 {
+		return VerifyNonAdjacent(chainID, trustedHeader, trustedVals, untrustedHeader, untrustedVals,
+			trustingPeriod, now, maxClockDrift, trustLevel)
+	}
+
+	return VerifyAdjacent(chainID, trustedHeader, untrustedHeader, untrustedVals, trustingPeriod, now, maxClockDrift)
+}
+
+// VerifySkipping verifies newLightBlock against trustedBlock, fetching the
+// intermediate light blocks it needs. It returns the trace of the blocks
+// verified along the way, from trustedBlock to newLightBlock.
+//
+// It bisects the range until a block is trusted by {trustLevel} of the
+// validators of the last verified block, then continues from there.
+func VerifySkipping(
+	ctx context.Context,
+	chainID string,
+	fetch func(ctx context.Context, height uint64) (*types.LightBlock, error),
+	trustedBlock *types.LightBlock,
+	newLightBlock *types.LightBlock,
+	trustingPeriod time.Duration,
+	now time.Time,
+	maxClockDrift time.Duration,
+	trustLevel kmath.Fraction) ([]*types.LightBlock, error) {
+
+	var (
+		blockCache = []*types.LightBlock{newLightBlock}
+		depth      = 0
+
+		verifiedBlock = trustedBlock
+		trace         = []*types.LightBlock{trustedBlock}
+	)
+
+	for {
+		err := Verify(chainID, verifiedBlock.SignedHeader, verifiedBlock.ValidatorSet, blockCache[depth].SignedHeader,
+			blockCache[depth].ValidatorSet, trustingPeriod, now, maxClockDrift, trustLevel)
+		switch err.(type) {
+		case nil:
+			// Have we verified the last header
+			if depth == 0 {
+				trace = append(trace, newLightBlock)
+				return trace, nil
+			}
+			// If not, update the lower bound to the previous upper bound
+			verifiedBlock = blockCache[depth]
+			// Remove the light block at the lower bound in the header cache - it will no longer be needed
+			blockCache = blockCache[:depth]
+			// Reset the cache depth so that we start from the upper bound again
+			depth = 0
+			// add verifiedBlock to the trace
+			trace = append(trace, verifiedBlock)
+
+		case ErrNewValSetCantBeTrusted:
+			// do add another header to the end of the cache
+			if depth == len(blockCache)-1 {
+				pivotHeight := verifiedBlock.Height + (blockCache[depth].Height-verifiedBlock.Height)/2
+				interimBlock, fetchErr := fetch(ctx, pivotHeight)
+				if fetchErr != nil {
+					return nil, ErrVerificationFailed{From: verifiedBlock.Height, To: pivotHeight, Reason: fetchErr}
+				}
+				blockCache = append(blockCache, interimBlock)
+			}
+			depth++
+
+		default:
+			return nil, ErrVerificationFailed{From: verifiedBlock.Height, To: blockCache[depth].Height, Reason: err}
+		}
+	}
+}
+
+func verifyNewHeaderAndVals(
+	untrustedHeader *types.SignedHeader,
+	untrustedVals *types.ValidatorSet,
+	trustedHeader *types.SignedHeader,
+	now time.Time,
+	maxClockDrift time.Duration) error {
+
+	if err := untrustedHeader.ValidateBasic(); err != nil {
+		return fmt.Errorf("untrustedHeader.ValidateBasic failed: %w", err)
+	}
+
+	if untrustedHeader.Height <= trustedHeader.Height {
+		return fmt.Errorf("expected new header height %d to be greater than one of old header %d",
+			untrustedHeader.Height,
+			trustedHeader.Height)
+	}
+
+	if !untrustedHeader.Time.After(trustedHeader.Time) {
+		return fmt.Errorf("expected new header time %v to be after old header time %v",
+			untrustedHeader.Time,
+			trustedHeader.Time)
+	}
+
+	if !untrustedHeader.Time.Before(now.Add(maxClockDrift)) {
+		return fmt.Errorf("new header has a time from the future %v (now: %v; max clock drift: %v)",
+			untrustedHeader.Time,
+			now,
+			maxClockDrift)
+	}
+
+	if untrustedVals == nil {
+		return errors.New("missing validator set of new header")
+	}
+	if hash := untrustedVals.Hash(); !untrustedHeader.ValidatorsHash.Equal(hash) {
+		return fmt.Errorf("expected new header validators (%v) to match those that were supplied (%v) at height %d",
+			untrustedHeader.ValidatorsHash,
+			hash,
+			untrustedHeader.Height,
+		)
+	}
+
+	return nil
+}
+
+// ValidateTrustLevel checks that trustLevel is within the allowed range [1/3,
+// 1]. If not, it returns an error. 1/3 is the minimum amount of trust needed
+// which does not break the security model.
+func ValidateTrustLevel(lvl kmath.Fraction) error {
+	if lvl.Numerator*3 < lvl.Denominator || // < 1/3
+		lvl.Numerator > lvl.Denominator || // > 1
+		lvl.Denominator == 0 {
+		return fmt.Errorf("trustLevel must be within [1/3, 1], given %v", lvl)
+	}
+	return nil
+}
+
+// HeaderExpired return true if the given header expired.
+func HeaderExpired(h *types.SignedHeader, trustingPeriod time.Duration, now time.Time) bool {
+	expirationTime := h.Time.Add(trustingPeriod)
+	return !expirationTime.After(now)
+}
+
+// VerifyBackwards verifies an untrusted header with a height one less than
+// that of an adjacent trusted header. It ensures that:
+//
+//	a) untrusted header is valid
+//	b) untrusted header has a time before the trusted header
+//	c) the trusted header's last block ID is the hash of the untrusted header
+//
+// For any of these cases ErrInvalidHeader is returned.
+func VerifyBackwards(untrustedHeader, trustedHeader *types.Header) error {
+	if err := untrustedHeader.ValidateBasic(); err != nil {
+		return ErrInvalidHeader{err}
+	}
+
+	if !untrustedHeader.Time.Before(trustedHeader.Time) {
+		return ErrInvalidHeader{
+			fmt.Errorf("expected older header time %v to be before new header time %v",
+				untrustedHeader.Time,
+				trustedHeader.Time)}
+	}
+
+	if hash := untrustedHeader.Hash(); !trustedHeader.LastBlockID.Hash.Equal(hash) {
+		return ErrInvalidHeader{
+			fmt.Errorf("older header hash %v does not match trusted header's last block %v",
+				hash,
+				trustedHeader.LastBlockID.Hash)}
+	}
+
+	return nil
+}
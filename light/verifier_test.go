@@ -0,0 +1,119 @@
+package light
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	kmath "github.com/kardiachain/go-kardia/lib/math"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+func TestVerifyAdjacentHeaders(t *testing.T) {
+	keys := genKeys(8)
+	blocks := genChain(t, keys, 12, changingVals(keys), 0)
+	forked := genChain(t, keys, 12, changingVals(keys), 2)
+	now := bTime.Add(time.Hour)
+
+	testCases := []struct {
+		name           string
+		trusted        *types.LightBlock
+		untrusted      *types.LightBlock
+		untrustedVals  *types.ValidatorSet
+		now            time.Time
+		trustingPeriod time.Duration
+		expErr         bool
+	}{
+		{"adjacent header", blocks[3], blocks[4], blocks[4].ValidatorSet, now, trustingPeriod, false},
+		{"adjacent header across validator change", blocks[4], blocks[5], blocks[5].ValidatorSet, now, trustingPeriod, false},
+		{"non adjacent header", blocks[3], blocks[5], blocks[5].ValidatorSet, now, trustingPeriod, true},
+		{"expired trusted header", blocks[3], blocks[4], blocks[4].ValidatorSet, now, time.Minute, true},
+		{"header from the future", blocks[3], blocks[4], blocks[4].ValidatorSet, bTime, trustingPeriod, true},
+		{"wrong validator set", blocks[4], blocks[5], blocks[4].ValidatorSet, now, trustingPeriod, true},
+		{"header not linked to trusted one", blocks[3], forked[4], forked[4].ValidatorSet, now, trustingPeriod, true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := VerifyAdjacent(chainID, tc.trusted.SignedHeader, tc.untrusted.SignedHeader, tc.untrustedVals,
+				tc.trustingPeriod, tc.now, defaultMaxClockDrift)
+			assert.Equal(t, tc.expErr, err != nil, "unexpected result: %v", err)
+		})
+	}
+}
+
+func TestVerifyNonAdjacentHeaders(t *testing.T) {
+	keys := genKeys(8)
+	blocks := genChain(t, keys, 12, changingVals(keys), 0)
+	now := bTime.Add(time.Hour)
+
+	// Half of the validators of block 1 sign block 6.
+	err := VerifyNonAdjacent(chainID, blocks[1].SignedHeader, blocks[1].ValidatorSet, blocks[6].SignedHeader,
+		blocks[6].ValidatorSet, trustingPeriod, now, defaultMaxClockDrift, DefaultTrustLevel)
+	assert.NoError(t, err)
+
+	// But not all of them.
+	err = VerifyNonAdjacent(chainID, blocks[1].SignedHeader, blocks[1].ValidatorSet, blocks[6].SignedHeader,
+		blocks[6].ValidatorSet, trustingPeriod, now, defaultMaxClockDrift, kmath.Fraction{Numerator: 1, Denominator: 1})
+	var errNotTrusted ErrNewValSetCantBeTrusted
+	assert.True(t, errors.As(err, &errNotTrusted), "unexpected error: %v", err)
+
+	// None of the validators of block 1 sign block 10.
+	err = VerifyNonAdjacent(chainID, blocks[1].SignedHeader, blocks[1].ValidatorSet, blocks[10].SignedHeader,
+		blocks[10].ValidatorSet, trustingPeriod, now, defaultMaxClockDrift, DefaultTrustLevel)
+	assert.True(t, errors.As(err, &errNotTrusted), "unexpected error: %v", err)
+
+	// Signed for another chain.
+	err = VerifyNonAdjacent("other-chain", blocks[1].SignedHeader, blocks[1].ValidatorSet, blocks[6].SignedHeader,
+		blocks[6].ValidatorSet, trustingPeriod, now, defaultMaxClockDrift, DefaultTrustLevel)
+	var errInvalid ErrInvalidHeader
+	assert.True(t, errors.As(err, &errInvalid), "unexpected error: %v", err)
+
+	// Expired trusted header.
+	err = VerifyNonAdjacent(chainID, blocks[1].SignedHeader, blocks[1].ValidatorSet, blocks[6].SignedHeader,
+		blocks[6].ValidatorSet, time.Minute, now, defaultMaxClockDrift, DefaultTrustLevel)
+	var errExpired ErrOldHeaderExpired
+	assert.True(t, errors.As(err, &errExpired), "unexpected error: %v", err)
+}
+
+func TestVerifyBackwards(t *testing.T) {
+	keys := genKeys(8)
+	blocks := genChain(t, keys, 4, changingVals(keys), 0)
+	forked := genChain(t, keys, 4, changingVals(keys), 1)
+
+	assert.NoError(t, VerifyBackwards(blocks[3].Header, blocks[4].Header))
+	assert.Error(t, VerifyBackwards(forked[3].Header, blocks[4].Header))
+	assert.Error(t, VerifyBackwards(blocks[2].Header, blocks[4].Header))
+}
+
+func TestValidateTrustLevel(t *testing.T) {
+	testCases := []struct {
+		lvl   kmath.Fraction
+		valid bool
+	}{
+		// valid
+		0: {kmath.Fraction{Numerator: 1, Denominator: 1}, true},
+		1: {kmath.Fraction{Numerator: 1, Denominator: 3}, true},
+		2: {kmath.Fraction{Numerator: 2, Denominator: 3}, true},
+		3: {kmath.Fraction{Numerator: 3, Denominator: 3}, true},
+		4: {kmath.Fraction{Numerator: 4, Denominator: 5}, true},
+
+		// invalid
+		5: {kmath.Fraction{Numerator: 6, Denominator: 5}, false},
+		6: {kmath.Fraction{Numerator: 0, Denominator: 1}, false},
+		7: {kmath.Fraction{Numerator: 0, Denominator: 0}, false},
+		8: {kmath.Fraction{Numerator: 1, Denominator: 0}, false},
+		9: {kmath.Fraction{Numerator: 1, Denominator: 4}, false},
+	}
+
+	for _, tc := range testCases {
+		err := ValidateTrustLevel(tc.lvl)
+		if !tc.valid {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+}
@@ -21,6 +21,7 @@ package kai
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 	"time"
 
@@ -33,6 +34,7 @@ import (
 	"github.com/kardiachain/go-kardia/lib/log"
 	"github.com/kardiachain/go-kardia/lib/rlp"
 	"github.com/kardiachain/go-kardia/rpc"
+	"github.com/kardiachain/go-kardia/trie"
 	"github.com/kardiachain/go-kardia/types"
 )
 
@@ -477,6 +479,148 @@ func (s *PublicKaiAPI) GetCommit(blockHeight rpc.BlockHeight) *types.Commit {
 	return s.kaiService.APIBackend.kai.blockchain.LoadBlockCommit(blockHeight.Uint64())
 }
 
+// LightBlock returns the light block at the given height: the signed header
+// and the validator set that signed it. The latest light block is the one of
+// the block before the head, the head having no commit yet.
+func (s *PublicKaiAPI) LightBlock(ctx context.Context, blockHeight rpc.BlockHeight) (*types.LightBlock, error) {
+	if blockHeight == rpc.LatestBlockHeight || blockHeight == rpc.PendingBlockHeight {
+		head := s.kaiService.blockchain.CurrentBlock().Height()
+		if head == 0 {
+			return nil, ErrCommitNotFound
+		}
+		blockHeight = rpc.BlockHeight(head - 1)
+	}
+	return s.lightBlock(ctx, blockHeight.Uint64())
+}
+
+func (s *PublicKaiAPI) lightBlock(ctx context.Context, height uint64) (*types.LightBlock, error) {
+	header := s.kaiService.blockchain.GetHeaderByHeight(height)
+	if header == nil {
+		return nil, ErrHeaderNotFound
+	}
+	commit := s.kaiService.blockchain.LoadBlockCommit(height)
+	if commit == nil {
+		return nil, ErrCommitNotFound
+	}
+	vals, err := s.kaiService.APIBackend.GetValidatorSet(ctx, rpc.BlockHeight(height))
+	if err != nil {
+		return nil, err
+	}
+	lb := &types.LightBlock{
+		SignedHeader: &types.SignedHeader{Header: header, Commit: commit},
+		ValidatorSet: vals,
+	}
+	if err := lb.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return lb, nil
+}
+
+// ProofBundle is an account proof along with the light block whose header
+// commits to the state the proof is taken from.
+type ProofBundle struct {
+	LightBlock *types.LightBlock `json:"lightBlock"`
+	Proof      *AccountResult    `json:"proof"`
+}
+
+// ProofBundle returns the Merkle-proof for a given account and optionally some
+// storage keys in the state after the block at the given height, bundled with
+// the light block of the next height, whose app hash is the root of that
+// state.
+func (s *PublicKaiAPI) ProofBundle(ctx context.Context, address common.Address, storageKeys []string, blockHeight rpc.BlockHeight) (*ProofBundle, error) {
+	head := s.kaiService.blockchain.CurrentBlock().Height()
+	if blockHeight == rpc.LatestBlockHeight || blockHeight == rpc.PendingBlockHeight {
+		if head < 2 {
+			return nil, ErrHeaderNotFound
+		}
+		blockHeight = rpc.BlockHeight(head - 2)
+	}
+	height := blockHeight.Uint64()
+	if height+1 >= head {
+		return nil, ErrCommitNotFound
+	}
+	lb, err := s.lightBlock(ctx, height+1)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := s.GetProof(ctx, address, storageKeys, rpc.BlockHeightOrHashWithHeight(blockHeight))
+	if err != nil {
+		return nil, err
+	}
+	return &ProofBundle{LightBlock: lb, Proof: proof}, nil
+}
+
+// GetTransactionProof returns the Merkle-proof of the inclusion of a transaction
+// in its block, against the TxHash of the block header.
+func (s *PublicKaiAPI) GetTransactionProof(ctx context.Context, hash common.Hash) (*types.TxProof, error) {
+	block, index, err := s.transactionBlock(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return txProof(block, index)
+}
+
+// GetReceiptProof returns the Merkle-proof of the receipt of a transaction in
+// the receipts of its block, along with the proof of the transaction. Headers
+// don't commit to receipts, see types.ReceiptProof.
+func (s *PublicKaiAPI) GetReceiptProof(ctx context.Context, hash common.Hash) (*types.ReceiptProof, error) {
+	block, index, err := s.transactionBlock(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := txProof(block, index)
+	if err != nil {
+		return nil, err
+	}
+	blockInfo := s.kaiService.APIBackend.BlockInfoByBlockHash(ctx, block.Hash())
+	if blockInfo == nil {
+		return nil, ErrBlockInfoNotFound
+	}
+	if len(blockInfo.Receipts) != block.Transactions().Len() || !blockInfo.Receipts[index].TxHash.Equal(hash) {
+		return nil, ErrReceiptsMismatch
+	}
+	root, receipt, nodes, err := trie.DeriveProof(blockInfo.Receipts, index)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ReceiptProof{TxProof: proof, ReceiptsRoot: root, Receipt: receipt, Proof: nodes}, nil
+}
+
+// transactionBlock returns the block including the transaction with the given
+// hash and the index of the transaction in it.
+func (s *PublicKaiAPI) transactionBlock(ctx context.Context, hash common.Hash) (*types.Block, int, error) {
+	tx, blockHash, _, index := rawdb.ReadTransaction(s.kaiService.chainDb, hash)
+	if tx == nil {
+		return nil, 0, ErrTransactionHashNotFound
+	}
+	block := s.kaiService.APIBackend.BlockByHash(ctx, blockHash)
+	if block == nil {
+		return nil, 0, ErrBlockNotFound
+	}
+	txs := block.Transactions()
+	if index >= uint64(txs.Len()) || !txs[index].Hash().Equal(hash) {
+		return nil, 0, ErrTransactionHashNotFound
+	}
+	return block, int(index), nil
+}
+
+func txProof(block *types.Block, index int) (*types.TxProof, error) {
+	root, tx, nodes, err := trie.DeriveProof(block.Transactions(), index)
+	if err != nil {
+		return nil, err
+	}
+	if root != block.Header().TxHash {
+		return nil, fmt.Errorf("derived txs root %v, header has %v", root.Hex(), block.Header().TxHash.Hex())
+	}
+	return &types.TxProof{
+		BlockHash:   block.Hash(),
+		BlockHeight: block.Height(),
+		Index:       uint64(index),
+		Tx:          tx,
+		Proof:       nodes,
+	}, nil
+}
+
 // AccountResult is the result structs for GetProof
 type AccountResult struct {
 	Address      common.Address  `json:"address"`
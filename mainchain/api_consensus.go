@@ -0,0 +1,177 @@
+/*
+ *  Copyright 2021 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package kai
+
+import (
+	"context"
+
+	"fmt"
+
+	"github.com/kardiachain/go-kardia/consensus"
+	cstypes "github.com/kardiachain/go-kardia/consensus/types"
+	"github.com/kardiachain/go-kardia/kai/state/cstate"
+	kpubsub "github.com/kardiachain/go-kardia/lib/pubsub"
+	"github.com/kardiachain/go-kardia/rpc"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+const (
+	// defaultScheduleHeights and defaultScheduleRounds are the size of the
+	// proposer schedule when it isn't given.
+	defaultScheduleHeights = 10
+	defaultScheduleRounds  = 1
+	// maxScheduleSlots caps the number of rounds of a proposer schedule.
+	maxScheduleSlots = 1000
+
+	// defaultUptimeWindow is the number of blocks uptime is counted over when
+	// the window isn't given, maxUptimeWindow caps it.
+	defaultUptimeWindow = 100
+	maxUptimeWindow     = 10000
+)
+
+// DumpConsensusStateResult is the full consensus state of the node and the
+// round states of its peers.
+type DumpConsensusStateResult struct {
+	RoundState *cstypes.RoundStateDump    `json:"round_state"`
+	Peers      []consensus.PeerRoundState `json:"peers"`
+}
+
+// PublicConsensusAPI offers the inspection of the consensus state and the
+// subscriptions to its events.
+type PublicConsensusAPI struct {
+	kaiService *Kardiachain
+}
+
+// NewPublicConsensusAPI creates a new consensus API.
+func NewPublicConsensusAPI(k *Kardiachain) *PublicConsensusAPI {
+	return &PublicConsensusAPI{kaiService: k}
+}
+
+// DumpConsensusState returns the round state of the node, with the votes of
+// every round at the current height, and the round states of its peers.
+func (api *PublicConsensusAPI) DumpConsensusState() *DumpConsensusStateResult {
+	return &DumpConsensusStateResult{
+		RoundState: api.kaiService.csManager.GetRoundState().Dump(),
+		Peers:      api.kaiService.csManager.PeerRoundStates(),
+	}
+}
+
+// ConsensusState returns a summary of the round state of the node.
+func (api *PublicConsensusAPI) ConsensusState() *cstypes.RoundStateSimple {
+	return api.kaiService.csManager.GetRoundState().Simple()
+}
+
+// ProposerSchedule predicts the proposers of the given number of rounds of the
+// next heights. The validator set is assumed not to change after the next
+// height.
+func (api *PublicConsensusAPI) ProposerSchedule(heights uint64, rounds uint32) ([]cstate.ProposerSlot, error) {
+	if heights == 0 {
+		heights = defaultScheduleHeights
+	}
+	if rounds == 0 {
+		rounds = defaultScheduleRounds
+	}
+	if heights*uint64(rounds) > maxScheduleSlots {
+		return nil, fmt.Errorf("schedule of %d heights and %d rounds exceeds %d rounds", heights, rounds, maxScheduleSlots)
+	}
+	return api.kaiService.stateDB.Load().ProposerSchedule(heights, rounds), nil
+}
+
+// ValidatorUptime returns the number of blocks each validator missed over the
+// given number of latest commits.
+func (api *PublicConsensusAPI) ValidatorUptime(window uint64) ([]*cstate.ValidatorUptime, error) {
+	if window == 0 {
+		window = defaultUptimeWindow
+	}
+	if window > maxUptimeWindow {
+		return nil, fmt.Errorf("window of %d blocks exceeds %d", window, maxUptimeWindow)
+	}
+	// The commit of the latest block is only known once the next one is.
+	height := api.kaiService.blockchain.CurrentBlock().Height()
+	if height == 0 {
+		return nil, nil
+	}
+	return api.kaiService.uptime.Uptime(height-1, window), nil
+}
+
+// NewRoundStep creates a subscription that fires on every step of the
+// consensus state machine.
+func (api *PublicConsensusAPI) NewRoundStep(ctx context.Context) (*rpc.Subscription, error) {
+	return api.subscribe(ctx, types.EventQueryNewRoundStep)
+}
+
+// Vote creates a subscription that fires on every vote the node adds.
+func (api *PublicConsensusAPI) Vote(ctx context.Context) (*rpc.Subscription, error) {
+	return api.subscribe(ctx, types.EventQueryVote)
+}
+
+// Polka creates a subscription that fires when the node sees +2/3 prevotes
+// for a block or nil.
+func (api *PublicConsensusAPI) Polka(ctx context.Context) (*rpc.Subscription, error) {
+	return api.subscribe(ctx, types.EventQueryPolka)
+}
+
+// Lock creates a subscription that fires when the node locks on a block.
+func (api *PublicConsensusAPI) Lock(ctx context.Context) (*rpc.Subscription, error) {
+	return api.subscribe(ctx, types.EventQueryLock)
+}
+
+// TimeoutPropose creates a subscription that fires when the node times out
+// waiting for a proposal.
+func (api *PublicConsensusAPI) TimeoutPropose(ctx context.Context) (*rpc.Subscription, error) {
+	return api.subscribe(ctx, types.EventQueryTimeoutPropose)
+}
+
+// subscribe bridges the events of the event bus matching query to an RPC
+// subscription.
+func (api *PublicConsensusAPI) subscribe(ctx context.Context, query kpubsub.Query) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	eventBus := api.kaiService.eventBus
+	subscriber := "rpc-" + string(rpcSub.ID)
+	sub, err := eventBus.Subscribe(context.Background(), subscriber, query)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		defer eventBus.Unsubscribe(context.Background(), subscriber, query) //nolint:errcheck // gone if it was cancelled
+
+		for {
+			select {
+			case msg := <-sub.Out():
+				notifier.Notify(rpcSub.ID, msg.Data())
+			case <-sub.Cancelled():
+				// Too slow to keep up with the events.
+				return
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
@@ -0,0 +1,150 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package kai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+	"github.com/kardiachain/go-kardia/rpc"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// EvidenceJSON is a piece of evidence with the validators it makes
+// accountable. Raw is the proto encoding broadcastEvidence accepts back.
+type EvidenceJSON struct {
+	Type       string           `json:"type"`
+	Hash       common.Hash      `json:"hash"`
+	Height     uint64           `json:"height"`
+	Time       time.Time        `json:"time"`
+	Validators []common.Address `json:"validators"`
+	Evidence   json.RawMessage  `json:"evidence"`
+	Raw        common.Bytes     `json:"raw"`
+}
+
+// NewEvidenceJSON returns the JSON representation of ev.
+func NewEvidenceJSON(ev types.Evidence) (*EvidenceJSON, error) {
+	evpb, err := types.EvidenceToProto(ev)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := (&jsonpb.Marshaler{}).Marshal(&buf, evpb); err != nil {
+		return nil, err
+	}
+	raw, err := evpb.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	result := &EvidenceJSON{
+		Hash:       ev.Hash(),
+		Height:     ev.Height(),
+		Time:       ev.Time(),
+		Validators: []common.Address{},
+		Evidence:   buf.Bytes(),
+		Raw:        raw,
+	}
+	switch ev.(type) {
+	case *types.DuplicateVoteEvidence:
+		result.Type = "duplicateVote"
+	case *types.LightClientAttackEvidence:
+		result.Type = "lightClientAttack"
+	}
+	for _, infraction := range ev.VM() {
+		result.Validators = append(result.Validators, infraction.Address)
+	}
+	return result, nil
+}
+
+// PublicEvidenceAPI offers the evidence of byzantine behaviour pending in the
+// evidence pool or committed in blocks, and the submission of new evidence.
+type PublicEvidenceAPI struct {
+	kaiService *Kardiachain
+}
+
+// NewPublicEvidenceAPI creates a new evidence API.
+func NewPublicEvidenceAPI(k *Kardiachain) *PublicEvidenceAPI {
+	return &PublicEvidenceAPI{kaiService: k}
+}
+
+// PendingEvidence returns the verified evidence waiting in the pool to be
+// committed in a block.
+func (api *PublicEvidenceAPI) PendingEvidence() ([]*EvidenceJSON, error) {
+	evidence, _ := api.kaiService.evPool.PendingEvidence(-1)
+	return newEvidenceListJSON(evidence)
+}
+
+// EvidenceByHeight returns the evidence committed in the block at the given
+// height.
+func (api *PublicEvidenceAPI) EvidenceByHeight(ctx context.Context, blockHeight rpc.BlockHeight) ([]*EvidenceJSON, error) {
+	block := api.kaiService.APIBackend.BlockByHeight(ctx, blockHeight)
+	if block == nil {
+		return nil, ErrBlockNotFound
+	}
+	return newEvidenceListJSON(block.Evidence().Evidence)
+}
+
+// BroadcastEvidence verifies the evidence, adds it to the pool and gossips it
+// to the peers. The evidence is either hex encoded proto, like the raw field
+// of the evidence this API returns, or its proto JSON encoding, like the
+// evidence field. It returns the hash of the evidence.
+func (api *PublicEvidenceAPI) BroadcastEvidence(input json.RawMessage) (common.Hash, error) {
+	var evpb kproto.Evidence
+	if bytes.HasPrefix(bytes.TrimSpace(input), []byte(`"`)) {
+		var raw common.Bytes
+		if err := json.Unmarshal(input, &raw); err != nil {
+			return common.Hash{}, err
+		}
+		if err := evpb.Unmarshal(raw); err != nil {
+			return common.Hash{}, err
+		}
+	} else if err := jsonpb.Unmarshal(bytes.NewReader(input), &evpb); err != nil {
+		return common.Hash{}, err
+	}
+	if evpb.Sum == nil {
+		return common.Hash{}, errors.New("empty evidence")
+	}
+	ev, err := types.EvidenceFromProto(&evpb)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := api.kaiService.evPool.AddEvidence(ev); err != nil {
+		return common.Hash{}, err
+	}
+	return ev.Hash(), nil
+}
+
+func newEvidenceListJSON(evidence []types.Evidence) ([]*EvidenceJSON, error) {
+	result := make([]*EvidenceJSON, 0, len(evidence))
+	for _, ev := range evidence {
+		evJSON, err := NewEvidenceJSON(ev)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, evJSON)
+	}
+	return result, nil
+}
@@ -0,0 +1,133 @@
+/*
+ *  Copyright 2021 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package kai
+
+import (
+	"context"
+
+	bcReactor "github.com/kardiachain/go-kardia/blockchain"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/rpc"
+)
+
+// SyncProgressJSON is the progress of a fast sync.
+type SyncProgressJSON struct {
+	StartingHeight  uint64  `json:"startingHeight"`
+	CurrentHeight   uint64  `json:"currentHeight"`
+	HighestHeight   uint64  `json:"highestHeight"`
+	BlocksPerSecond float64 `json:"blocksPerSecond"`
+	ETA             float64 `json:"eta"` // in seconds, 0 while unknown
+}
+
+func newSyncProgressJSON(p bcReactor.SyncProgress) *SyncProgressJSON {
+	return &SyncProgressJSON{
+		StartingHeight:  p.StartingHeight,
+		CurrentHeight:   p.CurrentHeight,
+		HighestHeight:   p.HighestHeight,
+		BlocksPerSecond: p.BlocksPerSecond,
+		ETA:             p.ETA.Seconds(),
+	}
+}
+
+// rpcMarshalSyncProgress marshals the progress the way web3 expects it, with
+// the rate and the ETA on top.
+func rpcMarshalSyncProgress(p bcReactor.SyncProgress) map[string]interface{} {
+	return map[string]interface{}{
+		"startingBlock":   common.Uint64(p.StartingHeight),
+		"currentBlock":    common.Uint64(p.CurrentHeight),
+		"highestBlock":    common.Uint64(p.HighestHeight),
+		"blocksPerSecond": p.BlocksPerSecond,
+		"eta":             common.Uint64(p.ETA.Seconds()),
+	}
+}
+
+// Syncing returns false if the node isn't fast syncing, otherwise the progress
+// of the sync.
+func (s *PublicKaiAPI) Syncing() (interface{}, error) {
+	progress := s.kaiService.bcR.Progress()
+	if !progress.Syncing {
+		return false, nil
+	}
+	return newSyncProgressJSON(progress), nil
+}
+
+// Syncing returns false if the node isn't fast syncing, otherwise an object
+// with the starting, current and highest block heights.
+func (s *PublicWeb3API) Syncing() (interface{}, error) {
+	progress := s.kaiService.bcR.Progress()
+	if !progress.Syncing {
+		return false, nil
+	}
+	return rpcMarshalSyncProgress(progress), nil
+}
+
+// SyncingResult is sent by the syncing subscription.
+type SyncingResult struct {
+	Syncing bool        `json:"syncing"`
+	Status  interface{} `json:"status"`
+}
+
+// PublicSyncAPI offers the syncing subscription, separately from the Syncing
+// methods since a service can't have both under the same name.
+type PublicSyncAPI struct {
+	kaiService *Kardiachain
+	isNative   bool
+}
+
+// NewPublicSyncAPI creates a new sync API, notifying the native progress if
+// isNative, the web3 one otherwise.
+func NewPublicSyncAPI(k *Kardiachain, isNative bool) *PublicSyncAPI {
+	return &PublicSyncAPI{kaiService: k, isNative: isNative}
+}
+
+// Syncing creates a subscription that fires when a fast sync starts, with its
+// progress, and false when it finishes.
+func (api *PublicSyncAPI) Syncing(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		progress := make(chan bcReactor.SyncProgress)
+		progressSub := api.kaiService.bcR.SubscribeSyncProgress(progress)
+		defer progressSub.Unsubscribe()
+
+		for {
+			select {
+			case p := <-progress:
+				if !p.Syncing {
+					notifier.Notify(rpcSub.ID, false)
+				} else if api.isNative {
+					notifier.Notify(rpcSub.ID, &SyncingResult{Syncing: true, Status: newSyncProgressJSON(p)})
+				} else {
+					notifier.Notify(rpcSub.ID, &SyncingResult{Syncing: true, Status: rpcMarshalSyncProgress(p)})
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
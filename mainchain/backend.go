@@ -25,6 +25,8 @@ import (
 	"github.com/kardiachain/go-kardia/mainchain/tracers"
 	"github.com/kardiachain/go-kardia/mainchain/tx_pool"
 	"github.com/kardiachain/go-kardia/node"
+	"github.com/kardiachain/go-kardia/privval"
+	"github.com/kardiachain/go-kardia/statesync"
 	"github.com/kardiachain/go-kardia/types"
 	"github.com/kardiachain/go-kardia/types/evidence"
 )
@@ -39,12 +41,17 @@ type Kardiachain struct {
 	blockExec  *cstate.BlockExecutor // TODO: remove this after finish merging block executor, block operations, blockchain
 	blockchain *blockchain.BlockChain
 	csManager  *consensus.ConsensusManager
+	privVal    types.PrivValidator
 	txpoolR    *tx_pool.Reactor
 	evR        *evidence.Reactor
-	bcR        p2p.Reactor // for fast-syncing
+	evPool     *evidence.Pool
+	bcR        *bcReactor.BlockchainReactor // for fast-syncing
+	ssR        *statesync.Reactor
 
 	// DB interfaces
-	chainDb kaidb.Database // Block chain database
+	chainDb kaidb.Database      // Block chain database
+	stateDB cstate.Store        // Consensus state database
+	uptime  *cstate.UptimeStore // Signers of every commit
 
 	eventBus  *types.EventBus
 	staking   *staking.StakingSmcUtil
@@ -149,6 +156,12 @@ func New(stack *node.Node, config *Config) (*Kardiachain, error) {
 
 	stateDB := cstate.NewStore(chainDb)
 
+	// Bring the consensus state, the evidence and the WAL back in line with the
+	// chain if the node went down in the middle of committing a block.
+	if _, _, err := Repair(chainDb, kai.blockchain, stateDB, config.Consensus.WalFile(), MaxStartupRepairBlocks); err != nil {
+		return nil, err
+	}
+
 	evPool, err := evidence.NewPool(stateDB, chainDb, kai.blockchain)
 	if err != nil {
 		return nil, err
@@ -165,10 +178,18 @@ func New(stack *node.Node, config *Config) (*Kardiachain, error) {
 	kai.txpoolR.SetLogger(logger)
 
 	bOper := blockchain.NewBlockOperations(logger, kai.blockchain, kai.txPool, evPool, stakingUtil)
+	// Refuse to run on another chain than the one of the trusted checkpoint.
+	if err := bcReactor.VerifyCheckpoint(bOper, config.FastSync); err != nil {
+		return nil, fmt.Errorf("%w: the node is on another chain, its data must be removed", err)
+	}
 
+	kai.evPool = evPool
 	kai.evR = evidence.NewReactor(evPool)
 	kai.evR.SetLogger(logger)
 	blockExec := cstate.NewBlockExecutor(stateDB, logger, evPool, bOper)
+	kai.stateDB = stateDB
+	kai.uptime = cstate.NewUptimeStore(chainDb)
+	blockExec.SetUptimeStore(kai.uptime)
 	kai.blockExec = blockExec
 
 	state, err := stateDB.LoadStateFromDBOrGenesisDoc(config.Genesis)
@@ -178,13 +199,28 @@ func New(stack *node.Node, config *Config) (*Kardiachain, error) {
 
 	// state starting configs
 	// Set private validator for consensus manager.
-	privValidator := types.NewDefaultPrivValidator(stack.Config().NodeKey())
+	privValidator, err := makePrivValidator(config, stack, state.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	kai.privVal = privValidator
 	// Determine whether we should do fast sync. This must happen after the handshake, since the
 	// app may modify the validator set, specifying ourself as the only validator.
 	config.FastSync.Enable = config.FastSync.Enable && !onlyValidatorIsUs(state, privValidator.GetAddress())
+	// State sync only runs on an empty node, and hands over to fast sync when done.
+	stateSync := config.StateSync != nil && config.StateSync.Enable && config.FastSync.Enable && state.LastBlockHeight == 0
 	// Make BlockchainReactor. Don't start fast sync if we're doing a state sync first.
-	bcR := bcReactor.NewBlockchainReactor(state, blockExec, bOper, config.FastSync)
+	fastSync := *config.FastSync
+	fastSync.Enable = fastSync.Enable && !stateSync
+	bcR := bcReactor.NewBlockchainReactor(state, blockExec, bOper, &fastSync)
 	kai.bcR = bcR
+	ssConfig := configs.DefaultStateSyncConfig()
+	if config.StateSync != nil {
+		*ssConfig = *config.StateSync
+	}
+	ssConfig.Enable = stateSync
+	kai.ssR = statesync.NewReactor(ssConfig, state, bOper, kai.blockchain, stateDB)
+	kai.ssR.SetLogger(logger)
 	consensusState := consensus.NewConsensusState(
 		log.New(),
 		config.Consensus,
@@ -197,6 +233,7 @@ func New(stack *node.Node, config *Config) (*Kardiachain, error) {
 	// Set private validator for consensus manager.
 	kai.csManager.SetPrivValidator(privValidator)
 	kai.csManager.SetEventBus(kai.eventBus)
+	kai.csManager.SetTxSource(kai.txPool)
 
 	// init gas price oracle
 	gpo := oracles.NewGasPriceOracle(kai.APIBackend, config.GasOracle)
@@ -219,6 +256,7 @@ func (k *Kardiachain) Config() *configs.ChainConfig       { return k.blockchain.
 
 func (k *Kardiachain) Start() error {
 	k.sw.AddReactor("BLOCKCHAIN", k.bcR)
+	k.sw.AddReactor("STATESYNC", k.ssR)
 	k.sw.AddReactor("CONSENSUS", k.csManager)
 	k.sw.AddReactor("TXPOOL", k.txpoolR)
 	k.sw.AddReactor("EVIDENCE", k.evR)
@@ -235,6 +273,9 @@ func (k *Kardiachain) Stop() error {
 	k.csManager.Stop()
 	k.blockExec.Stop()
 	k.blockchain.Stop()
+	if signer, ok := k.privVal.(*privval.SignerClient); ok {
+		signer.Close()
+	}
 	return nil
 }
 
@@ -254,6 +295,12 @@ func (k *Kardiachain) APIs() []rpc.API {
 			Service:   filters.NewPublicFilterAPI(k.APIBackend, true),
 			Public:    true,
 		},
+		{
+			Namespace: "kai",
+			Version:   "1.0",
+			Service:   NewPublicSyncAPI(k, true),
+			Public:    true,
+		},
 		{
 			Namespace: "tx",
 			Version:   "1.0",
@@ -266,6 +313,18 @@ func (k *Kardiachain) APIs() []rpc.API {
 			Service:   NewPublicAccountAPI(k),
 			Public:    true,
 		},
+		{
+			Namespace: "consensus",
+			Version:   "1.0",
+			Service:   NewPublicConsensusAPI(k),
+			Public:    true,
+		},
+		{
+			Namespace: "evidence",
+			Version:   "1.0",
+			Service:   NewPublicEvidenceAPI(k),
+			Public:    true,
+		},
 		{
 			Namespace: "debug",
 			Version:   "1.0",
@@ -291,6 +350,12 @@ func (k *Kardiachain) APIs() []rpc.API {
 			Service:   filters.NewPublicFilterAPI(k.APIBackend, false),
 			Public:    true,
 		},
+		{
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   NewPublicSyncAPI(k, false),
+			Public:    true,
+		},
 		{
 			Namespace: "eth",
 			Version:   "1.0",
@@ -324,6 +389,46 @@ func (k *Kardiachain) APIs() []rpc.API {
 	}...)
 }
 
+// makePrivValidator returns the signer of the votes and proposals of the
+// node: a remote signer dialing in on config.PrivValidatorListenAddr, or the
+// node key guarded by the last sign state in the data directory.
+func makePrivValidator(config *Config, stack *node.Node, chainID string) (types.PrivValidator, error) {
+	if config.PrivValidatorListenAddr == "" {
+		stateFile := stack.ResolvePath(privval.StateFileName)
+		if stateFile == "" {
+			// Ephemeral node, nothing to guard.
+			return types.NewDefaultPrivValidator(stack.Config().NodeKey()), nil
+		}
+		pv, err := privval.LoadFilePV(stack.Config().NodeKey(), stateFile)
+		if err != nil {
+			return nil, err
+		}
+		// A state file restored from a backup would let the node sign again
+		// what it signed before the backup, and double sign.
+		height, round, msgType, found, err := consensus.LastSignedInWAL(config.Consensus.WalFile(), pv.GetAddress())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the consensus WAL: %w", err)
+		}
+		if found {
+			if err := pv.LastSignState.CheckWAL(height, round, msgType); err != nil {
+				return nil, err
+			}
+		}
+		return pv, nil
+	}
+	endpoint, err := privval.NewSignerListener(config.PrivValidatorListenAddr, stack.Config().NodeKey(), log.New())
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the remote signer: %w", err)
+	}
+	log.Info("Waiting for the remote signer", "addr", config.PrivValidatorListenAddr)
+	signer, err := privval.NewSignerClient(endpoint, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the remote signer: %w", err)
+	}
+	log.Info("Remote signer connected", "address", signer.GetAddress())
+	return signer, nil
+}
+
 func onlyValidatorIsUs(state cstate.LatestBlockState, privValAddress common.Address) bool {
 	if state.Validators.Size() > 1 {
 		return false
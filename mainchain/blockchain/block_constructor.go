@@ -55,6 +55,9 @@ type proposalBlock struct {
 	gasLimit uint64
 	usedGas  *uint64
 
+	maxTxsBytes int64 // size budget of the transactions
+	txsBytes    int64
+
 	header   *types.Header
 	txs      []*types.Transaction
 	receipts []*types.Receipt
@@ -154,7 +157,7 @@ func (bcs *blockConstructor) constructionLoop() {
 }
 
 // newProposalBlock prepare a new block state to propose
-func (bo *BlockOperations) newProposalBlock(header *types.Header) (*proposalBlock, error) {
+func (bo *BlockOperations) newProposalBlock(header *types.Header, maxTxsBytes int64) (*proposalBlock, error) {
 	state, err := bo.blockchain.State()
 	if err != nil {
 		bo.logger.Error("Failed to get blockchain head state", "err", err)
@@ -166,11 +169,13 @@ func (bo *BlockOperations) newProposalBlock(header *types.Header) (*proposalBloc
 		signer:   types.LatestSigner(bo.blockchain.chainConfig),
 		state:    state,
 		tcount:   0,
-		gasLimit: configs.BlockGasLimitGalaxias,
+		gasLimit: header.GasLimit,
 		usedGas:  new(uint64),
 		header:   header,
 		txs:      []*types.Transaction{},
 		receipts: []*types.Receipt{},
+
+		maxTxsBytes: maxTxsBytes,
 	}
 	pb.gasPool = new(types.GasPool).AddGas(pb.gasLimit)
 	if err := pb.organizeTransactions(bo); err != nil {
@@ -227,6 +232,7 @@ func (pb *proposalBlock) commitTransaction(bo *BlockOperations, tx *types.Transa
 		return err
 	}
 	pb.txs = append(pb.txs, tx)
+	pb.txsBytes += txBytes(tx)
 	pb.receipts = append(pb.receipts, receipt)
 	return nil
 }
@@ -252,6 +258,12 @@ func (pb *proposalBlock) commitTransactions(bo *BlockOperations, txs *types.Tran
 			continue
 		}
 
+		if pb.txsBytes+txBytes(tx) > pb.maxTxsBytes {
+			log.Trace("Skipping transaction which exceeds the block max bytes", "hash", tx.Hash(), "size", tx.Size(), "left", pb.maxTxsBytes-pb.txsBytes)
+			txs.Pop()
+			continue
+		}
+
 		// Error may be ignored here. The error has already been checked
 		// during transaction acceptance is the transaction pool.
 		from, _ := types.Sender(pb.signer, tx)
@@ -19,6 +19,7 @@
 package blockchain
 
 import (
+	"math"
 	"sync"
 	"time"
 
@@ -32,6 +33,7 @@ import (
 	"github.com/kardiachain/go-kardia/mainchain/staking/misc"
 	stypes "github.com/kardiachain/go-kardia/mainchain/staking/types"
 	"github.com/kardiachain/go-kardia/mainchain/tx_pool"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
 	"github.com/kardiachain/go-kardia/trie"
 	"github.com/kardiachain/go-kardia/types"
 )
@@ -100,10 +102,9 @@ func (bo *BlockOperations) CreateProposalBlock(
 	// Tx execution can happen in parallel with voting or precommitted.
 	// For simplicity, this code executes & commits txs before sending proposal,
 	// so statedb of proposal node already contains the new state and txs receipts of this proposal block.
-	//maxBytes := lastState.ConsensusParams.Block.MaxBytes
+	maxBytes := lastState.ConsensusParams.Block.MaxBytes
 	// Fetch a limited amount of valid evidence
-	maxNumEvidence, _ := types.MaxEvidencePerBlock(lastState.ConsensusParams.Evidence.MaxBytes)
-	evidence, _ := bo.evPool.PendingEvidence(maxNumEvidence)
+	evidence, _ := bo.evPool.PendingEvidence(lastState.ConsensusParams.Evidence.MaxBytes)
 
 	// Set time.
 	var timestamp time.Time
@@ -115,12 +116,11 @@ func (bo *BlockOperations) CreateProposalBlock(
 
 	header := bo.newHeader(timestamp, height, 0, lastState.LastBlockID, proposerAddr, lastState.Validators.Hash(),
 		lastState.NextValidators.Hash(), lastState.AppHash)
-	header.GasLimit = configs.BlockGasLimit
+	header.GasLimit = cstate.BlockGasLimit(bo.blockchain.chainConfig, lastState)
 	bo.logger.Info("Creates new header", "header", header)
 
 	if bo.blockchain.chainConfig.IsGalaxias(&bo.height) {
-		header.GasLimit = configs.BlockGasLimitGalaxias
-		pb, err := bo.newProposalBlock(header)
+		pb, err := bo.newProposalBlock(header, maxTxsBytes(maxBytes, bo.newBlock(header, nil, commit, evidence)))
 		if err != nil {
 			bo.logger.Error("Failed to create new proposal block", "err", err)
 		}
@@ -132,17 +132,52 @@ func (bo *BlockOperations) CreateProposalBlock(
 	}
 
 	txs := bo.txPool.GetPendingData()
+	txs = limitTxsBytes(txs, maxTxsBytes(maxBytes, bo.newBlock(header, nil, commit, evidence)))
 
 	block = bo.newBlock(header, txs, commit, evidence)
 	bo.logger.Trace("Make block to propose", "block", block)
 	return block, block.MakePartSet(types.BlockPartSizeBytes)
 }
 
+const (
+	// blockTxsOverheadBytes bounds how much a block encoding grows with its
+	// transaction list, besides the transactions: Data field and Header.NumTxs.
+	blockTxsOverheadBytes = 24
+	// txOverheadBytes bounds the field header of an encoded transaction.
+	txOverheadBytes = 6
+)
+
+// maxTxsBytes returns how many bytes of transactions fit in a block of at most
+// maxBytes, given the same block without any transaction.
+func maxTxsBytes(maxBytes int64, emptyBlock *types.Block) int64 {
+	if maxBytes <= 0 {
+		return math.MaxInt64
+	}
+	return maxBytes - emptyBlock.ByteSize() - blockTxsOverheadBytes
+}
+
+// txBytes returns the size taken by tx in a block encoding.
+func txBytes(tx *types.Transaction) int64 {
+	return int64(tx.Size()) + txOverheadBytes
+}
+
+// limitTxsBytes returns the longest prefix of txs fitting in maxBytes.
+func limitTxsBytes(txs []*types.Transaction, maxBytes int64) []*types.Transaction {
+	var size int64
+	for i, tx := range txs {
+		size += txBytes(tx)
+		if size > maxBytes {
+			return txs[:i]
+		}
+	}
+	return txs
+}
+
 // CommitAndValidateBlockTxs executes and commits the transactions in the given block.
 // New calculated state root is validated against the root field in block.
 // Transactions, new state and receipts are saved to storage.
 func (bo *BlockOperations) CommitAndValidateBlockTxs(block *types.Block, lastCommit stypes.LastCommitInfo,
-	byzVals []stypes.Evidence) ([]*types.Validator, common.Hash, error) {
+	byzVals []stypes.Evidence) ([]*types.Validator, *kproto.ConsensusParams, common.Hash, error) {
 	// Update blacklisted addresses after interval
 	if block.Height()%tx_pool.UpdateBlacklistInterval == 0 {
 		err := tx_pool.UpdateBlacklist(tx_pool.BlacklistRequestTimeout)
@@ -156,17 +191,26 @@ func (bo *BlockOperations) CommitAndValidateBlockTxs(block *types.Block, lastCom
 	state, err := bo.blockchain.State()
 	if err != nil {
 		bo.logger.Error("Fail to get blockchain head state", "err", err)
-		return nil, common.Hash{}, err
+		return nil, nil, common.Hash{}, err
 	}
 
-	vals, blockInfo, err := bo.commitBlock(state, block.Transactions(), block.Header(), lastCommit, byzVals)
+	vals, params, blockInfo, err := bo.commitBlock(state, block.Transactions(), block.Header(), lastCommit, byzVals)
 	if err != nil {
-		return nil, common.Hash{}, err
+		return nil, nil, common.Hash{}, err
 	}
 
 	bo.blockchain.WriteBlockAndSetHead(block, blockInfo, state)
 
-	return vals, state.IntermediateRoot(true), nil
+	return vals, params, state.IntermediateRoot(true), nil
+}
+
+// ExecuteBlock runs every state transition of the given block on top of state,
+// exactly like CommitAndValidateBlockTxs does, but leaves the result in memory:
+// neither the block nor the resulting state are written to storage.
+func (bo *BlockOperations) ExecuteBlock(state *state.StateDB, block *types.Block, lastCommit stypes.LastCommitInfo,
+	byzVals []stypes.Evidence) ([]*types.Validator, *types.BlockInfo, error) {
+	vals, _, blockInfo, err := bo.commitBlock(state, block.Transactions(), block.Header(), lastCommit, byzVals)
+	return vals, blockInfo, err
 }
 
 // SaveBlock saves the given block, blockParts, and seenCommit to the underlying storage.
@@ -193,6 +237,29 @@ func (bo *BlockOperations) SaveBlock(block *types.Block, blockParts *types.PartS
 	bo.mtx.Unlock()
 }
 
+// WriteSyncedHead makes the block restored by state sync the head of the chain,
+// see BlockChain.WriteSyncedHead. The block becomes the base of the block store.
+func (bo *BlockOperations) WriteSyncedHead(block *types.Block, blockParts *types.PartSet, seenCommit *types.Commit, root common.Hash) error {
+	if err := bo.blockchain.WriteSyncedHead(block, blockParts, seenCommit, root); err != nil {
+		return err
+	}
+	bo.mtx.Lock()
+	bo.base = block.Height()
+	bo.height = block.Height()
+	bo.mtx.Unlock()
+	return nil
+}
+
+// ConsensusParams returns the consensus params set through governance in the
+// state committed by the block of the given header.
+func (bo *BlockOperations) ConsensusParams(header *types.Header) (*kproto.ConsensusParams, error) {
+	state, err := bo.blockchain.StateAt(header.Height)
+	if err != nil {
+		return nil, err
+	}
+	return bo.staking.ApplyAndReturnConsensusParams(state, header, bo.blockchain, kvm.Config{})
+}
+
 // LoadBlock returns the Block for the given height.
 // If no block is found for the given height, it returns nil.
 func (bo *BlockOperations) LoadBlock(height uint64) *types.Block {
@@ -256,7 +323,7 @@ func (bo *BlockOperations) commitBlock(
 	header *types.Header,
 	lastCommit stypes.LastCommitInfo,
 	byzVals []stypes.Evidence,
-) ([]*types.Validator, *types.BlockInfo, error) {
+) ([]*types.Validator, *kproto.ConsensusParams, *types.BlockInfo, error) {
 	var (
 		receipts = types.Receipts{}
 		usedGas  = new(uint64)
@@ -267,11 +334,15 @@ func (bo *BlockOperations) commitBlock(
 		valsList, err := bo.staking.GetAllValContracts(state, header, bo.blockchain, bo.blockchain.vmConfig)
 		if err != nil {
 			bo.logger.Error("Failed to apply Galaxias Staking hardfork")
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 		misc.ApplyGalaxiasContracts(state, valsList)
 		bo.logger.Info("Applied Galaxias hardfork successfully at", "block", header.Height)
 	}
+	if bo.blockchain.chainConfig.ParamsUpgradeBlock != nil && *bo.blockchain.chainConfig.ParamsUpgradeBlock == header.Height {
+		misc.ApplyParamsUpgradeContracts(state)
+		bo.logger.Info("Applied Params upgrade hardfork successfully at", "block", header.Height)
+	}
 
 	// GasPool
 	bo.logger.Debug("header gas limit", "limit", header.GasLimit)
@@ -285,17 +356,17 @@ func (bo *BlockOperations) commitBlock(
 	blockReward, err := bo.staking.Mint(state, header, bo.blockchain, kvmConfig)
 	if err != nil {
 		bo.logger.Error("Fail to mint", "err", err)
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	if err := bo.staking.FinalizeCommit(state, header, bo.blockchain, kvmConfig, lastCommit); err != nil {
 		bo.logger.Error("Fail to finalize commit", "err", err)
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	if err := bo.staking.DoubleSign(state, header, bo.blockchain, kvmConfig, byzVals); err != nil {
 		bo.logger.Error("Fail to apply double sign", "err", err)
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 LOOP:
@@ -314,7 +385,12 @@ LOOP:
 
 	vals, err := bo.staking.ApplyAndReturnValidatorSets(state, header, bo.blockchain, kvmConfig)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
+	}
+	params, err := bo.staking.ApplyAndReturnConsensusParams(state, header, bo.blockchain, kvmConfig)
+	if err != nil {
+		bo.logger.Error("Fail to read consensus params", "err", err)
+		return nil, nil, nil, err
 	}
 
 	proctime := time.Since(start) // processing + validation
@@ -328,5 +404,5 @@ LOOP:
 		Bloom:    types.CreateBloom(receipts),
 	}
 
-	return vals, blockInfo, nil
+	return vals, params, blockInfo, nil
 }
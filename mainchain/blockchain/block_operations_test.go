@@ -0,0 +1,51 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/trie"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+func TestLimitTxsBytes(t *testing.T) {
+	header := &types.Header{Height: 2, GasLimit: 20000000}
+	commit := &types.Commit{Height: 1}
+	var txs []*types.Transaction
+	for i := 0; i < 100; i++ {
+		txs = append(txs, types.NewTransaction(uint64(i), common.Address{}, big.NewInt(0), 100000, big.NewInt(1), make([]byte, 1000)))
+	}
+	newBlock := func(txs []*types.Transaction) *types.Block {
+		return types.NewBlock(header, txs, commit, nil, trie.NewStackTrie(nil))
+	}
+
+	for _, maxBytes := range []int64{2000, 10000, 50000, 99999} {
+		limited := limitTxsBytes(txs, maxTxsBytes(maxBytes, newBlock(nil)))
+		require.NotEmpty(t, limited)
+		require.Less(t, len(limited), len(txs))
+		require.LessOrEqual(t, newBlock(limited).ByteSize(), maxBytes)
+		require.Greater(t, newBlock(txs[:len(limited)+1]).ByteSize(), maxBytes-2*txOverheadBytes-blockTxsOverheadBytes)
+	}
+	require.Len(t, limitTxsBytes(txs, maxTxsBytes(0, newBlock(nil))), len(txs), "unset max bytes")
+}
@@ -20,6 +20,7 @@ package blockchain
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -354,10 +355,21 @@ func (bc *BlockChain) SetHead(head uint64) error {
 	bc.mu.Lock()
 	defer bc.mu.Unlock()
 
-	// Rewind the header chain, deleting all block bodies until then
+	// Rewind the header chain, deleting all block data until then
 	delFn := func(db kaidb.Database, height uint64) {
-		rawdb.DeleteBlockMeta(bc.db, height)
-		rawdb.DeleteBlockPart(bc.db, height)
+		if block := rawdb.ReadBlock(db, height); block != nil {
+			for _, tx := range block.Transactions() {
+				rawdb.DeleteTxLookupEntry(db, tx.Hash())
+			}
+			rawdb.DeleteBlockInfo(db, block.Hash(), height)
+			rawdb.DeleteHeader(db, block.Hash(), height)
+		}
+		rawdb.DeleteBlockPart(db, height)
+		rawdb.DeleteBlockMeta(db, height)
+		rawdb.DeleteCommit(db, height-1)
+		rawdb.DeleteSeenCommit(db, height)
+		rawdb.DeleteAppHash(db, height)
+		rawdb.DeleteConsensusUptime(db, height)
 	}
 	bc.hc.SetHead(head, delFn)
 	currentHeader := bc.hc.CurrentHeader()
@@ -382,6 +394,7 @@ func (bc *BlockChain) SetHead(head uint64) error {
 	if currentBlock := bc.CurrentBlock(); currentBlock == nil {
 		bc.currentBlock.Store(bc.genesisBlock)
 	}
+	rawdb.WriteHeadBlockHash(bc.db, bc.CurrentBlock().Hash())
 
 	return bc.loadLastState()
 }
@@ -488,6 +501,30 @@ func (bc *BlockChain) WriteBlockAndSetHead(block *types.Block, blockInfo *types.
 	return bc.writeBlockAndSetHead(block, blockInfo, state)
 }
 
+// WriteSyncedHead makes a block whose state was restored by state sync, rather
+// than by executing the chain up to it, the head of the chain. The state trie
+// of root must already be in the database.
+func (bc *BlockChain) WriteSyncedHead(block *types.Block, blockParts *types.PartSet, seenCommit *types.Commit, root common.Hash) error {
+	if !bc.mu.TryLock() {
+		return errChainStopped
+	}
+	defer bc.mu.Unlock()
+
+	if !bc.HasState(root) {
+		return fmt.Errorf("state %v of block #%d is missing", root, block.Height())
+	}
+	rawdb.WriteBlock(bc.db, block, blockParts, seenCommit)
+	rawdb.WriteAppHash(bc.db, block.Height(), root)
+	bc.writeHeadBlock(block)
+
+	// The restored state has no flat snapshot yet, generate it in the background
+	if bc.snaps != nil {
+		bc.snaps.Rebuild(root)
+	}
+	bc.chainHeadFeed.Send(events.ChainHeadEvent{Block: block})
+	return nil
+}
+
 // writeBlockAndSetHead is the internal implementation of WriteBlockAndSetHead.
 // This function expects the chain mutex to be held.
 func (bc *BlockChain) writeBlockAndSetHead(block *types.Block, blockInfo *types.BlockInfo, state *state.StateDB) error {
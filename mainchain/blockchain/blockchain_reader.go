@@ -24,9 +24,11 @@ import (
 	"github.com/kardiachain/go-kardia/kai/kaidb"
 	"github.com/kardiachain/go-kardia/kai/rawdb"
 	"github.com/kardiachain/go-kardia/kai/state"
+	"github.com/kardiachain/go-kardia/kai/state/snapshot"
 	"github.com/kardiachain/go-kardia/kvm"
 	"github.com/kardiachain/go-kardia/lib/common"
 	"github.com/kardiachain/go-kardia/lib/event"
+	"github.com/kardiachain/go-kardia/trie"
 	"github.com/kardiachain/go-kardia/types"
 )
 
@@ -154,6 +156,16 @@ func (bc *BlockChain) DB() kaidb.Database {
 	return bc.db
 }
 
+// Snapshots returns the blockchain snapshot tree, nil if snapshots are disabled.
+func (bc *BlockChain) Snapshots() *snapshot.Tree {
+	return bc.snaps
+}
+
+// TrieDB returns the trie database of the chain state.
+func (bc *BlockChain) TrieDB() *trie.Database {
+	return bc.triedb
+}
+
 // Config retrieves the blockchain's chain configuration.
 func (bc *BlockChain) Config() *configs.ChainConfig { return bc.chainConfig }
 
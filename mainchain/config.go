@@ -89,5 +89,14 @@ type Config struct {
 
 	FastSync *configs.FastSyncConfig `toml:",omitempty"`
 
+	// StateSync restores the state of an empty node from a peer snapshot
+	// before fast syncing the remaining blocks.
+	StateSync *configs.StateSyncConfig `toml:",omitempty"`
+
 	GasOracle *oracles.Config `toml:",omitempty"`
+
+	// PrivValidatorListenAddr is the address, tcp://host:port or
+	// unix:///path, on which the node waits for a remote signer to dial in
+	// and sign its votes and proposals. The node key signs them if empty.
+	PrivValidatorListenAddr string `toml:",omitempty"`
 }
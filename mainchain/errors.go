@@ -17,5 +17,7 @@ var (
 	ErrNilGasPrice             = errors.New("nil gas price")
 	ErrTxFeeCap                = errors.New("dropped due to high transaction fee")
 	ErrBlockNotFound           = errors.New("block not found")
+	ErrCommitNotFound          = errors.New("commit not found")
 	ErrTransactionHashNotFound = errors.New("transaction hash not found")
+	ErrReceiptsMismatch        = errors.New("receipts don't match the block transactions")
 )
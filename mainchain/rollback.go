@@ -0,0 +1,139 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package kai
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kardiachain/go-kardia/consensus"
+	"github.com/kardiachain/go-kardia/kai/kaidb"
+	"github.com/kardiachain/go-kardia/kai/rawdb"
+	"github.com/kardiachain/go-kardia/kai/state/cstate"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/log"
+	"github.com/kardiachain/go-kardia/mainchain/blockchain"
+	"github.com/kardiachain/go-kardia/types"
+	"github.com/kardiachain/go-kardia/types/evidence"
+)
+
+// MaxStartupRepairBlocks is the number of blocks the node rolls back on its own
+// at startup. Deeper repairs have to be run with `kaigo rollback --repair`.
+const MaxStartupRepairBlocks = 100
+
+// ErrRepairTooDeep is returned by Repair when the chain would be rolled back
+// further than allowed.
+var ErrRepairTooDeep = errors.New("repair would roll back too many blocks")
+
+// Rollback rewinds a stopped node to height: the blocks above it are deleted,
+// along with their consensus states, the evidence they committed is put back
+// to pending and the consensus WAL is restarted at height.
+// The state and the consensus state of the target block must be available.
+func Rollback(chainDb kaidb.Database, bc *blockchain.BlockChain, stateStore cstate.Store, walFile string, height uint64) error {
+	head := bc.CurrentBlock().Height()
+	if height >= head {
+		return fmt.Errorf("rollback height %d is not below the chain head #%d", height, head)
+	}
+	if root := rawdb.ReadAppHash(chainDb, height); !bc.HasState(root) {
+		return fmt.Errorf("state of block #%d (root %v) is not available", height, root)
+	}
+	if rawdb.ReadConsensusStateHeight(chainDb, height) == nil {
+		return fmt.Errorf("consensus state of block #%d is not available", height)
+	}
+	return rollback(chainDb, bc, stateStore, walFile, height)
+}
+
+// Repair looks for the inconsistencies an unclean shutdown can leave behind,
+// i.e. a head block without state or consensus state, an app hash that doesn't
+// match the one committed to by the next block, or consensus states above the
+// chain head, and rolls the node back to the highest consistent height.
+// If maxBlocks isn't 0, it refuses to roll back more than maxBlocks blocks or
+// to genesis, and returns ErrRepairTooDeep instead.
+// It returns that height and whether anything had to be repaired.
+func Repair(chainDb kaidb.Database, bc *blockchain.BlockChain, stateStore cstate.Store, walFile string, maxBlocks uint64) (uint64, bool, error) {
+	head := bc.CurrentBlock().Height()
+	height := lastConsistentHeight(chainDb, bc, head)
+	if height == head && rawdb.ReadConsensusStateHeight(chainDb, head+1) == nil {
+		return head, false, nil
+	}
+	if maxBlocks > 0 && (height == 0 || head-height > maxBlocks) {
+		log.Error("Inconsistent chain detected, refusing to roll back", "head", head, "target", height, "max", maxBlocks)
+		return height, false, fmt.Errorf("%w: from #%d to #%d, run `kaigo rollback --repair` to do it", ErrRepairTooDeep, head, height)
+	}
+	log.Warn("Inconsistent chain detected, rolling back", "head", head, "target", height)
+	return height, true, rollback(chainDb, bc, stateStore, walFile, height)
+}
+
+// lastConsistentHeight returns the highest height at or below head whose block,
+// state and consensus state are all present, and whose app hash is the one the
+// next block (if any) was built on.
+func lastConsistentHeight(chainDb kaidb.Database, bc *blockchain.BlockChain, head uint64) uint64 {
+	for height := head; height > 0; height-- {
+		root := rawdb.ReadAppHash(chainDb, height)
+		if root == (common.Hash{}) || rawdb.ReadBlockMeta(chainDb, height) == nil {
+			continue
+		}
+		if !bc.HasState(root) {
+			log.Warn("Block state missing", "height", height, "root", root)
+			continue
+		}
+		if rawdb.ReadConsensusStateHeight(chainDb, height) == nil {
+			log.Warn("Consensus state missing", "height", height)
+			continue
+		}
+		if next := rawdb.ReadHeader(chainDb, height+1); next != nil && !next.AppHash.Equal(root) {
+			log.Warn("App hash mismatch", "height", height, "root", root, "next block app hash", next.AppHash)
+			continue
+		}
+		return height
+	}
+	return 0
+}
+
+func rollback(chainDb kaidb.Database, bc *blockchain.BlockChain, stateStore cstate.Store, walFile string, height uint64) error {
+	head := bc.CurrentBlock().Height()
+
+	// Collect the evidence committed by the discarded blocks before deleting them
+	var evList types.EvidenceList
+	for h := height + 1; h <= head; h++ {
+		if block := bc.GetBlockByHeight(h); block != nil {
+			evList = append(evList, block.Evidence().Evidence...)
+		}
+	}
+	if err := bc.SetHead(height); err != nil {
+		return err
+	}
+	if newHead := bc.CurrentBlock().Height(); newHead != height {
+		return fmt.Errorf("chain rewound to #%d instead of #%d", newHead, height)
+	}
+	states, err := stateStore.Rollback(height)
+	if err != nil {
+		return err
+	}
+	if err := evidence.RevertCommittedEvidence(chainDb, evList); err != nil {
+		return err
+	}
+	backup, err := consensus.RollbackWAL(walFile, height)
+	if err != nil {
+		return err
+	}
+	log.Warn("Rolled back chain", "height", height, "blocks", head-height, "consensus states", states,
+		"evidence", len(evList), "wal backup", backup)
+	return nil
+}
@@ -0,0 +1,115 @@
+package staking
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/kardiachain/go-kardia/configs"
+	"github.com/kardiachain/go-kardia/kai/state"
+	"github.com/kardiachain/go-kardia/kvm"
+	vm "github.com/kardiachain/go-kardia/mainchain/kvm"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// Keys of the consensus parameters in the ParamKey enum of the Params contract.
+const (
+	paramBlockMaxBytes int64 = iota + 20
+	paramBlockMaxGas
+	paramEvidenceMaxAgeNumBlocks
+	paramEvidenceMaxAgeDuration
+	paramEvidenceMaxBytes
+	paramTimeoutPropose
+	paramTimeoutProposeDelta
+	paramTimeoutPrevote
+	paramTimeoutPrevoteDelta
+	paramTimeoutPrecommit
+	paramTimeoutPrecommitDelta
+	paramTimeoutCommit
+)
+
+// ApplyAndReturnConsensusParams returns the consensus parameters set through
+// the governance of the Params contract. The parameters governance never set
+// are left zero, see types.UpdateConsensusParams. It returns nil if the chain
+// has no Params contract.
+func (s *StakingSmcUtil) ApplyAndReturnConsensusParams(statedb *state.StateDB, header *types.Header, bc vm.ChainContext, cfg kvm.Config) (*kproto.ConsensusParams, error) {
+	values := make(map[int64]*big.Int)
+	for key := paramBlockMaxBytes; key <= paramTimeoutCommit; key++ {
+		value, err := s.getParam(statedb, header, bc, cfg, key)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			return nil, nil
+		}
+		// Values that don't fit are ignored like unset ones.
+		if value.IsInt64() {
+			values[key] = value
+		}
+	}
+	param := func(key int64) int64 {
+		if value, ok := values[key]; ok {
+			return value.Int64()
+		}
+		return 0
+	}
+	duration := func(key int64, unit time.Duration) time.Duration {
+		if value := param(key); value <= int64(1<<63-1)/int64(unit) {
+			return time.Duration(value) * unit
+		}
+		return 0
+	}
+
+	return &kproto.ConsensusParams{
+		Block: kproto.BlockParams{
+			MaxBytes: param(paramBlockMaxBytes),
+			MaxGas:   uint64(param(paramBlockMaxGas)),
+		},
+		Evidence: kproto.EvidenceParams{
+			MaxAgeNumBlocks: param(paramEvidenceMaxAgeNumBlocks),
+			MaxAgeDuration:  duration(paramEvidenceMaxAgeDuration, time.Second),
+			MaxBytes:        param(paramEvidenceMaxBytes),
+		},
+		Timeout: kproto.TimeoutParams{
+			Propose:        duration(paramTimeoutPropose, time.Millisecond),
+			ProposeDelta:   duration(paramTimeoutProposeDelta, time.Millisecond),
+			Prevote:        duration(paramTimeoutPrevote, time.Millisecond),
+			PrevoteDelta:   duration(paramTimeoutPrevoteDelta, time.Millisecond),
+			Precommit:      duration(paramTimeoutPrecommit, time.Millisecond),
+			PrecommitDelta: duration(paramTimeoutPrecommitDelta, time.Millisecond),
+			Commit:         duration(paramTimeoutCommit, time.Millisecond),
+		},
+	}, nil
+}
+
+// getParam returns the value of a parameter of the Params contract, nil if
+// there is no contract.
+func (s *StakingSmcUtil) getParam(statedb *state.StateDB, header *types.Header, bc vm.ChainContext, cfg kvm.Config, key int64) (*big.Int, error) {
+	payload, err := s.paramsAbi.Pack("params", big.NewInt(key))
+	if err != nil {
+		return nil, err
+	}
+	paramsAddr := configs.ParamsSMCAddress
+	msg := types.NewMessage(
+		s.ContractAddress,
+		&paramsAddr,
+		0,
+		big.NewInt(0),
+		100000000,
+		big.NewInt(0),
+		payload,
+		false,
+	)
+	res, err := Apply(s.logger, bc, statedb, header, cfg, msg)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, nil
+	}
+	var value *big.Int
+	if err := s.paramsAbi.UnpackIntoInterface(&value, "params", res); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,167 @@
+package staking_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/configs"
+	"github.com/kardiachain/go-kardia/kai/accounts/abi"
+	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
+	"github.com/kardiachain/go-kardia/kai/state"
+	"github.com/kardiachain/go-kardia/kvm"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/mainchain/genesis"
+	"github.com/kardiachain/go-kardia/mainchain/staking"
+	"github.com/kardiachain/go-kardia/mainchain/staking/misc"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+const (
+	paramMaxProposers    = 2
+	paramDeposit         = 18
+	paramBlockMaxGas     = 21
+	paramTimeoutPropose  = 25
+	paramTimeoutCommit   = 31
+	votingPeriod         = 30 * 24 * time.Hour
+	proposalDeposit      = "500000000000000000000000"
+	genesisAccountAmount = "1000000000000000000000000000"
+)
+
+// genesisState returns the state of a genesis with a single validator, which
+// is also the account proposing and voting param changes.
+func genesisState(t *testing.T) (*state.StateDB, common.Address, time.Time) {
+	configs.AddDefaultContract()
+	configs.AddDefaultStakingContractAddress()
+
+	val, _ := types.RandValidator(false, 1)
+	balance, _ := new(big.Int).SetString(genesisAccountAmount, 10)
+	timestamp := time.Unix(1600000000, 0)
+	g := &genesis.Genesis{
+		Timestamp: timestamp,
+		Validators: []*genesis.GenesisValidator{{
+			Address:          val.Address.String(),
+			StartWithGenesis: true,
+			SelfDelegate:     "12500000000000000000000000",
+			Name:             strings.Repeat(" ", 32),
+			CommissionRate:   "5",
+			MaxRate:          "20",
+			MaxChangeRate:    "5",
+		}},
+		Alloc: genesis.GenesisAlloc{val.Address: {Balance: balance}},
+	}
+	db := memorydb.New()
+	block := g.ToBlock(db)
+	statedb, err := state.New(block.AppHash(), state.NewDatabase(db), nil)
+	require.NoError(t, err)
+	return statedb, val.Address, timestamp
+}
+
+func applyParams(t *testing.T, statedb *state.StateDB, at time.Time, from common.Address, value *big.Int, method string, args ...interface{}) ([]byte, error) {
+	paramsAbi, err := abi.JSON(strings.NewReader(configs.ParamsContract.ABI))
+	require.NoError(t, err)
+	input, err := paramsAbi.Pack(method, args...)
+	require.NoError(t, err)
+	msg := types.NewMessage(from, &configs.ParamsSMCAddress, 0, value, 10000000, big.NewInt(0), input, false)
+	header := &types.Header{Time: at, GasLimit: configs.GenesisGasLimit}
+	return staking.Apply(nil, nil, statedb, header, kvm.Config{}, msg)
+}
+
+func callParams(t *testing.T, statedb *state.StateDB, at time.Time, from common.Address, value *big.Int, method string, args ...interface{}) []byte {
+	res, err := applyParams(t, statedb, at, from, value, method, args...)
+	require.NoError(t, err, method)
+	return res
+}
+
+// applyGalaxias installs the contracts of the Galaxias hardfork.
+func applyGalaxias(t *testing.T, statedb *state.StateDB, at time.Time) {
+	util, err := staking.NewSmcStakingUtil()
+	require.NoError(t, err)
+	header := &types.Header{Time: at, GasLimit: configs.GenesisGasLimit}
+	vals, err := util.GetAllValContracts(statedb, header, nil, kvm.Config{})
+	require.NoError(t, err)
+	misc.ApplyGalaxiasContracts(statedb, vals)
+}
+
+// governConsensusParams sets consensus params through a proposal voted by the
+// validator, and returns the params read by the node once it passed.
+func governConsensusParams(t *testing.T, statedb *state.StateDB, validator common.Address, now time.Time) {
+	util, err := staking.NewSmcStakingUtil()
+	require.NoError(t, err)
+	header := &types.Header{Time: now, GasLimit: configs.GenesisGasLimit}
+	params, err := util.ApplyAndReturnConsensusParams(statedb, header, nil, kvm.Config{})
+	require.NoError(t, err)
+	require.Zero(t, params.Block.MaxGas, "no consensus param is set at genesis")
+
+	deposit, _ := new(big.Int).SetString(proposalDeposit, 10)
+	keys := []uint8{paramBlockMaxGas, paramTimeoutPropose, paramTimeoutCommit}
+	values := []*big.Int{big.NewInt(15000000), big.NewInt(2500), big.NewInt(800)}
+	callParams(t, statedb, now, validator, deposit, "addProposal", keys, values)
+	callParams(t, statedb, now.Add(time.Minute), validator, big.NewInt(0), "addVote", big.NewInt(0), uint8(1))
+	passed := now.Add(votingPeriod + time.Hour)
+	callParams(t, statedb, passed, validator, big.NewInt(0), "confirmProposal", big.NewInt(0))
+
+	header.Time = passed
+	params, err = util.ApplyAndReturnConsensusParams(statedb, header, nil, kvm.Config{})
+	require.NoError(t, err)
+	require.EqualValues(t, 15000000, params.Block.MaxGas)
+	require.Equal(t, 2500*time.Millisecond, params.Timeout.Propose)
+	require.Equal(t, 800*time.Millisecond, params.Timeout.Commit)
+	require.Zero(t, params.Block.MaxBytes, "unset params are left zero")
+}
+
+func TestGovernConsensusParams(t *testing.T) {
+	statedb, validator, genesisTime := genesisState(t)
+	misc.ApplyParamsUpgradeContracts(statedb)
+	governConsensusParams(t, statedb, validator, genesisTime.Add(time.Hour))
+}
+
+func TestGovernConsensusParamsGalaxias(t *testing.T) {
+	statedb, validator, genesisTime := genesisState(t)
+	applyGalaxias(t, statedb, genesisTime)
+	misc.ApplyParamsUpgradeContracts(statedb)
+	governConsensusParams(t, statedb, validator, genesisTime.Add(time.Hour))
+}
+
+// The historical Params contracts bound the keys of a proposal to the staking
+// params, they are kept byte for byte since they are part of the state.
+func TestConsensusParamKeysBeforeUpgrade(t *testing.T) {
+	for _, galaxias := range []bool{false, true} {
+		statedb, validator, genesisTime := genesisState(t)
+		if galaxias {
+			applyGalaxias(t, statedb, genesisTime)
+		}
+		deposit, _ := new(big.Int).SetString(proposalDeposit, 10)
+		_, err := applyParams(t, statedb, genesisTime.Add(time.Hour), validator, deposit, "addProposal",
+			[]uint8{paramBlockMaxGas}, []*big.Int{big.NewInt(15000000)})
+		require.Error(t, err, "galaxias: %v", galaxias)
+	}
+}
+
+// The upgrade replaces the code of the Params contract only, the params and
+// the proposals in progress are kept.
+func TestParamsUpgradeKeepsState(t *testing.T) {
+	statedb, validator, genesisTime := genesisState(t)
+	applyGalaxias(t, statedb, genesisTime)
+	now := genesisTime.Add(time.Hour)
+	deposit, _ := new(big.Int).SetString(proposalDeposit, 10)
+	callParams(t, statedb, now, validator, deposit, "addProposal", []uint8{paramMaxProposers}, []*big.Int{big.NewInt(25)})
+	callParams(t, statedb, now.Add(time.Minute), validator, big.NewInt(0), "addVote", big.NewInt(0), uint8(1))
+
+	misc.ApplyParamsUpgradeContracts(statedb)
+	passed := now.Add(votingPeriod + time.Hour)
+	callParams(t, statedb, passed, validator, big.NewInt(0), "confirmProposal", big.NewInt(0))
+
+	getParam := func(key uint8) *big.Int {
+		paramsAbi, err := abi.JSON(strings.NewReader(configs.ParamsContract.ABI))
+		require.NoError(t, err)
+		var value *big.Int
+		require.NoError(t, paramsAbi.UnpackIntoInterface(&value, "getParam", callParams(t, statedb, passed, validator, big.NewInt(0), "getParam", key)))
+		return value
+	}
+	require.EqualValues(t, 25, getParam(paramMaxProposers).Int64())
+	require.Equal(t, deposit, getParam(paramDeposit), "the genesis params are kept")
+}
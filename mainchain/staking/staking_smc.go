@@ -25,6 +25,8 @@ type StakingSmcUtil struct {
 	ContractAddress common.Address
 	Bytecode        string
 	logger          log.Logger
+
+	paramsAbi *abi.ABI
 }
 
 type Validator struct {
@@ -65,13 +67,18 @@ type Delegator struct {
 func NewSmcStakingUtil() (*StakingSmcUtil, error) {
 	stakingSmcAbi := configs.GetContractABIByAddress(configs.DefaultStakingContractAddress)
 	bytecodeStaking := configs.GetContractByteCodeByAddress(configs.DefaultStakingContractAddress)
+	paramsAbi, err := abi.JSON(strings.NewReader(configs.ParamsContract.ABI))
+	if err != nil {
+		log.Error("Error reading abi", "err", err)
+		return nil, err
+	}
 	abi, err := abi.JSON(strings.NewReader(stakingSmcAbi))
 	if err != nil {
 		log.Error("Error reading abi", "err", err)
 		return nil, err
 	}
 
-	return &StakingSmcUtil{Abi: &abi, ContractAddress: common.HexToAddress(configs.DefaultStakingContractAddress), Bytecode: bytecodeStaking}, nil
+	return &StakingSmcUtil{Abi: &abi, ContractAddress: common.HexToAddress(configs.DefaultStakingContractAddress), Bytecode: bytecodeStaking, paramsAbi: &paramsAbi}, nil
 }
 
 //CreateValidator create validator
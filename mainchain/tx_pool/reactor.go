@@ -96,6 +96,7 @@ func (txR *Reactor) GetChannels() []*p2p.ChannelDescriptor {
 	return []*p2p.ChannelDescriptor{
 		{
 			ID:                  TxpoolChannel,
+			Name:                "txpool",
 			Priority:            5,
 			RecvMessageCapacity: DefaultTxPoolConfig.MaxTxsBatchSize,
 			RecvBufferCapacity:  DefaultTxPoolConfig.RecvBufferCapacity,
@@ -162,7 +163,7 @@ func (txR *Reactor) Receive(chID byte, src p2p.Peer, msgBytes []byte) {
 	msg, err := decodeMsg(msgBytes)
 	if err != nil {
 		txR.Logger.Error("Error decoding message", "src", src, "chId", chID, "msg", msg, "err", err, "bytes", msgBytes)
-		txR.Switch.StopPeerForError(src, err)
+		txR.Switch.MarkPeerAsBad(src, err)
 		return
 	}
 
@@ -198,7 +199,7 @@ func (txR *Reactor) Receive(chID byte, src p2p.Peer, msgBytes []byte) {
 	case RequestPooledTransactionHashes:
 		txR.handleRequestPooledTransactions(src, m)
 	default:
-		txR.Switch.StopPeerForError(src, err)
+		txR.Switch.MarkPeerAsBad(src, err)
 		return
 	}
 
@@ -946,6 +946,14 @@ func (pool *TxPool) Get(hash common.Hash) *types.Transaction {
 	return pool.all.Get(hash)
 }
 
+// Range calls f on each transaction of the pool, pending and queued, until f
+// returns false.
+func (pool *TxPool) Range(f func(tx *types.Transaction) bool) {
+	pool.all.Range(func(_ common.Hash, tx *types.Transaction, _ bool) bool {
+		return f(tx)
+	}, true, true)
+}
+
 // Has returns an indicator whether txpool has a transaction cached with the
 // given hash.
 func (pool *TxPool) Has(hash common.Hash) bool {
@@ -54,6 +54,7 @@ type Peer struct {
 	IsOutbound       bool                 `json:"is_outbound"`
 	ConnectionStatus p2p.ConnectionStatus `json:"connection_status"`
 	RemoteIP         string               `json:"remote_ip"`
+	TrustScore       *int                 `json:"trust_score,omitempty"`
 }
 
 // Peers retrieves all the information we know about each individual peer at the
@@ -66,12 +67,16 @@ func (api *publicAdminAPI) Peers() ([]Peer, error) {
 		if !ok {
 			return nil, fmt.Errorf("peer.NodeInfo() is not DefaultNodeInfo")
 		}
-		peers = append(peers, Peer{
+		p := Peer{
 			NodeInfo:         nodeInfo,
 			IsOutbound:       peer.IsOutbound(),
 			ConnectionStatus: peer.Status(),
 			RemoteIP:         peer.RemoteIP().String(),
-		})
+		}
+		if score, ok := api.node.sw.PeerTrustScore(peer.ID()); ok {
+			p.TrustScore = &score
+		}
+		peers = append(peers, p)
 	}
 	return peers, nil
 }
@@ -82,3 +87,9 @@ func (api *publicAdminAPI) NodeInfo() (p2p.NodeInfo, error) {
 	nodeInfo := api.node.sw.NodeInfo()
 	return nodeInfo, nil
 }
+
+// TopTalkers retrieves the current traffic with the n peers with the highest
+// send and receive rate, or with all peers if n is not positive.
+func (api *publicAdminAPI) TopTalkers(n int) []p2p.PeerTraffic {
+	return api.node.sw.TopTalkers(n)
+}
@@ -47,6 +47,8 @@ var DefaultConfig = Config{
 		"tx",
 		"account",
 		"debug",
+		"consensus",
+		"evidence",
 		"net",
 		"eth",
 		"txpool",
@@ -63,6 +65,8 @@ var DefaultConfig = Config{
 		"tx",
 		"account",
 		"debug",
+		"consensus",
+		"evidence",
 		"net",
 		"eth",
 		"txpool",
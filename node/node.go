@@ -40,9 +40,11 @@ import (
 	"github.com/kardiachain/go-kardia/lib/log"
 	"github.com/kardiachain/go-kardia/lib/p2p"
 	"github.com/kardiachain/go-kardia/lib/p2p/pex"
+	"github.com/kardiachain/go-kardia/lib/p2p/trust"
 	bs "github.com/kardiachain/go-kardia/lib/service"
 	"github.com/kardiachain/go-kardia/mainchain/tx_pool"
 	"github.com/kardiachain/go-kardia/rpc"
+	"github.com/kardiachain/go-kardia/statesync"
 	"github.com/kardiachain/go-kardia/types"
 	"github.com/kardiachain/go-kardia/types/evidence"
 )
@@ -69,6 +71,7 @@ type Node struct {
 	sw         *p2p.Switch   // p2p connections
 	blockStore types.StoreDB
 	stateDB    cstate.Store
+	trustDB    types.StoreDB // peer trust scores and bans
 	nodeKey    *p2p.NodeKey
 	transport  *p2p.MultiplexTransport
 	addrBook   pex.AddrBook // known peers
@@ -147,10 +150,18 @@ func New(conf *Config) (*Node, error) {
 	if err != nil {
 		return nil, err
 	}
-	transport, peerFilters := createTransport(node.config, nodeInfo, node.nodeKey)
+	transport, peerFilters, err := createTransport(node.config, nodeInfo, node.nodeKey)
+	if err != nil {
+		return nil, err
+	}
 	node.transport = transport
+	trustDB, err := node.OpenDatabase("trusthistory", 0, 0, "trusthistory")
+	if err != nil {
+		return nil, err
+	}
+	node.trustDB = trustDB
 	node.sw = createSwitch(
-		node.config, node.transport, peerFilters, nodeInfo, node.nodeKey, node.logger,
+		node.config, node.transport, peerFilters, nodeInfo, node.nodeKey, trustDB, node.logger,
 	)
 
 	// Configure RPC servers.
@@ -377,6 +388,10 @@ func (n *Node) OnStop() {
 		n.Logger.Error("Error closing switch", "err", err)
 	}
 
+	if err := n.trustDB.DB().Close(); err != nil {
+		n.Logger.Error("Error closing peer trust database", "err", err)
+	}
+
 	if err := n.transport.Close(); err != nil {
 		n.Logger.Error("Error closing transport", "err", err)
 	}
@@ -528,6 +543,7 @@ func createTransport(
 ) (
 	*p2p.MultiplexTransport,
 	[]p2p.PeerFilterFunc,
+	error,
 ) {
 	var (
 		mConnConfig = p2p.MConnConfig(config.P2P)
@@ -539,7 +555,14 @@ func createTransport(
 	max := config.P2P.MaxNumInboundPeers + len(config.P2P.UnconditionalPeerIDs)
 	p2p.MultiplexTransportMaxIncomingConnections(max)(transport)
 
-	return transport, peerFilters
+	// Limit the inbound connections per source before any handshake.
+	limits, err := p2p.InboundLimitsFromConfig(config.P2P)
+	if err != nil {
+		return nil, nil, err
+	}
+	p2p.MultiplexTransportInboundLimits(limits)(transport)
+
+	return transport, peerFilters, nil
 }
 
 // splitAndTrimEmpty slices s into all subslices separated by sep and returns a
@@ -594,6 +617,10 @@ func makeNodeInfo(
 	if config.FastSync != nil {
 		nodeInfo.Channels = append(nodeInfo.Channels, blockchain.BlockchainChannel)
 	}
+	nodeInfo.Channels = append(nodeInfo.Channels, statesync.SnapshotChannel, statesync.StateChannel)
+	if config.P2P.CompactBlocks {
+		nodeInfo.Capabilities = append(nodeInfo.Capabilities, cs.CompactBlocksCapability)
+	}
 
 	lAddr := config.P2P.ExternalAddress
 
@@ -612,11 +639,13 @@ func createSwitch(config *Config,
 	peerFilters []p2p.PeerFilterFunc,
 	nodeInfo p2p.NodeInfo,
 	nodeKey *p2p.NodeKey,
+	trustDB types.StoreDB,
 	p2pLogger log.Logger) *p2p.Switch {
 
 	sw := p2p.NewSwitch(
 		config.P2P,
 		transport,
+		p2p.SwitchTrustMetricStore(trust.NewTrustMetricStore(trustDB.DB(), trust.DefaultConfig())),
 	)
 	sw.SetLogger(p2pLogger)
 
@@ -648,6 +677,7 @@ func createAddrBookAndSetOnSwitch(config *Config, sw *p2p.Switch,
 		addrBook.AddOurAddress(addr)
 	}
 
+	addrBook.SetPeerScorer(sw)
 	sw.SetAddrBook(addrBook)
 
 	return addrBook, nil
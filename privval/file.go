@@ -0,0 +1,341 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package privval
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/crypto"
+	"github.com/kardiachain/go-kardia/lib/tempfile"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// StateFileName is the name of the last sign state file in the data
+// directory of the node.
+const StateFileName = "priv_validator_state.json"
+
+// The steps of a height and round, in the order they are signed.
+const (
+	stepNone      int8 = 0 // Used to distinguish the initial state
+	stepPropose   int8 = 1
+	stepPrevote   int8 = 2
+	stepPrecommit int8 = 3
+)
+
+// ErrConflictingData is returned when asked to sign a message different from
+// the one already signed at the same height, round and step.
+var ErrConflictingData = errors.New("conflicting data")
+
+// voteToStep is a helper function to map a vote type to its step.
+func voteToStep(msgType kproto.SignedMsgType) int8 {
+	switch msgType {
+	case kproto.PrevoteType:
+		return stepPrevote
+	case kproto.PrecommitType:
+		return stepPrecommit
+	case kproto.ProposalType:
+		return stepPropose
+	default:
+		panic(fmt.Sprintf("Unknown vote type: %v", msgType))
+	}
+}
+
+//-------------------------------------------------------------------------------
+
+// FilePVLastSignState stores the mutable part of PrivValidator: the height,
+// round and step of the last message signed, with the hash of its sign bytes,
+// its timestamp and its signature.
+type FilePVLastSignState struct {
+	Height        uint64      `json:"height"`
+	Round         uint32      `json:"round"`
+	Step          int8        `json:"step"`
+	SignBytesHash common.Hash `json:"sign_bytes_hash,omitempty"`
+	Timestamp     time.Time   `json:"timestamp"`
+	Signature     []byte      `json:"signature,omitempty"`
+
+	filePath string
+}
+
+// CheckHRS checks the given height, round, step (HRS) against that of the
+// FilePVLastSignState. It returns an error if the arguments constitute a
+// regression, or if they match but the sign bytes hash is empty. The
+// returned boolean indicates whether the last signature should be reused -
+// it returns true if the HRS matches the arguments and the sign bytes hash
+// is not empty (indicating we have already signed for this HRS, and can
+// reuse the signature).
+func (lss *FilePVLastSignState) CheckHRS(height uint64, round uint32, step int8) (bool, error) {
+	if lss.Height > height {
+		return false, fmt.Errorf("height regression. Got %v, last height %v", height, lss.Height)
+	}
+
+	if lss.Height == height {
+		if lss.Round > round {
+			return false, fmt.Errorf("round regression at height %v. Got %v, last round %v", height, round, lss.Round)
+		}
+
+		if lss.Round == round {
+			if lss.Step > step {
+				return false, fmt.Errorf(
+					"step regression at height %v round %v. Got %v, last step %v",
+					height,
+					round,
+					step,
+					lss.Step,
+				)
+			} else if lss.Step == step {
+				if lss.SignBytesHash.IsZero() {
+					return false, errors.New("no SignBytesHash found")
+				}
+				if lss.Signature == nil {
+					panic("pv: Signature is nil but SignBytesHash is not!")
+				}
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// CheckWAL returns an error if the state is behind a message of msgType the
+// validator signed at height and round, as found in the consensus WAL. The
+// state file was then restored from a backup, and signing again could
+// double sign. A state that never signed, as created for a validator
+// without state file, is instead moved up to the WAL message and saved.
+func (lss *FilePVLastSignState) CheckWAL(height uint64, round uint32, msgType kproto.SignedMsgType) error {
+	step := voteToStep(msgType)
+	if lss.Height == 0 && lss.SignBytesHash.IsZero() {
+		// Without the sign bytes, the message itself won't be signed again.
+		lss.Height, lss.Round, lss.Step = height, round, step
+		return lss.Save()
+	}
+	if lss.Height < height ||
+		(lss.Height == height && (lss.Round < round || (lss.Round == round && lss.Step < step))) {
+		return fmt.Errorf("last sign state %d/%d/%d of %s is behind the WAL at %d/%d/%d",
+			lss.Height, lss.Round, lss.Step, lss.filePath, height, round, step)
+	}
+	return nil
+}
+
+// Save persists the FilePvLastSignState to its filePath.
+func (lss *FilePVLastSignState) Save() error {
+	outFile := lss.filePath
+	if outFile == "" {
+		return errors.New("cannot save FilePVLastSignState: filePath not set")
+	}
+	jsonBytes, err := json.MarshalIndent(lss, "", "  ")
+	if err != nil {
+		return err
+	}
+	return tempfile.WriteFileAtomic(outFile, jsonBytes, 0600)
+}
+
+//-------------------------------------------------------------------------------
+
+// FilePV implements PrivValidator with a key held in memory and the last
+// sign state persisted to a file. It refuses to sign a message conflicting
+// with one it already signed, so the validator never double signs, even
+// across restarts.
+type FilePV struct {
+	privKey       *ecdsa.PrivateKey
+	LastSignState FilePVLastSignState
+}
+
+var _ types.PrivValidator = (*FilePV)(nil)
+
+// LoadFilePV returns a FilePV signing with privKey, whose last sign state is
+// read from stateFilePath, or starts empty if the file doesn't exist.
+func LoadFilePV(privKey *ecdsa.PrivateKey, stateFilePath string) (*FilePV, error) {
+	pv := &FilePV{
+		privKey:       privKey,
+		LastSignState: FilePVLastSignState{filePath: stateFilePath},
+	}
+	stateJSONBytes, err := ioutil.ReadFile(stateFilePath)
+	if os.IsNotExist(err) {
+		return pv, pv.LastSignState.Save()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(stateJSONBytes, &pv.LastSignState); err != nil {
+		return nil, fmt.Errorf("error reading PrivValidator state from %v: %w", stateFilePath, err)
+	}
+	pv.LastSignState.filePath = stateFilePath
+	return pv, nil
+}
+
+// GetAddress returns the address of the validator.
+func (pv *FilePV) GetAddress() common.Address {
+	return crypto.PubkeyToAddress(pv.privKey.PublicKey)
+}
+
+// GetPubKey returns the public key of the validator.
+func (pv *FilePV) GetPubKey() ecdsa.PublicKey {
+	return pv.privKey.PublicKey
+}
+
+// SignVote signs a canonical representation of the vote, along with the
+// chainID. Implements PrivValidator.
+func (pv *FilePV) SignVote(chainID string, vote *kproto.Vote) error {
+	if err := pv.signVote(chainID, vote); err != nil {
+		return fmt.Errorf("error signing vote: %w", err)
+	}
+	return nil
+}
+
+// SignProposal signs a canonical representation of the proposal, along with
+// the chainID. Implements PrivValidator.
+func (pv *FilePV) SignProposal(chainID string, proposal *kproto.Proposal) error {
+	if err := pv.signProposal(chainID, proposal); err != nil {
+		return fmt.Errorf("error signing proposal: %w", err)
+	}
+	return nil
+}
+
+// ExtractIntoValidator returns the validator of the key.
+func (pv *FilePV) ExtractIntoValidator(votingPower int64) *types.Validator {
+	return &types.Validator{
+		Address:     pv.GetAddress(),
+		VotingPower: votingPower,
+	}
+}
+
+// String returns a string representation of the FilePV.
+func (pv *FilePV) String() string {
+	return fmt.Sprintf(
+		"PrivValidator{%v LH:%v, LR:%v, LS:%v}",
+		pv.GetAddress(),
+		pv.LastSignState.Height,
+		pv.LastSignState.Round,
+		pv.LastSignState.Step,
+	)
+}
+
+//------------------------------------------------------------------------------------
+
+// signVote checks if the vote is good to sign and sets the vote signature.
+// It may need to set the timestamp as well if the vote is otherwise the same
+// as a previously signed vote (ie. we crashed after signing but before the
+// vote hit the WAL).
+func (pv *FilePV) signVote(chainID string, vote *kproto.Vote) error {
+	height, round, step := vote.Height, vote.Round, voteToStep(vote.Type)
+
+	lss := pv.LastSignState
+
+	sameHRS, err := lss.CheckHRS(height, round, step)
+	if err != nil {
+		return err
+	}
+
+	// We might crash before writing to the wal,
+	// causing us to try to re-sign for the same HRS.
+	// If signbytes are the same, use the last signature.
+	// If they only differ by timestamp, use last timestamp and signature
+	// Otherwise, return error
+	if sameHRS {
+		timestamp := vote.Timestamp
+		vote.Timestamp = lss.Timestamp
+		if lss.SignBytesHash != crypto.Keccak256Hash(types.VoteSignBytes(chainID, vote)) {
+			vote.Timestamp = timestamp
+			return ErrConflictingData
+		}
+		vote.Signature = lss.Signature
+		return nil
+	}
+
+	// It passed the checks. Sign the vote
+	signBytes := types.VoteSignBytes(chainID, vote)
+	sig, err := crypto.Sign(crypto.Keccak256(signBytes), pv.privKey)
+	if err != nil {
+		return err
+	}
+	if err := pv.saveSigned(height, round, step, signBytes, vote.Timestamp, sig); err != nil {
+		return err
+	}
+	vote.Signature = sig
+	return nil
+}
+
+// signProposal checks if the proposal is good to sign and sets the proposal
+// signature. It may need to set the timestamp as well if the proposal is
+// otherwise the same as a previously signed proposal ie. we crashed after
+// signing but before the proposal hit the WAL).
+func (pv *FilePV) signProposal(chainID string, proposal *kproto.Proposal) error {
+	height, round, step := proposal.Height, proposal.Round, stepPropose
+
+	lss := pv.LastSignState
+
+	sameHRS, err := lss.CheckHRS(height, round, step)
+	if err != nil {
+		return err
+	}
+
+	// We might crash before writing to the wal,
+	// causing us to try to re-sign for the same HRS.
+	// If signbytes are the same, use the last signature.
+	// If they only differ by timestamp, use last timestamp and signature
+	// Otherwise, return error
+	if sameHRS {
+		timestamp := proposal.Timestamp
+		proposal.Timestamp = lss.Timestamp
+		if lss.SignBytesHash != crypto.Keccak256Hash(types.ProposalSignBytes(chainID, proposal)) {
+			proposal.Timestamp = timestamp
+			return ErrConflictingData
+		}
+		proposal.Signature = lss.Signature
+		return nil
+	}
+
+	// It passed the checks. Sign the proposal
+	signBytes := types.ProposalSignBytes(chainID, proposal)
+	sig, err := crypto.Sign(crypto.Keccak256(signBytes), pv.privKey)
+	if err != nil {
+		return err
+	}
+	if err := pv.saveSigned(height, round, step, signBytes, proposal.Timestamp, sig); err != nil {
+		return err
+	}
+	proposal.Signature = sig
+	return nil
+}
+
+// saveSigned persists the height, round and step of a signed message before
+// the signature is handed out.
+func (pv *FilePV) saveSigned(height uint64, round uint32, step int8,
+	signBytes []byte, timestamp time.Time, sig []byte) error {
+	lss := pv.LastSignState
+	lss.Height = height
+	lss.Round = round
+	lss.Step = step
+	lss.SignBytesHash = crypto.Keccak256Hash(signBytes)
+	lss.Timestamp = timestamp
+	lss.Signature = sig
+	if err := lss.Save(); err != nil {
+		return err
+	}
+	pv.LastSignState = lss
+	return nil
+}
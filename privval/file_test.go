@@ -0,0 +1,110 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package privval
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/crypto"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+)
+
+func TestFilePVSignVote(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	stateFile := filepath.Join(t.TempDir(), StateFileName)
+	pv, err := LoadFilePV(key, stateFile)
+	require.NoError(t, err)
+
+	vote := testVote()
+	require.NoError(t, pv.SignVote(testChainID, vote))
+	sig := vote.Signature
+
+	// Signing the same vote again returns the same signature.
+	same := testVote()
+	require.NoError(t, pv.SignVote(testChainID, same))
+	assert.Equal(t, sig, same.Signature)
+
+	// As does a vote differing only by its timestamp, which is reset.
+	later := testVote()
+	later.Timestamp = later.Timestamp.Add(time.Second)
+	require.NoError(t, pv.SignVote(testChainID, later))
+	assert.Equal(t, sig, later.Signature)
+	assert.Equal(t, vote.Timestamp, later.Timestamp)
+
+	// Another block at the same height, round and step is refused.
+	conflicting := testVote()
+	conflicting.BlockID.Hash = common.BytesToHash([]byte("other block")).Bytes()
+	assert.ErrorIs(t, pv.SignVote(testChainID, conflicting), ErrConflictingData)
+	assert.Nil(t, conflicting.Signature)
+
+	// So are the earlier steps.
+	prevote := testVote()
+	prevote.Type = kproto.PrevoteType
+	assert.Error(t, pv.SignVote(testChainID, prevote))
+	proposal := &kproto.Proposal{Type: kproto.ProposalType, Height: vote.Height, Round: vote.Round}
+	assert.Error(t, pv.SignProposal(testChainID, proposal))
+
+	// The state survives a restart.
+	pv, err = LoadFilePV(key, stateFile)
+	require.NoError(t, err)
+	assert.ErrorIs(t, pv.SignVote(testChainID, conflicting), ErrConflictingData)
+	next := testVote()
+	next.Round++
+	next.BlockID.Hash = conflicting.BlockID.Hash
+	assert.NoError(t, pv.SignVote(testChainID, next))
+}
+
+func TestFilePVSignProposal(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	pv, err := LoadFilePV(key, filepath.Join(t.TempDir(), StateFileName))
+	require.NoError(t, err)
+
+	newProposal := func() *kproto.Proposal {
+		return &kproto.Proposal{Type: kproto.ProposalType, Height: 5, Round: 2, PolRound: 1, Timestamp: time.Unix(1600000000, 0).UTC()}
+	}
+	proposal := newProposal()
+	require.NoError(t, pv.SignProposal(testChainID, proposal))
+
+	later := newProposal()
+	later.Timestamp = later.Timestamp.Add(time.Second)
+	require.NoError(t, pv.SignProposal(testChainID, later))
+	assert.Equal(t, proposal, later)
+
+	conflicting := newProposal()
+	conflicting.PolRound = 0
+	assert.ErrorIs(t, pv.SignProposal(testChainID, conflicting), ErrConflictingData)
+}
+
+func TestFilePVLastSignStateCheckWAL(t *testing.T) {
+	lss := FilePVLastSignState{Height: 10, Round: 1, Step: stepPrevote}
+	assert.NoError(t, lss.CheckWAL(9, 5, kproto.PrecommitType))
+	assert.NoError(t, lss.CheckWAL(10, 1, kproto.PrevoteType))
+	assert.NoError(t, lss.CheckWAL(10, 1, kproto.ProposalType))
+	assert.Error(t, lss.CheckWAL(10, 1, kproto.PrecommitType))
+	assert.Error(t, lss.CheckWAL(10, 2, kproto.ProposalType))
+	assert.Error(t, lss.CheckWAL(11, 0, kproto.ProposalType))
+}
@@ -0,0 +1,187 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package privval
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"time"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/crypto"
+	"github.com/kardiachain/go-kardia/lib/crypto/encoding"
+	privvalproto "github.com/kardiachain/go-kardia/proto/kardiachain/privval"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// SignerClient implements PrivValidator.
+// Handles remote validator connections that provide signing services
+type SignerClient struct {
+	endpoint *SignerListenerEndpoint
+	chainID  string
+	pubKey   ecdsa.PublicKey
+}
+
+var _ types.PrivValidator = (*SignerClient)(nil)
+
+// NewSignerClient returns an instance of SignerClient.
+// It starts the endpoint (if not already started) and blocks until the
+// signer connects, then fetches its public key, as the PrivValidator
+// interface cannot fail to return it.
+func NewSignerClient(endpoint *SignerListenerEndpoint, chainID string) (*SignerClient, error) {
+	if !endpoint.IsRunning() {
+		if err := endpoint.Start(); err != nil {
+			return nil, fmt.Errorf("failed to start listener endpoint: %w", err)
+		}
+	}
+	for {
+		err := endpoint.WaitForConnection(endpoint.timeoutAccept)
+		if err == nil {
+			break
+		}
+		if !IsConnTimeout(err) {
+			endpoint.Stop() //nolint:errcheck // the connection error matters
+			return nil, err
+		}
+		endpoint.Logger.Info("Waiting for the remote signer to connect")
+	}
+
+	sc := &SignerClient{endpoint: endpoint, chainID: chainID}
+	pubKey, err := sc.requestPubKey()
+	if err != nil {
+		endpoint.Stop() //nolint:errcheck // the request error matters
+		return nil, err
+	}
+	sc.pubKey = pubKey
+	return sc, nil
+}
+
+// Close closes the underlying connection
+func (sc *SignerClient) Close() error {
+	return sc.endpoint.Stop()
+}
+
+// IsConnected indicates whether the signer is connected to a remote signing service
+func (sc *SignerClient) IsConnected() bool {
+	return sc.endpoint.IsConnected()
+}
+
+// WaitForConnection waits maxWait for a connection or returns a timeout error
+func (sc *SignerClient) WaitForConnection(maxWait time.Duration) error {
+	return sc.endpoint.WaitForConnection(maxWait)
+}
+
+//--------------------------------------------------------
+// Implement PrivValidator
+
+// Ping sends a ping request to the remote signer
+func (sc *SignerClient) Ping() error {
+	response, err := sc.endpoint.SendRequest(mustWrapMsg(&privvalproto.PingRequest{}))
+	if err != nil {
+		return err
+	}
+	if response.GetPingResponse() == nil {
+		return ErrUnexpectedResponse
+	}
+	return nil
+}
+
+// GetPubKey returns the public key of the remote signer, fetched when the
+// client was created.
+func (sc *SignerClient) GetPubKey() ecdsa.PublicKey {
+	return sc.pubKey
+}
+
+// GetAddress returns the address of the remote signer.
+func (sc *SignerClient) GetAddress() common.Address {
+	return crypto.PubkeyToAddress(sc.pubKey)
+}
+
+// SignVote requests a remote signer to sign a vote
+func (sc *SignerClient) SignVote(chainID string, vote *kproto.Vote) error {
+	response, err := sc.endpoint.SendRequest(mustWrapMsg(&privvalproto.SignVoteRequest{Vote: vote, ChainID: chainID}))
+	if err != nil {
+		return err
+	}
+
+	resp := response.GetSignedVoteResponse()
+	if resp == nil {
+		return ErrUnexpectedResponse
+	}
+	if resp.Error != nil {
+		return &RemoteSignerError{Code: int(resp.Error.Code), Description: resp.Error.Description}
+	}
+
+	*vote = resp.Vote
+
+	return nil
+}
+
+// SignProposal requests a remote signer to sign a proposal
+func (sc *SignerClient) SignProposal(chainID string, proposal *kproto.Proposal) error {
+	response, err := sc.endpoint.SendRequest(mustWrapMsg(
+		&privvalproto.SignProposalRequest{Proposal: proposal, ChainID: chainID},
+	))
+	if err != nil {
+		return err
+	}
+
+	resp := response.GetSignedProposalResponse()
+	if resp == nil {
+		return ErrUnexpectedResponse
+	}
+	if resp.Error != nil {
+		return &RemoteSignerError{Code: int(resp.Error.Code), Description: resp.Error.Description}
+	}
+
+	*proposal = resp.Proposal
+
+	return nil
+}
+
+// ExtractIntoValidator returns the validator of the remote signer.
+func (sc *SignerClient) ExtractIntoValidator(votingPower int64) *types.Validator {
+	return &types.Validator{
+		Address:     sc.GetAddress(),
+		VotingPower: votingPower,
+	}
+}
+
+func (sc *SignerClient) requestPubKey() (ecdsa.PublicKey, error) {
+	response, err := sc.endpoint.SendRequest(mustWrapMsg(&privvalproto.PubKeyRequest{ChainID: sc.chainID}))
+	if err != nil {
+		return ecdsa.PublicKey{}, fmt.Errorf("send: %w", err)
+	}
+
+	resp := response.GetPubKeyResponse()
+	if resp == nil {
+		return ecdsa.PublicKey{}, ErrUnexpectedResponse
+	}
+	if resp.Error != nil {
+		return ecdsa.PublicKey{}, &RemoteSignerError{Code: int(resp.Error.Code), Description: resp.Error.Description}
+	}
+
+	pk, err := encoding.PubKeyFromProto(resp.PubKey)
+	if err != nil {
+		return ecdsa.PublicKey{}, err
+	}
+
+	return *pk, nil
+}
@@ -0,0 +1,114 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package privval
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/crypto"
+	"github.com/kardiachain/go-kardia/lib/log"
+	knet "github.com/kardiachain/go-kardia/lib/net"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+const testChainID = "kai-test"
+
+// newTestSigner connects a signer server signing with key to a client
+// listening on addr.
+func newTestSigner(t *testing.T, addr string, key *ecdsa.PrivateKey) *SignerClient {
+	nodeKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	connKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	listener, err := NewSignerListener(addr, nodeKey, log.New())
+	require.NoError(t, err)
+	dialer, err := NewSignerDialer(addr, connKey, log.New(), SignerDialerEndpointRetryWaitInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	server := NewSignerServer(dialer, testChainID, types.NewDefaultPrivValidator(key))
+	require.NoError(t, server.Start())
+	t.Cleanup(func() { server.Stop() })
+
+	client, err := NewSignerClient(listener, testChainID)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func testVote() *kproto.Vote {
+	return &kproto.Vote{
+		Type:      kproto.PrecommitType,
+		Height:    10,
+		Round:     1,
+		BlockID:   kproto.BlockID{Hash: common.BytesToHash([]byte("block")).Bytes()},
+		Timestamp: time.Unix(1600000000, 0).UTC(),
+	}
+}
+
+func TestSignerClient(t *testing.T) {
+	port, err := knet.GetFreePort()
+	require.NoError(t, err)
+	for name, addr := range map[string]string{
+		"unix": fmt.Sprintf("unix://%s", filepath.Join(t.TempDir(), "privval.sock")),
+		"tcp":  fmt.Sprintf("tcp://127.0.0.1:%d", port),
+	} {
+		addr := addr
+		t.Run(name, func(t *testing.T) {
+			key, err := crypto.GenerateKey()
+			require.NoError(t, err)
+			local := types.NewDefaultPrivValidator(key)
+			client := newTestSigner(t, addr, key)
+
+			assert.Equal(t, local.GetAddress(), client.GetAddress())
+			assert.Equal(t, local.GetPubKey(), client.GetPubKey())
+			require.NoError(t, client.Ping())
+
+			want, got := testVote(), testVote()
+			require.NoError(t, local.SignVote(testChainID, want))
+			require.NoError(t, client.SignVote(testChainID, got))
+			assert.Equal(t, want, got)
+
+			wantProposal := &kproto.Proposal{Type: kproto.ProposalType, Height: 10, Round: 1, Timestamp: time.Unix(1600000000, 0).UTC()}
+			gotProposal := *wantProposal
+			require.NoError(t, local.SignProposal(testChainID, wantProposal))
+			require.NoError(t, client.SignProposal(testChainID, &gotProposal))
+			assert.Equal(t, wantProposal, &gotProposal)
+
+			// The signer refuses to sign for another chain.
+			err = client.SignVote("other-chain", testVote())
+			assert.IsType(t, &RemoteSignerError{}, err)
+		})
+	}
+}
+
+func TestNewSignerListenerProtocol(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	_, err = NewSignerListener("udp://127.0.0.1:0", key, log.New())
+	assert.Error(t, err)
+}
@@ -0,0 +1,168 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package privval
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/kardiachain/go-kardia/lib/protoio"
+	"github.com/kardiachain/go-kardia/lib/service"
+	privvalproto "github.com/kardiachain/go-kardia/proto/kardiachain/privval"
+)
+
+const (
+	defaultTimeoutReadWriteSeconds = 5
+	// A signed vote or proposal, with the vote or proposal it signs.
+	maxRemoteSignerMsgSize = 1024 * 10
+)
+
+type signerEndpoint struct {
+	service.BaseService
+
+	connMtx sync.Mutex
+	conn    net.Conn
+
+	timeoutReadWrite time.Duration
+}
+
+// Close closes the underlying net.Conn.
+func (se *signerEndpoint) Close() error {
+	se.DropConnection()
+	return nil
+}
+
+// IsConnected indicates if there is an active connection
+func (se *signerEndpoint) IsConnected() bool {
+	se.connMtx.Lock()
+	defer se.connMtx.Unlock()
+	return se.isConnected()
+}
+
+// GetAvailableConnection takes the connection waiting in
+// connectionAvailableCh, if any.
+func (se *signerEndpoint) GetAvailableConnection(connectionAvailableCh chan net.Conn) bool {
+	se.connMtx.Lock()
+	defer se.connMtx.Unlock()
+
+	// Is there a connection ready?
+	select {
+	case se.conn = <-connectionAvailableCh:
+		return true
+	default:
+	}
+	return false
+}
+
+// WaitConnection waits up to maxWait for a connection on
+// connectionAvailableCh.
+func (se *signerEndpoint) WaitConnection(connectionAvailableCh chan net.Conn, maxWait time.Duration) error {
+	se.connMtx.Lock()
+	defer se.connMtx.Unlock()
+
+	select {
+	case se.conn = <-connectionAvailableCh:
+	case <-time.After(maxWait):
+		return ErrConnectionTimeout
+	}
+
+	return nil
+}
+
+// SetConnection replaces the current connection object
+func (se *signerEndpoint) SetConnection(newConnection net.Conn) {
+	se.connMtx.Lock()
+	defer se.connMtx.Unlock()
+	se.conn = newConnection
+}
+
+// DropConnection closes the current connection, if any.
+func (se *signerEndpoint) DropConnection() {
+	se.connMtx.Lock()
+	defer se.connMtx.Unlock()
+	se.dropConnection()
+}
+
+// ReadMessage reads a message from the endpoint
+func (se *signerEndpoint) ReadMessage() (msg privvalproto.Message, err error) {
+	se.connMtx.Lock()
+	defer se.connMtx.Unlock()
+
+	if !se.isConnected() {
+		return msg, fmt.Errorf("endpoint is not connected: %w", ErrNoConnection)
+	}
+	// Reset read deadline
+	deadline := time.Now().Add(se.timeoutReadWrite)
+
+	err = se.conn.SetReadDeadline(deadline)
+	if err != nil {
+		return
+	}
+	protoReader := protoio.NewDelimitedReader(se.conn, maxRemoteSignerMsgSize)
+	err = protoReader.ReadMsg(&msg)
+	if _, ok := err.(timeoutError); ok {
+		err = fmt.Errorf("%v: %w", err, ErrReadTimeout)
+		se.Logger.Debug("Dropping signer connection due to read timeout", "err", err)
+		se.dropConnection()
+	}
+
+	return
+}
+
+// WriteMessage writes a message to the endpoint
+func (se *signerEndpoint) WriteMessage(msg privvalproto.Message) (err error) {
+	se.connMtx.Lock()
+	defer se.connMtx.Unlock()
+
+	if !se.isConnected() {
+		return fmt.Errorf("endpoint is not connected: %w", ErrNoConnection)
+	}
+
+	protoWriter := protoio.NewDelimitedWriter(se.conn)
+
+	// Reset write deadline
+	deadline := time.Now().Add(se.timeoutReadWrite)
+	err = se.conn.SetWriteDeadline(deadline)
+	if err != nil {
+		return
+	}
+
+	_, err = protoWriter.WriteMsg(&msg)
+	if _, ok := err.(timeoutError); ok {
+		err = fmt.Errorf("%v: %w", err, ErrWriteTimeout)
+		se.dropConnection()
+	}
+
+	return
+}
+
+func (se *signerEndpoint) isConnected() bool {
+	return se.conn != nil
+}
+
+func (se *signerEndpoint) dropConnection() {
+	if se.conn != nil {
+		if err := se.conn.Close(); err != nil {
+			se.Logger.Error("signerEndpoint::dropConnection", "err", err)
+		}
+		se.conn = nil
+	}
+}
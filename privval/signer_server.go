@@ -0,0 +1,206 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package privval
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/kardiachain/go-kardia/lib/crypto/encoding"
+	"github.com/kardiachain/go-kardia/lib/service"
+	privvalproto "github.com/kardiachain/go-kardia/proto/kardiachain/privval"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// ValidationRequestHandlerFunc handles different remoteSigner requests
+type ValidationRequestHandlerFunc func(
+	privVal types.PrivValidator,
+	requestMessage privvalproto.Message,
+	chainID string) (privvalproto.Message, error)
+
+// SignerServer serves the signature requests of a node with its privVal,
+// over the connection of its endpoint.
+type SignerServer struct {
+	service.BaseService
+
+	endpoint *SignerDialerEndpoint
+	chainID  string
+	privVal  types.PrivValidator
+
+	handlerMtx               sync.Mutex
+	validationRequestHandler ValidationRequestHandlerFunc
+}
+
+// NewSignerServer returns a SignerServer signing the requests for chainID
+// received over endpoint with privVal.
+func NewSignerServer(endpoint *SignerDialerEndpoint, chainID string, privVal types.PrivValidator) *SignerServer {
+	ss := &SignerServer{
+		endpoint:                 endpoint,
+		chainID:                  chainID,
+		privVal:                  privVal,
+		validationRequestHandler: DefaultValidationRequestHandler,
+	}
+
+	ss.BaseService = *service.NewBaseService(endpoint.Logger, "SignerServer", ss)
+
+	return ss
+}
+
+// OnStart implements service.Service.
+func (ss *SignerServer) OnStart() error {
+	go ss.serviceLoop()
+	return nil
+}
+
+// OnStop implements service.Service.
+func (ss *SignerServer) OnStop() {
+	ss.endpoint.Logger.Debug("SignerServer: OnStop calling Close")
+	_ = ss.endpoint.Close()
+}
+
+// SetRequestHandler override the default function that is used to service requests
+func (ss *SignerServer) SetRequestHandler(validationRequestHandler ValidationRequestHandlerFunc) {
+	ss.handlerMtx.Lock()
+	defer ss.handlerMtx.Unlock()
+	ss.validationRequestHandler = validationRequestHandler
+}
+
+func (ss *SignerServer) servicePendingRequest() {
+	if !ss.IsRunning() {
+		return // Ignore error from closing.
+	}
+
+	req, err := ss.endpoint.ReadMessage()
+	if err != nil {
+		if err != io.EOF {
+			ss.Logger.Error("SignerServer: HandleMessage", "err", err)
+		}
+		return
+	}
+
+	var res privvalproto.Message
+	{
+		// limit the scope of the lock
+		ss.handlerMtx.Lock()
+		defer ss.handlerMtx.Unlock()
+		res, err = ss.validationRequestHandler(ss.privVal, req, ss.chainID)
+		if err != nil {
+			// only log the error; we'll reply with an error in res
+			ss.Logger.Error("SignerServer: handleMessage", "err", err)
+		}
+	}
+
+	err = ss.endpoint.WriteMessage(res)
+	if err != nil {
+		ss.Logger.Error("SignerServer: writeMessage", "err", err)
+	}
+}
+
+func (ss *SignerServer) serviceLoop() {
+	for {
+		select {
+		default:
+			err := ss.endpoint.ensureConnection()
+			if err != nil {
+				return
+			}
+			ss.servicePendingRequest()
+
+		case <-ss.Quit():
+			return
+		}
+	}
+}
+
+// DefaultValidationRequestHandler signs the votes and proposals of chainID
+// with privVal and answers the public key and ping requests.
+func DefaultValidationRequestHandler(
+	privVal types.PrivValidator,
+	req privvalproto.Message,
+	chainID string,
+) (privvalproto.Message, error) {
+	var (
+		res privvalproto.Message
+		err error
+	)
+
+	switch r := req.Sum.(type) {
+	case *privvalproto.Message_PubKeyRequest:
+		if r.PubKeyRequest.GetChainID() != chainID {
+			res = mustWrapMsg(&privvalproto.PubKeyResponse{
+				Error: &privvalproto.RemoteSignerError{
+					Code: 0, Description: "unable to provide pubkey"}})
+			return res, fmt.Errorf("want chainID: %s, got chainID: %s", chainID, r.PubKeyRequest.GetChainID())
+		}
+
+		pk, err := encoding.PubKeyToProto(privVal.GetPubKey())
+		if err != nil {
+			res = mustWrapMsg(&privvalproto.PubKeyResponse{
+				Error: &privvalproto.RemoteSignerError{Code: 0, Description: err.Error()}})
+		} else {
+			res = mustWrapMsg(&privvalproto.PubKeyResponse{PubKey: pk, Error: nil})
+		}
+
+	case *privvalproto.Message_SignVoteRequest:
+		if r.SignVoteRequest.GetChainID() != chainID || r.SignVoteRequest.Vote == nil {
+			res = mustWrapMsg(&privvalproto.SignedVoteResponse{
+				Vote: kproto.Vote{}, Error: &privvalproto.RemoteSignerError{
+					Code: 0, Description: "unable to sign vote"}})
+			return res, fmt.Errorf("want a vote of chainID: %s, got chainID: %s", chainID, r.SignVoteRequest.GetChainID())
+		}
+
+		vote := r.SignVoteRequest.Vote
+
+		err = privVal.SignVote(chainID, vote)
+		if err != nil {
+			res = mustWrapMsg(&privvalproto.SignedVoteResponse{
+				Vote: kproto.Vote{}, Error: &privvalproto.RemoteSignerError{Code: 0, Description: err.Error()}})
+		} else {
+			res = mustWrapMsg(&privvalproto.SignedVoteResponse{Vote: *vote, Error: nil})
+		}
+
+	case *privvalproto.Message_SignProposalRequest:
+		if r.SignProposalRequest.GetChainID() != chainID || r.SignProposalRequest.Proposal == nil {
+			res = mustWrapMsg(&privvalproto.SignedProposalResponse{
+				Proposal: kproto.Proposal{}, Error: &privvalproto.RemoteSignerError{
+					Code:        0,
+					Description: "unable to sign proposal"}})
+			return res, fmt.Errorf("want a proposal of chainID: %s, got chainID: %s", chainID, r.SignProposalRequest.GetChainID())
+		}
+
+		proposal := r.SignProposalRequest.Proposal
+
+		err = privVal.SignProposal(chainID, proposal)
+		if err != nil {
+			res = mustWrapMsg(&privvalproto.SignedProposalResponse{
+				Proposal: kproto.Proposal{}, Error: &privvalproto.RemoteSignerError{Code: 0, Description: err.Error()}})
+		} else {
+			res = mustWrapMsg(&privvalproto.SignedProposalResponse{Proposal: *proposal, Error: nil})
+		}
+	case *privvalproto.Message_PingRequest:
+		err, res = nil, mustWrapMsg(&privvalproto.PingResponse{})
+
+	default:
+		err = fmt.Errorf("unknown msg: %v", r)
+	}
+
+	return res, err
+}
@@ -0,0 +1,81 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package privval
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/kardiachain/go-kardia/lib/log"
+	knet "github.com/kardiachain/go-kardia/lib/net"
+)
+
+// IsConnTimeout returns a boolean indicating whether the error is known to
+// report that a connection timeout occurred. This detects both fundamental
+// network timeouts, as well as ErrConnTimeout errors.
+func IsConnTimeout(err error) bool {
+	var timeoutErr timeoutError
+	return errors.Is(err, ErrConnectionTimeout) || (errors.As(err, &timeoutErr) && timeoutErr.Timeout())
+}
+
+// NewSignerListener creates a new SignerListenerEndpoint using the
+// corresponding listen address: tcp://host:port, whose connections are
+// encrypted and authenticated with connKey, or unix:///path/to/socket.
+func NewSignerListener(listenAddr string, connKey *ecdsa.PrivateKey, logger log.Logger) (*SignerListenerEndpoint, error) {
+	var listener net.Listener
+
+	protocol, address := knet.ProtocolAndAddress(listenAddr)
+	ln, err := net.Listen(protocol, address)
+	if err != nil {
+		return nil, err
+	}
+	switch protocol {
+	case "unix":
+		listener = NewUnixListener(ln)
+	case "tcp":
+		listener = NewTCPListener(ln, connKey)
+	default:
+		ln.Close()
+		return nil, fmt.Errorf("wrong listen address: expected either 'tcp' or 'unix' protocols, got %s", protocol)
+	}
+
+	return NewSignerListenerEndpoint(logger.New("module", "privval"), listener), nil
+}
+
+// NewSignerDialer creates a new SignerDialerEndpoint dialing the node at
+// addr: tcp://host:port, authenticating with connKey, or
+// unix:///path/to/socket.
+func NewSignerDialer(addr string, connKey *ecdsa.PrivateKey, logger log.Logger, options ...SignerServiceEndpointOption) (*SignerDialerEndpoint, error) {
+	var dialer SocketDialer
+
+	protocol, address := knet.ProtocolAndAddress(addr)
+	switch protocol {
+	case "unix":
+		dialer = DialUnixFn(address)
+	case "tcp":
+		dialer = DialTCPFn(addr, defaultTimeoutReadWriteSeconds*time.Second, connKey)
+	default:
+		return nil, fmt.Errorf("wrong address: expected either 'tcp' or 'unix' protocols, got %s", protocol)
+	}
+
+	return NewSignerDialerEndpoint(logger, dialer, options...), nil
+}
@@ -4,6 +4,7 @@
 package consensus
 
 import (
+	encoding_binary "encoding/binary"
 	fmt "fmt"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
@@ -609,6 +610,272 @@ func (m *VoteSetBits) GetVotes() bits.BitArray {
 	return bits.BitArray{}
 }
 
+// CompactBlock is sent instead of the parts of the proposal block to peers
+// which can rebuild them from the transactions in their pool.
+type CompactBlock struct {
+	Height             uint64              `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Round              uint32              `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	BlockPartSetHeader types.PartSetHeader `protobuf:"bytes,3,opt,name=block_part_set_header,json=blockPartSetHeader,proto3" json:"block_part_set_header"`
+	// The block without its transactions.
+	Block types.Block `protobuf:"bytes,4,opt,name=block,proto3" json:"block"`
+	// Short IDs of the transactions, in block order.
+	ShortTxIDs []uint64 `protobuf:"fixed64,5,rep,packed,name=short_tx_ids,json=shortTxIds,proto3" json:"short_tx_ids,omitempty"`
+}
+
+func (m *CompactBlock) Reset()         { *m = CompactBlock{} }
+func (m *CompactBlock) String() string { return proto.CompactTextString(m) }
+func (*CompactBlock) ProtoMessage()    {}
+func (*CompactBlock) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f187ebe8a20aa92, []int{9}
+}
+func (m *CompactBlock) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CompactBlock) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CompactBlock.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CompactBlock) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompactBlock.Merge(m, src)
+}
+func (m *CompactBlock) XXX_Size() int {
+	return m.Size()
+}
+func (m *CompactBlock) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompactBlock.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompactBlock proto.InternalMessageInfo
+
+func (m *CompactBlock) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *CompactBlock) GetRound() uint32 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
+func (m *CompactBlock) GetBlockPartSetHeader() types.PartSetHeader {
+	if m != nil {
+		return m.BlockPartSetHeader
+	}
+	return types.PartSetHeader{}
+}
+
+func (m *CompactBlock) GetBlock() types.Block {
+	if m != nil {
+		return m.Block
+	}
+	return types.Block{}
+}
+
+func (m *CompactBlock) GetShortTxIDs() []uint64 {
+	if m != nil {
+		return m.ShortTxIDs
+	}
+	return nil
+}
+
+// BlockTxsRequest requests the transactions of a compact block which are
+// missing from the pool.
+type BlockTxsRequest struct {
+	Height  uint64   `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Round   uint32   `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	Indexes []uint32 `protobuf:"varint,3,rep,packed,name=indexes,proto3" json:"indexes,omitempty"`
+}
+
+func (m *BlockTxsRequest) Reset()         { *m = BlockTxsRequest{} }
+func (m *BlockTxsRequest) String() string { return proto.CompactTextString(m) }
+func (*BlockTxsRequest) ProtoMessage()    {}
+func (*BlockTxsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f187ebe8a20aa92, []int{10}
+}
+func (m *BlockTxsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BlockTxsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_BlockTxsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *BlockTxsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockTxsRequest.Merge(m, src)
+}
+func (m *BlockTxsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *BlockTxsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockTxsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockTxsRequest proto.InternalMessageInfo
+
+func (m *BlockTxsRequest) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *BlockTxsRequest) GetRound() uint32 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
+func (m *BlockTxsRequest) GetIndexes() []uint32 {
+	if m != nil {
+		return m.Indexes
+	}
+	return nil
+}
+
+// BlockTxsResponse is sent in response to BlockTxsRequest, with the
+// transactions in the requested order.
+type BlockTxsResponse struct {
+	Height uint64   `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Round  uint32   `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	Txs    [][]byte `protobuf:"bytes,3,rep,name=txs,proto3" json:"txs,omitempty"`
+}
+
+func (m *BlockTxsResponse) Reset()         { *m = BlockTxsResponse{} }
+func (m *BlockTxsResponse) String() string { return proto.CompactTextString(m) }
+func (*BlockTxsResponse) ProtoMessage()    {}
+func (*BlockTxsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f187ebe8a20aa92, []int{11}
+}
+func (m *BlockTxsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BlockTxsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_BlockTxsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *BlockTxsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockTxsResponse.Merge(m, src)
+}
+func (m *BlockTxsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *BlockTxsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockTxsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockTxsResponse proto.InternalMessageInfo
+
+func (m *BlockTxsResponse) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *BlockTxsResponse) GetRound() uint32 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
+func (m *BlockTxsResponse) GetTxs() [][]byte {
+	if m != nil {
+		return m.Txs
+	}
+	return nil
+}
+
+// CompactBlockAck tells whether a compact block was rebuilt. If it wasn't, its
+// parts have to be sent instead.
+type CompactBlockAck struct {
+	Height  uint64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Round   uint32 `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	Rebuilt bool   `protobuf:"varint,3,opt,name=rebuilt,proto3" json:"rebuilt,omitempty"`
+}
+
+func (m *CompactBlockAck) Reset()         { *m = CompactBlockAck{} }
+func (m *CompactBlockAck) String() string { return proto.CompactTextString(m) }
+func (*CompactBlockAck) ProtoMessage()    {}
+func (*CompactBlockAck) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8f187ebe8a20aa92, []int{12}
+}
+func (m *CompactBlockAck) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CompactBlockAck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CompactBlockAck.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CompactBlockAck) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompactBlockAck.Merge(m, src)
+}
+func (m *CompactBlockAck) XXX_Size() int {
+	return m.Size()
+}
+func (m *CompactBlockAck) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompactBlockAck.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompactBlockAck proto.InternalMessageInfo
+
+func (m *CompactBlockAck) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *CompactBlockAck) GetRound() uint32 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
+func (m *CompactBlockAck) GetRebuilt() bool {
+	if m != nil {
+		return m.Rebuilt
+	}
+	return false
+}
+
 type Message struct {
 	// Types that are valid to be assigned to Sum:
 	//	*Message_NewRoundStep
@@ -620,6 +887,10 @@ type Message struct {
 	//	*Message_HasVote
 	//	*Message_VoteSetMaj23
 	//	*Message_VoteSetBits
+	//	*Message_CompactBlock
+	//	*Message_BlockTxsRequest
+	//	*Message_BlockTxsResponse
+	//	*Message_CompactBlockAck
 	Sum isMessage_Sum `protobuf_oneof:"sum"`
 }
 
@@ -627,7 +898,7 @@ func (m *Message) Reset()         { *m = Message{} }
 func (m *Message) String() string { return proto.CompactTextString(m) }
 func (*Message) ProtoMessage()    {}
 func (*Message) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8f187ebe8a20aa92, []int{9}
+	return fileDescriptor_8f187ebe8a20aa92, []int{13}
 }
 func (m *Message) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -689,16 +960,32 @@ type Message_VoteSetMaj23 struct {
 type Message_VoteSetBits struct {
 	VoteSetBits *VoteSetBits `protobuf:"bytes,9,opt,name=vote_set_bits,json=voteSetBits,proto3,oneof" json:"vote_set_bits,omitempty"`
 }
+type Message_CompactBlock struct {
+	CompactBlock *CompactBlock `protobuf:"bytes,10,opt,name=compact_block,json=compactBlock,proto3,oneof" json:"compact_block,omitempty"`
+}
+type Message_BlockTxsRequest struct {
+	BlockTxsRequest *BlockTxsRequest `protobuf:"bytes,11,opt,name=block_txs_request,json=blockTxsRequest,proto3,oneof" json:"block_txs_request,omitempty"`
+}
+type Message_BlockTxsResponse struct {
+	BlockTxsResponse *BlockTxsResponse `protobuf:"bytes,12,opt,name=block_txs_response,json=blockTxsResponse,proto3,oneof" json:"block_txs_response,omitempty"`
+}
+type Message_CompactBlockAck struct {
+	CompactBlockAck *CompactBlockAck `protobuf:"bytes,13,opt,name=compact_block_ack,json=compactBlockAck,proto3,oneof" json:"compact_block_ack,omitempty"`
+}
 
-func (*Message_NewRoundStep) isMessage_Sum()  {}
-func (*Message_NewValidBlock) isMessage_Sum() {}
-func (*Message_Proposal) isMessage_Sum()      {}
-func (*Message_ProposalPol) isMessage_Sum()   {}
-func (*Message_BlockPart) isMessage_Sum()     {}
-func (*Message_Vote) isMessage_Sum()          {}
-func (*Message_HasVote) isMessage_Sum()       {}
-func (*Message_VoteSetMaj23) isMessage_Sum()  {}
-func (*Message_VoteSetBits) isMessage_Sum()   {}
+func (*Message_NewRoundStep) isMessage_Sum()     {}
+func (*Message_NewValidBlock) isMessage_Sum()    {}
+func (*Message_Proposal) isMessage_Sum()         {}
+func (*Message_ProposalPol) isMessage_Sum()      {}
+func (*Message_BlockPart) isMessage_Sum()        {}
+func (*Message_Vote) isMessage_Sum()             {}
+func (*Message_HasVote) isMessage_Sum()          {}
+func (*Message_VoteSetMaj23) isMessage_Sum()     {}
+func (*Message_VoteSetBits) isMessage_Sum()      {}
+func (*Message_CompactBlock) isMessage_Sum()     {}
+func (*Message_BlockTxsRequest) isMessage_Sum()  {}
+func (*Message_BlockTxsResponse) isMessage_Sum() {}
+func (*Message_CompactBlockAck) isMessage_Sum()  {}
 
 func (m *Message) GetSum() isMessage_Sum {
 	if m != nil {
@@ -770,6 +1057,34 @@ func (m *Message) GetVoteSetBits() *VoteSetBits {
 	return nil
 }
 
+func (m *Message) GetCompactBlock() *CompactBlock {
+	if x, ok := m.GetSum().(*Message_CompactBlock); ok {
+		return x.CompactBlock
+	}
+	return nil
+}
+
+func (m *Message) GetBlockTxsRequest() *BlockTxsRequest {
+	if x, ok := m.GetSum().(*Message_BlockTxsRequest); ok {
+		return x.BlockTxsRequest
+	}
+	return nil
+}
+
+func (m *Message) GetBlockTxsResponse() *BlockTxsResponse {
+	if x, ok := m.GetSum().(*Message_BlockTxsResponse); ok {
+		return x.BlockTxsResponse
+	}
+	return nil
+}
+
+func (m *Message) GetCompactBlockAck() *CompactBlockAck {
+	if x, ok := m.GetSum().(*Message_CompactBlockAck); ok {
+		return x.CompactBlockAck
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*Message) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -782,6 +1097,10 @@ func (*Message) XXX_OneofWrappers() []interface{} {
 		(*Message_HasVote)(nil),
 		(*Message_VoteSetMaj23)(nil),
 		(*Message_VoteSetBits)(nil),
+		(*Message_CompactBlock)(nil),
+		(*Message_BlockTxsRequest)(nil),
+		(*Message_BlockTxsResponse)(nil),
+		(*Message_CompactBlockAck)(nil),
 	}
 }
 
@@ -795,67 +1114,84 @@ func init() {
 	proto.RegisterType((*HasVote)(nil), "kardiachain.consensus.HasVote")
 	proto.RegisterType((*VoteSetMaj23)(nil), "kardiachain.consensus.VoteSetMaj23")
 	proto.RegisterType((*VoteSetBits)(nil), "kardiachain.consensus.VoteSetBits")
+	proto.RegisterType((*CompactBlock)(nil), "kardiachain.consensus.CompactBlock")
+	proto.RegisterType((*BlockTxsRequest)(nil), "kardiachain.consensus.BlockTxsRequest")
+	proto.RegisterType((*BlockTxsResponse)(nil), "kardiachain.consensus.BlockTxsResponse")
+	proto.RegisterType((*CompactBlockAck)(nil), "kardiachain.consensus.CompactBlockAck")
 	proto.RegisterType((*Message)(nil), "kardiachain.consensus.Message")
 }
 
 func init() { proto.RegisterFile("kardiachain/consensus/types.proto", fileDescriptor_8f187ebe8a20aa92) }
 
 var fileDescriptor_8f187ebe8a20aa92 = []byte{
-	// 855 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x56, 0x4f, 0x6f, 0xe3, 0x44,
-	0x14, 0xb7, 0x59, 0xb7, 0x49, 0x9f, 0x9b, 0x16, 0x46, 0x5b, 0x88, 0xba, 0x22, 0x2d, 0x86, 0xc3,
-	0x8a, 0x3f, 0x8e, 0x48, 0x91, 0x38, 0x2c, 0x48, 0xac, 0x41, 0xe0, 0x6a, 0xb7, 0xdd, 0xc8, 0x59,
-	0x55, 0x82, 0x8b, 0xe5, 0xc4, 0x23, 0x67, 0x58, 0xc7, 0x63, 0x79, 0x26, 0x29, 0x3d, 0xf3, 0x05,
-	0xf8, 0x02, 0x7c, 0x0c, 0x2e, 0x7c, 0x82, 0x3d, 0xee, 0x91, 0xd3, 0x0a, 0xa5, 0xdf, 0x01, 0x8e,
-	0xa0, 0x79, 0x9e, 0x24, 0x0e, 0x72, 0xb6, 0xf4, 0x82, 0xc4, 0xcd, 0x33, 0xef, 0xbd, 0xdf, 0xbc,
-	0xf9, 0xbd, 0xf7, 0x7e, 0x1e, 0x78, 0xe7, 0x59, 0x54, 0xc4, 0x2c, 0x1a, 0x8d, 0x23, 0x96, 0x75,
-	0x47, 0x3c, 0x13, 0x34, 0x13, 0x53, 0xd1, 0x95, 0x57, 0x39, 0x15, 0x6e, 0x5e, 0x70, 0xc9, 0xc9,
-	0x41, 0xc5, 0xc5, 0x5d, 0xba, 0x1c, 0xde, 0x4d, 0x78, 0xc2, 0xd1, 0xa3, 0xab, 0xbe, 0x4a, 0xe7,
-	0xc3, 0xb7, 0xab, 0x78, 0x88, 0x52, 0xc5, 0x3a, 0x5c, 0x3b, 0x2e, 0x65, 0x43, 0xd1, 0x1d, 0x32,
-	0xb9, 0xe6, 0xe2, 0xfc, 0x62, 0xc2, 0xee, 0x39, 0xbd, 0x0c, 0xf8, 0x34, 0x8b, 0x07, 0x92, 0xe6,
-	0xe4, 0x4d, 0xd8, 0x1e, 0x53, 0x96, 0x8c, 0x65, 0xdb, 0x3c, 0x36, 0xef, 0x5b, 0x81, 0x5e, 0x91,
-	0xbb, 0xb0, 0x55, 0x28, 0xa7, 0xf6, 0x6b, 0xc7, 0xe6, 0xfd, 0x56, 0x50, 0x2e, 0x08, 0x01, 0x4b,
-	0x48, 0x9a, 0xb7, 0xef, 0xe0, 0x26, 0x7e, 0x93, 0x4f, 0xa1, 0x2d, 0xe8, 0x88, 0x67, 0xb1, 0x08,
-	0x05, 0xcb, 0x46, 0x34, 0x14, 0x32, 0x2a, 0x64, 0x28, 0xd9, 0x84, 0xb6, 0x2d, 0xc4, 0x3c, 0xd0,
-	0xf6, 0x81, 0x32, 0x0f, 0x94, 0xf5, 0x29, 0x9b, 0x50, 0xf2, 0x3e, 0xbc, 0x91, 0x46, 0x42, 0x86,
-	0x23, 0x3e, 0x99, 0x30, 0x19, 0x96, 0xc7, 0x6d, 0x21, 0xf2, 0xbe, 0x32, 0x7c, 0x89, 0xfb, 0x98,
-	0xaa, 0xf3, 0xa7, 0x09, 0xad, 0x73, 0x7a, 0x79, 0x11, 0xa5, 0x2c, 0xf6, 0x52, 0x3e, 0x7a, 0x76,
-	0xcb, 0xc4, 0xbf, 0x85, 0x83, 0xa1, 0x0a, 0x0b, 0x73, 0x95, 0x9b, 0xa0, 0x32, 0x1c, 0xd3, 0x28,
-	0xa6, 0x05, 0xde, 0xc4, 0xee, 0x1d, 0xbb, 0xd5, 0x32, 0x94, 0x84, 0xf5, 0xa3, 0x42, 0x0e, 0xa8,
-	0xf4, 0xd1, 0xcf, 0xb3, 0x9e, 0xbf, 0x3c, 0x32, 0x02, 0x82, 0x20, 0x6b, 0x16, 0xf2, 0x05, 0xd8,
-	0x2b, 0x68, 0x81, 0x57, 0xb6, 0x7b, 0x47, 0x6b, 0x80, 0xaa, 0x16, 0xae, 0xaa, 0x85, 0xeb, 0x31,
-	0xf9, 0xb0, 0x28, 0xa2, 0xab, 0x00, 0x96, 0x48, 0x82, 0xdc, 0x83, 0x1d, 0x26, 0x34, 0x0d, 0x48,
-	0x40, 0x33, 0x68, 0x32, 0x51, 0x5e, 0xdf, 0x39, 0x85, 0x66, 0xbf, 0xe0, 0x39, 0x17, 0x51, 0x4a,
-	0x3e, 0x87, 0x66, 0xae, 0xbf, 0xf1, 0xd6, 0x76, 0xef, 0x5e, 0x5d, 0xe2, 0xda, 0x45, 0xe7, 0xbc,
-	0x0c, 0x71, 0x7e, 0x36, 0xc1, 0x5e, 0x18, 0xfb, 0x4f, 0x1e, 0x6f, 0xa4, 0xf0, 0x43, 0x20, 0x8b,
-	0x98, 0x30, 0xe7, 0x69, 0x58, 0xe5, 0xf3, 0xf5, 0x85, 0xa5, 0xcf, 0x53, 0x2c, 0x0d, 0xf1, 0x61,
-	0xb7, 0xea, 0xad, 0x19, 0xbd, 0x89, 0x00, 0x9d, 0x9c, 0x5d, 0x81, 0x73, 0x52, 0xd8, 0xf1, 0x16,
-	0xac, 0xdc, 0xb2, 0xbe, 0x1f, 0x83, 0xa5, 0xe8, 0xd7, 0x87, 0xbf, 0xb5, 0xa1, 0x9c, 0xfa, 0x50,
-	0x74, 0x75, 0x4e, 0xc0, 0xba, 0xe0, 0x92, 0x92, 0x0f, 0xc0, 0x9a, 0x71, 0x49, 0x35, 0xa1, 0x75,
-	0xa1, 0xca, 0x2d, 0x40, 0x27, 0xe7, 0x47, 0x13, 0x1a, 0x7e, 0x24, 0x30, 0xf0, 0x76, 0x19, 0x7e,
-	0x02, 0x96, 0x42, 0xc3, 0x0c, 0xf7, 0x6a, 0x1b, 0x6e, 0xc0, 0x92, 0x8c, 0xc6, 0x67, 0x22, 0x79,
-	0x7a, 0x95, 0xd3, 0x00, 0xbd, 0x15, 0x16, 0xcb, 0x62, 0xfa, 0x03, 0xb6, 0x55, 0x2b, 0x28, 0x17,
-	0xce, 0xaf, 0x26, 0xec, 0xaa, 0x14, 0x06, 0x54, 0x9e, 0x45, 0xdf, 0xf7, 0x4e, 0xfe, 0x93, 0x54,
-	0xbe, 0x86, 0x66, 0xd9, 0xe7, 0x2c, 0xd6, 0x4d, 0x7e, 0x58, 0x13, 0x89, 0x05, 0x3c, 0xfd, 0xca,
-	0xdb, 0x57, 0x4c, 0xcf, 0x5f, 0x1e, 0x35, 0xf4, 0x46, 0xd0, 0xc0, 0xe0, 0xd3, 0xd8, 0xf9, 0xc3,
-	0x04, 0x5b, 0x27, 0xef, 0x31, 0x29, 0xfe, 0x4f, 0xb9, 0x93, 0x07, 0xb0, 0xa5, 0xda, 0x40, 0xe0,
-	0x94, 0xfe, 0xeb, 0x26, 0x2f, 0x63, 0x9c, 0xbf, 0x2c, 0x68, 0x9c, 0x51, 0x21, 0xa2, 0x84, 0x92,
-	0x47, 0xb0, 0x97, 0xd1, 0xcb, 0x72, 0xb2, 0x42, 0x94, 0xd4, 0xb2, 0xfd, 0xde, 0x75, 0x6b, 0xff,
-	0x07, 0x6e, 0x55, 0xb3, 0x7d, 0x23, 0xd8, 0xcd, 0xaa, 0x1a, 0x7e, 0x0e, 0xfb, 0x0a, 0x6c, 0xa6,
-	0xc4, 0x31, 0xc4, 0x54, 0x91, 0x33, 0xbb, 0xf7, 0xde, 0x66, 0xb4, 0x95, 0x92, 0xfa, 0x46, 0xd0,
-	0xca, 0xd6, 0xa4, 0xb5, 0x2a, 0x33, 0x75, 0xd3, 0xbc, 0x02, 0x5a, 0xa8, 0x89, 0x5f, 0x91, 0x19,
-	0xf2, 0xcd, 0x3f, 0x04, 0xa1, 0x24, 0xdc, 0xb9, 0x01, 0xa2, 0xff, 0xe4, 0xb1, 0xbf, 0xae, 0x07,
-	0xe4, 0x21, 0xc0, 0x4a, 0x59, 0x35, 0xe5, 0xc7, 0x1b, 0x60, 0x96, 0xc2, 0xe1, 0x1b, 0xc1, 0xce,
-	0x52, 0x5b, 0x95, 0x2e, 0xe0, 0x70, 0x6f, 0xd7, 0xa8, 0xe5, 0x2a, 0x58, 0xb5, 0xa3, 0x6f, 0x94,
-	0x23, 0x4e, 0x1e, 0x40, 0x73, 0x1c, 0x89, 0x10, 0xc3, 0x1a, 0x18, 0xd6, 0xd9, 0x10, 0xa6, 0x85,
-	0xc0, 0x37, 0x82, 0xc6, 0x58, 0x6b, 0xc2, 0x23, 0xd8, 0x53, 0x81, 0xf8, 0x87, 0x99, 0xa8, 0xd1,
-	0x6c, 0x37, 0x5f, 0x59, 0xd7, 0xea, 0x14, 0xab, 0xba, 0xce, 0xaa, 0x53, 0xed, 0x43, 0x6b, 0x09,
-	0xa6, 0xfa, 0xaa, 0xbd, 0xf3, 0x4a, 0x26, 0x2b, 0x43, 0xa5, 0x98, 0x9c, 0xad, 0x96, 0xde, 0x16,
-	0xdc, 0x11, 0xd3, 0x89, 0x77, 0xf1, 0x7c, 0xde, 0x31, 0x5f, 0xcc, 0x3b, 0xe6, 0xef, 0xf3, 0x8e,
-	0xf9, 0xd3, 0x75, 0xc7, 0x78, 0x71, 0xdd, 0x31, 0x7e, 0xbb, 0xee, 0x18, 0xdf, 0x7d, 0x96, 0x30,
-	0x39, 0x9e, 0x0e, 0xdd, 0x11, 0x9f, 0x74, 0xab, 0xaf, 0x88, 0x84, 0x7f, 0x54, 0x2e, 0xbb, 0xe5,
-	0x63, 0xa4, 0xf6, 0x41, 0x33, 0xdc, 0x46, 0xe3, 0xc9, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x98,
-	0x63, 0xf3, 0xc0, 0xf0, 0x08, 0x00, 0x00,
+	// 1068 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x57, 0xcd, 0x6e, 0x23, 0x45,
+	0x10, 0x9e, 0x59, 0xdb, 0xb1, 0x53, 0xb6, 0x93, 0x6c, 0x6b, 0x03, 0xa3, 0xac, 0x70, 0xc2, 0x80,
+	0x20, 0xe2, 0xc7, 0x86, 0x64, 0x25, 0x0e, 0x0b, 0x12, 0xf1, 0xae, 0x60, 0x02, 0x9b, 0xac, 0xd5,
+	0xb6, 0x82, 0x96, 0xcb, 0x68, 0x3c, 0xd3, 0xb2, 0x9b, 0xd8, 0x33, 0xc3, 0x74, 0x3b, 0x71, 0xce,
+	0xbc, 0x00, 0x2f, 0xc0, 0x63, 0x70, 0xe1, 0x09, 0xf6, 0x98, 0x23, 0xa7, 0x08, 0x39, 0xef, 0x00,
+	0x47, 0x50, 0x77, 0x8f, 0xed, 0x76, 0x64, 0x7b, 0xf1, 0x05, 0xb1, 0xb7, 0xae, 0xbf, 0x6f, 0xbe,
+	0xae, 0xaa, 0xae, 0xb2, 0xe1, 0xed, 0x73, 0x2f, 0x09, 0xa8, 0xe7, 0x77, 0x3d, 0x1a, 0xd6, 0xfc,
+	0x28, 0x64, 0x24, 0x64, 0x03, 0x56, 0xe3, 0x57, 0x31, 0x61, 0xd5, 0x38, 0x89, 0x78, 0x84, 0xb6,
+	0x35, 0x97, 0xea, 0xc4, 0x65, 0xe7, 0x41, 0x27, 0xea, 0x44, 0xd2, 0xa3, 0x26, 0x4e, 0xca, 0x79,
+	0xe7, 0x2d, 0x1d, 0x4f, 0xa2, 0xe8, 0x58, 0xf3, 0xcc, 0xed, 0x5e, 0xe4, 0x9f, 0xa7, 0xe6, 0x19,
+	0x36, 0x3d, 0xda, 0x66, 0xb5, 0x36, 0xe5, 0x33, 0x08, 0xf6, 0xaf, 0x26, 0x94, 0x4e, 0xc9, 0x25,
+	0x8e, 0x06, 0x61, 0xd0, 0xe4, 0x24, 0x46, 0x6f, 0xc0, 0x5a, 0x97, 0xd0, 0x4e, 0x97, 0x5b, 0xe6,
+	0x9e, 0xb9, 0x9f, 0xc5, 0xa9, 0x84, 0x1e, 0x40, 0x2e, 0x11, 0x4e, 0xd6, 0xbd, 0x3d, 0x73, 0xbf,
+	0x8c, 0x95, 0x80, 0x10, 0x64, 0x19, 0x27, 0xb1, 0x95, 0x91, 0x4a, 0x79, 0x46, 0x9f, 0x81, 0xc5,
+	0x88, 0x1f, 0x85, 0x01, 0x73, 0x19, 0x0d, 0x7d, 0xe2, 0x32, 0xee, 0x25, 0xdc, 0xe5, 0xb4, 0x4f,
+	0xac, 0xac, 0xc4, 0xdc, 0x4e, 0xed, 0x4d, 0x61, 0x6e, 0x0a, 0x6b, 0x8b, 0xf6, 0x09, 0xfa, 0x00,
+	0xee, 0xf7, 0x3c, 0xc6, 0x5d, 0x3f, 0xea, 0xf7, 0x29, 0x77, 0xd5, 0xe7, 0x72, 0x12, 0x79, 0x53,
+	0x18, 0x9e, 0x48, 0xbd, 0xa4, 0x6a, 0xff, 0x65, 0x42, 0xf9, 0x94, 0x5c, 0x9e, 0x79, 0x3d, 0x1a,
+	0xd4, 0xc5, 0x95, 0x57, 0x24, 0xfe, 0x02, 0xb6, 0x65, 0xa6, 0xdc, 0x58, 0x70, 0x63, 0x84, 0xbb,
+	0x5d, 0xe2, 0x05, 0x24, 0x91, 0x37, 0x29, 0x1e, 0xec, 0x55, 0xf5, 0x2a, 0xa9, 0x84, 0x35, 0xbc,
+	0x84, 0x37, 0x09, 0x77, 0xa4, 0x5f, 0x3d, 0xfb, 0xf2, 0x66, 0xd7, 0xc0, 0x48, 0x82, 0xcc, 0x58,
+	0xd0, 0x97, 0x50, 0x9c, 0x42, 0x33, 0x79, 0xe5, 0xe2, 0xc1, 0xee, 0x0c, 0xa0, 0xa8, 0x45, 0x55,
+	0xd4, 0xa2, 0x5a, 0xa7, 0xfc, 0x28, 0x49, 0xbc, 0x2b, 0x0c, 0x13, 0x24, 0x86, 0x1e, 0xc2, 0x3a,
+	0x65, 0x69, 0x1a, 0x64, 0x02, 0x0a, 0xb8, 0x40, 0x99, 0xba, 0xbe, 0x7d, 0x0c, 0x85, 0x46, 0x12,
+	0xc5, 0x11, 0xf3, 0x7a, 0xe8, 0x0b, 0x28, 0xc4, 0xe9, 0x59, 0xde, 0xba, 0x78, 0xf0, 0x70, 0x1e,
+	0xf1, 0xd4, 0x25, 0xe5, 0x3c, 0x09, 0xb1, 0x7f, 0x31, 0xa1, 0x38, 0x36, 0x36, 0x9e, 0x3f, 0x5b,
+	0x98, 0xc2, 0x8f, 0x00, 0x8d, 0x63, 0xdc, 0x38, 0xea, 0xb9, 0x7a, 0x3e, 0xb7, 0xc6, 0x96, 0x46,
+	0xd4, 0x93, 0xa5, 0x41, 0x0e, 0x94, 0x74, 0x6f, 0x2b, 0xf3, 0xaf, 0x12, 0x90, 0x92, 0x2b, 0x6a,
+	0x70, 0x76, 0x0f, 0xd6, 0xeb, 0xe3, 0xac, 0xac, 0x58, 0xdf, 0x4f, 0x21, 0x2b, 0xd2, 0x9f, 0x7e,
+	0xfc, 0xcd, 0x05, 0xe5, 0x4c, 0x3f, 0x2a, 0x5d, 0xed, 0x43, 0xc8, 0x9e, 0x45, 0x9c, 0xa0, 0x0f,
+	0x21, 0x7b, 0x11, 0x71, 0x62, 0x99, 0x0b, 0x43, 0x85, 0x1b, 0x96, 0x4e, 0xf6, 0x4f, 0x26, 0xe4,
+	0x1d, 0x8f, 0xc9, 0xc0, 0xd5, 0x18, 0x3e, 0x82, 0xac, 0x40, 0x93, 0x0c, 0x37, 0xe6, 0x36, 0x5c,
+	0x93, 0x76, 0x42, 0x12, 0x9c, 0xb0, 0x4e, 0xeb, 0x2a, 0x26, 0x58, 0x7a, 0x0b, 0x2c, 0x1a, 0x06,
+	0x64, 0x28, 0xdb, 0xaa, 0x8c, 0x95, 0x60, 0xff, 0x66, 0x42, 0x49, 0x50, 0x68, 0x12, 0x7e, 0xe2,
+	0xfd, 0x70, 0x70, 0xf8, 0x9f, 0x50, 0xf9, 0x0a, 0x0a, 0xaa, 0xcf, 0x69, 0x90, 0x36, 0xf9, 0xce,
+	0x9c, 0x48, 0x59, 0xc0, 0xe3, 0xa7, 0xf5, 0x4d, 0x91, 0xe9, 0xd1, 0xcd, 0x6e, 0x3e, 0x55, 0xe0,
+	0xbc, 0x0c, 0x3e, 0x0e, 0xec, 0x3f, 0x4d, 0x28, 0xa6, 0xe4, 0xeb, 0x94, 0xb3, 0xd7, 0x89, 0x3b,
+	0x7a, 0x0c, 0x39, 0xd1, 0x06, 0xcc, 0xca, 0xad, 0xd2, 0xe4, 0x2a, 0xc6, 0xfe, 0xdb, 0x84, 0xd2,
+	0x93, 0xa8, 0x1f, 0x7b, 0x3e, 0xff, 0x9f, 0x8d, 0xb0, 0x47, 0x90, 0x93, 0xda, 0x34, 0x37, 0xd6,
+	0xa2, 0xdc, 0x8c, 0xef, 0x23, 0x9d, 0xd1, 0x27, 0x50, 0x62, 0xdd, 0x48, 0x8c, 0xfa, 0xa1, 0x4b,
+	0x03, 0x91, 0x93, 0xcc, 0xfe, 0x5a, 0x7d, 0x63, 0x74, 0xb3, 0x0b, 0x4d, 0xa1, 0x6f, 0x0d, 0x8f,
+	0x9f, 0x32, 0x0c, 0x2c, 0x3d, 0x07, 0xcc, 0x7e, 0x01, 0x9b, 0x12, 0xa7, 0x35, 0x64, 0x98, 0xfc,
+	0x38, 0x20, 0x6c, 0xd5, 0x67, 0x6e, 0x41, 0x5e, 0xbe, 0x00, 0xc2, 0xac, 0xcc, 0x5e, 0x66, 0xbf,
+	0x8c, 0xc7, 0xa2, 0x8d, 0x61, 0x6b, 0x0a, 0xcd, 0x62, 0xb1, 0x67, 0x57, 0xc4, 0xde, 0x82, 0x0c,
+	0x1f, 0x2a, 0xdc, 0x12, 0x16, 0x47, 0x41, 0x57, 0xaf, 0xd7, 0xd1, 0xca, 0x25, 0xb3, 0x20, 0x9f,
+	0x90, 0xf6, 0x80, 0xf6, 0xd4, 0x60, 0x2a, 0xe0, 0xb1, 0x68, 0x5f, 0xe7, 0x21, 0x7f, 0x42, 0x18,
+	0xf3, 0x3a, 0x04, 0x7d, 0x0b, 0x1b, 0x21, 0xb9, 0x54, 0x53, 0xd6, 0x95, 0xeb, 0x55, 0x8d, 0xa2,
+	0x77, 0xaa, 0x73, 0x7f, 0x3a, 0x54, 0xf5, 0xfd, 0xed, 0x18, 0xb8, 0x14, 0x6a, 0x32, 0x3a, 0x85,
+	0x4d, 0x01, 0x76, 0x21, 0x16, 0xa5, 0xab, 0x8a, 0x7a, 0x4f, 0xa2, 0xbd, 0xbb, 0x18, 0x6d, 0xba,
+	0x55, 0x1d, 0x03, 0x97, 0x43, 0x5d, 0x31, 0xb3, 0x72, 0xe6, 0x4d, 0xf6, 0x29, 0xd0, 0x78, 0xb3,
+	0x38, 0xda, 0xca, 0x41, 0x5f, 0xdf, 0x59, 0x0e, 0xaa, 0xc1, 0xec, 0x57, 0x40, 0x34, 0x9e, 0x3f,
+	0x73, 0x66, 0x77, 0x03, 0x3a, 0x02, 0x98, 0x76, 0xbf, 0x95, 0x9b, 0xd3, 0xf2, 0x53, 0x98, 0xc9,
+	0x12, 0x71, 0x0c, 0xbc, 0x3e, 0x69, 0x77, 0xb1, 0x23, 0xe4, 0xa0, 0x5f, 0x9b, 0xb3, 0x39, 0xa7,
+	0xc1, 0x62, 0x34, 0x39, 0x86, 0x1a, 0xf7, 0xe8, 0x31, 0x14, 0xba, 0x1e, 0x73, 0x65, 0x58, 0x5e,
+	0x86, 0x55, 0x16, 0x84, 0xa5, 0x4b, 0xc1, 0x31, 0x70, 0xbe, 0xab, 0x8e, 0xa2, 0xae, 0x22, 0x50,
+	0x3e, 0xd5, 0xbe, 0x18, 0xd3, 0x56, 0x61, 0x69, 0x5d, 0xf5, 0x89, 0x2e, 0xea, 0x7a, 0xa1, 0xc9,
+	0xc8, 0x81, 0xf2, 0x04, 0x4c, 0xcc, 0x18, 0x6b, 0x7d, 0x69, 0x26, 0xb5, 0x01, 0x2b, 0x32, 0x79,
+	0x31, 0x15, 0xd1, 0x37, 0x50, 0xf6, 0x55, 0x57, 0xa7, 0xfd, 0x01, 0x4b, 0x59, 0xe9, 0x2f, 0x40,
+	0xb0, 0xf2, 0x35, 0x19, 0xb5, 0xe0, 0xbe, 0xaa, 0x0a, 0x1f, 0x32, 0x37, 0x51, 0x4f, 0xda, 0x2a,
+	0x4a, 0xbc, 0xf7, 0x96, 0x15, 0x67, 0x3a, 0x00, 0x1c, 0x03, 0x6f, 0xb6, 0x67, 0x55, 0xe8, 0x3b,
+	0x40, 0x3a, 0xaa, 0x7a, 0xcd, 0x56, 0x49, 0xc2, 0xbe, 0xff, 0x4a, 0x58, 0xe5, 0xee, 0x18, 0x78,
+	0xab, 0x7d, 0x47, 0x27, 0xe8, 0xce, 0x5c, 0xdd, 0xf5, 0xfc, 0x73, 0xab, 0xbc, 0x94, 0xee, 0x9d,
+	0x01, 0x20, 0xe8, 0xfa, 0xb3, 0xaa, 0x7a, 0x0e, 0x32, 0x6c, 0xd0, 0xaf, 0x9f, 0xbd, 0x1c, 0x55,
+	0xcc, 0xeb, 0x51, 0xc5, 0xfc, 0x63, 0x54, 0x31, 0x7f, 0xbe, 0xad, 0x18, 0xd7, 0xb7, 0x15, 0xe3,
+	0xf7, 0xdb, 0x8a, 0xf1, 0xfd, 0xe7, 0x1d, 0xca, 0xbb, 0x83, 0x76, 0xd5, 0x8f, 0xfa, 0x35, 0xfd,
+	0x27, 0x7a, 0x27, 0xfa, 0x58, 0x89, 0x35, 0xf5, 0x47, 0x60, 0xee, 0x9f, 0x89, 0xf6, 0x9a, 0x34,
+	0x1e, 0xfe, 0x33, 0x00, 0x14, 0x86, 0xfb, 0xfa, 0x6c, 0x0c, 0x00, 0x00,
 }
 
 func (m *NewRoundStep) Marshal() (dAtA []byte, err error) {
@@ -1274,7 +1610,7 @@ func (m *VoteSetBits) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *Message) Marshal() (dAtA []byte, err error) {
+func (m *CompactBlock) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1284,58 +1620,256 @@ func (m *Message) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Message) MarshalTo(dAtA []byte) (int, error) {
+func (m *CompactBlock) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Message) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *CompactBlock) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Sum != nil {
-		{
-			size := m.Sum.Size()
-			i -= size
-			if _, err := m.Sum.MarshalTo(dAtA[i:]); err != nil {
-				return 0, err
-			}
+	if len(m.ShortTxIDs) > 0 {
+		for iNdEx := len(m.ShortTxIDs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= 8
+			encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(m.ShortTxIDs[iNdEx]))
+		}
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.ShortTxIDs)*8))
+		i--
+		dAtA[i] = 0x2a
+	}
+	{
+		size, err := m.Block.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintTypes(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x22
+	{
+		size, err := m.BlockPartSetHeader.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
 		}
+		i -= size
+		i = encodeVarintTypes(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x1a
+	if m.Round != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Round))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Height != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *Message_NewRoundStep) MarshalTo(dAtA []byte) (int, error) {
+func (m *BlockTxsRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *Message_NewRoundStep) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	if m.NewRoundStep != nil {
-		{
-			size, err := m.NewRoundStep.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintTypes(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0xa
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	return dAtA[:n], nil
 }
-func (m *Message_NewValidBlock) MarshalTo(dAtA []byte) (int, error) {
+
+func (m *BlockTxsRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Message_NewValidBlock) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *BlockTxsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
-	if m.NewValidBlock != nil {
-		{
+	_ = i
+	var l int
+	_ = l
+	if len(m.Indexes) > 0 {
+		dAtA13 := make([]byte, len(m.Indexes)*10)
+		var j12 int
+		for _, num := range m.Indexes {
+			for num >= 1<<7 {
+				dAtA13[j12] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j12++
+			}
+			dAtA13[j12] = uint8(num)
+			j12++
+		}
+		i -= j12
+		copy(dAtA[i:], dAtA13[:j12])
+		i = encodeVarintTypes(dAtA, i, uint64(j12))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Round != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Round))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Height != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *BlockTxsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BlockTxsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BlockTxsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Txs) > 0 {
+		for iNdEx := len(m.Txs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Txs[iNdEx])
+			copy(dAtA[i:], m.Txs[iNdEx])
+			i = encodeVarintTypes(dAtA, i, uint64(len(m.Txs[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.Round != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Round))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Height != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CompactBlockAck) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CompactBlockAck) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CompactBlockAck) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Rebuilt {
+		i--
+		if m.Rebuilt {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Round != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Round))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Height != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Message) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Message) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Message) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Sum != nil {
+		{
+			size := m.Sum.Size()
+			i -= size
+			if _, err := m.Sum.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Message_NewRoundStep) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Message_NewRoundStep) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.NewRoundStep != nil {
+		{
+			size, err := m.NewRoundStep.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Message_NewValidBlock) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Message_NewValidBlock) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.NewValidBlock != nil {
+		{
 			size, err := m.NewValidBlock.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
@@ -1495,6 +2029,90 @@ func (m *Message_VoteSetBits) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	}
 	return len(dAtA) - i, nil
 }
+func (m *Message_CompactBlock) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Message_CompactBlock) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.CompactBlock != nil {
+		{
+			size, err := m.CompactBlock.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x52
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Message_BlockTxsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Message_BlockTxsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.BlockTxsRequest != nil {
+		{
+			size, err := m.BlockTxsRequest.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x5a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Message_BlockTxsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Message_BlockTxsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.BlockTxsResponse != nil {
+		{
+			size, err := m.BlockTxsResponse.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x62
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Message_CompactBlockAck) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Message_CompactBlockAck) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.CompactBlockAck != nil {
+		{
+			size, err := m.CompactBlockAck.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x6a
+	}
+	return len(dAtA) - i, nil
+}
 func encodeVarintTypes(dAtA []byte, offset int, v uint64) int {
 	offset -= sovTypes(v)
 	base := offset
@@ -1675,51 +2293,134 @@ func (m *VoteSetBits) Size() (n int) {
 	return n
 }
 
-func (m *Message) Size() (n int) {
+func (m *CompactBlock) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if m.Sum != nil {
-		n += m.Sum.Size()
+	if m.Height != 0 {
+		n += 1 + sovTypes(uint64(m.Height))
+	}
+	if m.Round != 0 {
+		n += 1 + sovTypes(uint64(m.Round))
+	}
+	l = m.BlockPartSetHeader.Size()
+	n += 1 + l + sovTypes(uint64(l))
+	l = m.Block.Size()
+	n += 1 + l + sovTypes(uint64(l))
+	if len(m.ShortTxIDs) > 0 {
+		n += 1 + sovTypes(uint64(len(m.ShortTxIDs)*8)) + len(m.ShortTxIDs)*8
 	}
 	return n
 }
 
-func (m *Message_NewRoundStep) Size() (n int) {
+func (m *BlockTxsRequest) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if m.NewRoundStep != nil {
-		l = m.NewRoundStep.Size()
-		n += 1 + l + sovTypes(uint64(l))
+	if m.Height != 0 {
+		n += 1 + sovTypes(uint64(m.Height))
+	}
+	if m.Round != 0 {
+		n += 1 + sovTypes(uint64(m.Round))
+	}
+	if len(m.Indexes) > 0 {
+		l = 0
+		for _, e := range m.Indexes {
+			l += sovTypes(uint64(e))
+		}
+		n += 1 + sovTypes(uint64(l)) + l
 	}
 	return n
 }
-func (m *Message_NewValidBlock) Size() (n int) {
+
+func (m *BlockTxsResponse) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if m.NewValidBlock != nil {
-		l = m.NewValidBlock.Size()
-		n += 1 + l + sovTypes(uint64(l))
+	if m.Height != 0 {
+		n += 1 + sovTypes(uint64(m.Height))
+	}
+	if m.Round != 0 {
+		n += 1 + sovTypes(uint64(m.Round))
+	}
+	if len(m.Txs) > 0 {
+		for _, b := range m.Txs {
+			l = len(b)
+			n += 1 + l + sovTypes(uint64(l))
+		}
 	}
 	return n
 }
-func (m *Message_Proposal) Size() (n int) {
+
+func (m *CompactBlockAck) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if m.Proposal != nil {
-		l = m.Proposal.Size()
-		n += 1 + l + sovTypes(uint64(l))
+	if m.Height != 0 {
+		n += 1 + sovTypes(uint64(m.Height))
+	}
+	if m.Round != 0 {
+		n += 1 + sovTypes(uint64(m.Round))
+	}
+	if m.Rebuilt {
+		n += 2
+	}
+	return n
+}
+
+func (m *Message) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Sum != nil {
+		n += m.Sum.Size()
+	}
+	return n
+}
+
+func (m *Message_NewRoundStep) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.NewRoundStep != nil {
+		l = m.NewRoundStep.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+func (m *Message_NewValidBlock) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.NewValidBlock != nil {
+		l = m.NewValidBlock.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+func (m *Message_Proposal) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Proposal != nil {
+		l = m.Proposal.Size()
+		n += 1 + l + sovTypes(uint64(l))
 	}
 	return n
 }
@@ -1795,6 +2496,54 @@ func (m *Message_VoteSetBits) Size() (n int) {
 	}
 	return n
 }
+func (m *Message_CompactBlock) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.CompactBlock != nil {
+		l = m.CompactBlock.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+func (m *Message_BlockTxsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.BlockTxsRequest != nil {
+		l = m.BlockTxsRequest.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+func (m *Message_BlockTxsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.BlockTxsResponse != nil {
+		l = m.BlockTxsResponse.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+func (m *Message_CompactBlockAck) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.CompactBlockAck != nil {
+		l = m.CompactBlockAck.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
 
 func sovTypes(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
@@ -2102,7 +2851,544 @@ func (m *NewValidBlock) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-			m.IsCommit = bool(v != 0)
+			m.IsCommit = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Proposal) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Proposal: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Proposal: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Proposal", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Proposal.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ProposalPOL) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ProposalPOL: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ProposalPOL: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProposalPolRound", wireType)
+			}
+			m.ProposalPolRound = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ProposalPolRound |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProposalPol", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.ProposalPol.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *BlockPart) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BlockPart: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BlockPart: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Round", wireType)
+			}
+			m.Round = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Round |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Part", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Part.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Vote) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Vote: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Vote: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Vote", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Vote == nil {
+				m.Vote = &types.Vote{}
+			}
+			if err := m.Vote.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HasVote) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HasVote: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HasVote: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Round", wireType)
+			}
+			m.Round = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Round |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= types.SignedMsgType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -2124,7 +3410,7 @@ func (m *NewValidBlock) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Proposal) Unmarshal(dAtA []byte) error {
+func (m *VoteSetMaj23) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2147,15 +3433,72 @@ func (m *Proposal) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Proposal: wiretype end group for non-group")
+			return fmt.Errorf("proto: VoteSetMaj23: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Proposal: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: VoteSetMaj23: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Round", wireType)
+			}
+			m.Round = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Round |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= types.SignedMsgType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Proposal", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockID", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2182,7 +3525,7 @@ func (m *Proposal) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Proposal.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.BlockID.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -2207,7 +3550,7 @@ func (m *Proposal) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ProposalPOL) Unmarshal(dAtA []byte) error {
+func (m *VoteSetBits) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2230,10 +3573,10 @@ func (m *ProposalPOL) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ProposalPOL: wiretype end group for non-group")
+			return fmt.Errorf("proto: VoteSetBits: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ProposalPOL: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: VoteSetBits: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -2257,9 +3600,9 @@ func (m *ProposalPOL) Unmarshal(dAtA []byte) error {
 			}
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ProposalPolRound", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Round", wireType)
 			}
-			m.ProposalPolRound = 0
+			m.Round = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTypes
@@ -2269,14 +3612,33 @@ func (m *ProposalPOL) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ProposalPolRound |= uint32(b&0x7F) << shift
+				m.Round |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= types.SignedMsgType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ProposalPol", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockID", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2303,7 +3665,40 @@ func (m *ProposalPOL) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.ProposalPol.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.BlockID.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Votes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Votes.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -2328,7 +3723,7 @@ func (m *ProposalPOL) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *BlockPart) Unmarshal(dAtA []byte) error {
+func (m *CompactBlock) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2351,10 +3746,10 @@ func (m *BlockPart) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: BlockPart: wiretype end group for non-group")
+			return fmt.Errorf("proto: CompactBlock: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: BlockPart: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CompactBlock: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -2397,7 +3792,7 @@ func (m *BlockPart) Unmarshal(dAtA []byte) error {
 			}
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Part", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockPartSetHeader", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2424,63 +3819,13 @@ func (m *BlockPart) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Part.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.BlockPartSetHeader.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTypes(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthTypes
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Vote) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTypes
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Vote: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Vote: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Vote", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Block", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2507,13 +3852,62 @@ func (m *Vote) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Vote == nil {
-				m.Vote = &types.Vote{}
-			}
-			if err := m.Vote.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Block.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType == 1 {
+				var v uint64
+				if (iNdEx + 8) > l {
+					return io.ErrUnexpectedEOF
+				}
+				v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+				iNdEx += 8
+				m.ShortTxIDs = append(m.ShortTxIDs, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowTypes
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthTypes
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthTypes
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				elementCount = packedLen / 8
+				if elementCount != 0 && len(m.ShortTxIDs) == 0 {
+					m.ShortTxIDs = make([]uint64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					if (iNdEx + 8) > l {
+						return io.ErrUnexpectedEOF
+					}
+					v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+					iNdEx += 8
+					m.ShortTxIDs = append(m.ShortTxIDs, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field ShortTxIDs", wireType)
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -2535,7 +3929,7 @@ func (m *Vote) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *HasVote) Unmarshal(dAtA []byte) error {
+func (m *BlockTxsRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2558,55 +3952,17 @@ func (m *HasVote) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: HasVote: wiretype end group for non-group")
+			return fmt.Errorf("proto: BlockTxsRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: HasVote: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
-			}
-			m.Height = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTypes
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Height |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Round", wireType)
-			}
-			m.Round = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTypes
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Round |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 3:
+			return fmt.Errorf("proto: BlockTxsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
 			}
-			m.Type = 0
+			m.Height = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTypes
@@ -2616,16 +3972,16 @@ func (m *HasVote) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Type |= types.SignedMsgType(b&0x7F) << shift
+				m.Height |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Round", wireType)
 			}
-			m.Index = 0
+			m.Round = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTypes
@@ -2635,11 +3991,87 @@ func (m *HasVote) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Index |= uint32(b&0x7F) << shift
+				m.Round |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+		case 3:
+			if wireType == 0 {
+				var v uint32
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowTypes
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= uint32(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Indexes = append(m.Indexes, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowTypes
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthTypes
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthTypes
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.Indexes) == 0 {
+					m.Indexes = make([]uint32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint32
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowTypes
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= uint32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Indexes = append(m.Indexes, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Indexes", wireType)
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -2661,7 +4093,7 @@ func (m *HasVote) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *VoteSetMaj23) Unmarshal(dAtA []byte) error {
+func (m *BlockTxsResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2684,10 +4116,10 @@ func (m *VoteSetMaj23) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: VoteSetMaj23: wiretype end group for non-group")
+			return fmt.Errorf("proto: BlockTxsResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: VoteSetMaj23: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: BlockTxsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -2729,29 +4161,10 @@ func (m *VoteSetMaj23) Unmarshal(dAtA []byte) error {
 				}
 			}
 		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
-			}
-			m.Type = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTypes
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Type |= types.SignedMsgType(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field BlockID", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Txs", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTypes
@@ -2761,24 +4174,23 @@ func (m *VoteSetMaj23) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthTypes
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTypes
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.BlockID.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Txs = append(m.Txs, make([]byte, postIndex-iNdEx))
+			copy(m.Txs[len(m.Txs)-1], dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -2801,7 +4213,7 @@ func (m *VoteSetMaj23) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *VoteSetBits) Unmarshal(dAtA []byte) error {
+func (m *CompactBlockAck) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2824,10 +4236,10 @@ func (m *VoteSetBits) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: VoteSetBits: wiretype end group for non-group")
+			return fmt.Errorf("proto: CompactBlockAck: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: VoteSetBits: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CompactBlockAck: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -2870,61 +4282,9 @@ func (m *VoteSetBits) Unmarshal(dAtA []byte) error {
 			}
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
-			}
-			m.Type = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTypes
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Type |= types.SignedMsgType(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field BlockID", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowTypes
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+				return fmt.Errorf("proto: wrong wireType = %d for field Rebuilt", wireType)
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthTypes
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTypes
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if err := m.BlockID.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Votes", wireType)
-			}
-			var msglen int
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTypes
@@ -2934,25 +4294,12 @@ func (m *VoteSetBits) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthTypes
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthTypes
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if err := m.Votes.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
+			m.Rebuilt = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -3318,6 +4665,146 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 			}
 			m.Sum = &Message_VoteSetBits{v}
 			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CompactBlock", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &CompactBlock{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Message_CompactBlock{v}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockTxsRequest", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &BlockTxsRequest{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Message_BlockTxsRequest{v}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockTxsResponse", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &BlockTxsResponse{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Message_BlockTxsResponse{v}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CompactBlockAck", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &CompactBlockAck{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Message_CompactBlockAck{v}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
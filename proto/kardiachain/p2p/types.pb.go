@@ -152,6 +152,7 @@ type DefaultNodeInfo struct {
 	Channels        []byte               `protobuf:"bytes,6,opt,name=channels,proto3" json:"channels,omitempty"`
 	Moniker         string               `protobuf:"bytes,7,opt,name=moniker,proto3" json:"moniker,omitempty"`
 	Other           DefaultNodeInfoOther `protobuf:"bytes,8,opt,name=other,proto3" json:"other"`
+	Capabilities    []string             `protobuf:"bytes,9,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
 }
 
 func (m *DefaultNodeInfo) Reset()         { *m = DefaultNodeInfo{} }
@@ -243,6 +244,13 @@ func (m *DefaultNodeInfo) GetOther() DefaultNodeInfoOther {
 	return DefaultNodeInfoOther{}
 }
 
+func (m *DefaultNodeInfo) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
 type DefaultNodeInfoOther struct {
 	TxIndex    string `protobuf:"bytes,1,opt,name=tx_index,json=txIndex,proto3" json:"tx_index,omitempty"`
 	RPCAddress string `protobuf:"bytes,2,opt,name=rpc_address,json=rpcAddress,proto3" json:"rpc_address,omitempty"`
@@ -305,38 +313,39 @@ func init() {
 func init() { proto.RegisterFile("kardiachain/p2p/types.proto", fileDescriptor_6cbe2e01d4b0a5bd) }
 
 var fileDescriptor_6cbe2e01d4b0a5bd = []byte{
-	// 486 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x53, 0x4d, 0x8b, 0xdb, 0x30,
-	0x10, 0x8d, 0x1d, 0xe7, 0x63, 0x27, 0x4d, 0xb3, 0x15, 0xa1, 0x78, 0xb7, 0x60, 0x87, 0x40, 0x21,
-	0x97, 0xc6, 0x90, 0x42, 0xa1, 0xc7, 0x4d, 0x73, 0xc9, 0x65, 0xeb, 0x8a, 0xd2, 0x43, 0x2f, 0xc1,
-	0xb1, 0xb4, 0x89, 0x48, 0x56, 0x12, 0xb2, 0xb6, 0x4d, 0xff, 0x45, 0x7f, 0xd6, 0x1e, 0xf7, 0xd8,
-	0x53, 0x28, 0xce, 0xb9, 0xff, 0xa1, 0x58, 0xf2, 0x16, 0xe3, 0xee, 0x6d, 0xde, 0x3c, 0xcd, 0xcc,
-	0x9b, 0xc7, 0x08, 0x5e, 0xed, 0x12, 0x45, 0x58, 0x92, 0x6e, 0x13, 0xc6, 0x23, 0x39, 0x93, 0x91,
-	0xfe, 0x21, 0x69, 0x36, 0x95, 0x4a, 0x68, 0x81, 0x06, 0x15, 0x72, 0x2a, 0x67, 0xf2, 0x72, 0xb8,
-	0x11, 0x1b, 0x61, 0xb8, 0xa8, 0x88, 0xec, 0xb3, 0x71, 0x0c, 0x70, 0x4d, 0xf5, 0x15, 0x21, 0x8a,
-	0x66, 0x19, 0x7a, 0x09, 0x2e, 0x23, 0xbe, 0x33, 0x72, 0x26, 0x67, 0xf3, 0x76, 0x7e, 0x0c, 0xdd,
-	0xe5, 0x02, 0xbb, 0x8c, 0x98, 0xbc, 0xf4, 0xdd, 0x4a, 0x3e, 0xc6, 0x2e, 0x93, 0x08, 0x81, 0x27,
-	0x85, 0xd2, 0x7e, 0x73, 0xe4, 0x4c, 0xfa, 0xd8, 0xc4, 0xe3, 0xcf, 0x30, 0x88, 0x8b, 0xd6, 0xa9,
-	0xd8, 0x7f, 0xa1, 0x2a, 0x63, 0x82, 0xa3, 0x0b, 0x68, 0xca, 0x99, 0x34, 0x7d, 0xbd, 0x79, 0x27,
-	0x3f, 0x86, 0xcd, 0x78, 0x16, 0xe3, 0x22, 0x87, 0x86, 0xd0, 0x5a, 0xef, 0x45, 0xba, 0x33, 0xcd,
-	0x3d, 0x6c, 0x01, 0x3a, 0x87, 0x66, 0x22, 0xa5, 0x69, 0xeb, 0xe1, 0x22, 0x1c, 0xff, 0x71, 0x61,
-	0xb0, 0xa0, 0x37, 0xc9, 0xdd, 0x5e, 0x5f, 0x0b, 0x42, 0x97, 0xfc, 0x46, 0xa0, 0x4f, 0x70, 0x2e,
-	0xcb, 0x49, 0xab, 0x6f, 0x76, 0x94, 0x99, 0xd1, 0x9b, 0x8d, 0xa6, 0xb5, 0xed, 0xa7, 0x35, 0x49,
-	0x73, 0xef, 0xfe, 0x18, 0x36, 0xf0, 0x40, 0xd6, 0x94, 0xbe, 0x87, 0x01, 0xb1, 0x53, 0x56, 0x5c,
-	0x10, 0xba, 0x62, 0xa4, 0xdc, 0xfa, 0x45, 0x7e, 0x0c, 0xfb, 0x55, 0x01, 0x0b, 0xdc, 0x27, 0x15,
-	0x48, 0x50, 0x08, 0xbd, 0x3d, 0xcb, 0x34, 0xe5, 0xab, 0x84, 0x10, 0x65, 0xb4, 0x9f, 0x61, 0xb0,
-	0xa9, 0xc2, 0x5f, 0xe4, 0x43, 0x87, 0x53, 0xfd, 0x5d, 0xa8, 0x9d, 0xef, 0x19, 0xf2, 0x11, 0x16,
-	0xcc, 0xa3, 0xfe, 0x96, 0x65, 0x4a, 0x88, 0x2e, 0xa1, 0x9b, 0x6e, 0x13, 0xce, 0xe9, 0x3e, 0xf3,
-	0xdb, 0x23, 0x67, 0xf2, 0x0c, 0xff, 0xc3, 0x45, 0xd5, 0xad, 0xe0, 0x6c, 0x47, 0x95, 0xdf, 0xb1,
-	0x55, 0x25, 0x44, 0x57, 0xd0, 0x12, 0x7a, 0x4b, 0x95, 0xdf, 0x35, 0x6e, 0xbc, 0xfe, 0xcf, 0x8d,
-	0x9a, 0x93, 0x1f, 0x8b, 0xc7, 0xa5, 0x25, 0xb6, 0x72, 0xbc, 0x86, 0xe1, 0x53, 0x8f, 0xd0, 0x05,
-	0x74, 0xf5, 0x61, 0xc5, 0x38, 0xa1, 0x07, 0x7b, 0x27, 0xb8, 0xa3, 0x0f, 0xcb, 0x02, 0xa2, 0x08,
-	0x7a, 0x4a, 0xa6, 0x66, 0x7b, 0x9a, 0x65, 0xa5, 0x6f, 0xcf, 0xf3, 0x63, 0x08, 0x38, 0xfe, 0x50,
-	0x5e, 0x18, 0x06, 0x25, 0xd3, 0x32, 0x9e, 0xc7, 0xf7, 0x79, 0xe0, 0x3c, 0xe4, 0x81, 0xf3, 0x3b,
-	0x0f, 0x9c, 0x9f, 0xa7, 0xa0, 0xf1, 0x70, 0x0a, 0x1a, 0xbf, 0x4e, 0x41, 0xe3, 0xeb, 0xbb, 0x0d,
-	0xd3, 0xdb, 0xbb, 0xf5, 0x34, 0x15, 0xb7, 0x51, 0xf5, 0xc8, 0x37, 0xe2, 0x8d, 0x85, 0x91, 0x3d,
-	0xe4, 0xda, 0x07, 0x58, 0xb7, 0x4d, 0xfa, 0xed, 0xdf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x58, 0xfc,
-	0x76, 0xf6, 0x1a, 0x03, 0x00, 0x00,
+	// 501 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x53, 0x4d, 0x8f, 0xd3, 0x30,
+	0x10, 0x6d, 0x9a, 0xf4, 0x6b, 0xba, 0xa5, 0x8b, 0x55, 0xa1, 0xec, 0x22, 0x25, 0x55, 0x25, 0xa4,
+	0x5e, 0x68, 0xa4, 0x22, 0x21, 0x71, 0xdc, 0xd2, 0x4b, 0x2f, 0x4b, 0xb0, 0x10, 0x07, 0x2e, 0x55,
+	0x1a, 0x7b, 0x5b, 0xab, 0x59, 0xdb, 0x72, 0xbc, 0x50, 0xfe, 0x05, 0xfc, 0xab, 0x3d, 0xee, 0x91,
+	0x53, 0x85, 0xd2, 0x3f, 0x82, 0x62, 0x67, 0x51, 0x29, 0x7b, 0x9b, 0xf7, 0xc6, 0x7e, 0xf3, 0xfc,
+	0x34, 0x86, 0x97, 0xdb, 0x44, 0x11, 0x96, 0xa4, 0x9b, 0x84, 0xf1, 0x48, 0x4e, 0x65, 0xa4, 0xbf,
+	0x4b, 0x9a, 0x4f, 0xa4, 0x12, 0x5a, 0xa0, 0xfe, 0x51, 0x73, 0x22, 0xa7, 0xf2, 0x72, 0xb0, 0x16,
+	0x6b, 0x61, 0x7a, 0x51, 0x59, 0xd9, 0x63, 0xa3, 0x18, 0xe0, 0x9a, 0xea, 0x2b, 0x42, 0x14, 0xcd,
+	0x73, 0xf4, 0x02, 0xea, 0x8c, 0xf8, 0xce, 0xd0, 0x19, 0x77, 0x66, 0xcd, 0x62, 0x1f, 0xd6, 0x17,
+	0x73, 0x5c, 0x67, 0xc4, 0xf0, 0xd2, 0xaf, 0x1f, 0xf1, 0x31, 0xae, 0x33, 0x89, 0x10, 0x78, 0x52,
+	0x28, 0xed, 0xbb, 0x43, 0x67, 0xdc, 0xc3, 0xa6, 0x1e, 0x7d, 0x82, 0x7e, 0x5c, 0x4a, 0xa7, 0x22,
+	0xfb, 0x4c, 0x55, 0xce, 0x04, 0x47, 0x17, 0xe0, 0xca, 0xa9, 0x34, 0xba, 0xde, 0xac, 0x55, 0xec,
+	0x43, 0x37, 0x9e, 0xc6, 0xb8, 0xe4, 0xd0, 0x00, 0x1a, 0xab, 0x4c, 0xa4, 0x5b, 0x23, 0xee, 0x61,
+	0x0b, 0xd0, 0x39, 0xb8, 0x89, 0x94, 0x46, 0xd6, 0xc3, 0x65, 0x39, 0xfa, 0xe9, 0x42, 0x7f, 0x4e,
+	0x6f, 0x92, 0xbb, 0x4c, 0x5f, 0x0b, 0x42, 0x17, 0xfc, 0x46, 0xa0, 0x8f, 0x70, 0x2e, 0xab, 0x49,
+	0xcb, 0xaf, 0x76, 0x94, 0x99, 0xd1, 0x9d, 0x0e, 0x27, 0x27, 0xaf, 0x9f, 0x9c, 0x58, 0x9a, 0x79,
+	0xf7, 0xfb, 0xb0, 0x86, 0xfb, 0xf2, 0xc4, 0xe9, 0x3b, 0xe8, 0x13, 0x3b, 0x65, 0xc9, 0x05, 0xa1,
+	0x4b, 0x46, 0xaa, 0x57, 0x3f, 0x2f, 0xf6, 0x61, 0xef, 0xd8, 0xc0, 0x1c, 0xf7, 0xc8, 0x11, 0x24,
+	0x28, 0x84, 0x6e, 0xc6, 0x72, 0x4d, 0xf9, 0x32, 0x21, 0x44, 0x19, 0xef, 0x1d, 0x0c, 0x96, 0x2a,
+	0xf3, 0x45, 0x3e, 0xb4, 0x38, 0xd5, 0xdf, 0x84, 0xda, 0xfa, 0x9e, 0x69, 0x3e, 0xc2, 0xb2, 0xf3,
+	0xe8, 0xbf, 0x61, 0x3b, 0x15, 0x44, 0x97, 0xd0, 0x4e, 0x37, 0x09, 0xe7, 0x34, 0xcb, 0xfd, 0xe6,
+	0xd0, 0x19, 0x9f, 0xe1, 0xbf, 0xb8, 0xbc, 0x75, 0x2b, 0x38, 0xdb, 0x52, 0xe5, 0xb7, 0xec, 0xad,
+	0x0a, 0xa2, 0x2b, 0x68, 0x08, 0xbd, 0xa1, 0xca, 0x6f, 0x9b, 0x34, 0x5e, 0xfd, 0x97, 0xc6, 0x49,
+	0x92, 0x1f, 0xca, 0xc3, 0x55, 0x24, 0xf6, 0x26, 0x1a, 0xc1, 0x59, 0x9a, 0xc8, 0x64, 0xc5, 0x32,
+	0xa6, 0x19, 0xcd, 0xfd, 0xce, 0xd0, 0x1d, 0x77, 0xf0, 0x3f, 0xdc, 0x68, 0x05, 0x83, 0xa7, 0x84,
+	0xd0, 0x05, 0xb4, 0xf5, 0x6e, 0xc9, 0x38, 0xa1, 0x3b, 0xbb, 0x4b, 0xb8, 0xa5, 0x77, 0x8b, 0x12,
+	0xa2, 0x08, 0xba, 0x4a, 0xa6, 0x26, 0x21, 0x9a, 0xe7, 0x55, 0xb6, 0xcf, 0x8a, 0x7d, 0x08, 0x38,
+	0x7e, 0x5f, 0x6d, 0x21, 0x06, 0x25, 0xd3, 0xaa, 0x9e, 0xc5, 0xf7, 0x45, 0xe0, 0x3c, 0x14, 0x81,
+	0xf3, 0xbb, 0x08, 0x9c, 0x1f, 0x87, 0xa0, 0xf6, 0x70, 0x08, 0x6a, 0xbf, 0x0e, 0x41, 0xed, 0xcb,
+	0xdb, 0x35, 0xd3, 0x9b, 0xbb, 0xd5, 0x24, 0x15, 0xb7, 0xd1, 0xf1, 0x47, 0x58, 0x8b, 0xd7, 0x16,
+	0x46, 0x76, 0xd9, 0x4f, 0x3e, 0xc9, 0xaa, 0x69, 0xe8, 0x37, 0x7f, 0x06, 0x00, 0x3f, 0x36, 0x91,
+	0xef, 0x3e, 0x03, 0x00, 0x00,
 }
 
 func (m *NetAddress) Marshal() (dAtA []byte, err error) {
@@ -439,6 +448,15 @@ func (m *DefaultNodeInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Capabilities) > 0 {
+		for iNdEx := len(m.Capabilities) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Capabilities[iNdEx])
+			copy(dAtA[i:], m.Capabilities[iNdEx])
+			i = encodeVarintTypes(dAtA, i, uint64(len(m.Capabilities[iNdEx])))
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
 	{
 		size, err := m.Other.MarshalToSizedBuffer(dAtA[:i])
 		if err != nil {
@@ -624,6 +642,12 @@ func (m *DefaultNodeInfo) Size() (n int) {
 	}
 	l = m.Other.Size()
 	n += 1 + l + sovTypes(uint64(l))
+	if len(m.Capabilities) > 0 {
+		for _, s := range m.Capabilities {
+			l = len(s)
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -1179,6 +1203,38 @@ func (m *DefaultNodeInfo) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Capabilities", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Capabilities = append(m.Capabilities, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
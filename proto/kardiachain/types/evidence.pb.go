@@ -105,9 +105,141 @@ func (m *DuplicateVoteEvidence) GetTimestamp() time.Time {
 	return time.Time{}
 }
 
+// LightBlock is a signed header and the validator set that signed it.
+type LightBlock struct {
+	SignedHeader *SignedHeader `protobuf:"bytes,1,opt,name=signed_header,json=signedHeader,proto3" json:"signed_header,omitempty"`
+	ValidatorSet *ValidatorSet `protobuf:"bytes,2,opt,name=validator_set,json=validatorSet,proto3" json:"validator_set,omitempty"`
+}
+
+func (m *LightBlock) Reset()         { *m = LightBlock{} }
+func (m *LightBlock) String() string { return proto.CompactTextString(m) }
+func (*LightBlock) ProtoMessage()    {}
+func (*LightBlock) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9916f59e043142ef, []int{1}
+}
+func (m *LightBlock) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LightBlock) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LightBlock.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *LightBlock) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LightBlock.Merge(m, src)
+}
+func (m *LightBlock) XXX_Size() int {
+	return m.Size()
+}
+func (m *LightBlock) XXX_DiscardUnknown() {
+	xxx_messageInfo_LightBlock.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LightBlock proto.InternalMessageInfo
+
+func (m *LightBlock) GetSignedHeader() *SignedHeader {
+	if m != nil {
+		return m.SignedHeader
+	}
+	return nil
+}
+
+func (m *LightBlock) GetValidatorSet() *ValidatorSet {
+	if m != nil {
+		return m.ValidatorSet
+	}
+	return nil
+}
+
+// LightClientAttackEvidence contains evidence of a set of validators
+// attempting to mislead a light client.
+type LightClientAttackEvidence struct {
+	ConflictingBlock    *LightBlock  `protobuf:"bytes,1,opt,name=conflicting_block,json=conflictingBlock,proto3" json:"conflicting_block,omitempty"`
+	CommonHeight        uint64       `protobuf:"varint,2,opt,name=common_height,json=commonHeight,proto3" json:"common_height,omitempty"`
+	ByzantineValidators []*Validator `protobuf:"bytes,3,rep,name=byzantine_validators,json=byzantineValidators,proto3" json:"byzantine_validators,omitempty"`
+	TotalVotingPower    int64        `protobuf:"varint,4,opt,name=total_voting_power,json=totalVotingPower,proto3" json:"total_voting_power,omitempty"`
+	Timestamp           time.Time    `protobuf:"bytes,5,opt,name=timestamp,proto3,stdtime" json:"timestamp"`
+}
+
+func (m *LightClientAttackEvidence) Reset()         { *m = LightClientAttackEvidence{} }
+func (m *LightClientAttackEvidence) String() string { return proto.CompactTextString(m) }
+func (*LightClientAttackEvidence) ProtoMessage()    {}
+func (*LightClientAttackEvidence) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9916f59e043142ef, []int{2}
+}
+func (m *LightClientAttackEvidence) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LightClientAttackEvidence) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LightClientAttackEvidence.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *LightClientAttackEvidence) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LightClientAttackEvidence.Merge(m, src)
+}
+func (m *LightClientAttackEvidence) XXX_Size() int {
+	return m.Size()
+}
+func (m *LightClientAttackEvidence) XXX_DiscardUnknown() {
+	xxx_messageInfo_LightClientAttackEvidence.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LightClientAttackEvidence proto.InternalMessageInfo
+
+func (m *LightClientAttackEvidence) GetConflictingBlock() *LightBlock {
+	if m != nil {
+		return m.ConflictingBlock
+	}
+	return nil
+}
+
+func (m *LightClientAttackEvidence) GetCommonHeight() uint64 {
+	if m != nil {
+		return m.CommonHeight
+	}
+	return 0
+}
+
+func (m *LightClientAttackEvidence) GetByzantineValidators() []*Validator {
+	if m != nil {
+		return m.ByzantineValidators
+	}
+	return nil
+}
+
+func (m *LightClientAttackEvidence) GetTotalVotingPower() int64 {
+	if m != nil {
+		return m.TotalVotingPower
+	}
+	return 0
+}
+
+func (m *LightClientAttackEvidence) GetTimestamp() time.Time {
+	if m != nil {
+		return m.Timestamp
+	}
+	return time.Time{}
+}
+
 type Evidence struct {
 	// Types that are valid to be assigned to Sum:
 	//	*Evidence_DuplicateVoteEvidence
+	//	*Evidence_LightClientAttackEvidence
 	Sum isEvidence_Sum `protobuf_oneof:"sum"`
 }
 
@@ -115,7 +247,7 @@ func (m *Evidence) Reset()         { *m = Evidence{} }
 func (m *Evidence) String() string { return proto.CompactTextString(m) }
 func (*Evidence) ProtoMessage()    {}
 func (*Evidence) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9916f59e043142ef, []int{1}
+	return fileDescriptor_9916f59e043142ef, []int{3}
 }
 func (m *Evidence) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -153,8 +285,12 @@ type isEvidence_Sum interface {
 type Evidence_DuplicateVoteEvidence struct {
 	DuplicateVoteEvidence *DuplicateVoteEvidence `protobuf:"bytes,1,opt,name=duplicate_vote_evidence,json=duplicateVoteEvidence,proto3,oneof" json:"duplicate_vote_evidence,omitempty"`
 }
+type Evidence_LightClientAttackEvidence struct {
+	LightClientAttackEvidence *LightClientAttackEvidence `protobuf:"bytes,2,opt,name=light_client_attack_evidence,json=lightClientAttackEvidence,proto3,oneof" json:"light_client_attack_evidence,omitempty"`
+}
 
-func (*Evidence_DuplicateVoteEvidence) isEvidence_Sum() {}
+func (*Evidence_DuplicateVoteEvidence) isEvidence_Sum()     {}
+func (*Evidence_LightClientAttackEvidence) isEvidence_Sum() {}
 
 func (m *Evidence) GetSum() isEvidence_Sum {
 	if m != nil {
@@ -170,10 +306,18 @@ func (m *Evidence) GetDuplicateVoteEvidence() *DuplicateVoteEvidence {
 	return nil
 }
 
+func (m *Evidence) GetLightClientAttackEvidence() *LightClientAttackEvidence {
+	if x, ok := m.GetSum().(*Evidence_LightClientAttackEvidence); ok {
+		return x.LightClientAttackEvidence
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*Evidence) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
 		(*Evidence_DuplicateVoteEvidence)(nil),
+		(*Evidence_LightClientAttackEvidence)(nil),
 	}
 }
 
@@ -186,7 +330,7 @@ func (m *EvidenceData) Reset()         { *m = EvidenceData{} }
 func (m *EvidenceData) String() string { return proto.CompactTextString(m) }
 func (*EvidenceData) ProtoMessage()    {}
 func (*EvidenceData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9916f59e043142ef, []int{2}
+	return fileDescriptor_9916f59e043142ef, []int{4}
 }
 func (m *EvidenceData) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -224,6 +368,8 @@ func (m *EvidenceData) GetEvidence() []Evidence {
 
 func init() {
 	proto.RegisterType((*DuplicateVoteEvidence)(nil), "kardiachain.types.DuplicateVoteEvidence")
+	proto.RegisterType((*LightBlock)(nil), "kardiachain.types.LightBlock")
+	proto.RegisterType((*LightClientAttackEvidence)(nil), "kardiachain.types.LightClientAttackEvidence")
 	proto.RegisterType((*Evidence)(nil), "kardiachain.types.Evidence")
 	proto.RegisterType((*EvidenceData)(nil), "kardiachain.types.EvidenceData")
 }
@@ -231,32 +377,45 @@ func init() {
 func init() { proto.RegisterFile("kardiachain/types/evidence.proto", fileDescriptor_9916f59e043142ef) }
 
 var fileDescriptor_9916f59e043142ef = []byte{
-	// 388 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0xcd, 0x4a, 0xc3, 0x40,
-	0x10, 0xc7, 0x93, 0xa6, 0x2d, 0x75, 0x2b, 0x7e, 0x04, 0x4b, 0x43, 0xc5, 0xb4, 0xf4, 0x62, 0x0e,
-	0xba, 0x81, 0x7a, 0xf1, 0xe2, 0xc1, 0x50, 0xc1, 0x8b, 0x20, 0x41, 0x7a, 0xf0, 0x12, 0x36, 0xc9,
-	0x9a, 0x2e, 0x26, 0xdd, 0x90, 0x6e, 0x22, 0xbe, 0x45, 0xf1, 0xa9, 0x7a, 0xec, 0xd1, 0x93, 0x4a,
-	0xfb, 0x22, 0x92, 0xcd, 0x87, 0x85, 0xe6, 0xe0, 0x25, 0x64, 0xe7, 0xff, 0x9b, 0x9d, 0x99, 0xff,
-	0x0e, 0x18, 0xbc, 0xa2, 0xc8, 0x25, 0xc8, 0x99, 0x22, 0x32, 0xd3, 0xd9, 0x7b, 0x88, 0xe7, 0x3a,
-	0x4e, 0x88, 0x8b, 0x67, 0x0e, 0x86, 0x61, 0x44, 0x19, 0x95, 0x8f, 0xb7, 0x08, 0xc8, 0x89, 0xde,
-	0x89, 0x47, 0x3d, 0xca, 0x55, 0x3d, 0xfd, 0xcb, 0xc0, 0xde, 0xd9, 0xee, 0x55, 0xfc, 0x9b, 0xcb,
-	0x7d, 0x8f, 0x52, 0xcf, 0xc7, 0x3a, 0x3f, 0xd9, 0xf1, 0x8b, 0xce, 0x48, 0x80, 0xe7, 0x0c, 0x05,
-	0x61, 0x06, 0x0c, 0x3f, 0x6a, 0xa0, 0x33, 0x8e, 0x43, 0x9f, 0x38, 0x88, 0xe1, 0x09, 0x65, 0xf8,
-	0x2e, 0x6f, 0x44, 0x86, 0xa0, 0x99, 0x50, 0x86, 0x2d, 0xa4, 0x88, 0x03, 0x51, 0x6b, 0x8f, 0xba,
-	0x70, 0xa7, 0x27, 0x98, 0x26, 0x98, 0x8d, 0x14, 0xbb, 0x2d, 0x79, 0x5b, 0xa9, 0xfd, 0x83, 0x37,
-	0xe4, 0x0b, 0x20, 0x33, 0xca, 0x90, 0x6f, 0x25, 0x94, 0x91, 0x99, 0x67, 0x85, 0xf4, 0x0d, 0x47,
-	0x8a, 0x34, 0x10, 0x35, 0xc9, 0x3c, 0xe2, 0xca, 0x84, 0x0b, 0x8f, 0x69, 0x5c, 0x3e, 0x07, 0x87,
-	0x09, 0xf2, 0x89, 0x8b, 0x18, 0x8d, 0x72, 0xb4, 0xce, 0xd1, 0x83, 0x32, 0x9c, 0x81, 0x06, 0xd8,
-	0x2b, 0x67, 0x54, 0x1a, 0xbc, 0x93, 0x1e, 0xcc, 0x5c, 0x80, 0x85, 0x0b, 0xf0, 0xa9, 0x20, 0x8c,
-	0xd6, 0xf2, 0xab, 0x2f, 0x2c, 0xbe, 0xfb, 0xa2, 0xf9, 0x97, 0x36, 0x8c, 0x41, 0xab, 0xb4, 0xc1,
-	0x06, 0x5d, 0xb7, 0xf0, 0xc7, 0xe2, 0x03, 0x16, 0x4f, 0x95, 0xfb, 0xa2, 0x55, 0xcc, 0x59, 0xe9,
-	0xe8, 0xbd, 0x60, 0x76, 0xdc, 0x2a, 0xc1, 0x68, 0x00, 0x69, 0x1e, 0x07, 0xc3, 0x07, 0xb0, 0x5f,
-	0x84, 0xc6, 0x88, 0x21, 0xf9, 0x06, 0xb4, 0xb6, 0x6a, 0x49, 0x5a, 0x7b, 0x74, 0x5a, 0x51, 0xab,
-	0xbc, 0xa5, 0x9e, 0x8e, 0x62, 0x96, 0x29, 0x86, 0xb9, 0x5c, 0xab, 0xe2, 0x6a, 0xad, 0x8a, 0x3f,
-	0x6b, 0x55, 0x5c, 0x6c, 0x54, 0x61, 0xb5, 0x51, 0x85, 0xcf, 0x8d, 0x2a, 0x3c, 0x5f, 0x7b, 0x84,
-	0x4d, 0x63, 0x1b, 0x3a, 0x34, 0xd0, 0xb7, 0xf7, 0xc7, 0xa3, 0x97, 0xd9, 0x31, 0xdb, 0x17, 0x7d,
-	0x67, 0xb7, 0xec, 0x26, 0x17, 0xae, 0x7e, 0x03, 0x00, 0x00, 0xff, 0xff, 0xeb, 0x0a, 0x1a, 0xc5,
-	0xc2, 0x02, 0x00, 0x00,
+	// 593 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x94, 0xcf, 0x6f, 0xd3, 0x30,
+	0x14, 0xc7, 0x93, 0xfe, 0x98, 0x8a, 0xd7, 0xc1, 0x16, 0x36, 0x2d, 0x2b, 0x5b, 0x5a, 0xca, 0x81,
+	0x1e, 0x46, 0x22, 0x8d, 0x0b, 0x17, 0x0e, 0x0b, 0x45, 0xaa, 0x10, 0x08, 0xe4, 0xa1, 0x1e, 0xb8,
+	0x44, 0x4e, 0xe2, 0xa5, 0x56, 0x93, 0xb8, 0x6a, 0xdc, 0xa2, 0xf1, 0x57, 0x4c, 0x9c, 0xf8, 0x93,
+	0x76, 0x63, 0x47, 0x4e, 0x80, 0x5a, 0x89, 0xbf, 0x03, 0xd9, 0x49, 0xdc, 0x48, 0x4d, 0x11, 0x87,
+	0x5d, 0xa2, 0xf8, 0xbd, 0xcf, 0xb3, 0xdf, 0xf7, 0x3d, 0x3f, 0x83, 0xce, 0x18, 0x4d, 0x7d, 0x82,
+	0xbc, 0x11, 0x22, 0xb1, 0xc5, 0xae, 0x26, 0x38, 0xb1, 0xf0, 0x9c, 0xf8, 0x38, 0xf6, 0xb0, 0x39,
+	0x99, 0x52, 0x46, 0xb5, 0xbd, 0x02, 0x61, 0x0a, 0xa2, 0xb5, 0x1f, 0xd0, 0x80, 0x0a, 0xaf, 0xc5,
+	0xff, 0x52, 0xb0, 0x75, 0xb2, 0xbe, 0x95, 0xf8, 0x66, 0xee, 0xc7, 0xeb, 0xee, 0x39, 0x0a, 0x89,
+	0x8f, 0x18, 0x9d, 0x66, 0x48, 0x3b, 0xa0, 0x34, 0x08, 0xb1, 0x25, 0x56, 0xee, 0xec, 0xd2, 0x62,
+	0x24, 0xc2, 0x09, 0x43, 0xd1, 0x24, 0x05, 0xba, 0x5f, 0x2b, 0xe0, 0xa0, 0x3f, 0x9b, 0x84, 0xc4,
+	0x43, 0x0c, 0x0f, 0x29, 0xc3, 0xaf, 0xb3, 0x5c, 0x35, 0x13, 0x6c, 0xcd, 0x29, 0xc3, 0x0e, 0xd2,
+	0xd5, 0x8e, 0xda, 0xdb, 0x3e, 0x3b, 0x34, 0xd7, 0xd2, 0x36, 0x79, 0x00, 0xac, 0x73, 0xec, 0x5c,
+	0xf2, 0xae, 0x5e, 0xf9, 0x0f, 0xde, 0xd6, 0x4e, 0x81, 0xc6, 0x28, 0x43, 0xa1, 0x33, 0xa7, 0x8c,
+	0xc4, 0x81, 0x33, 0xa1, 0x9f, 0xf1, 0x54, 0xaf, 0x76, 0xd4, 0x5e, 0x15, 0xee, 0x0a, 0xcf, 0x50,
+	0x38, 0x3e, 0x70, 0xbb, 0xf6, 0x14, 0x3c, 0x90, 0xda, 0x32, 0xb4, 0x26, 0xd0, 0xfb, 0xd2, 0x9c,
+	0x82, 0x36, 0xb8, 0x27, 0x35, 0xea, 0x75, 0x91, 0x49, 0xcb, 0x4c, 0xab, 0x60, 0xe6, 0x55, 0x30,
+	0x3f, 0xe6, 0x84, 0xdd, 0xb8, 0xf9, 0xd9, 0x56, 0xae, 0x7f, 0xb5, 0x55, 0xb8, 0x0a, 0xeb, 0x7e,
+	0x53, 0x01, 0x78, 0x4b, 0x82, 0x11, 0xb3, 0x43, 0xea, 0x8d, 0xb5, 0x3e, 0xd8, 0x49, 0x48, 0x10,
+	0x63, 0xdf, 0x19, 0x61, 0xe4, 0xe3, 0x69, 0x56, 0x90, 0x76, 0x89, 0xc0, 0x0b, 0xc1, 0x0d, 0x04,
+	0x06, 0x9b, 0x49, 0x61, 0xc5, 0x77, 0x59, 0x29, 0x48, 0x30, 0xd3, 0x2b, 0x1b, 0x77, 0x19, 0xe6,
+	0xdc, 0x05, 0x66, 0xb0, 0x39, 0x2f, 0xac, 0xba, 0xdf, 0x2b, 0xe0, 0x48, 0xa4, 0xf6, 0x2a, 0x24,
+	0x38, 0x66, 0xe7, 0x8c, 0x21, 0x6f, 0x2c, 0x7b, 0xf6, 0x06, 0xec, 0x79, 0x34, 0xbe, 0x0c, 0x89,
+	0x27, 0x4a, 0xea, 0xf2, 0xf4, 0xb3, 0x6c, 0x4f, 0x4a, 0xce, 0x59, 0x69, 0x84, 0xbb, 0x85, 0xb8,
+	0x54, 0xf5, 0x13, 0xb0, 0xe3, 0xd1, 0x28, 0xa2, 0xb1, 0x33, 0xc2, 0x9c, 0x13, 0xf9, 0xd6, 0x60,
+	0x33, 0x35, 0x0e, 0x84, 0x4d, 0x7b, 0x0f, 0xf6, 0xdd, 0xab, 0x2f, 0x28, 0x66, 0x24, 0xc6, 0x8e,
+	0x4c, 0x34, 0xd1, 0xab, 0x9d, 0x6a, 0x6f, 0xfb, 0xec, 0xf8, 0x5f, 0xda, 0xe0, 0x43, 0x19, 0x29,
+	0x6d, 0xc9, 0x86, 0x5b, 0x51, 0xdb, 0x70, 0x2b, 0xee, 0xa2, 0xd9, 0x7f, 0x54, 0xd0, 0x90, 0x05,
+	0x74, 0xc1, 0xa1, 0x9f, 0x4f, 0x83, 0x23, 0xae, 0x73, 0x3e, 0xbb, 0x59, 0x19, 0x7b, 0x25, 0x92,
+	0x4a, 0xe7, 0x67, 0xa0, 0xc0, 0x03, 0xbf, 0x74, 0xb0, 0x28, 0x38, 0x0e, 0x79, 0xf1, 0x1c, 0x4f,
+	0xb4, 0xd0, 0x41, 0xa2, 0x87, 0xab, 0x83, 0xd2, 0x7b, 0x71, 0xba, 0xa9, 0x5f, 0x65, 0x8d, 0x1f,
+	0x28, 0xf0, 0x28, 0xdc, 0xe4, 0xb4, 0xeb, 0xa0, 0x9a, 0xcc, 0xa2, 0xee, 0x3b, 0xd0, 0xcc, 0x4d,
+	0x7d, 0xc4, 0x90, 0xf6, 0x12, 0x34, 0x0a, 0xe2, 0x78, 0xbf, 0x1e, 0x95, 0x9c, 0x29, 0x77, 0xa9,
+	0xf1, 0xe2, 0x41, 0x19, 0x62, 0xc3, 0x9b, 0x85, 0xa1, 0xde, 0x2e, 0x0c, 0xf5, 0xf7, 0xc2, 0x50,
+	0xaf, 0x97, 0x86, 0x72, 0xbb, 0x34, 0x94, 0x1f, 0x4b, 0x43, 0xf9, 0xf4, 0x22, 0x20, 0x6c, 0x34,
+	0x73, 0x4d, 0x8f, 0x46, 0x56, 0xf1, 0x89, 0x0a, 0xe8, 0xb3, 0x74, 0x99, 0x3e, 0x47, 0xd6, 0xda,
+	0xf3, 0xe5, 0x6e, 0x09, 0xc7, 0xf3, 0xbf, 0x03, 0x00, 0x45, 0xbb, 0x3c, 0x29, 0x44, 0x05, 0x00,
+	0x00,
 }
 
 func (m *DuplicateVoteEvidence) Marshal() (dAtA []byte, err error) {
@@ -324,6 +483,120 @@ func (m *DuplicateVoteEvidence) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *LightBlock) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LightBlock) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LightBlock) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.ValidatorSet != nil {
+		{
+			size, err := m.ValidatorSet.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintEvidence(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.SignedHeader != nil {
+		{
+			size, err := m.SignedHeader.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintEvidence(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *LightClientAttackEvidence) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LightClientAttackEvidence) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LightClientAttackEvidence) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	n6, err6 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Timestamp, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Timestamp):])
+	if err6 != nil {
+		return 0, err6
+	}
+	i -= n6
+	i = encodeVarintEvidence(dAtA, i, uint64(n6))
+	i--
+	dAtA[i] = 0x2a
+	if m.TotalVotingPower != 0 {
+		i = encodeVarintEvidence(dAtA, i, uint64(m.TotalVotingPower))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.ByzantineValidators) > 0 {
+		for iNdEx := len(m.ByzantineValidators) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.ByzantineValidators[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintEvidence(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.CommonHeight != 0 {
+		i = encodeVarintEvidence(dAtA, i, uint64(m.CommonHeight))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.ConflictingBlock != nil {
+		{
+			size, err := m.ConflictingBlock.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintEvidence(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *Evidence) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -377,6 +650,27 @@ func (m *Evidence_DuplicateVoteEvidence) MarshalToSizedBuffer(dAtA []byte) (int,
 	}
 	return len(dAtA) - i, nil
 }
+func (m *Evidence_LightClientAttackEvidence) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Evidence_LightClientAttackEvidence) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.LightClientAttackEvidence != nil {
+		{
+			size, err := m.LightClientAttackEvidence.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintEvidence(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	return len(dAtA) - i, nil
+}
 func (m *EvidenceData) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -450,6 +744,50 @@ func (m *DuplicateVoteEvidence) Size() (n int) {
 	return n
 }
 
+func (m *LightBlock) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.SignedHeader != nil {
+		l = m.SignedHeader.Size()
+		n += 1 + l + sovEvidence(uint64(l))
+	}
+	if m.ValidatorSet != nil {
+		l = m.ValidatorSet.Size()
+		n += 1 + l + sovEvidence(uint64(l))
+	}
+	return n
+}
+
+func (m *LightClientAttackEvidence) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ConflictingBlock != nil {
+		l = m.ConflictingBlock.Size()
+		n += 1 + l + sovEvidence(uint64(l))
+	}
+	if m.CommonHeight != 0 {
+		n += 1 + sovEvidence(uint64(m.CommonHeight))
+	}
+	if len(m.ByzantineValidators) > 0 {
+		for _, e := range m.ByzantineValidators {
+			l = e.Size()
+			n += 1 + l + sovEvidence(uint64(l))
+		}
+	}
+	if m.TotalVotingPower != 0 {
+		n += 1 + sovEvidence(uint64(m.TotalVotingPower))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Timestamp)
+	n += 1 + l + sovEvidence(uint64(l))
+	return n
+}
+
 func (m *Evidence) Size() (n int) {
 	if m == nil {
 		return 0
@@ -474,6 +812,18 @@ func (m *Evidence_DuplicateVoteEvidence) Size() (n int) {
 	}
 	return n
 }
+func (m *Evidence_LightClientAttackEvidence) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.LightClientAttackEvidence != nil {
+		l = m.LightClientAttackEvidence.Size()
+		n += 1 + l + sovEvidence(uint64(l))
+	}
+	return n
+}
 func (m *EvidenceData) Size() (n int) {
 	if m == nil {
 		return 0
@@ -688,7 +1038,7 @@ func (m *DuplicateVoteEvidence) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Evidence) Unmarshal(dAtA []byte) error {
+func (m *LightBlock) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -711,15 +1061,15 @@ func (m *Evidence) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Evidence: wiretype end group for non-group")
+			return fmt.Errorf("proto: LightBlock: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Evidence: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: LightBlock: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DuplicateVoteEvidence", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SignedHeader", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -746,11 +1096,359 @@ func (m *Evidence) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &DuplicateVoteEvidence{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.SignedHeader == nil {
+				m.SignedHeader = &SignedHeader{}
+			}
+			if err := m.SignedHeader.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
-			m.Sum = &Evidence_DuplicateVoteEvidence{v}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValidatorSet", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvidence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEvidence
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvidence
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ValidatorSet == nil {
+				m.ValidatorSet = &ValidatorSet{}
+			}
+			if err := m.ValidatorSet.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEvidence(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthEvidence
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LightClientAttackEvidence) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEvidence
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LightClientAttackEvidence: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LightClientAttackEvidence: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConflictingBlock", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvidence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEvidence
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvidence
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ConflictingBlock == nil {
+				m.ConflictingBlock = &LightBlock{}
+			}
+			if err := m.ConflictingBlock.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CommonHeight", wireType)
+			}
+			m.CommonHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvidence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CommonHeight |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ByzantineValidators", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvidence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEvidence
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvidence
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ByzantineValidators = append(m.ByzantineValidators, &Validator{})
+			if err := m.ByzantineValidators[len(m.ByzantineValidators)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalVotingPower", wireType)
+			}
+			m.TotalVotingPower = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvidence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalVotingPower |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvidence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEvidence
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvidence
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Timestamp, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipEvidence(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthEvidence
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Evidence) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowEvidence
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Evidence: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Evidence: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DuplicateVoteEvidence", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvidence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEvidence
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvidence
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &DuplicateVoteEvidence{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Evidence_DuplicateVoteEvidence{v}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LightClientAttackEvidence", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowEvidence
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthEvidence
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthEvidence
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &LightClientAttackEvidence{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Evidence_LightClientAttackEvidence{v}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -65,11 +65,23 @@ var (
 		"tx_getTransaction",
 		"tx_getTransactionReceipt",
 		"kai_gasPrice",
+		"kai_syncing",
 
 		// KAI only
 		"kai_validator",
 		"kai_validators",
 		"kai_getValidatorSet",
+		"kai_lightBlock",
+		"kai_proofBundle",
+		"kai_getTransactionProof",
+		"kai_getReceiptProof",
+		"consensus_dumpConsensusState",
+		"consensus_consensusState",
+		"consensus_proposerSchedule",
+		"consensus_validatorUptime",
+		"evidence_pendingEvidence",
+		"evidence_evidenceByHeight",
+		"evidence_broadcastEvidence",
 		"tx_pendingTransactions",
 		"debug_traceTransaction",
 		"debug_traceCall",
@@ -88,6 +100,7 @@ var (
 		"eth_getTransactionByHash",
 		"eth_getTransactionReceipt",
 		"eth_gasPrice",
+		"eth_syncing",
 
 		// ETH only
 		"eth_chainId",
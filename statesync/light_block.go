@@ -0,0 +1,107 @@
+package statesync
+
+import (
+	"errors"
+	"fmt"
+
+	ssproto "github.com/kardiachain/go-kardia/proto/kardiachain/statesync"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// lightBlock is a header with the commit for it and the validator sets needed
+// to verify it and the header of the next height.
+type lightBlock struct {
+	header         *types.Header
+	commit         *types.Commit
+	validators     *types.ValidatorSet
+	nextValidators *types.ValidatorSet
+}
+
+func (lb *lightBlock) toProto() (*ssproto.LightBlock, error) {
+	vals, err := lb.validators.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	nextVals, err := lb.nextValidators.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	return &ssproto.LightBlock{
+		SignedHeader: &kproto.SignedHeader{
+			Header: lb.header.ToProto(),
+			Commit: lb.commit.ToProto(),
+		},
+		ValidatorSet:     vals,
+		NextValidatorSet: nextVals,
+	}, nil
+}
+
+// toLightBlock returns the light block of the light client.
+func (lb *lightBlock) toLightBlock() *types.LightBlock {
+	return &types.LightBlock{SignedHeader: lb.signedHeader(), ValidatorSet: lb.validators}
+}
+
+func (lb *lightBlock) signedHeader() *types.SignedHeader {
+	return &types.SignedHeader{Header: lb.header, Commit: lb.commit}
+}
+
+func lightBlockFromProto(pb *ssproto.LightBlock) (*lightBlock, error) {
+	if pb.SignedHeader == nil || pb.SignedHeader.Header == nil || pb.SignedHeader.Commit == nil {
+		return nil, errors.New("missing signed header")
+	}
+	header, err := types.HeaderFromProto(pb.SignedHeader.Header)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := types.CommitFromProto(pb.SignedHeader.Commit)
+	if err != nil {
+		return nil, err
+	}
+	vals, err := types.ValidatorSetFromProto(pb.ValidatorSet)
+	if err != nil {
+		return nil, err
+	}
+	nextVals, err := types.ValidatorSetFromProto(pb.NextValidatorSet)
+	if err != nil {
+		return nil, err
+	}
+	return &lightBlock{header: &header, commit: commit, validators: vals, nextValidators: nextVals}, nil
+}
+
+// verify checks that the light block is self-consistent: the validator sets
+// are the ones the header commits to, and +2/3 of the validators signed the
+// header. Whether the validators can be trusted is up to the caller.
+func (lb *lightBlock) verify(chainID string) error {
+	if err := lb.header.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid header: %w", err)
+	}
+	if lb.commit.Height != lb.header.Height {
+		return fmt.Errorf("commit height %d doesn't match header height %d", lb.commit.Height, lb.header.Height)
+	}
+	if hash := lb.header.Hash(); !lb.commit.BlockID.Hash.Equal(hash) {
+		return fmt.Errorf("commit signs block %v, header hash is %v", lb.commit.BlockID.Hash, hash)
+	}
+	if hash := lb.validators.Hash(); !hash.Equal(lb.header.ValidatorsHash) {
+		return fmt.Errorf("validators hash %v doesn't match header %v", hash, lb.header.ValidatorsHash)
+	}
+	if hash := lb.nextValidators.Hash(); !hash.Equal(lb.header.NextValidatorsHash) {
+		return fmt.Errorf("next validators hash %v doesn't match header %v", hash, lb.header.NextValidatorsHash)
+	}
+	return lb.validators.VerifyCommit(chainID, lb.commit.BlockID, lb.header.Height, lb.commit)
+}
+
+// verifyAdjacent checks that the light block directly follows the trusted one.
+func (lb *lightBlock) verifyAdjacent(chainID string, trusted *lightBlock) error {
+	if lb.header.Height != trusted.header.Height+1 {
+		return fmt.Errorf("header #%d doesn't follow trusted header #%d", lb.header.Height, trusted.header.Height)
+	}
+	if hash := trusted.header.Hash(); !lb.header.LastBlockID.Hash.Equal(hash) {
+		return fmt.Errorf("header #%d links to %v, trusted header is %v", lb.header.Height, lb.header.LastBlockID.Hash, hash)
+	}
+	if !lb.header.ValidatorsHash.Equal(trusted.header.NextValidatorsHash) {
+		return fmt.Errorf("validators %v of header #%d aren't the ones trusted header committed to (%v)",
+			lb.header.ValidatorsHash, lb.header.Height, trusted.header.NextValidatorsHash)
+	}
+	return lb.verify(chainID)
+}
@@ -0,0 +1,80 @@
+package statesync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	ssproto "github.com/kardiachain/go-kardia/proto/kardiachain/statesync"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+const testChainID = "test-chain"
+
+// makeLightBlocks creates a chain of n light blocks signed by the same validators.
+func makeLightBlocks(t *testing.T, n int) []*lightBlock {
+	vals, privVals := types.RandValidatorSet(4, 10)
+	var (
+		blocks []*lightBlock
+		lastID types.BlockID
+	)
+	for height := uint64(1); height <= uint64(n); height++ {
+		header := &types.Header{
+			Height:             height,
+			Time:               time.Now().UTC(),
+			LastBlockID:        lastID,
+			ValidatorsHash:     vals.Hash(),
+			NextValidatorsHash: vals.Hash(),
+		}
+		blockID := types.BlockID{Hash: header.Hash(), PartsHeader: types.PartSetHeader{Total: 1, Hash: common.BytesToHash([]byte{byte(height)})}}
+		voteSet := types.NewVoteSet(testChainID, height, 0, kproto.PrecommitType, vals)
+		commit, err := types.MakeCommit(blockID, height, 0, voteSet, privVals, time.Now())
+		require.NoError(t, err)
+		blocks = append(blocks, &lightBlock{header: header, commit: commit, validators: vals, nextValidators: vals})
+		lastID = blockID
+	}
+	return blocks
+}
+
+func TestLightBlockVerify(t *testing.T) {
+	blocks := makeLightBlocks(t, 3)
+	require.NoError(t, blocks[0].verify(testChainID))
+	require.NoError(t, blocks[1].verifyAdjacent(testChainID, blocks[0]))
+	require.NoError(t, blocks[2].verifyAdjacent(testChainID, blocks[1]))
+
+	// Not adjacent.
+	assert.Error(t, blocks[2].verifyAdjacent(testChainID, blocks[0]))
+	// Signed for another chain.
+	assert.Error(t, blocks[1].verify("other-chain"))
+
+	// Signed by other validators than the header commits to.
+	otherVals, _ := types.RandValidatorSet(4, 10)
+	forged := *blocks[1]
+	forged.validators = otherVals
+	assert.Error(t, forged.verify(testChainID))
+
+	// Commit for another header.
+	forged = *blocks[1]
+	forged.commit = blocks[2].commit
+	assert.Error(t, forged.verify(testChainID))
+}
+
+func TestLightBlockProto(t *testing.T) {
+	lb := makeLightBlocks(t, 1)[0]
+	pb, err := lb.toProto()
+	require.NoError(t, err)
+	bz, err := EncodeMsg(&ssproto.LightBlockResponse{Id: 1, LightBlock: pb})
+	require.NoError(t, err)
+	msg, err := DecodeMsg(bz)
+	require.NoError(t, err)
+
+	decoded, err := lightBlockFromProto(msg.(*ssproto.LightBlockResponse).LightBlock)
+	require.NoError(t, err)
+	assert.Equal(t, lb.header.Hash(), decoded.header.Hash())
+	assert.Equal(t, lb.validators.Hash(), decoded.validators.Hash())
+	require.NoError(t, decoded.verify(testChainID))
+}
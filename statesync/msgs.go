@@ -0,0 +1,188 @@
+package statesync
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	ssproto "github.com/kardiachain/go-kardia/proto/kardiachain/statesync"
+	"github.com/kardiachain/go-kardia/trie"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+const (
+	// maxResponseBytes caps the data of a state range response, however large
+	// the requested size.
+	maxResponseBytes = 2 * 1024 * 1024
+
+	// maxCodesPerRequest is the maximum number of codes requested or served at once.
+	maxCodesPerRequest = 256
+
+	// maxStateMsgSize bounds the messages of the state channel: a response of
+	// maxResponseBytes plus its proofs, or maxCodesPerRequest codes.
+	maxStateMsgSize = 2*maxResponseBytes + maxCodesPerRequest*24*1024
+
+	// maxSnapshotMsgSize bounds the messages of the snapshot channel, the
+	// largest being a block response.
+	maxSnapshotMsgSize = types.MaxBlockSizeBytes + 1024
+)
+
+// EncodeMsg encodes a Protobuf message
+func EncodeMsg(pb proto.Message) ([]byte, error) {
+	msg := ssproto.Message{}
+
+	switch pb := pb.(type) {
+	case *ssproto.SnapshotsRequest:
+		msg.Sum = &ssproto.Message_SnapshotsRequest{SnapshotsRequest: pb}
+	case *ssproto.SnapshotsResponse:
+		msg.Sum = &ssproto.Message_SnapshotsResponse{SnapshotsResponse: pb}
+	case *ssproto.LightBlockRequest:
+		msg.Sum = &ssproto.Message_LightBlockRequest{LightBlockRequest: pb}
+	case *ssproto.LightBlockResponse:
+		msg.Sum = &ssproto.Message_LightBlockResponse{LightBlockResponse: pb}
+	case *ssproto.BlockRequest:
+		msg.Sum = &ssproto.Message_BlockRequest{BlockRequest: pb}
+	case *ssproto.BlockResponse:
+		msg.Sum = &ssproto.Message_BlockResponse{BlockResponse: pb}
+	case *ssproto.AccountRangeRequest:
+		msg.Sum = &ssproto.Message_AccountRangeRequest{AccountRangeRequest: pb}
+	case *ssproto.AccountRangeResponse:
+		msg.Sum = &ssproto.Message_AccountRangeResponse{AccountRangeResponse: pb}
+	case *ssproto.StorageRangeRequest:
+		msg.Sum = &ssproto.Message_StorageRangeRequest{StorageRangeRequest: pb}
+	case *ssproto.StorageRangeResponse:
+		msg.Sum = &ssproto.Message_StorageRangeResponse{StorageRangeResponse: pb}
+	case *ssproto.CodesRequest:
+		msg.Sum = &ssproto.Message_CodesRequest{CodesRequest: pb}
+	case *ssproto.CodesResponse:
+		msg.Sum = &ssproto.Message_CodesResponse{CodesResponse: pb}
+	default:
+		return nil, fmt.Errorf("unknown message type %T", pb)
+	}
+
+	bz, err := proto.Marshal(&msg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal %T: %w", pb, err)
+	}
+
+	return bz, nil
+}
+
+// DecodeMsg decodes a Protobuf message.
+func DecodeMsg(bz []byte) (proto.Message, error) {
+	pb := &ssproto.Message{}
+
+	err := proto.Unmarshal(bz, pb)
+	if err != nil {
+		return nil, err
+	}
+
+	switch msg := pb.Sum.(type) {
+	case *ssproto.Message_SnapshotsRequest:
+		return msg.SnapshotsRequest, nil
+	case *ssproto.Message_SnapshotsResponse:
+		return msg.SnapshotsResponse, nil
+	case *ssproto.Message_LightBlockRequest:
+		return msg.LightBlockRequest, nil
+	case *ssproto.Message_LightBlockResponse:
+		return msg.LightBlockResponse, nil
+	case *ssproto.Message_BlockRequest:
+		return msg.BlockRequest, nil
+	case *ssproto.Message_BlockResponse:
+		return msg.BlockResponse, nil
+	case *ssproto.Message_AccountRangeRequest:
+		return msg.AccountRangeRequest, nil
+	case *ssproto.Message_AccountRangeResponse:
+		return msg.AccountRangeResponse, nil
+	case *ssproto.Message_StorageRangeRequest:
+		return msg.StorageRangeRequest, nil
+	case *ssproto.Message_StorageRangeResponse:
+		return msg.StorageRangeResponse, nil
+	case *ssproto.Message_CodesRequest:
+		return msg.CodesRequest, nil
+	case *ssproto.Message_CodesResponse:
+		return msg.CodesResponse, nil
+	default:
+		return nil, fmt.Errorf("unknown message type %T", msg)
+	}
+}
+
+// ValidateMsg validates a message.
+func ValidateMsg(pb proto.Message) error {
+	if pb == nil {
+		return errors.New("message cannot be nil")
+	}
+
+	switch msg := pb.(type) {
+	case *ssproto.SnapshotsRequest:
+		return nil
+	case *ssproto.SnapshotsResponse:
+		if msg.Height < 1 {
+			return errors.New("invalid height")
+		}
+		if len(msg.Root) != common.HashLength {
+			return fmt.Errorf("invalid root length %d", len(msg.Root))
+		}
+	case *ssproto.LightBlockRequest:
+		if msg.Height < 1 {
+			return errors.New("invalid height")
+		}
+	case *ssproto.LightBlockResponse:
+		return nil
+	case *ssproto.BlockRequest:
+		if msg.Height < 1 {
+			return errors.New("invalid height")
+		}
+	case *ssproto.BlockResponse:
+		if msg.Block != nil {
+			if _, err := types.BlockFromProto(msg.Block, trie.NewStackTrie(nil)); err != nil {
+				return err
+			}
+		}
+	case *ssproto.AccountRangeRequest:
+		if err := validateHashes(msg.Root, msg.Origin, msg.Limit); err != nil {
+			return err
+		}
+	case *ssproto.AccountRangeResponse:
+		for _, account := range msg.Accounts {
+			if account == nil || len(account.Hash) != common.HashLength {
+				return errors.New("invalid account hash")
+			}
+		}
+	case *ssproto.StorageRangeRequest:
+		if err := validateHashes(msg.Root, msg.Account, msg.Origin, msg.Limit); err != nil {
+			return err
+		}
+	case *ssproto.StorageRangeResponse:
+		for _, slot := range msg.Slots {
+			if slot == nil || len(slot.Hash) != common.HashLength {
+				return errors.New("invalid storage slot hash")
+			}
+		}
+	case *ssproto.CodesRequest:
+		if len(msg.Hashes) > maxCodesPerRequest {
+			return fmt.Errorf("too many codes requested: %d", len(msg.Hashes))
+		}
+		if err := validateHashes(msg.Hashes...); err != nil {
+			return err
+		}
+	case *ssproto.CodesResponse:
+		if len(msg.Codes) > maxCodesPerRequest {
+			return fmt.Errorf("too many codes: %d", len(msg.Codes))
+		}
+	default:
+		return fmt.Errorf("unknown message type %T", msg)
+	}
+	return nil
+}
+
+func validateHashes(hashes ...[]byte) error {
+	for _, hash := range hashes {
+		if len(hash) != common.HashLength {
+			return fmt.Errorf("invalid hash length %d", len(hash))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package statesync
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	ssproto "github.com/kardiachain/go-kardia/proto/kardiachain/statesync"
+)
+
+func TestValidateMsg(t *testing.T) {
+	hash := common.HexToHash("0x01").Bytes()
+	testCases := []struct {
+		testName  string
+		msg       proto.Message
+		expectErr bool
+	}{
+		{"Snapshots request", &ssproto.SnapshotsRequest{}, false},
+		{"Valid snapshot", &ssproto.SnapshotsResponse{Height: 16, Root: hash}, false},
+		{"Snapshot at height 0", &ssproto.SnapshotsResponse{Height: 0, Root: hash}, true},
+		{"Snapshot with short root", &ssproto.SnapshotsResponse{Height: 16, Root: hash[1:]}, true},
+		{"Valid light block request", &ssproto.LightBlockRequest{Height: 1}, false},
+		{"Light block request at height 0", &ssproto.LightBlockRequest{Height: 0}, true},
+		{"Valid block request", &ssproto.BlockRequest{Height: 1}, false},
+		{"Block request at height 0", &ssproto.BlockRequest{Height: 0}, true},
+		{"Empty block response", &ssproto.BlockResponse{Id: 1}, false},
+		{"Valid account range request", &ssproto.AccountRangeRequest{Root: hash, Origin: hash, Limit: hash}, false},
+		{"Account range request without origin", &ssproto.AccountRangeRequest{Root: hash, Limit: hash}, true},
+		{"Valid account range", &ssproto.AccountRangeResponse{Accounts: []*ssproto.AccountData{{Hash: hash}}}, false},
+		{"Account range with short hash", &ssproto.AccountRangeResponse{Accounts: []*ssproto.AccountData{{Hash: hash[1:]}}}, true},
+		{"Valid storage range request", &ssproto.StorageRangeRequest{Root: hash, Account: hash, Origin: hash, Limit: hash}, false},
+		{"Storage range request without account", &ssproto.StorageRangeRequest{Root: hash, Origin: hash, Limit: hash}, true},
+		{"Storage range with short hash", &ssproto.StorageRangeResponse{Slots: []*ssproto.StorageData{{Hash: hash[1:]}}}, true},
+		{"Valid codes request", &ssproto.CodesRequest{Hashes: [][]byte{hash}}, false},
+		{"Codes request with short hash", &ssproto.CodesRequest{Hashes: [][]byte{hash[1:]}}, true},
+		{"Too many codes requested", &ssproto.CodesRequest{Hashes: make([][]byte, maxCodesPerRequest+1)}, true},
+		{"Too many codes", &ssproto.CodesResponse{Codes: make([][]byte, maxCodesPerRequest+1)}, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			assert.Equal(t, tc.expectErr, ValidateMsg(tc.msg) != nil, "Validate Basic had an unexpected result")
+		})
+	}
+}
+
+func TestEncodeDecodeMsg(t *testing.T) {
+	hash := common.HexToHash("0x01").Bytes()
+	msgs := []proto.Message{
+		&ssproto.SnapshotsRequest{},
+		&ssproto.SnapshotsResponse{Height: 16, Root: hash},
+		&ssproto.AccountRangeRequest{Id: 1, Root: hash, Origin: hash, Limit: hash, Bytes: 1024},
+		&ssproto.StorageRangeResponse{Id: 2, Slots: []*ssproto.StorageData{{Hash: hash, Body: []byte{1}}}, Proof: [][]byte{{2}}},
+		&ssproto.CodesResponse{Id: 3, Codes: [][]byte{{0x60}}},
+	}
+	for _, msg := range msgs {
+		bz, err := EncodeMsg(msg)
+		require.NoError(t, err)
+		decoded, err := DecodeMsg(bz)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(msg, decoded), "%T changed by encoding", msg)
+	}
+}
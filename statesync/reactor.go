@@ -0,0 +1,523 @@
+package statesync
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/kardiachain/go-kardia/configs"
+	"github.com/kardiachain/go-kardia/kai/kaidb"
+	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
+	"github.com/kardiachain/go-kardia/kai/rawdb"
+	"github.com/kardiachain/go-kardia/kai/state/cstate"
+	"github.com/kardiachain/go-kardia/kai/state/snapshot"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/p2p"
+	ksync "github.com/kardiachain/go-kardia/lib/sync"
+	ssproto "github.com/kardiachain/go-kardia/proto/kardiachain/statesync"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+	"github.com/kardiachain/go-kardia/trie"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+const (
+	// SnapshotChannel carries snapshot discovery, light blocks and blocks.
+	SnapshotChannel = byte(0x60)
+	// StateChannel carries account ranges, storage ranges and codes.
+	StateChannel = byte(0x61)
+
+	// snapshotInterval is the distance between the heights advertised as
+	// snapshots, so that peers agree on the same few targets.
+	snapshotInterval = 16
+
+	// snapshotWindow is how far below the head snapshots are advertised. The
+	// snapshot tree keeps 128 diff layers, older states can't be iterated.
+	snapshotWindow = 128
+
+	// maxServedRequests is the number of state range and code requests served
+	// to a peer per second, as they iterate the snapshot and prove the ranges.
+	// The requests above it are answered as if the state was unavailable.
+	maxServedRequests = 20
+)
+
+type blockStore interface {
+	Height() uint64
+	LoadBlock(height uint64) *types.Block
+	LoadBlockMeta(height uint64) *types.BlockMeta
+	LoadBlockCommit(height uint64) *types.Commit
+	LoadSeenCommit(height uint64) *types.Commit
+	WriteSyncedHead(block *types.Block, blockParts *types.PartSet, seenCommit *types.Commit, root common.Hash) error
+	// ConsensusParams returns the consensus params set through governance
+	// in the state committed by the block of header.
+	ConsensusParams(header *types.Header) (*kproto.ConsensusParams, error)
+}
+
+type stateSource interface {
+	DB() kaidb.Database
+	Snapshots() *snapshot.Tree
+	TrieDB() *trie.Database
+}
+
+type fastSyncReactor interface {
+	// for when we switch from state sync to fast syncing the blocks above the
+	// restored state
+	SwitchToFastSync(state cstate.LatestBlockState) error
+}
+
+// Reactor serves the snapshots of recent states to peers and, on an empty
+// node with state sync enabled, restores the state from them.
+type Reactor struct {
+	p2p.BaseReactor
+
+	config       *configs.StateSyncConfig
+	initialState cstate.LatestBlockState
+	store        blockStore
+	chain        stateSource
+	stateStore   cstate.Store
+
+	mtx    ksync.RWMutex
+	syncer *syncer // non-nil while state syncing
+
+	servedMtx sync.Mutex
+	served    map[p2p.ID]*servedRequests
+}
+
+// servedRequests counts the requests served to a peer since start.
+type servedRequests struct {
+	start time.Time
+	count int
+}
+
+// NewReactor returns a new state sync reactor. The state is the one the node
+// started from, used as trust anchor when no trusted header is configured and
+// to fall back to fast sync if state sync fails.
+func NewReactor(config *configs.StateSyncConfig, state cstate.LatestBlockState, store blockStore,
+	chain stateSource, stateStore cstate.Store) *Reactor {
+	r := &Reactor{
+		config:       config,
+		initialState: state.Copy(),
+		store:        store,
+		chain:        chain,
+		stateStore:   stateStore,
+	}
+	r.BaseReactor = *p2p.NewBaseReactor("StateSync", r)
+	return r
+}
+
+// OnStart implements Service by starting the state sync if enabled.
+func (r *Reactor) OnStart() error {
+	if !r.config.Enable {
+		return nil
+	}
+	r.mtx.Lock()
+	r.syncer = newSyncer(r.config, r.Logger, r.initialState, r.store, r.chain, r.stateStore, r.Switch)
+	r.mtx.Unlock()
+	go r.sync()
+	return nil
+}
+
+// OnStop implements Service by aborting a running state sync.
+func (r *Reactor) OnStop() {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	if r.syncer != nil {
+		r.syncer.stop()
+	}
+}
+
+// sync restores the state, then hands over to fast sync from its height. If no
+// snapshot could be restored, fast sync starts from the initial state instead.
+func (r *Reactor) sync() {
+	r.mtx.RLock()
+	syncer := r.syncer
+	r.mtx.RUnlock()
+
+	state, err := syncer.sync()
+
+	r.mtx.Lock()
+	r.syncer = nil
+	r.mtx.Unlock()
+	if errors.Is(err, errAborted) {
+		return
+	}
+	if err != nil {
+		r.Logger.Error("State sync failed, falling back to fast sync", "err", err)
+		state = r.initialState
+	} else {
+		r.Logger.Info("State sync complete, switching to fast sync", "height", state.LastBlockHeight, "appHash", state.AppHash)
+	}
+	bcR, ok := r.Switch.Reactor("BLOCKCHAIN").(fastSyncReactor)
+	if !ok {
+		r.Logger.Error("Failed to switch to fast sync reactor")
+		return
+	}
+	if err := bcR.SwitchToFastSync(state); err != nil {
+		r.Logger.Error("Failed to start fast sync", "err", err)
+	}
+}
+
+// GetChannels implements Reactor
+func (r *Reactor) GetChannels() []*p2p.ChannelDescriptor {
+	return []*p2p.ChannelDescriptor{
+		{
+			ID:                  SnapshotChannel,
+			Name:                "statesync_snapshot",
+			Priority:            5,
+			SendQueueCapacity:   10,
+			RecvBufferCapacity:  4096,
+			RecvMessageCapacity: maxSnapshotMsgSize,
+		},
+		{
+			ID:                  StateChannel,
+			Name:                "statesync_state",
+			Priority:            3,
+			SendQueueCapacity:   10,
+			RecvBufferCapacity:  4096,
+			RecvMessageCapacity: maxStateMsgSize,
+		},
+	}
+}
+
+// AddPeer implements Reactor by asking the peer for its snapshots while syncing.
+func (r *Reactor) AddPeer(peer p2p.Peer) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	if r.syncer != nil {
+		r.send(peer, SnapshotChannel, &ssproto.SnapshotsRequest{})
+	}
+}
+
+// RemovePeer implements Reactor interface.
+func (r *Reactor) RemovePeer(peer p2p.Peer, reason interface{}) {
+	r.servedMtx.Lock()
+	delete(r.served, peer.ID())
+	r.servedMtx.Unlock()
+
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	if r.syncer != nil {
+		r.syncer.removePeer(peer.ID())
+	}
+}
+
+// Receive implements Reactor by serving requests and passing the responses to
+// the running state sync.
+func (r *Reactor) Receive(chID byte, src p2p.Peer, msgBytes []byte) {
+	msg, err := DecodeMsg(msgBytes)
+	if err != nil {
+		r.Logger.Error("Error decoding message", "src", src, "chId", chID, "err", err)
+		r.Switch.MarkPeerAsBad(src, err)
+		return
+	}
+	if err = ValidateMsg(msg); err != nil {
+		r.Logger.Error("Peer sent us invalid msg", "peer", src, "msg", msg, "err", err)
+		r.Switch.MarkPeerAsBad(src, err)
+		return
+	}
+	r.Logger.Trace("Receive", "src", src.ID(), "chID", chID, "msg", msg)
+
+	switch msg := msg.(type) {
+	case *ssproto.SnapshotsRequest:
+		for _, snap := range r.recentSnapshots() {
+			r.send(src, SnapshotChannel, snap)
+		}
+	case *ssproto.LightBlockRequest:
+		resp := &ssproto.LightBlockResponse{Id: msg.Id}
+		if lb := r.loadLightBlock(msg.Height); lb != nil {
+			if resp.LightBlock, err = lb.toProto(); err != nil {
+				r.Logger.Error("Failed to encode light block", "height", msg.Height, "err", err)
+				resp.LightBlock = nil
+			}
+		}
+		r.send(src, SnapshotChannel, resp)
+	case *ssproto.BlockRequest:
+		resp := &ssproto.BlockResponse{Id: msg.Id}
+		if block := r.store.LoadBlock(msg.Height); block != nil {
+			if resp.Block, err = block.ToProto(); err != nil {
+				r.Logger.Error("Failed to encode block", "height", msg.Height, "err", err)
+				resp.Block = nil
+			}
+		}
+		r.send(src, SnapshotChannel, resp)
+	case *ssproto.AccountRangeRequest:
+		if !r.allowRequest(src.ID(), time.Now()) {
+			r.send(src, StateChannel, &ssproto.AccountRangeResponse{Id: msg.Id})
+			break
+		}
+		r.send(src, StateChannel, r.accountRange(msg))
+	case *ssproto.StorageRangeRequest:
+		if !r.allowRequest(src.ID(), time.Now()) {
+			r.send(src, StateChannel, &ssproto.StorageRangeResponse{Id: msg.Id})
+			break
+		}
+		r.send(src, StateChannel, r.storageRange(msg))
+	case *ssproto.CodesRequest:
+		if !r.allowRequest(src.ID(), time.Now()) {
+			r.send(src, StateChannel, &ssproto.CodesResponse{Id: msg.Id})
+			break
+		}
+		r.send(src, StateChannel, r.codes(msg))
+
+	case *ssproto.SnapshotsResponse:
+		r.mtx.RLock()
+		if r.syncer != nil {
+			r.syncer.addSnapshot(src.ID(), msg.Height, common.BytesToHash(msg.Root))
+		}
+		r.mtx.RUnlock()
+	case *ssproto.LightBlockResponse, *ssproto.BlockResponse, *ssproto.AccountRangeResponse,
+		*ssproto.StorageRangeResponse, *ssproto.CodesResponse:
+		r.mtx.RLock()
+		if r.syncer != nil {
+			r.syncer.deliver(src.ID(), msg)
+		}
+		r.mtx.RUnlock()
+	}
+}
+
+// allowRequest returns whether a request of the peer can be served, at most
+// maxServedRequests per second.
+func (r *Reactor) allowRequest(peer p2p.ID, now time.Time) bool {
+	r.servedMtx.Lock()
+	defer r.servedMtx.Unlock()
+	if r.served == nil {
+		r.served = make(map[p2p.ID]*servedRequests)
+	}
+	served := r.served[peer]
+	if served == nil || now.Sub(served.start) >= time.Second {
+		served = &servedRequests{start: now}
+		r.served[peer] = served
+	}
+	if served.count >= maxServedRequests {
+		r.Logger.Debug("Too many state sync requests", "peer", peer)
+		return false
+	}
+	served.count++
+	return true
+}
+
+func (r *Reactor) send(peer p2p.Peer, chID byte, msg proto.Message) {
+	bz, err := EncodeMsg(msg)
+	if err != nil {
+		r.Logger.Error("Failed to encode message", "msg", msg, "err", err)
+		return
+	}
+	if !peer.TrySend(chID, bz) {
+		r.Logger.Debug("Failed to send message, peer queue full", "peer", peer.ID(), "chID", chID)
+	}
+}
+
+// recentSnapshots returns the snapshots this node can serve: heights at the
+// snapshot interval within the window below the head, whose state is in the
+// snapshot tree. The head itself is excluded since the header committing to
+// its state doesn't exist yet.
+func (r *Reactor) recentSnapshots() []*ssproto.SnapshotsResponse {
+	snaps := r.chain.Snapshots()
+	head := r.store.Height()
+	if snaps == nil || head < 2 {
+		return nil
+	}
+	var res []*ssproto.SnapshotsResponse
+	for height := (head - 1) / snapshotInterval * snapshotInterval; height > 0 && head-height <= snapshotWindow; height -= snapshotInterval {
+		root := rawdb.ReadAppHash(r.chain.DB(), height)
+		if root.IsZero() || snaps.Snapshot(root) == nil {
+			continue
+		}
+		res = append(res, &ssproto.SnapshotsResponse{Height: height, Root: root.Bytes()})
+	}
+	return res
+}
+
+// loadLightBlock assembles the light block at height, or returns nil if the
+// header, its commit or the validator sets are not available.
+func (r *Reactor) loadLightBlock(height uint64) *lightBlock {
+	meta := r.store.LoadBlockMeta(height)
+	if meta == nil {
+		return nil
+	}
+	commit := r.store.LoadBlockCommit(height)
+	if commit == nil {
+		if commit = r.store.LoadSeenCommit(height); commit == nil {
+			return nil
+		}
+	}
+	cs := rawdb.ReadConsensusStateHeight(r.chain.DB(), height)
+	if cs == nil {
+		return nil
+	}
+	vals := r.loadValidators(cs.LastValidatorsInfoHash)
+	nextVals := r.loadValidators(cs.ValidatorsInfoHash)
+	if vals == nil || nextVals == nil {
+		return nil
+	}
+	return &lightBlock{header: meta.Header, commit: commit, validators: vals, nextValidators: nextVals}
+}
+
+func (r *Reactor) loadValidators(hash []byte) *types.ValidatorSet {
+	info := rawdb.ReadConsensusValidatorsInfo(r.chain.DB(), common.BytesToHash(hash))
+	if info == nil {
+		return nil
+	}
+	vals, err := types.ValidatorSetFromProto(info.ValidatorSet)
+	if err != nil {
+		return nil
+	}
+	return vals
+}
+
+// responseBytes returns the soft size limit of a range response.
+func responseBytes(requested uint64) uint64 {
+	if requested == 0 || requested > maxResponseBytes {
+		return maxResponseBytes
+	}
+	return requested
+}
+
+// accountRange serves the accounts of the state root from origin, up to limit
+// or the requested size, in full RLP format. Unless the range is the whole
+// trie, it comes with the proofs of origin and of the last account. An empty
+// response without proof means the state is not available.
+func (r *Reactor) accountRange(req *ssproto.AccountRangeRequest) *ssproto.AccountRangeResponse {
+	resp := &ssproto.AccountRangeResponse{Id: req.Id}
+	snaps := r.chain.Snapshots()
+	if snaps == nil {
+		return resp
+	}
+	root, origin, limit := common.BytesToHash(req.Root), common.BytesToHash(req.Origin), common.BytesToHash(req.Limit)
+	it, err := snaps.AccountIterator(root, origin)
+	if err != nil {
+		return resp
+	}
+	defer it.Release()
+
+	var (
+		size, max = uint64(0), responseBytes(req.Bytes)
+		last      common.Hash
+		more      bool
+	)
+	for it.Next() {
+		if len(resp.Accounts) > 0 && (bytes.Compare(last[:], limit[:]) >= 0 || size > max) {
+			more = true
+			break
+		}
+		hash := it.Hash()
+		data, err := types.FullAccountRLP(it.Account())
+		if err != nil {
+			return &ssproto.AccountRangeResponse{Id: req.Id}
+		}
+		resp.Accounts = append(resp.Accounts, &ssproto.AccountData{Hash: hash.Bytes(), Body: data})
+		size += uint64(common.HashLength + len(data))
+		last = hash
+	}
+	if it.Error() != nil {
+		return &ssproto.AccountRangeResponse{Id: req.Id}
+	}
+	if origin == (common.Hash{}) && !more {
+		return resp
+	}
+	proof, err := r.prove(trie.StateTrieID(root), origin, last, len(resp.Accounts) > 0)
+	if err != nil {
+		r.Logger.Debug("Failed to prove account range", "root", root, "err", err)
+		return &ssproto.AccountRangeResponse{Id: req.Id}
+	}
+	resp.Proof = proof
+	return resp
+}
+
+// storageRange serves the storage slots of an account, like accountRange.
+func (r *Reactor) storageRange(req *ssproto.StorageRangeRequest) *ssproto.StorageRangeResponse {
+	resp := &ssproto.StorageRangeResponse{Id: req.Id}
+	snaps := r.chain.Snapshots()
+	if snaps == nil {
+		return resp
+	}
+	root, account := common.BytesToHash(req.Root), common.BytesToHash(req.Account)
+	origin, limit := common.BytesToHash(req.Origin), common.BytesToHash(req.Limit)
+	snap := snaps.Snapshot(root)
+	if snap == nil {
+		return resp
+	}
+	acc, err := snap.Account(account)
+	if err != nil || acc == nil || len(acc.Root) == 0 {
+		return resp
+	}
+	it, err := snaps.StorageIterator(root, account, origin)
+	if err != nil {
+		return resp
+	}
+	defer it.Release()
+
+	var (
+		size, max = uint64(0), responseBytes(req.Bytes)
+		last      common.Hash
+		more      bool
+	)
+	for it.Next() {
+		if len(resp.Slots) > 0 && (bytes.Compare(last[:], limit[:]) >= 0 || size > max) {
+			more = true
+			break
+		}
+		hash := it.Hash()
+		slot := common.CopyBytes(it.Slot())
+		resp.Slots = append(resp.Slots, &ssproto.StorageData{Hash: hash.Bytes(), Body: slot})
+		size += uint64(common.HashLength + len(slot))
+		last = hash
+	}
+	if it.Error() != nil {
+		return &ssproto.StorageRangeResponse{Id: req.Id}
+	}
+	if origin == (common.Hash{}) && !more {
+		return resp
+	}
+	id := trie.StorageTrieID(root, account, common.BytesToHash(acc.Root))
+	proof, err := r.prove(id, origin, last, len(resp.Slots) > 0)
+	if err != nil {
+		r.Logger.Debug("Failed to prove storage range", "root", root, "account", account, "err", err)
+		return &ssproto.StorageRangeResponse{Id: req.Id}
+	}
+	resp.Proof = proof
+	return resp
+}
+
+// prove returns the nodes proving origin and, if any, the last key of a range.
+func (r *Reactor) prove(id *trie.ID, origin, last common.Hash, hasLast bool) ([][]byte, error) {
+	tr, err := trie.New(id, r.chain.TrieDB())
+	if err != nil {
+		return nil, err
+	}
+	proofDb := memorydb.New()
+	if err := tr.Prove(origin[:], 0, proofDb); err != nil {
+		return nil, err
+	}
+	if hasLast {
+		if err := tr.Prove(last[:], 0, proofDb); err != nil {
+			return nil, err
+		}
+	}
+	var nodes [][]byte
+	it := proofDb.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		nodes = append(nodes, common.CopyBytes(it.Value()))
+	}
+	return nodes, nil
+}
+
+// codes serves the requested contract codes, skipping the unknown ones.
+func (r *Reactor) codes(req *ssproto.CodesRequest) *ssproto.CodesResponse {
+	resp := &ssproto.CodesResponse{Id: req.Id}
+	var size uint64
+	for _, hash := range req.Hashes {
+		code := rawdb.ReadCode(r.chain.DB(), common.BytesToHash(hash))
+		if len(code) == 0 {
+			continue
+		}
+		resp.Codes = append(resp.Codes, code)
+		if size += uint64(len(code)); size > maxResponseBytes {
+			break
+		}
+	}
+	return resp
+}
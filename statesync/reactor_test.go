@@ -0,0 +1,187 @@
+package statesync
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/kai/kaidb"
+	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
+	"github.com/kardiachain/go-kardia/kai/state"
+	"github.com/kardiachain/go-kardia/kai/state/snapshot"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/crypto"
+	"github.com/kardiachain/go-kardia/lib/p2p"
+	ssproto "github.com/kardiachain/go-kardia/proto/kardiachain/statesync"
+	"github.com/kardiachain/go-kardia/trie"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+type testChain struct {
+	db     kaidb.Database
+	snaps  *snapshot.Tree
+	triedb *trie.Database
+}
+
+func (c *testChain) DB() kaidb.Database        { return c.db }
+func (c *testChain) Snapshots() *snapshot.Tree { return c.snaps }
+func (c *testChain) TrieDB() *trie.Database    { return c.triedb }
+
+// newTestChain creates a state of accounts, some with code and storage, and
+// its snapshot.
+func newTestChain(t *testing.T) (*testChain, common.Hash) {
+	db := memorydb.New()
+	sdb := state.NewDatabase(db)
+	statedb, err := state.New(common.Hash{}, sdb, nil)
+	require.NoError(t, err)
+	for i := 0; i < 300; i++ {
+		addr := common.BigToAddress(big.NewInt(int64(i + 1)))
+		statedb.AddBalance(addr, big.NewInt(int64(i+1)))
+		statedb.SetNonce(addr, uint64(i))
+		if i%50 == 0 {
+			statedb.SetCode(addr, []byte{byte(i), 0x60, 0x00})
+			for j := 0; j < 20*(i/50+1); j++ {
+				statedb.SetState(addr, common.BigToHash(big.NewInt(int64(j))), common.BigToHash(big.NewInt(int64(j+1))))
+			}
+		}
+	}
+	root, err := statedb.Commit(true)
+	require.NoError(t, err)
+	require.NoError(t, sdb.TrieDB().Commit(root, false))
+
+	snaps, err := snapshot.New(snapshot.Config{CacheSize: 16}, db, sdb.TrieDB(), root)
+	require.NoError(t, err)
+	return &testChain{db: db, snaps: snaps, triedb: sdb.TrieDB()}, root
+}
+
+func newTestReactor(chain stateSource) *Reactor {
+	r := &Reactor{chain: chain}
+	r.BaseReactor = *p2p.NewBaseReactor("StateSync", r)
+	return r
+}
+
+// TestServeAndRestoreState restores a state from small verified ranges served
+// by the reactor.
+func TestServeAndRestoreState(t *testing.T) {
+	chain, root := newTestChain(t)
+	r := newTestReactor(chain)
+
+	db := memorydb.New()
+	imp := snapshot.NewStateImporter(db, trie.NewDatabase(db).Scheme())
+	var (
+		origin   common.Hash
+		requests int
+	)
+	for {
+		resp := r.accountRange(&ssproto.AccountRangeRequest{Root: root.Bytes(), Origin: origin.Bytes(), Limit: maxHash.Bytes(), Bytes: 1024})
+		requests++
+		var keys, values [][]byte
+		for _, account := range resp.Accounts {
+			keys, values = append(keys, account.Hash), append(values, account.Body)
+		}
+		more, err := verifyRange(root, origin, keys, values, resp.Proof)
+		require.NoError(t, err)
+
+		for i, key := range keys {
+			hash := common.BytesToHash(key)
+			require.NoError(t, imp.AddAccount(hash, values[i]))
+			account, err := types.FullAccount(values[i])
+			require.NoError(t, err)
+			if codeHash := common.BytesToHash(account.CodeHash); codeHash != types.EmptyCodeHash {
+				codes := r.codes(&ssproto.CodesRequest{Hashes: [][]byte{codeHash.Bytes()}})
+				require.Len(t, codes.Codes, 1)
+				require.Equal(t, codeHash, crypto.Keccak256Hash(codes.Codes[0]))
+				imp.AddCode(codes.Codes[0])
+			}
+			if account.Root != types.EmptyRootHash {
+				var slotOrigin common.Hash
+				for {
+					resp := r.storageRange(&ssproto.StorageRangeRequest{Root: root.Bytes(), Account: key,
+						Origin: slotOrigin.Bytes(), Limit: maxHash.Bytes(), Bytes: 512})
+					var slotKeys, slotValues [][]byte
+					for _, slot := range resp.Slots {
+						slotKeys, slotValues = append(slotKeys, slot.Hash), append(slotValues, slot.Body)
+					}
+					more, err := verifyRange(account.Root, slotOrigin, slotKeys, slotValues, resp.Proof)
+					require.NoError(t, err)
+					for j, slot := range slotKeys {
+						require.NoError(t, imp.AddStorage(hash, common.BytesToHash(slot), slotValues[j]))
+					}
+					if !more {
+						break
+					}
+					slotOrigin, _ = incHash(common.BytesToHash(slotKeys[len(slotKeys)-1]))
+				}
+			}
+		}
+		if !more {
+			break
+		}
+		origin, _ = incHash(common.BytesToHash(keys[len(keys)-1]))
+	}
+	require.Greater(t, requests, 1, "ranges should be split")
+	require.NoError(t, imp.Finish(root))
+}
+
+func TestVerifyRangeRejectsForgedRange(t *testing.T) {
+	chain, root := newTestChain(t)
+	r := newTestReactor(chain)
+
+	origin := common.HexToHash("0x10")
+	resp := r.accountRange(&ssproto.AccountRangeRequest{Root: root.Bytes(), Origin: origin.Bytes(), Limit: maxHash.Bytes(), Bytes: 1024})
+	require.True(t, len(resp.Accounts) > 2)
+	require.NotEmpty(t, resp.Proof)
+
+	var keys, values [][]byte
+	for _, account := range resp.Accounts {
+		keys, values = append(keys, account.Hash), append(values, account.Body)
+	}
+	_, err := verifyRange(root, origin, keys, values, resp.Proof)
+	require.NoError(t, err)
+
+	// An account left out of the range.
+	_, err = verifyRange(root, origin, append(append([][]byte{}, keys[:1]...), keys[2:]...),
+		append(append([][]byte{}, values[:1]...), values[2:]...), resp.Proof)
+	assert.Error(t, err)
+
+	// A modified account.
+	forged := append([][]byte{}, values...)
+	forged[1] = values[0]
+	_, err = verifyRange(root, origin, keys, forged, resp.Proof)
+	assert.Error(t, err)
+
+	// A partial range without proof.
+	_, err = verifyRange(root, origin, keys, values, nil)
+	assert.Error(t, err)
+}
+
+func TestServeUnknownState(t *testing.T) {
+	chain, _ := newTestChain(t)
+	r := newTestReactor(chain)
+
+	unknown := common.HexToHash("0x01")
+	accounts := r.accountRange(&ssproto.AccountRangeRequest{Root: unknown.Bytes(), Origin: common.Hash{}.Bytes(), Limit: maxHash.Bytes()})
+	assert.Empty(t, accounts.Accounts)
+	assert.Empty(t, accounts.Proof)
+
+	codes := r.codes(&ssproto.CodesRequest{Hashes: [][]byte{unknown.Bytes()}})
+	assert.Empty(t, codes.Codes)
+
+	// An empty response without proof doesn't verify against a non-empty root.
+	_, err := verifyRange(unknown, common.Hash{}, nil, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestServeRequestsLimit(t *testing.T) {
+	r := newTestReactor(nil)
+	now := time.Now()
+	for i := 0; i < maxServedRequests; i++ {
+		require.True(t, r.allowRequest("peer", now))
+	}
+	assert.False(t, r.allowRequest("peer", now.Add(500*time.Millisecond)))
+	assert.True(t, r.allowRequest("other", now), "limited per peer")
+	assert.True(t, r.allowRequest("peer", now.Add(time.Second)))
+}
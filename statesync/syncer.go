@@ -0,0 +1,850 @@
+package statesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/kardiachain/go-kardia/configs"
+	"github.com/kardiachain/go-kardia/kai/kaidb"
+	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
+	"github.com/kardiachain/go-kardia/kai/state/cstate"
+	"github.com/kardiachain/go-kardia/kai/state/snapshot"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/crypto"
+	"github.com/kardiachain/go-kardia/lib/log"
+	"github.com/kardiachain/go-kardia/lib/p2p"
+	"github.com/kardiachain/go-kardia/light"
+	ssproto "github.com/kardiachain/go-kardia/proto/kardiachain/statesync"
+	"github.com/kardiachain/go-kardia/trie"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+const (
+	// discoveryRounds is the number of discovery rounds without any usable
+	// snapshot after which state sync gives up.
+	discoveryRounds = 4
+
+	// maxSnapshotsPerPeer bounds the snapshots recorded for a single peer.
+	maxSnapshotsPerPeer = 2 * snapshotWindow / snapshotInterval
+
+	// lightBlockBatch is the number of light blocks fetched concurrently while
+	// walking from the trusted header to the snapshot.
+	lightBlockBatch = 16
+
+	// maxRequestAttempts is the number of peers a request is sent to before
+	// the snapshot is given up.
+	maxRequestAttempts = 8
+
+	// maxRestoreAttempts is the number of failed snapshot restores after which
+	// state sync gives up, and the node fast syncs from its initial state.
+	maxRestoreAttempts = 6
+
+	// maxClockDrift is how far in the future the headers skipped to may be.
+	maxClockDrift = 10 * time.Second
+)
+
+var (
+	errAborted     = errors.New("state sync aborted")
+	errNoPeers     = errors.New("no peers left serving the snapshot")
+	errNoResponse  = errors.New("no valid response")
+	errPeerGone    = errors.New("peer disconnected")
+	errTimeout     = errors.New("request timed out")
+	errUnavailable = errors.New("data unavailable")
+)
+
+// snapshotKey identifies a snapshot advertised by peers.
+type snapshotKey struct {
+	height uint64
+	root   common.Hash
+}
+
+// request is a request waiting for the response of a peer.
+type request struct {
+	peer p2p.ID
+	resp chan proto.Message // receives nil if the peer disconnects
+}
+
+// restoreProgress is where the restore of a state root stopped, so that it
+// resumes from the last verified range if the snapshot is restored again.
+type restoreProgress struct {
+	root common.Hash
+	imp  *snapshot.StateImporter
+
+	origin common.Hash   // origin of the next account range
+	done   bool          // whether the last account range was fetched
+	hashes []common.Hash // account range being imported
+	bodies [][]byte
+	next   int // index of the next account of the range to import
+
+	added         bool        // whether the next account was added to the importer
+	storageOrigin common.Hash // origin of the next storage range of the added account
+
+	codes map[common.Hash]struct{}
+	queue []common.Hash // codes not fetched yet
+
+	accounts, slots int
+}
+
+// syncer restores the state at a snapshot advertised by peers. The snapshot
+// is only trusted once the header committing to its root has been verified
+// from the trusted header, or the genesis validators, on.
+type syncer struct {
+	config       *configs.StateSyncConfig
+	logger       log.Logger
+	initialState cstate.LatestBlockState
+	store        blockStore
+	chain        stateSource
+	stateStore   cstate.Store
+	sw           *p2p.Switch
+
+	mtx       sync.Mutex
+	snapshots map[snapshotKey]map[p2p.ID]struct{}
+	counts    map[p2p.ID]int // number of snapshots recorded per peer
+	rejected  map[snapshotKey]struct{}
+	pending   map[uint64]*request
+	nextID    uint64
+	turn      int
+
+	progress *restoreProgress // interrupted restore, only used by sync
+
+	quit     chan struct{}
+	stopOnce sync.Once
+}
+
+func newSyncer(config *configs.StateSyncConfig, logger log.Logger, state cstate.LatestBlockState,
+	store blockStore, chain stateSource, stateStore cstate.Store, sw *p2p.Switch) *syncer {
+	return &syncer{
+		config:       config,
+		logger:       logger,
+		initialState: state,
+		store:        store,
+		chain:        chain,
+		stateStore:   stateStore,
+		sw:           sw,
+		snapshots:    make(map[snapshotKey]map[p2p.ID]struct{}),
+		counts:       make(map[p2p.ID]int),
+		rejected:     make(map[snapshotKey]struct{}),
+		pending:      make(map[uint64]*request),
+		quit:         make(chan struct{}),
+	}
+}
+
+func (s *syncer) stop() {
+	s.stopOnce.Do(func() { close(s.quit) })
+}
+
+// addSnapshot records a snapshot advertised by a peer.
+func (s *syncer) addSnapshot(peer p2p.ID, height uint64, root common.Hash) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	key := snapshotKey{height, root}
+	if _, ok := s.rejected[key]; ok || s.counts[peer] >= maxSnapshotsPerPeer {
+		return
+	}
+	peers := s.snapshots[key]
+	if peers == nil {
+		peers = make(map[p2p.ID]struct{})
+		s.snapshots[key] = peers
+	}
+	if _, ok := peers[peer]; !ok {
+		peers[peer] = struct{}{}
+		s.counts[peer]++
+	}
+}
+
+// removePeer forgets the snapshots of a disconnected peer and fails its
+// pending requests.
+func (s *syncer) removePeer(peer p2p.ID) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for _, peers := range s.snapshots {
+		delete(peers, peer)
+	}
+	delete(s.counts, peer)
+	for id, req := range s.pending {
+		if req.peer == peer {
+			delete(s.pending, id)
+			req.resp <- nil
+		}
+	}
+}
+
+// dropSnapshotPeer stops asking a peer for the data of a snapshot, after it
+// failed to serve it.
+func (s *syncer) dropSnapshotPeer(key snapshotKey, peer p2p.ID) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if peers := s.snapshots[key]; peers != nil {
+		if _, ok := peers[peer]; ok {
+			delete(peers, peer)
+			s.counts[peer]--
+		}
+	}
+}
+
+// deliver passes a response to the request waiting for it. Unsolicited
+// responses are dropped.
+func (s *syncer) deliver(peer p2p.ID, msg proto.Message) {
+	resp, ok := msg.(interface{ GetId() uint64 })
+	if !ok {
+		return
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	req := s.pending[resp.GetId()]
+	if req == nil || req.peer != peer {
+		s.logger.Debug("Unsolicited state sync response", "peer", peer, "id", resp.GetId())
+		return
+	}
+	delete(s.pending, resp.GetId())
+	req.resp <- msg
+}
+
+// request sends the message built for a new request id to the peer and waits
+// for the response.
+func (s *syncer) request(peer p2p.ID, chID byte, build func(id uint64) proto.Message) (proto.Message, error) {
+	s.mtx.Lock()
+	s.nextID++
+	id := s.nextID
+	req := &request{peer: peer, resp: make(chan proto.Message, 1)}
+	s.pending[id] = req
+	s.mtx.Unlock()
+	defer func() {
+		s.mtx.Lock()
+		delete(s.pending, id)
+		s.mtx.Unlock()
+	}()
+
+	p := s.sw.Peers().Get(peer)
+	if p == nil {
+		return nil, errPeerGone
+	}
+	bz, err := EncodeMsg(build(id))
+	if err != nil {
+		return nil, err
+	}
+	if !p.TrySend(chID, bz) {
+		return nil, errors.New("peer queue full")
+	}
+	timer := time.NewTimer(s.config.RequestTimeout)
+	defer timer.Stop()
+	select {
+	case msg := <-req.resp:
+		if msg == nil {
+			return nil, errPeerGone
+		}
+		return msg, nil
+	case <-timer.C:
+		return nil, errTimeout
+	case <-s.quit:
+		return nil, errAborted
+	}
+}
+
+// pickPeer returns the next peer serving the snapshot, in turn.
+func (s *syncer) pickPeer(key snapshotKey) (p2p.ID, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	var peers []p2p.ID
+	for peer := range s.snapshots[key] {
+		peers = append(peers, peer)
+	}
+	if len(peers) == 0 {
+		return "", false
+	}
+	s.turn++
+	return peers[s.turn%len(peers)], true
+}
+
+// ask sends a request to the peers serving the snapshot in turn, until one
+// responds with a message accepted by handle. Peers failing to do so aren't
+// asked again for this snapshot.
+func (s *syncer) ask(key snapshotKey, chID byte, build func(id uint64) proto.Message,
+	handle func(msg proto.Message) error) error {
+	for attempt := 0; attempt < maxRequestAttempts; attempt++ {
+		peer, ok := s.pickPeer(key)
+		if !ok {
+			return errNoPeers
+		}
+		msg, err := s.request(peer, chID, build)
+		if err == nil {
+			if err = handle(msg); err == nil {
+				return nil
+			}
+		}
+		if errors.Is(err, errAborted) {
+			return err
+		}
+		s.logger.Debug("State sync request failed", "peer", peer, "height", key.height, "err", err)
+		s.dropSnapshotPeer(key, peer)
+	}
+	return fmt.Errorf("%w after %d attempts", errNoResponse, maxRequestAttempts)
+}
+
+// sync restores the state of the best snapshot found. Snapshots which fail
+// are rejected and the next best one is tried, unless the peers stopped
+// serving the state in the middle of the restore: it is then resumed from
+// where it stopped, as long as the peers still advertise the snapshot. State
+// sync gives up after maxRestoreAttempts failures.
+func (s *syncer) sync() (cstate.LatestBlockState, error) {
+	for attempt := 1; ; attempt++ {
+		key, err := s.discover()
+		if err != nil {
+			return cstate.LatestBlockState{}, err
+		}
+		s.logger.Info("Restoring snapshot", "height", key.height, "root", key.root)
+		state, err := s.restore(key)
+		if err == nil {
+			return state, nil
+		}
+		if errors.Is(err, errAborted) {
+			return cstate.LatestBlockState{}, err
+		}
+		s.logger.Error("Failed to restore snapshot", "height", key.height, "root", key.root, "attempt", attempt, "err", err)
+		if !errors.Is(err, errNoPeers) && !errors.Is(err, errNoResponse) {
+			s.progress = nil
+		}
+		if s.progress == nil || s.progress.root != key.root {
+			s.mtx.Lock()
+			s.rejected[key] = struct{}{}
+			s.mtx.Unlock()
+		}
+		if attempt >= maxRestoreAttempts {
+			return cstate.LatestBlockState{}, fmt.Errorf("no snapshot restored after %d attempts: %w", attempt, err)
+		}
+	}
+}
+
+// discover asks the peers for their snapshots and returns the one of the
+// interrupted restore if any, else the highest one at or above the trusted
+// height.
+func (s *syncer) discover() (snapshotKey, error) {
+	for round := 0; round < discoveryRounds; round++ {
+		s.mtx.Lock()
+		s.snapshots = make(map[snapshotKey]map[p2p.ID]struct{})
+		s.counts = make(map[p2p.ID]int)
+		s.mtx.Unlock()
+
+		if bz, err := EncodeMsg(&ssproto.SnapshotsRequest{}); err == nil {
+			s.sw.Broadcast(SnapshotChannel, bz)
+		}
+		s.logger.Info("Discovering snapshots", "wait", s.config.DiscoveryTime)
+		select {
+		case <-time.After(s.config.DiscoveryTime):
+		case <-s.quit:
+			return snapshotKey{}, errAborted
+		}
+
+		if best, found := s.bestSnapshot(); found {
+			return best, nil
+		}
+	}
+	return snapshotKey{}, errors.New("no usable snapshot found")
+}
+
+// bestSnapshot returns the snapshot to restore among the advertised ones.
+// Once the root of the interrupted restore is no longer advertised, it left
+// the snapshot window of the peers: the restore is dropped and the highest
+// snapshot picked instead.
+func (s *syncer) bestSnapshot() (snapshotKey, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	var (
+		best   snapshotKey
+		found  bool
+		resume bool
+	)
+	for key, peers := range s.snapshots {
+		if _, ok := s.rejected[key]; ok || len(peers) == 0 || key.height < s.config.TrustHeight {
+			continue
+		}
+		if s.progress != nil && key.root == s.progress.root {
+			if !resume || key.height > best.height {
+				best, found, resume = key, true, true
+			}
+			continue
+		}
+		if !resume && (!found || key.height > best.height) {
+			best, found = key, true
+		}
+	}
+	if found && !resume && s.progress != nil {
+		s.logger.Info("Snapshot no longer served, re-pivoting", "root", s.progress.root, "height", best.height, "newRoot", best.root)
+		s.progress = nil
+	}
+	return best, found
+}
+
+// restore verifies the snapshot against the light client, downloads its block
+// and state and persists them as the new head.
+func (s *syncer) restore(key snapshotKey) (cstate.LatestBlockState, error) {
+	lb, next, err := s.verifyLightBlocks(key)
+	if err != nil {
+		return cstate.LatestBlockState{}, err
+	}
+	if next.header.AppHash != key.root {
+		return cstate.LatestBlockState{}, fmt.Errorf("snapshot root %v doesn't match app hash %v of header #%d",
+			key.root, next.header.AppHash, next.header.Height)
+	}
+	block, parts, err := s.fetchBlock(key, next.header.LastBlockID)
+	if err != nil {
+		return cstate.LatestBlockState{}, fmt.Errorf("failed to fetch block #%d: %w", key.height, err)
+	}
+	if err := s.restoreState(key); err != nil {
+		return cstate.LatestBlockState{}, err
+	}
+
+	state := s.initialState.Copy()
+	state.LastBlockHeight = key.height
+	state.LastBlockID = next.header.LastBlockID
+	state.LastBlockTime = lb.header.Time
+	state.LastBlockTotalTx = lb.header.NumTxs
+	state.LastValidators = lb.validators
+	state.Validators = next.validators
+	state.NextValidators = next.nextValidators
+	state.LastHeightValidatorsChanged = key.height + 1
+	state.AppHash = key.root
+
+	if err := s.store.WriteSyncedHead(block, parts, lb.commit, key.root); err != nil {
+		return cstate.LatestBlockState{}, err
+	}
+	// The params in force above the snapshot are the ones set through
+	// governance in its state, as updateState derives them at every block.
+	governed, err := s.store.ConsensusParams(lb.header)
+	if err != nil {
+		return cstate.LatestBlockState{}, fmt.Errorf("failed to read consensus params at #%d: %w", key.height, err)
+	}
+	state.ConsensusParams, _ = cstate.NextConsensusParams(s.logger, state.ConsensusParams, governed)
+	state.LastHeightConsensusParamsChanged = key.height + 1
+	if err := s.stateStore.Bootstrap(state); err != nil {
+		return cstate.LatestBlockState{}, err
+	}
+	return state, nil
+}
+
+// verifyLightBlocks verifies the header committing to the snapshot state
+// from the trusted one, and returns the light blocks at the snapshot height
+// and above it. Within the trusting period of the trusted header, the headers
+// in between are skipped as the light client does. Past it, the validators
+// of the trusted header may have unbonded and every header is verified.
+func (s *syncer) verifyLightBlocks(key snapshotKey) (*lightBlock, *lightBlock, error) {
+	chainID := s.initialState.ChainID
+	height := s.config.TrustHeight
+	if height == 0 {
+		height = s.initialState.InitialHeight
+	}
+	if height > key.height {
+		return nil, nil, fmt.Errorf("trusted height %d above snapshot height %d", height, key.height)
+	}
+	trusted, err := s.fetchLightBlock(key, height)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.config.TrustHeight > 0 {
+		if hash := trusted.header.Hash(); hash != s.config.TrustHash {
+			return nil, nil, fmt.Errorf("header #%d is %v, trusted hash is %v", height, hash, s.config.TrustHash)
+		}
+	} else if hash := trusted.validators.Hash(); hash != s.initialState.Validators.Hash() {
+		return nil, nil, fmt.Errorf("validators %v of header #%d aren't the genesis validators", hash, height)
+	}
+	if err := trusted.verify(chainID); err != nil {
+		return nil, nil, fmt.Errorf("invalid light block #%d: %w", height, err)
+	}
+
+	if light.HeaderExpired(trusted.signedHeader(), s.config.TrustPeriod, time.Now()) {
+		return s.verifyAdjacentLightBlocks(key, trusted)
+	}
+	return s.verifySkippingLightBlocks(key, trusted)
+}
+
+// verifySkippingLightBlocks verifies the light block above the snapshot with
+// the skipping verification of the light client, then the snapshot one from
+// its hash.
+func (s *syncer) verifySkippingLightBlocks(key snapshotKey, trusted *lightBlock) (*lightBlock, *lightBlock, error) {
+	chainID := s.initialState.ChainID
+	next, err := s.fetchLightBlock(key, key.height+1)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := next.verify(chainID); err != nil {
+		return nil, nil, fmt.Errorf("invalid light block #%d: %w", key.height+1, err)
+	}
+	fetch := func(ctx context.Context, height uint64) (*types.LightBlock, error) {
+		lb, err := s.fetchLightBlock(key, height)
+		if err != nil {
+			return nil, err
+		}
+		return lb.toLightBlock(), nil
+	}
+	trace, err := light.VerifySkipping(context.Background(), chainID, fetch, trusted.toLightBlock(), next.toLightBlock(),
+		s.config.TrustPeriod, time.Now(), maxClockDrift, light.DefaultTrustLevel)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.logger.Debug("Verified light blocks", "from", trusted.header.Height, "to", key.height+1, "skipped", len(trace))
+
+	lb := trusted
+	if trusted.header.Height != key.height {
+		if lb, err = s.fetchLightBlock(key, key.height); err != nil {
+			return nil, nil, err
+		}
+		if err := light.VerifyBackwards(lb.header, next.header); err != nil {
+			return nil, nil, fmt.Errorf("invalid light block #%d: %w", key.height, err)
+		}
+	}
+	if err := lb.verify(chainID); err != nil {
+		return nil, nil, fmt.Errorf("invalid light block #%d: %w", key.height, err)
+	}
+	return lb, next, nil
+}
+
+// verifyAdjacentLightBlocks walks the headers from the trusted one up to the
+// one committing to the snapshot state.
+func (s *syncer) verifyAdjacentLightBlocks(key snapshotKey, trusted *lightBlock) (*lightBlock, *lightBlock, error) {
+	chainID := s.initialState.ChainID
+	var prev *lightBlock
+	for from := trusted.header.Height + 1; from <= key.height+1; from += lightBlockBatch {
+		to := from + lightBlockBatch - 1
+		if to > key.height+1 {
+			to = key.height + 1
+		}
+		batch, err := s.fetchLightBlocks(key, from, to)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, lb := range batch {
+			if err := lb.verifyAdjacent(chainID, trusted); err != nil {
+				return nil, nil, fmt.Errorf("invalid light block #%d: %w", lb.header.Height, err)
+			}
+			prev, trusted = trusted, lb
+		}
+		s.logger.Debug("Verified light blocks", "to", to, "target", key.height+1)
+	}
+	return prev, trusted, nil
+}
+
+// fetchLightBlocks fetches the light blocks of the heights from..to concurrently.
+func (s *syncer) fetchLightBlocks(key snapshotKey, from, to uint64) ([]*lightBlock, error) {
+	var (
+		batch = make([]*lightBlock, to-from+1)
+		errs  = make([]error, len(batch))
+		wg    sync.WaitGroup
+	)
+	for i := range batch {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			batch[i], errs[i] = s.fetchLightBlock(key, from+uint64(i))
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return batch, nil
+}
+
+func (s *syncer) fetchLightBlock(key snapshotKey, height uint64) (*lightBlock, error) {
+	var lb *lightBlock
+	err := s.ask(key, SnapshotChannel, func(id uint64) proto.Message {
+		return &ssproto.LightBlockRequest{Id: id, Height: height}
+	}, func(msg proto.Message) error {
+		resp, ok := msg.(*ssproto.LightBlockResponse)
+		if !ok {
+			return fmt.Errorf("unexpected response %T", msg)
+		}
+		if resp.LightBlock == nil {
+			return errUnavailable
+		}
+		var err error
+		if lb, err = lightBlockFromProto(resp.LightBlock); err != nil {
+			return err
+		}
+		if lb.header.Height != height {
+			return fmt.Errorf("got light block #%d, want #%d", lb.header.Height, height)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch light block #%d: %w", height, err)
+	}
+	return lb, nil
+}
+
+// fetchBlock fetches the block of the snapshot height, which must match the
+// block id in the header above it.
+func (s *syncer) fetchBlock(key snapshotKey, blockID types.BlockID) (*types.Block, *types.PartSet, error) {
+	var (
+		block *types.Block
+		parts *types.PartSet
+	)
+	err := s.ask(key, SnapshotChannel, func(id uint64) proto.Message {
+		return &ssproto.BlockRequest{Id: id, Height: key.height}
+	}, func(msg proto.Message) error {
+		resp, ok := msg.(*ssproto.BlockResponse)
+		if !ok {
+			return fmt.Errorf("unexpected response %T", msg)
+		}
+		if resp.Block == nil {
+			return errUnavailable
+		}
+		var err error
+		if block, err = types.BlockFromProto(resp.Block, trie.NewStackTrie(nil)); err != nil {
+			return err
+		}
+		parts = block.MakePartSet(types.BlockPartSizeBytes)
+		if have := (types.BlockID{Hash: block.Hash(), PartsHeader: parts.Header()}); !have.Equal(blockID) {
+			return fmt.Errorf("got block %v, want %v", have, blockID)
+		}
+		return nil
+	})
+	return block, parts, err
+}
+
+// restoreState downloads the accounts, storage slots and codes of the snapshot
+// and rebuilds its state trie. If the peers fail to serve the state, the
+// progress is kept to resume the restore of the same root later on.
+func (s *syncer) restoreState(key snapshotKey) error {
+	p := s.progress
+	if p == nil || p.root != key.root {
+		p = &restoreProgress{
+			root:  key.root,
+			imp:   snapshot.NewStateImporter(s.chain.DB(), s.chain.TrieDB().Scheme()),
+			codes: make(map[common.Hash]struct{}),
+		}
+	} else {
+		s.logger.Info("Resuming state restore", "height", key.height, "origin", p.origin, "accounts", p.accounts, "slots", p.slots)
+	}
+	s.progress = nil
+	err := s.importState(key, p)
+	if errors.Is(err, errNoPeers) || errors.Is(err, errNoResponse) {
+		s.progress = p
+	}
+	return err
+}
+
+// importState downloads the state of the snapshot into the importer of the
+// progress, from where it stopped.
+func (s *syncer) importState(key snapshotKey, p *restoreProgress) error {
+	logged := time.Now()
+	for {
+		if p.next == len(p.hashes) {
+			if p.done {
+				break
+			}
+			hashes, bodies, more, err := s.fetchRange(key, key.root, p.origin, func(id uint64) proto.Message {
+				return &ssproto.AccountRangeRequest{Id: id, Root: key.root.Bytes(), Origin: p.origin.Bytes(),
+					Limit: maxHash.Bytes(), Bytes: s.config.RequestBytes}
+			})
+			if err != nil {
+				return fmt.Errorf("failed to fetch accounts from %v: %w", p.origin, err)
+			}
+			p.hashes, p.bodies, p.next = hashes, bodies, 0
+			if p.done = !more || len(hashes) == 0; !p.done {
+				p.origin, p.done = incHash(hashes[len(hashes)-1])
+			}
+		}
+		for ; p.next < len(p.hashes); p.next++ {
+			hash := p.hashes[p.next]
+			account, err := types.FullAccount(p.bodies[p.next])
+			if err != nil {
+				return err
+			}
+			if !p.added {
+				if err := p.imp.AddAccount(hash, p.bodies[p.next]); err != nil {
+					return err
+				}
+				if codeHash := common.BytesToHash(account.CodeHash); codeHash != types.EmptyCodeHash {
+					if _, ok := p.codes[codeHash]; !ok {
+						p.codes[codeHash] = struct{}{}
+						p.queue = append(p.queue, codeHash)
+					}
+				}
+				p.added, p.storageOrigin = true, common.Hash{}
+			}
+			if account.Root != types.EmptyRootHash {
+				if err := s.restoreStorage(key, hash, account.Root, p); err != nil {
+					return err
+				}
+			}
+			p.added = false
+			p.accounts++
+		}
+		for len(p.queue) >= maxCodesPerRequest {
+			if err := s.restoreCodes(key, p.queue[:maxCodesPerRequest], p.imp); err != nil {
+				return err
+			}
+			p.queue = p.queue[maxCodesPerRequest:]
+		}
+		if err := p.imp.Flush(); err != nil {
+			return err
+		}
+		if time.Since(logged) > 8*time.Second {
+			s.logger.Info("Restoring state", "height", key.height, "accounts", p.accounts, "slots", p.slots, "codes", len(p.codes))
+			logged = time.Now()
+		}
+	}
+	if len(p.queue) > 0 {
+		if err := s.restoreCodes(key, p.queue, p.imp); err != nil {
+			return err
+		}
+		p.queue = nil
+	}
+	return p.imp.Finish(key.root)
+}
+
+// restoreStorage downloads the storage slots of the added account of the
+// progress into its importer, from where it stopped.
+func (s *syncer) restoreStorage(key snapshotKey, account, root common.Hash, p *restoreProgress) error {
+	for {
+		hashes, bodies, more, err := s.fetchRange(key, root, p.storageOrigin, func(id uint64) proto.Message {
+			return &ssproto.StorageRangeRequest{Id: id, Root: key.root.Bytes(), Account: account.Bytes(),
+				Origin: p.storageOrigin.Bytes(), Limit: maxHash.Bytes(), Bytes: s.config.RequestBytes}
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch storage of %v from %v: %w", account, p.storageOrigin, err)
+		}
+		for i, hash := range hashes {
+			if err := p.imp.AddStorage(account, hash, bodies[i]); err != nil {
+				return err
+			}
+		}
+		p.slots += len(hashes)
+		if err := p.imp.Flush(); err != nil {
+			return err
+		}
+		if !more || len(hashes) == 0 {
+			return nil
+		}
+		var overflow bool
+		if p.storageOrigin, overflow = incHash(hashes[len(hashes)-1]); overflow {
+			return nil
+		}
+	}
+}
+
+// fetchRange fetches a range of accounts or storage slots and verifies it
+// against the trie root. It returns whether the trie has more entries.
+func (s *syncer) fetchRange(key snapshotKey, root, origin common.Hash, build func(id uint64) proto.Message) ([]common.Hash, [][]byte, bool, error) {
+	var (
+		hashes []common.Hash
+		bodies [][]byte
+		more   bool
+	)
+	err := s.ask(key, StateChannel, build, func(msg proto.Message) error {
+		var (
+			keys  [][]byte
+			proof [][]byte
+		)
+		hashes, bodies = hashes[:0], bodies[:0]
+		switch resp := msg.(type) {
+		case *ssproto.AccountRangeResponse:
+			for _, account := range resp.Accounts {
+				keys = append(keys, account.Hash)
+				bodies = append(bodies, account.Body)
+			}
+			proof = resp.Proof
+		case *ssproto.StorageRangeResponse:
+			for _, slot := range resp.Slots {
+				keys = append(keys, slot.Hash)
+				bodies = append(bodies, slot.Body)
+			}
+			proof = resp.Proof
+		default:
+			return fmt.Errorf("unexpected response %T", msg)
+		}
+		var err error
+		if more, err = verifyRange(root, origin, keys, bodies, proof); err != nil {
+			return err
+		}
+		for _, key := range keys {
+			hashes = append(hashes, common.BytesToHash(key))
+		}
+		return nil
+	})
+	return hashes, bodies, more, err
+}
+
+// verifyRange checks a range of trie entries from origin against the root with
+// the range proof, and returns whether the trie has more entries. A range
+// without proof must be the whole trie.
+func verifyRange(root, origin common.Hash, keys, values, proof [][]byte) (bool, error) {
+	var proofDb kaidb.KeyValueReader
+	if len(proof) > 0 {
+		db := memorydb.New()
+		for _, node := range proof {
+			db.Put(crypto.Keccak256(node), node)
+		}
+		proofDb = db
+	}
+	last := origin[:]
+	if len(keys) > 0 {
+		last = keys[len(keys)-1]
+	}
+	more, err := trie.VerifyRangeProof(root, origin[:], last, keys, values, proofDb)
+	if err != nil {
+		return false, fmt.Errorf("invalid range proof: %w", err)
+	}
+	return more, nil
+}
+
+// restoreCodes downloads the given codes into the importer. Peers may serve
+// a part of the requested codes, the rest is asked again.
+func (s *syncer) restoreCodes(key snapshotKey, hashes []common.Hash, imp *snapshot.StateImporter) error {
+	missing := make(map[common.Hash]struct{}, len(hashes))
+	for _, hash := range hashes {
+		missing[hash] = struct{}{}
+	}
+	for len(missing) > 0 {
+		err := s.ask(key, StateChannel, func(id uint64) proto.Message {
+			req := &ssproto.CodesRequest{Id: id}
+			for hash := range missing {
+				req.Hashes = append(req.Hashes, hash.Bytes())
+			}
+			return req
+		}, func(msg proto.Message) error {
+			resp, ok := msg.(*ssproto.CodesResponse)
+			if !ok {
+				return fmt.Errorf("unexpected response %T", msg)
+			}
+			if len(resp.Codes) == 0 {
+				return errUnavailable
+			}
+			for _, code := range resp.Codes {
+				if _, ok := missing[crypto.Keccak256Hash(code)]; !ok {
+					return errors.New("unrequested code")
+				}
+			}
+			for _, code := range resp.Codes {
+				imp.AddCode(code)
+				delete(missing, crypto.Keccak256Hash(code))
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch codes: %w", err)
+		}
+	}
+	return nil
+}
+
+// maxHash is the upper limit of the ranges requested.
+var maxHash = common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+
+// incHash returns the hash following h, and whether it overflowed.
+func incHash(h common.Hash) (common.Hash, bool) {
+	for i := len(h) - 1; i >= 0; i-- {
+		h[i]++
+		if h[i] != 0 {
+			return h, false
+		}
+	}
+	return h, true
+}
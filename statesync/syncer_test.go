@@ -0,0 +1,39 @@
+package statesync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/configs"
+	"github.com/kardiachain/go-kardia/kai/state/cstate"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/log"
+)
+
+func TestBestSnapshotResumesRestore(t *testing.T) {
+	s := newSyncer(configs.DefaultStateSyncConfig(), log.New(), cstate.LatestBlockState{}, nil, nil, nil, nil)
+	older := snapshotKey{height: 32, root: common.HexToHash("0x01")}
+	newer := snapshotKey{height: 48, root: common.HexToHash("0x02")}
+	s.addSnapshot("peer", older.height, older.root)
+	s.addSnapshot("peer", newer.height, newer.root)
+
+	best, ok := s.bestSnapshot()
+	require.True(t, ok)
+	assert.Equal(t, newer, best)
+
+	// The interrupted restore is resumed while the peers serve its root.
+	s.progress = &restoreProgress{root: older.root}
+	best, ok = s.bestSnapshot()
+	require.True(t, ok)
+	assert.Equal(t, older, best)
+	assert.NotNil(t, s.progress)
+
+	// Once its root left the window of the peers, the restore re-pivots.
+	s.dropSnapshotPeer(older, "peer")
+	best, ok = s.bestSnapshot()
+	require.True(t, ok)
+	assert.Equal(t, newer, best)
+	assert.Nil(t, s.progress)
+}
@@ -0,0 +1,65 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package trie
+
+import (
+	"bytes"
+
+	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/rlp"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// proofList collects the trie nodes of a proof, from the root down.
+type proofList []common.Bytes
+
+func (n *proofList) Put(key []byte, value []byte) error {
+	*n = append(*n, common.CopyBytes(value))
+	return nil
+}
+
+func (n *proofList) Delete(key []byte) error {
+	panic("not supported")
+}
+
+// DeriveProof rebuilds the trie of list the way types.DeriveSha does and
+// returns its root, the encoding of the element at index and its proof.
+func DeriveProof(list types.DerivableList, index int) (common.Hash, []byte, []common.Bytes, error) {
+	var (
+		t     = NewEmpty(NewDatabase(memorydb.New()))
+		buf   = new(bytes.Buffer)
+		value []byte
+	)
+	for i := 0; i < list.Len(); i++ {
+		buf.Reset()
+		list.EncodeIndex(i, buf)
+		if i == index {
+			value = common.CopyBytes(buf.Bytes())
+		}
+		if err := t.Update(rlp.AppendUint64(nil, uint64(i)), common.CopyBytes(buf.Bytes())); err != nil {
+			return common.Hash{}, nil, nil, err
+		}
+	}
+	var proof proofList
+	if err := t.Prove(rlp.AppendUint64(nil, uint64(index)), 0, &proof); err != nil {
+		return common.Hash{}, nil, nil, err
+	}
+	return t.Hash(), value, proof, nil
+}
@@ -474,6 +474,19 @@ func (b *Block) Size() common.StorageSize {
 	return common.StorageSize(c)
 }
 
+// ByteSize returns the size of the block serialized as in MakePartSet, which is
+// the size limited by ConsensusParams.Block.MaxBytes.
+func (b *Block) ByteSize() int64 {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	pbb, err := b.ToProto()
+	if err != nil {
+		return 0
+	}
+	return int64(pbb.Size())
+}
+
 // ValidateBasic performs basic validation that doesn't involve state data.
 // It checks the internal consistency of the block.
 func (b *Block) ValidateBasic(hasher TrieHasher) error {
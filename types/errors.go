@@ -8,18 +8,18 @@ import (
 
 // ErrEvidenceOverflow is for when there is too much evidence in a block.
 type ErrEvidenceOverflow struct {
-	MaxNum int64
-	GotNum int64
+	MaxBytes int64
+	GotBytes int64
 }
 
-// NewErrEvidenceOverflow returns a new ErrEvidenceOverflow where got > max.
+// NewErrEvidenceOverflow returns a new ErrEvidenceOverflow where got > max bytes.
 func NewErrEvidenceOverflow(max, got int64) *ErrEvidenceOverflow {
 	return &ErrEvidenceOverflow{max, got}
 }
 
 // Error returns a string representation of the error.
 func (err *ErrEvidenceOverflow) Error() string {
-	return fmt.Sprintf("Too much evidence: Max %d, got %d", err.MaxNum, err.GotNum)
+	return fmt.Sprintf("Too much evidence: Max %d bytes, got %d bytes", err.MaxBytes, err.GotBytes)
 }
 
 // ErrEvidenceInvalid wraps a piece of evidence and the error denoting how or why it is invalid.
@@ -65,6 +65,8 @@ var (
 	EventQueryTimeoutPropose      = QueryForEvent(EventTimeoutPropose)
 	EventQueryTimeoutWait         = QueryForEvent(EventTimeoutWait)
 	EventQueryUnlock              = QueryForEvent(EventUnlock)
+	EventQueryPolka               = QueryForEvent(EventPolka)
+	EventQueryLock                = QueryForEvent(EventLock)
 )
 
 // NOTE: This goes into the replay WAL
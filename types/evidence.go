@@ -20,9 +20,11 @@ package types
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"strings"
 	"time"
 
@@ -38,27 +40,16 @@ type EvidenceType uint8
 
 // EvidenceType
 const (
-	EvidenceDuplicateVote       = EvidenceType(0x01)
-	EvidenceMock                = EvidenceType(0x02)
-	MaxEvidenceBytesDenominator = 10
-	// MaxEvidenceBytes is a maximum size of any evidence
-	MaxEvidenceBytes int64 = 484
+	EvidenceDuplicateVote     = EvidenceType(0x01)
+	EvidenceMock              = EvidenceType(0x02)
+	EvidenceLightClientAttack = EvidenceType(0x03)
 )
 
-// MaxEvidencePerBlock returns the maximum number of evidences
-// allowed in the block and their maximum total size (limitted to 1/10th
-// of the maximum block size).
-// TODO: change to a constant, or to a fraction of the validator set size.
-func MaxEvidencePerBlock(blockMaxBytes int64) (int64, int64) {
-	maxBytes := blockMaxBytes / MaxEvidenceBytesDenominator
-	maxNum := maxBytes / MaxEvidenceBytes
-	return maxNum, maxBytes
-}
-
 // Evidence represents any provable malicious activity by a validator
 type Evidence interface {
 	Height() uint64    // height of the equivocation
 	Bytes() []byte     // bytes which comprise the evidence
+	ByteSize() int64   // size of the proto-encoded evidence
 	Hash() common.Hash // hash of the evidence
 	ValidateBasic() error
 	String() string
@@ -85,6 +76,17 @@ func EvidenceToProto(evidence Evidence) (*kproto.Evidence, error) {
 			},
 		}, nil
 
+	case *LightClientAttackEvidence:
+		pbev, err := evi.ToProto()
+		if err != nil {
+			return nil, err
+		}
+		return &kproto.Evidence{
+			Sum: &kproto.Evidence_LightClientAttackEvidence{
+				LightClientAttackEvidence: pbev,
+			},
+		}, nil
+
 	default:
 		return nil, fmt.Errorf("toproto: evidence is not recognized: %T", evi)
 	}
@@ -100,6 +102,8 @@ func EvidenceFromProto(evidence *kproto.Evidence) (Evidence, error) {
 	switch evi := evidence.Sum.(type) {
 	case *kproto.Evidence_DuplicateVoteEvidence:
 		return DuplicateVoteEvidenceFromProto(evi.DuplicateVoteEvidence)
+	case *kproto.Evidence_LightClientAttackEvidence:
+		return LightClientAttackEvidenceFromProto(evi.LightClientAttackEvidence)
 	default:
 		return nil, errors.New("evidence is not recognized")
 	}
@@ -185,6 +189,11 @@ func (dve *DuplicateVoteEvidence) Bytes() []byte {
 	return bz
 }
 
+// ByteSize returns the size of the proto-encoded evidence.
+func (dve *DuplicateVoteEvidence) ByteSize() int64 {
+	return int64(dve.ToProto().Size())
+}
+
 // Hash returns the hash of the evidence.
 func (dve *DuplicateVoteEvidence) Hash() common.Hash {
 	return hash(dve.Bytes())
@@ -316,6 +325,255 @@ func DuplicateVoteEvidenceFromProto(pb *kproto.DuplicateVoteEvidence) (*Duplicat
 	return dve, dve.ValidateBasic()
 }
 
+//-------------------------------------------
+
+// LightClientAttackEvidence is a generalized evidence that captures all forms of
+// known attacks on a light client such that a full node can verify, propose and
+// commit the evidence on-chain for punishment of the malicious validators. The
+// attacks are exhaustive:
+//
+//   - lunatic: validators of the common block signed a block that isn't the
+//     product of a valid state transition.
+//   - equivocation: the same validators signed two blocks in the same round.
+//   - amnesia: the same validators signed two blocks in different rounds.
+type LightClientAttackEvidence struct {
+	ConflictingBlock *LightBlock
+	CommonHeight     uint64
+
+	ByzantineValidators []*Validator // validators of the common validator set that signed the conflicting block
+	TotalVotingPower    int64        // total voting power of the validator set at the common height
+	Timestamp           time.Time    // timestamp of the block at the common height
+}
+
+// NewLightClientAttackEvidence builds the evidence against conflicted, trusted
+// being the block at the same height of the chain that holds commonBlock.
+func NewLightClientAttackEvidence(conflicted, trusted, commonBlock *LightBlock) *LightClientAttackEvidence {
+	ev := &LightClientAttackEvidence{ConflictingBlock: conflicted}
+	// A lunatic attack is accountable to the validators of the common block,
+	// equivocation and amnesia to the ones of the trusted block.
+	if ev.ConflictingHeaderIsInvalid(trusted.Header) {
+		ev.CommonHeight = commonBlock.Height
+		ev.Timestamp = commonBlock.Time
+		ev.TotalVotingPower = commonBlock.ValidatorSet.TotalVotingPower()
+	} else {
+		ev.CommonHeight = trusted.Height
+		ev.Timestamp = trusted.Time
+		ev.TotalVotingPower = trusted.ValidatorSet.TotalVotingPower()
+	}
+	ev.ByzantineValidators = ev.GetByzantineValidators(commonBlock.ValidatorSet, trusted.SignedHeader)
+	return ev
+}
+
+// String returns a string representation of the evidence.
+func (l *LightClientAttackEvidence) String() string {
+	return fmt.Sprintf("LightClientAttackEvidence{ConflictingBlock: %v, CommonHeight: %d, ByzantineValidators: %v}",
+		l.ConflictingBlock.Hash(), l.CommonHeight, l.ByzantineValidators)
+}
+
+// Height returns the last height at which the primary provider and witness
+// provider had the same header.
+func (l *LightClientAttackEvidence) Height() uint64 {
+	return l.CommonHeight
+}
+
+// Time returns the time of the common block where the infraction leveraged
+// off.
+func (l *LightClientAttackEvidence) Time() time.Time {
+	return l.Timestamp
+}
+
+// Equal checks if two pieces of evidence are equal.
+func (l *LightClientAttackEvidence) Equal(ev Evidence) bool {
+	if _, ok := ev.(*LightClientAttackEvidence); !ok {
+		return false
+	}
+	return l.Hash().Equal(ev.Hash())
+}
+
+// Bytes returns the proto-encoded evidence as a byte array.
+func (l *LightClientAttackEvidence) Bytes() []byte {
+	pbe, err := l.ToProto()
+	if err != nil {
+		panic(err)
+	}
+	bz, err := pbe.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+// ByteSize returns the size of the proto-encoded evidence.
+func (l *LightClientAttackEvidence) ByteSize() int64 {
+	pbe, err := l.ToProto()
+	if err != nil {
+		panic(err)
+	}
+	return int64(pbe.Size())
+}
+
+// Hash returns the hash of the conflicting header and the common height. The
+// byzantine validators are left out so that the same attack reported by
+// different nodes is only committed once.
+func (l *LightClientAttackEvidence) Hash() common.Hash {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, l.CommonHeight)
+	return hash(append(l.ConflictingBlock.Hash().Bytes(), buf[:n]...))
+}
+
+// VM returns an infraction for each byzantine validator, which the staking
+// contract slashes as it does double signing.
+func (l *LightClientAttackEvidence) VM() []types.Evidence {
+	evs := make([]types.Evidence, 0, len(l.ByzantineValidators))
+	for _, val := range l.ByzantineValidators {
+		evs = append(evs, types.Evidence{
+			Address:          val.Address,
+			Height:           l.CommonHeight,
+			Time:             l.Timestamp,
+			TotalVotingPower: uint64(l.TotalVotingPower),
+			VotingPower:      big.NewInt(val.VotingPower),
+		})
+	}
+	return evs
+}
+
+// GetByzantineValidators finds out what style of attack the evidence is and
+// returns the malicious validators, ordered by voting power. It is used both
+// for forming the ByzantineValidators field and for validating that it is
+// correct.
+func (l *LightClientAttackEvidence) GetByzantineValidators(commonVals *ValidatorSet, trusted *SignedHeader) []*Validator {
+	var validators []*Validator
+	switch {
+	case l.ConflictingHeaderIsInvalid(trusted.Header):
+		// Lunatic: the validators of the common block that signed the
+		// conflicting block.
+		for _, sig := range l.ConflictingBlock.Commit.Signatures {
+			if !sig.ForBlock() {
+				continue
+			}
+			if _, val := commonVals.GetByAddress(sig.ValidatorAddress); val != nil {
+				validators = append(validators, val)
+			}
+		}
+	case trusted.Commit.Round == l.ConflictingBlock.Commit.Round:
+		// Equivocation: the validators that signed both blocks in the same
+		// round.
+		signed := make(map[common.Address]bool, len(trusted.Commit.Signatures))
+		for _, sig := range trusted.Commit.Signatures {
+			if sig.ForBlock() {
+				signed[sig.ValidatorAddress] = true
+			}
+		}
+		for _, sig := range l.ConflictingBlock.Commit.Signatures {
+			if !sig.ForBlock() || !signed[sig.ValidatorAddress] {
+				continue
+			}
+			if _, val := l.ConflictingBlock.ValidatorSet.GetByAddress(sig.ValidatorAddress); val != nil {
+				validators = append(validators, val)
+			}
+		}
+	default:
+		// Amnesia: the blocks were committed in different rounds, and which of
+		// the validators were malicious can't be deduced from the commits.
+		return validators
+	}
+	sort.Sort(ValidatorsByVotingPower(validators))
+	return validators
+}
+
+// ConflictingHeaderIsInvalid takes a trusted header and matches it against the
+// conflicting header to determine whether the conflicting header was the
+// product of a valid state transition or not. If it is then all the
+// deterministic fields of the header should be the same. If not, it is an
+// invalid header and constitutes a lunatic attack.
+func (l *LightClientAttackEvidence) ConflictingHeaderIsInvalid(trustedHeader *Header) bool {
+	return !trustedHeader.ValidatorsHash.Equal(l.ConflictingBlock.ValidatorsHash) ||
+		!trustedHeader.NextValidatorsHash.Equal(l.ConflictingBlock.NextValidatorsHash) ||
+		!trustedHeader.ConsensusHash.Equal(l.ConflictingBlock.ConsensusHash) ||
+		!trustedHeader.AppHash.Equal(l.ConflictingBlock.AppHash)
+}
+
+// ValidateBasic performs basic validation such that the evidence is consistent
+// and can now be used for verification.
+func (l *LightClientAttackEvidence) ValidateBasic() error {
+	if l == nil {
+		return errors.New("empty light client attack evidence")
+	}
+	if l.ConflictingBlock == nil {
+		return errors.New("conflicting block is nil")
+	}
+	// this check needs to be done before we can run validate basic
+	if l.ConflictingBlock.SignedHeader == nil || l.ConflictingBlock.Header == nil {
+		return errors.New("conflicting block missing header")
+	}
+	if l.TotalVotingPower <= 0 {
+		return errors.New("negative or zero total voting power")
+	}
+	if l.CommonHeight == 0 {
+		return errors.New("zero common height")
+	}
+	// The common height may be the one of the conflicting block for
+	// equivocation and amnesia, never above it.
+	if l.CommonHeight > l.ConflictingBlock.Height {
+		return fmt.Errorf("common height is ahead of the conflicting block height (%d > %d)",
+			l.CommonHeight, l.ConflictingBlock.Height)
+	}
+	if err := l.ConflictingBlock.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid conflicting light block: %w", err)
+	}
+	return nil
+}
+
+// ToProto encodes LightClientAttackEvidence to protobuf
+func (l *LightClientAttackEvidence) ToProto() (*kproto.LightClientAttackEvidence, error) {
+	conflictingBlock, err := l.ConflictingBlock.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	byzVals := make([]*kproto.Validator, len(l.ByzantineValidators))
+	for i, val := range l.ByzantineValidators {
+		valpb, err := val.ToProto()
+		if err != nil {
+			return nil, err
+		}
+		byzVals[i] = valpb
+	}
+	return &kproto.LightClientAttackEvidence{
+		ConflictingBlock:    conflictingBlock,
+		CommonHeight:        l.CommonHeight,
+		ByzantineValidators: byzVals,
+		TotalVotingPower:    l.TotalVotingPower,
+		Timestamp:           l.Timestamp,
+	}, nil
+}
+
+// LightClientAttackEvidenceFromProto decodes protobuf into LightClientAttackEvidence
+func LightClientAttackEvidenceFromProto(pb *kproto.LightClientAttackEvidence) (*LightClientAttackEvidence, error) {
+	if pb == nil {
+		return nil, errors.New("nil light client attack evidence")
+	}
+	conflictingBlock, err := LightBlockFromProto(pb.ConflictingBlock)
+	if err != nil {
+		return nil, err
+	}
+	byzVals := make([]*Validator, len(pb.ByzantineValidators))
+	for i, valpb := range pb.ByzantineValidators {
+		val, err := ValidatorFromProto(valpb)
+		if err != nil {
+			return nil, err
+		}
+		byzVals[i] = val
+	}
+	l := &LightClientAttackEvidence{
+		ConflictingBlock:    conflictingBlock,
+		CommonHeight:        pb.CommonHeight,
+		ByzantineValidators: byzVals,
+		TotalVotingPower:    pb.TotalVotingPower,
+		Timestamp:           pb.Timestamp,
+	}
+	return l, l.ValidateBasic()
+}
+
 //-------------------------------------------- MOCKING --------------------------------------
 
 // unstable - use only for testing
@@ -382,6 +640,20 @@ func (evl EvidenceList) Hash() common.Hash {
 	return common.BytesToHash(proof)
 }
 
+// ByteSize returns the size of the list encoded as in a block's EvidenceData,
+// which is the size limited by ConsensusParams.Evidence.MaxBytes.
+func (evl EvidenceList) ByteSize() int64 {
+	var pbe kproto.EvidenceData
+	for _, ev := range evl {
+		pb, err := EvidenceToProto(ev)
+		if err != nil {
+			panic(err)
+		}
+		pbe.Evidence = append(pbe.Evidence, *pb)
+	}
+	return int64(pbe.Size())
+}
+
 func (evl EvidenceList) String() string {
 	s := ""
 	for _, e := range evl {
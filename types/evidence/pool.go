@@ -90,7 +90,8 @@ func NewPool(stateDB cstate.Store, evidenceDB kaidb.Database, blockStore BlockSt
 	return evpool, nil
 }
 
-// PendingEvidence is used primarily as part of block proposal and returns up to maxNum of uncommitted evidence.
+// PendingEvidence is used primarily as part of block proposal and returns the uncommitted evidence
+// whose encoded size fits in maxBytes (-1 for no limit), along with that size.
 func (evpool *Pool) PendingEvidence(maxBytes int64) ([]types.Evidence, int64) {
 	if evpool.Size() == 0 {
 		return nil, 0
@@ -159,6 +160,32 @@ func (evpool *Pool) markEvidenceAsCommitted(evidence types.EvidenceList) {
 	}
 }
 
+// RevertCommittedEvidence undoes markEvidenceAsCommitted for the evidence of
+// blocks being rolled back: the evidence is no longer marked as committed and
+// is put back into the pending set so it can be proposed again. Evidence that
+// expired in the meantime is pruned when the pool is next opened.
+// It must not be called while a pool is running on top of evidenceDB.
+func RevertCommittedEvidence(evidenceDB kaidb.Database, evidence types.EvidenceList) error {
+	batch := evidenceDB.NewBatch()
+	for _, ev := range evidence {
+		if err := batch.Delete(keyCommitted(ev)); err != nil {
+			return err
+		}
+		evpb, err := types.EvidenceToProto(ev)
+		if err != nil {
+			return fmt.Errorf("unable to convert to proto, err: %w", err)
+		}
+		evBytes, err := evpb.Marshal()
+		if err != nil {
+			return fmt.Errorf("unable to marshal evidence: %w", err)
+		}
+		if err := batch.Put(keyPending(ev), evBytes); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
 func (evpool *Pool) Size() uint32 {
 	return atomic.LoadUint32(&evpool.evidenceSize)
 }
@@ -24,6 +24,8 @@ import (
 
 	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
 	cState "github.com/kardiachain/go-kardia/kai/state/cstate"
+	"github.com/kardiachain/go-kardia/lib/clist"
+	"github.com/kardiachain/go-kardia/lib/log"
 	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
 	"github.com/kardiachain/go-kardia/types"
 	"github.com/kardiachain/go-kardia/types/evidence/mocks"
@@ -119,3 +121,22 @@ func TestEvidencePool(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, pool.evidenceList.Len())
 }
+
+func TestRevertCommittedEvidence(t *testing.T) {
+	_, privVals := types.RandValidatorSet(1, 10)
+	evidenceDB := memorydb.New()
+	pool := &Pool{
+		logger:       log.New(),
+		evidenceList: clist.New(),
+		evidenceDB:   evidenceDB,
+	}
+
+	ev := types.NewMockDuplicateVoteEvidenceWithValidator(10, defaultEvidenceTime, privVals[0], "kai")
+	pool.markEvidenceAsCommitted(types.EvidenceList{ev})
+	require.True(t, pool.isCommitted(ev))
+	require.False(t, pool.isPending(ev))
+
+	require.NoError(t, RevertCommittedEvidence(evidenceDB, types.EvidenceList{ev}))
+	assert.False(t, pool.isCommitted(ev))
+	assert.True(t, pool.isPending(ev))
+}
@@ -68,6 +68,7 @@ func (evR *Reactor) GetChannels() []*p2p.ChannelDescriptor {
 	return []*p2p.ChannelDescriptor{
 		{
 			ID:                  EvidenceChannel,
+			Name:                "evidence",
 			Priority:            6,
 			RecvMessageCapacity: maxMsgSize,
 			RecvBufferCapacity:  4096,
@@ -86,7 +87,7 @@ func (evR *Reactor) Receive(chID byte, src p2p.Peer, msgBytes []byte) {
 	evis, err := decodeMsg(msgBytes)
 	if err != nil {
 		evR.Logger.Error("Error decoding message", "src", src, "chId", chID, "err", err, "bytes", msgBytes)
-		evR.Switch.StopPeerForError(src, err)
+		evR.Switch.MarkPeerAsBad(src, err)
 		return
 	}
 	for _, ev := range evis {
@@ -95,7 +96,7 @@ func (evR *Reactor) Receive(chID byte, src p2p.Peer, msgBytes []byte) {
 		case *types.ErrEvidenceInvalid:
 			evR.Logger.Error(err.Error())
 			// punish peer
-			evR.Switch.StopPeerForError(src, err)
+			evR.Switch.MarkPeerAsBad(src, err)
 			return
 		case nil:
 		default:
@@ -2,9 +2,11 @@ package evidence
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 
 	"github.com/kardiachain/go-kardia/lib/crypto"
+	"github.com/kardiachain/go-kardia/light"
 	"github.com/kardiachain/go-kardia/types"
 )
 
@@ -55,11 +57,105 @@ func (evpool *Pool) verify(evidence types.Evidence) error {
 			return err
 		}
 		return VerifyDuplicateVote(ev, state.ChainID, valSet)
+	case *types.LightClientAttackEvidence:
+		commonHeader, err := getSignedHeader(evpool.blockStore, ev.Height())
+		if err != nil {
+			return err
+		}
+		commonVals, err := evpool.stateDB.LoadValidators(ev.Height())
+		if err != nil {
+			return err
+		}
+		// in the case of lunatic the trusted header is different to the common header
+		trustedHeader := commonHeader
+		if ev.Height() != ev.ConflictingBlock.Height {
+			trustedHeader, err = getSignedHeader(evpool.blockStore, ev.ConflictingBlock.Height)
+			if err != nil {
+				return err
+			}
+		}
+		return VerifyLightClientAttack(ev, state.ChainID, commonHeader, trustedHeader, commonVals)
 	default:
 		return fmt.Errorf("unrecognized evidence type: %T", evidence)
 	}
 }
 
+// VerifyLightClientAttack verifies LightClientAttackEvidence against the state of the full node. This involves
+// the following checks:
+//   - the common header from the full node has at least 1/3 voting power which is also present in
+//     the conflicting header's commit
+//   - 2/3+ of the conflicting validator set correctly signed the conflicting block
+//   - the nodes trusted header at the same height as the conflicting header has a different hash
+//   - all signatures must be checked as this will be used as evidence
+//
+// CONTRACT: must run ValidateBasic() on the evidence before verifying
+func VerifyLightClientAttack(e *types.LightClientAttackEvidence, chainID string, commonHeader, trustedHeader *types.SignedHeader,
+	commonVals *types.ValidatorSet) error {
+	// In the case of lunatic attack there will be a different commonHeader height. Therefore the node perform a
+	// single verification jump between the common header and the conflicting one
+	if commonHeader.Height != e.ConflictingBlock.Height {
+		err := commonVals.VerifyCommitTrusting(chainID, e.ConflictingBlock.Commit, light.DefaultTrustLevel)
+		if err != nil {
+			return fmt.Errorf("skipping verification of conflicting block failed: %w", err)
+		}
+
+		// In the case of equivocation and amnesia we expect all header hashes to be correctly derived
+	} else if e.ConflictingHeaderIsInvalid(trustedHeader.Header) {
+		return errors.New("common height is the same as conflicting block height so expected the conflicting" +
+			" block to be correctly derived yet it wasn't")
+	}
+
+	// Verify that the 2/3+ commits from the conflicting validator set were for the conflicting header
+	if err := e.ConflictingBlock.ValidatorSet.VerifyCommit(chainID, e.ConflictingBlock.Commit.BlockID,
+		e.ConflictingBlock.Height, e.ConflictingBlock.Commit); err != nil {
+		return fmt.Errorf("invalid commit from conflicting block: %w", err)
+	}
+
+	// Assert the correct amount of voting power of the validator set
+	if evTotal, valsTotal := e.TotalVotingPower, commonVals.TotalVotingPower(); evTotal != valsTotal {
+		return fmt.Errorf("total voting power from the evidence and our validator set does not match (%d != %d)",
+			evTotal, valsTotal)
+	}
+
+	// check in the case of a forward lunatic attack that monotonically increasing time has been violated
+	if e.ConflictingBlock.Height > trustedHeader.Height && e.ConflictingBlock.Time.After(trustedHeader.Time) {
+		return fmt.Errorf("conflicting block doesn't violate monotonically increasing time (%v is after %v)",
+			e.ConflictingBlock.Time, trustedHeader.Time,
+		)
+
+		// In all other cases check that the hashes of the conflicting header and the trusted header are different
+	} else if trustedHeader.Hash().Equal(e.ConflictingBlock.Hash()) {
+		return fmt.Errorf("trusted header hash matches the evidence's conflicting header hash: %v",
+			trustedHeader.Hash())
+	}
+
+	return validateABCIEvidence(e, commonVals, trustedHeader)
+}
+
+// validateABCIEvidence checks that the byzantine validators of the evidence,
+// which the staking contract slashes, are exactly the ones the attack makes
+// accountable.
+func validateABCIEvidence(ev *types.LightClientAttackEvidence, commonVals *types.ValidatorSet,
+	trustedHeader *types.SignedHeader) error {
+	validators := ev.GetByzantineValidators(commonVals, trustedHeader)
+	if len(validators) != len(ev.ByzantineValidators) {
+		return fmt.Errorf("expected %d byzantine validators from evidence but got %d", len(validators),
+			len(ev.ByzantineValidators))
+	}
+	for idx, val := range validators {
+		evVal := ev.ByzantineValidators[idx]
+		if !val.Address.Equal(evVal.Address) {
+			return fmt.Errorf("evidence contained an unexpected byzantine validator address; expected: %v, got: %v",
+				val.Address, evVal.Address)
+		}
+		if val.VotingPower != evVal.VotingPower {
+			return fmt.Errorf("evidence contained unexpected byzantine validator power; expected %d, got %d",
+				val.VotingPower, evVal.VotingPower)
+		}
+	}
+	return nil
+}
+
 // VerifyDuplicateVote verifies DuplicateVoteEvidence against the state of full node. This involves the
 // following checks:
 //      - the validator is in the validator set at the height of the evidence
@@ -119,3 +215,18 @@ func VerifyDuplicateVote(e *types.DuplicateVoteEvidence, chainID string, valSet
 
 	return nil
 }
+
+func getSignedHeader(blockStore BlockStore, height uint64) (*types.SignedHeader, error) {
+	blockMeta := blockStore.LoadBlockMeta(height)
+	if blockMeta == nil {
+		return nil, fmt.Errorf("don't have header at height #%d", height)
+	}
+	commit := blockStore.LoadBlockCommit(height)
+	if commit == nil {
+		return nil, fmt.Errorf("don't have commit at height #%d", height)
+	}
+	return &types.SignedHeader{
+		Header: blockMeta.Header,
+		Commit: commit,
+	}, nil
+}
@@ -149,3 +149,117 @@ func makeBlockID(hash []byte, partSetSize uint32, partSetHash []byte) types.Bloc
 		},
 	}
 }
+
+func TestVerifyLightClientAttack(t *testing.T) {
+	const chainID = "mychain"
+	keys := []types.PrivValidator{types.NewMockPV(), types.NewMockPV(), types.NewMockPV(), types.NewMockPV()}
+	vals := make([]*types.Validator, len(keys))
+	for i, key := range keys {
+		vals[i] = key.ExtractIntoValidator(10)
+	}
+	valSet := types.NewValidatorSet(vals)
+
+	commonBlock := makeLightBlock(t, chainID, keys, valSet, 5, 0, common.Hash{}, common.Hash{})
+	trusted := makeLightBlock(t, chainID, keys, valSet, 10, 0, common.Hash{}, common.Hash{})
+
+	t.Run("equivocation", func(t *testing.T) {
+		conflicting := makeLightBlock(t, chainID, keys, valSet, 10, 0, common.BytesToHash([]byte("txs")), common.Hash{})
+		ev := types.NewLightClientAttackEvidence(conflicting, trusted, commonBlock)
+		require.NoError(t, ev.ValidateBasic())
+		assert.EqualValues(t, 10, ev.Height())
+		assert.Len(t, ev.ByzantineValidators, len(keys))
+		assert.Len(t, ev.VM(), len(keys))
+		assert.NoError(t, VerifyLightClientAttack(ev, chainID, trusted.SignedHeader, trusted.SignedHeader, valSet))
+
+		// the byzantine validators and voting power must be the accountable ones
+		ev.ByzantineValidators = ev.ByzantineValidators[1:]
+		assert.Error(t, VerifyLightClientAttack(ev, chainID, trusted.SignedHeader, trusted.SignedHeader, valSet))
+		ev = types.NewLightClientAttackEvidence(conflicting, trusted, commonBlock)
+		ev.TotalVotingPower++
+		assert.Error(t, VerifyLightClientAttack(ev, chainID, trusted.SignedHeader, trusted.SignedHeader, valSet))
+
+		// the trusted block isn't conflicting
+		ev = types.NewLightClientAttackEvidence(trusted, trusted, commonBlock)
+		assert.Error(t, VerifyLightClientAttack(ev, chainID, trusted.SignedHeader, trusted.SignedHeader, valSet))
+	})
+
+	t.Run("amnesia", func(t *testing.T) {
+		conflicting := makeLightBlock(t, chainID, keys, valSet, 10, 1, common.BytesToHash([]byte("txs")), common.Hash{})
+		ev := types.NewLightClientAttackEvidence(conflicting, trusted, commonBlock)
+		assert.EqualValues(t, 10, ev.Height())
+		assert.Empty(t, ev.ByzantineValidators)
+		assert.NoError(t, VerifyLightClientAttack(ev, chainID, trusted.SignedHeader, trusted.SignedHeader, valSet))
+	})
+
+	t.Run("lunatic", func(t *testing.T) {
+		conflicting := makeLightBlock(t, chainID, keys, valSet, 10, 0, common.Hash{}, common.BytesToHash([]byte("lunatic")))
+		ev := types.NewLightClientAttackEvidence(conflicting, trusted, commonBlock)
+		require.NoError(t, ev.ValidateBasic())
+		assert.EqualValues(t, 5, ev.Height())
+		assert.Equal(t, commonBlock.Time, ev.Time())
+		assert.Len(t, ev.ByzantineValidators, len(keys))
+		assert.NoError(t, VerifyLightClientAttack(ev, chainID, commonBlock.SignedHeader, trusted.SignedHeader, valSet))
+
+		// validators that didn't sign the common block can't be trusted
+		otherSet := types.NewValidatorSet([]*types.Validator{types.NewMockPV().ExtractIntoValidator(10)})
+		assert.Error(t, VerifyLightClientAttack(ev, chainID, commonBlock.SignedHeader, trusted.SignedHeader, otherSet))
+	})
+
+	t.Run("pool", func(t *testing.T) {
+		conflicting := makeLightBlock(t, chainID, keys, valSet, 10, 0, common.BytesToHash([]byte("txs")), common.Hash{})
+		ev := types.NewLightClientAttackEvidence(conflicting, trusted, commonBlock)
+
+		// the evidence survives gossip
+		evpb, err := types.EvidenceToProto(ev)
+		require.NoError(t, err)
+		decoded, err := types.EvidenceFromProto(evpb)
+		require.NoError(t, err)
+		assert.Equal(t, ev.Hash(), decoded.Hash())
+
+		state := cstate.LatestBlockState{
+			ChainID:         chainID,
+			InitialHeight:   1,
+			LastBlockTime:   trusted.Time.Add(1 * time.Minute),
+			LastBlockHeight: 11,
+			ConsensusParams: *types.DefaultConsensusParams(),
+		}
+		stateStore := &smocks.Store{}
+		stateStore.On("LoadValidators", uint64(10)).Return(valSet, nil)
+		stateStore.On("Load").Return(state, nil)
+		blockStore := &mocks.BlockStore{}
+		blockStore.On("LoadBlockMeta", uint64(10)).Return(&types.BlockMeta{Header: trusted.Header})
+		blockStore.On("LoadBlockCommit", uint64(10)).Return(trusted.Commit)
+
+		pool, err := NewPool(stateStore, memorydb.New(), blockStore)
+		require.NoError(t, err)
+		assert.NoError(t, pool.CheckEvidence(types.EvidenceList{decoded}))
+	})
+}
+
+// makeLightBlock makes a light block at height signed by all the keys in
+// round. txHash and appHash tell conflicting blocks apart.
+func makeLightBlock(t *testing.T, chainID string, keys []types.PrivValidator, vals *types.ValidatorSet,
+	height uint64, round uint32, txHash, appHash common.Hash) *types.LightBlock {
+
+	header := &types.Header{
+		Height:             height,
+		Time:               defaultEvidenceTime.Add(time.Duration(height) * time.Minute),
+		ProposerAddress:    vals.Validators[0].Address,
+		TxHash:             txHash,
+		ValidatorsHash:     vals.Hash(),
+		NextValidatorsHash: vals.Hash(),
+		AppHash:            appHash,
+	}
+	blockID := types.BlockID{Hash: header.Hash(), PartsHeader: types.PartSetHeader{Total: 1, Hash: header.Hash()}}
+	voteSet := types.NewVoteSet(chainID, height, round, kproto.PrecommitType, vals)
+	for _, key := range keys {
+		idx, _ := vals.GetByAddress(key.GetAddress())
+		vote := makeVote(t, key, chainID, uint32(idx), height, round, int(kproto.PrecommitType), blockID, header.Time)
+		_, err := voteSet.AddVote(vote)
+		require.NoError(t, err)
+	}
+	return &types.LightBlock{
+		SignedHeader: &types.SignedHeader{Header: header, Commit: voteSet.MakeCommit()},
+		ValidatorSet: vals,
+	}
+}
@@ -0,0 +1,47 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvidenceListByteSize(t *testing.T) {
+	require.Zero(t, EvidenceList(nil).ByteSize())
+
+	evl := EvidenceList{
+		NewMockDuplicateVoteEvidence(1, time.Now(), "test"),
+		NewMockDuplicateVoteEvidence(2, time.Now(), "test"),
+	}
+	data := &EvidenceData{Evidence: evl}
+	pbe, err := data.ToProto()
+	require.NoError(t, err)
+	require.Equal(t, int64(pbe.Size()), evl.ByteSize())
+
+	// The list is budgeted by its encoding, not by a fixed size per evidence.
+	var sum int64
+	for _, ev := range evl {
+		require.Equal(t, int64(len(ev.Bytes())), ev.ByteSize())
+		sum += ev.ByteSize()
+	}
+	require.Greater(t, evl.ByteSize(), sum)
+}
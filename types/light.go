@@ -0,0 +1,214 @@
+/*
+ *  Copyright 2022 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+)
+
+// SignedHeader is a header along with the commit that proves it.
+type SignedHeader struct {
+	*Header
+
+	Commit *Commit
+}
+
+type signedHeaderJSON struct {
+	Header *Header `json:"header"`
+	Commit *Commit `json:"commit"`
+}
+
+// MarshalJSON implements json.Marshaler. The JSON methods of the embedded
+// Header would otherwise be promoted and drop the commit.
+func (sh SignedHeader) MarshalJSON() ([]byte, error) {
+	return json.Marshal(signedHeaderJSON{Header: sh.Header, Commit: sh.Commit})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (sh *SignedHeader) UnmarshalJSON(input []byte) error {
+	var dec signedHeaderJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	sh.Header, sh.Commit = dec.Header, dec.Commit
+	return nil
+}
+
+// ValidateBasic checks that the commit is for the header. It doesn't verify
+// the signatures.
+func (sh SignedHeader) ValidateBasic() error {
+	if sh.Header == nil {
+		return errors.New("missing header")
+	}
+	if sh.Commit == nil {
+		return errors.New("missing commit")
+	}
+	if err := sh.Header.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid header: %w", err)
+	}
+	if err := sh.Commit.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid commit: %w", err)
+	}
+	if sh.Commit.Height != sh.Height {
+		return fmt.Errorf("header and commit height mismatch: %d vs %d", sh.Height, sh.Commit.Height)
+	}
+	if hash := sh.Header.Hash(); !sh.Commit.BlockID.Hash.Equal(hash) {
+		return fmt.Errorf("commit signs block %v, header is block %v", sh.Commit.BlockID.Hash, hash)
+	}
+	return nil
+}
+
+// String returns a string representation of SignedHeader.
+func (sh SignedHeader) String() string {
+	return fmt.Sprintf("SignedHeader{%v %v}", sh.Header, sh.Commit)
+}
+
+// ToProto converts SignedHeader to protobuf
+func (sh *SignedHeader) ToProto() *kproto.SignedHeader {
+	if sh == nil {
+		return nil
+	}
+	return &kproto.SignedHeader{
+		Header: sh.Header.ToProto(),
+		Commit: sh.Commit.ToProto(),
+	}
+}
+
+// SignedHeaderFromProto sets a protobuf SignedHeader to the given pointer.
+// It returns an error if the header or the commit is invalid.
+func SignedHeaderFromProto(shp *kproto.SignedHeader) (*SignedHeader, error) {
+	if shp == nil {
+		return nil, errors.New("nil SignedHeader")
+	}
+	sh := new(SignedHeader)
+	if shp.Header != nil {
+		h, err := HeaderFromProto(shp.Header)
+		if err != nil {
+			return nil, err
+		}
+		sh.Header = &h
+	}
+	if shp.Commit != nil {
+		c, err := CommitFromProto(shp.Commit)
+		if err != nil {
+			return nil, err
+		}
+		sh.Commit = c
+	}
+	return sh, nil
+}
+
+// LightBlock is a SignedHeader and the validator set that signed it, which is
+// everything a light client needs to verify a header.
+type LightBlock struct {
+	*SignedHeader
+
+	ValidatorSet *ValidatorSet
+}
+
+type lightBlockJSON struct {
+	SignedHeader *SignedHeader `json:"signedHeader"`
+	ValidatorSet *ValidatorSet `json:"validatorSet"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (lb LightBlock) MarshalJSON() ([]byte, error) {
+	return json.Marshal(lightBlockJSON{SignedHeader: lb.SignedHeader, ValidatorSet: lb.ValidatorSet})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (lb *LightBlock) UnmarshalJSON(input []byte) error {
+	var dec lightBlockJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	lb.SignedHeader, lb.ValidatorSet = dec.SignedHeader, dec.ValidatorSet
+	return nil
+}
+
+// ValidateBasic checks that the signed header is consistent and the validator
+// set is the one the header commits to.
+func (lb LightBlock) ValidateBasic() error {
+	if lb.SignedHeader == nil {
+		return errors.New("missing signed header")
+	}
+	if lb.ValidatorSet == nil {
+		return errors.New("missing validator set")
+	}
+	if err := lb.SignedHeader.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid signed header: %w", err)
+	}
+	if err := lb.ValidatorSet.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid validator set: %w", err)
+	}
+	if hash := lb.ValidatorSet.Hash(); !hash.Equal(lb.ValidatorsHash) {
+		return fmt.Errorf("expected validator hash of header to match validator set hash (%v != %v)",
+			lb.ValidatorsHash, hash)
+	}
+	return nil
+}
+
+// String returns a string representation of LightBlock.
+func (lb LightBlock) String() string {
+	return fmt.Sprintf("LightBlock{%v %v}", lb.SignedHeader, lb.ValidatorSet.StringIndented(""))
+}
+
+// ToProto converts LightBlock to protobuf
+func (lb *LightBlock) ToProto() (*kproto.LightBlock, error) {
+	if lb == nil {
+		return nil, nil
+	}
+	lbp := &kproto.LightBlock{SignedHeader: lb.SignedHeader.ToProto()}
+	if lb.ValidatorSet != nil {
+		vsp, err := lb.ValidatorSet.ToProto()
+		if err != nil {
+			return nil, err
+		}
+		lbp.ValidatorSet = vsp
+	}
+	return lbp, nil
+}
+
+// LightBlockFromProto converts from protobuf back into the LightBlock.
+// An error is returned if either the validator set or signed header are invalid
+func LightBlockFromProto(lbp *kproto.LightBlock) (*LightBlock, error) {
+	if lbp == nil {
+		return nil, errors.New("nil light block")
+	}
+	lb := new(LightBlock)
+	if lbp.SignedHeader != nil {
+		sh, err := SignedHeaderFromProto(lbp.SignedHeader)
+		if err != nil {
+			return nil, err
+		}
+		lb.SignedHeader = sh
+	}
+	if lbp.ValidatorSet != nil {
+		vals, err := ValidatorSetFromProto(lbp.ValidatorSet)
+		if err != nil {
+			return nil, err
+		}
+		lb.ValidatorSet = vals
+	}
+	return lb, nil
+}
@@ -19,6 +19,7 @@
 package types
 
 import (
+	"fmt"
 	"time"
 
 	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
@@ -67,3 +68,71 @@ func DefaultEvidenceParams() kproto.EvidenceParams {
 func DefaultValidatorParams() kproto.ValidatorParams {
 	return kproto.ValidatorParams{}
 }
+
+// ValidateConsensusParams validates the consensus params.
+func ValidateConsensusParams(params kproto.ConsensusParams) error {
+	if params.Block.MaxBytes <= 0 {
+		return fmt.Errorf("block.MaxBytes must be greater than 0. Got %d", params.Block.MaxBytes)
+	}
+	if params.Block.MaxBytes > MaxBlockSizeBytes {
+		return fmt.Errorf("block.MaxBytes is too big. %d > %d", params.Block.MaxBytes, MaxBlockSizeBytes)
+	}
+	if params.Evidence.MaxAgeNumBlocks <= 0 {
+		return fmt.Errorf("evidence.MaxAgeNumBlocks must be greater than 0. Got %d", params.Evidence.MaxAgeNumBlocks)
+	}
+	if params.Evidence.MaxAgeDuration <= 0 {
+		return fmt.Errorf("evidence.MaxAgeDuration must be greater than 0. Got %v", params.Evidence.MaxAgeDuration)
+	}
+	if params.Evidence.MaxBytes > params.Block.MaxBytes {
+		return fmt.Errorf("evidence.MaxBytes is greater than the block.MaxBytes. %d > %d",
+			params.Evidence.MaxBytes, params.Block.MaxBytes)
+	}
+	if params.Evidence.MaxBytes < 0 {
+		return fmt.Errorf("evidence.MaxBytes must be non negative. Got %d", params.Evidence.MaxBytes)
+	}
+	timeouts := params.Timeout
+	for _, t := range []time.Duration{timeouts.Propose, timeouts.ProposeDelta, timeouts.Prevote, timeouts.PrevoteDelta,
+		timeouts.Precommit, timeouts.PrecommitDelta, timeouts.Commit} {
+		if t < 0 {
+			return fmt.Errorf("timeouts must be non negative. Got %v", t)
+		}
+	}
+	return nil
+}
+
+// UpdateConsensusParams returns a copy of params with the non zero fields of
+// the block, evidence and timeout params of update applied to it.
+func UpdateConsensusParams(params kproto.ConsensusParams, update *kproto.ConsensusParams) kproto.ConsensusParams {
+	if update == nil {
+		return params
+	}
+	if update.Block.MaxBytes > 0 {
+		params.Block.MaxBytes = update.Block.MaxBytes
+	}
+	if update.Block.MaxGas > 0 {
+		params.Block.MaxGas = update.Block.MaxGas
+	}
+	if update.Evidence.MaxAgeNumBlocks > 0 {
+		params.Evidence.MaxAgeNumBlocks = update.Evidence.MaxAgeNumBlocks
+	}
+	if update.Evidence.MaxAgeDuration > 0 {
+		params.Evidence.MaxAgeDuration = update.Evidence.MaxAgeDuration
+	}
+	if update.Evidence.MaxBytes > 0 {
+		params.Evidence.MaxBytes = update.Evidence.MaxBytes
+	}
+	updateDuration(&params.Timeout.Propose, update.Timeout.Propose)
+	updateDuration(&params.Timeout.ProposeDelta, update.Timeout.ProposeDelta)
+	updateDuration(&params.Timeout.Prevote, update.Timeout.Prevote)
+	updateDuration(&params.Timeout.PrevoteDelta, update.Timeout.PrevoteDelta)
+	updateDuration(&params.Timeout.Precommit, update.Timeout.Precommit)
+	updateDuration(&params.Timeout.PrecommitDelta, update.Timeout.PrecommitDelta)
+	updateDuration(&params.Timeout.Commit, update.Timeout.Commit)
+	return params
+}
+
+func updateDuration(d *time.Duration, update time.Duration) {
+	if update > 0 {
+		*d = update
+	}
+}
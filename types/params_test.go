@@ -0,0 +1,78 @@
+/*
+ *  Copyright 2023 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
+)
+
+func TestUpdateConsensusParams(t *testing.T) {
+	params := *DefaultConsensusParams()
+
+	assert.Equal(t, params, UpdateConsensusParams(params, nil))
+	assert.Equal(t, params, UpdateConsensusParams(params, &kproto.ConsensusParams{}))
+
+	updated := UpdateConsensusParams(params, &kproto.ConsensusParams{
+		Block:    kproto.BlockParams{MaxGas: 1000},
+		Evidence: kproto.EvidenceParams{MaxAgeDuration: time.Hour},
+		Timeout:  kproto.TimeoutParams{Propose: time.Second, Commit: 2 * time.Second},
+	})
+	assert.EqualValues(t, 1000, updated.Block.MaxGas)
+	assert.Equal(t, params.Block.MaxBytes, updated.Block.MaxBytes)
+	assert.Equal(t, params.Block.TimeIotaMs, updated.Block.TimeIotaMs)
+	assert.Equal(t, time.Hour, updated.Evidence.MaxAgeDuration)
+	assert.Equal(t, params.Evidence.MaxAgeNumBlocks, updated.Evidence.MaxAgeNumBlocks)
+	assert.Equal(t, time.Second, updated.Timeout.Propose)
+	assert.Equal(t, 2*time.Second, updated.Timeout.Commit)
+	assert.Zero(t, updated.Timeout.Prevote)
+	// The params are copied.
+	assert.Zero(t, params.Block.MaxGas)
+}
+
+func TestValidateConsensusParams(t *testing.T) {
+	testCases := []struct {
+		name    string
+		update  func(*kproto.ConsensusParams)
+		wantErr bool
+	}{
+		{"default", func(*kproto.ConsensusParams) {}, false},
+		{"no block bytes", func(p *kproto.ConsensusParams) { p.Block.MaxBytes = 0 }, true},
+		{"too many block bytes", func(p *kproto.ConsensusParams) { p.Block.MaxBytes = MaxBlockSizeBytes + 1 }, true},
+		{"no evidence age", func(p *kproto.ConsensusParams) { p.Evidence.MaxAgeNumBlocks = 0 }, true},
+		{"no evidence duration", func(p *kproto.ConsensusParams) { p.Evidence.MaxAgeDuration = 0 }, true},
+		{"evidence bigger than block", func(p *kproto.ConsensusParams) { p.Evidence.MaxBytes = p.Block.MaxBytes + 1 }, true},
+		{"negative timeout", func(p *kproto.ConsensusParams) { p.Timeout.PrecommitDelta = -time.Second }, true},
+		{"timeouts", func(p *kproto.ConsensusParams) { p.Timeout.Propose = time.Second }, false},
+	}
+	for _, tc := range testCases {
+		params := *DefaultConsensusParams()
+		tc.update(&params)
+		err := ValidateConsensusParams(params)
+		if tc.wantErr {
+			assert.Error(t, err, tc.name)
+		} else {
+			assert.NoError(t, err, tc.name)
+		}
+	}
+}
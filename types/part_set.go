@@ -20,6 +20,7 @@ package types
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"sync"
@@ -338,3 +339,15 @@ func (ps *PartSet) StringShort() string {
 	defer ps.mtx.Unlock()
 	return fmt.Sprintf("(%v of %v)", ps.Count(), ps.Total())
 }
+
+// MarshalJSON marshals the number of parts received out of the total, with
+// their bit array.
+func (ps *PartSet) MarshalJSON() ([]byte, error) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+	return json.Marshal(struct {
+		Count         uint32 `json:"count"`
+		Total         uint32 `json:"total"`
+		PartsBitArray string `json:"parts_bit_array"`
+	}{ps.count, ps.total, ps.partsBitArray.String()})
+}
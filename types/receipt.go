@@ -215,6 +215,13 @@ type Receipts []*Receipt
 // Len returns the number of receipts in this list.
 func (r Receipts) Len() int { return len(r) }
 
+// EncodeIndex encodes the i'th receipt to w in its consensus encoding.
+func (r Receipts) EncodeIndex(i int, w *bytes.Buffer) {
+	if err := rlp.Encode(w, r[i]); err != nil {
+		panic(err)
+	}
+}
+
 // GetRlp returns the RLP encoding of one receipt from the list.
 func (r Receipts) GetRlp(i int) []byte {
 	bytes, err := rlp.EncodeToBytes(r[i])
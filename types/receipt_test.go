@@ -19,6 +19,7 @@
 package types
 
 import (
+	"bytes"
 	"math/big"
 	"testing"
 
@@ -70,3 +71,14 @@ func CreateNewReceipt() *Receipt {
 		TxHash:            rlpHash(emptyTx),
 	}
 }
+
+func TestReceiptsEncodeIndex(t *testing.T) {
+	receipts := Receipts{CreateNewReceipt(), NewReceipt(true, 42)}
+	for i := range receipts {
+		var buf bytes.Buffer
+		receipts.EncodeIndex(i, &buf)
+		if !bytes.Equal(buf.Bytes(), receipts.GetRlp(i)) {
+			t.Errorf("receipt %d: encoding mismatch, have %x, want %x", i, buf.Bytes(), receipts.GetRlp(i))
+		}
+	}
+}
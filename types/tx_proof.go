@@ -0,0 +1,140 @@
+/*
+ *  Copyright 2022 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/kardiachain/go-kardia/kai/kaidb"
+	"github.com/kardiachain/go-kardia/kai/kaidb/memorydb"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/lib/crypto"
+	"github.com/kardiachain/go-kardia/lib/rlp"
+)
+
+// ProofVerifier checks a Merkle-Patricia proof of key in the trie with the
+// given root, returning the proven value, or nil if the proof shows the key
+// is absent. It's implemented by trie.VerifyProof.
+// This is internal, do not use.
+type ProofVerifier func(root common.Hash, key []byte, proofDb kaidb.KeyValueReader) ([]byte, error)
+
+// TxProof proves that a transaction is included in a block: Tx is the leaf at
+// Index of the trie whose root is the TxHash of the block header, see
+// DeriveSha.
+type TxProof struct {
+	BlockHash   common.Hash    `json:"blockHash"`
+	BlockHeight uint64         `json:"blockHeight"`
+	Index       uint64         `json:"index"`
+	Tx          common.Bytes   `json:"tx"`
+	Proof       []common.Bytes `json:"proof"`
+}
+
+// Transaction decodes the proven transaction.
+func (p *TxProof) Transaction() (*Transaction, error) {
+	tx := new(Transaction)
+	if err := rlp.DecodeBytes(p.Tx, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// Verify checks that the transaction is included in the block of the given
+// header.
+func (p *TxProof) Verify(header *Header, verify ProofVerifier) error {
+	if header == nil {
+		return errors.New("nil header")
+	}
+	if p.BlockHeight != header.Height {
+		return fmt.Errorf("proof for block #%d, header #%d", p.BlockHeight, header.Height)
+	}
+	if !p.BlockHash.Equal(header.Hash()) {
+		return fmt.Errorf("proof for block %v, header %v", p.BlockHash.Hex(), header.Hash().Hex())
+	}
+	if err := verifyLeaf(header.TxHash, p.Index, p.Tx, p.Proof, verify); err != nil {
+		return fmt.Errorf("tx proof: %w", err)
+	}
+	return nil
+}
+
+// ReceiptProof proves the receipt of a transaction: Receipt is the consensus
+// encoding of the leaf at the index of the transaction in the trie of the
+// receipts of the block, whose root is ReceiptsRoot.
+//
+// NOTE: the header doesn't commit to the receipts of its block, only the
+// transaction proof ties the receipt to the header. ReceiptsRoot is the one
+// computed by the node serving the proof, so Verify takes the receipts root
+// the caller trusts, e.g. obtained by re-executing the block.
+type ReceiptProof struct {
+	TxProof      *TxProof       `json:"txProof"`
+	ReceiptsRoot common.Hash    `json:"receiptsRoot"`
+	Receipt      common.Bytes   `json:"receipt"`
+	Proof        []common.Bytes `json:"proof"`
+}
+
+// DecodeReceipt decodes the consensus fields of the proven receipt.
+func (p *ReceiptProof) DecodeReceipt() (*Receipt, error) {
+	receipt := new(Receipt)
+	if err := rlp.DecodeBytes(p.Receipt, receipt); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// Verify checks that the transaction is included in the block of the given
+// header and that the receipt is the one of the transaction in the trusted
+// receipts of the block, with the root receiptsRoot.
+func (p *ReceiptProof) Verify(header *Header, receiptsRoot common.Hash, verify ProofVerifier) error {
+	if p.TxProof == nil {
+		return errors.New("missing tx proof")
+	}
+	if err := p.TxProof.Verify(header, verify); err != nil {
+		return err
+	}
+	if !p.ReceiptsRoot.Equal(receiptsRoot) {
+		return fmt.Errorf("proof for receipts root %v, trusted root %v", p.ReceiptsRoot.Hex(), receiptsRoot.Hex())
+	}
+	if err := verifyLeaf(receiptsRoot, p.TxProof.Index, p.Receipt, p.Proof, verify); err != nil {
+		return fmt.Errorf("receipt proof: %w", err)
+	}
+	return nil
+}
+
+// verifyLeaf checks that value is the leaf at index in the trie of a derivable
+// list with the given root.
+func verifyLeaf(root common.Hash, index uint64, value []byte, proof []common.Bytes, verify ProofVerifier) error {
+	db := memorydb.New()
+	for _, node := range proof {
+		if err := db.Put(crypto.Keccak256(node), node); err != nil {
+			return err
+		}
+	}
+	have, err := verify(root, rlp.AppendUint64(nil, index), db)
+	if err != nil {
+		return err
+	}
+	if have == nil {
+		return fmt.Errorf("no leaf at index %d", index)
+	}
+	if !bytes.Equal(have, value) {
+		return fmt.Errorf("leaf at index %d doesn't match", index)
+	}
+	return nil
+}
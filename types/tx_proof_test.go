@@ -0,0 +1,121 @@
+package types_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/kardiachain/go-kardia/trie"
+	"github.com/kardiachain/go-kardia/types"
+)
+
+// deriveProof returns the root of the trie of list, the element at index and
+// its proof.
+func deriveProof(t *testing.T, list types.DerivableList, index int) (common.Hash, []byte, []common.Bytes) {
+	root, value, proof, err := trie.DeriveProof(list, index)
+	require.NoError(t, err)
+	return root, value, proof
+}
+
+func makeTxBlock(t *testing.T, n int) (*types.Block, types.Transactions) {
+	txs := make(types.Transactions, n)
+	for i := range txs {
+		txs[i] = types.NewTransaction(uint64(i), common.HexToAddress("0x1234"), big.NewInt(int64(i)), 21000, big.NewInt(1), nil)
+	}
+	header := &types.Header{Height: 10, Time: time.Now().UTC(), GasLimit: 1e8}
+	return types.NewBlock(header, txs, &types.Commit{}, nil, trie.NewStackTrie(nil)), txs
+}
+
+func txProof(t *testing.T, block *types.Block, index int) *types.TxProof {
+	root, tx, nodes := deriveProof(t, block.Transactions(), index)
+	require.Equal(t, block.Header().TxHash, root)
+	return &types.TxProof{
+		BlockHash:   block.Hash(),
+		BlockHeight: block.Height(),
+		Index:       uint64(index),
+		Tx:          tx,
+		Proof:       nodes,
+	}
+}
+
+func TestTxProof(t *testing.T) {
+	// More than 0x7f txs, for DeriveSha inserts those in another order.
+	block, txs := makeTxBlock(t, 200)
+	for _, index := range []int{0, 1, 0x7f, 0x80, 199} {
+		proof := txProof(t, block, index)
+		require.NoError(t, proof.Verify(block.Header(), trie.VerifyProof), "index %d", index)
+		tx, err := proof.Transaction()
+		require.NoError(t, err)
+		assert.Equal(t, txs[index].Hash(), tx.Hash())
+	}
+
+	other, _ := makeTxBlock(t, 3)
+	testCases := []struct {
+		name   string
+		tamper func(p *types.TxProof)
+		header *types.Header
+	}{
+		{"other header", func(p *types.TxProof) {}, other.Header()},
+		{"other index", func(p *types.TxProof) { p.Index++ }, block.Header()},
+		{"absent index", func(p *types.TxProof) { p.Index = 1000 }, block.Header()},
+		{"other tx", func(p *types.TxProof) { p.Tx = txProof(t, block, 6).Tx }, block.Header()},
+		{"missing node", func(p *types.TxProof) { p.Proof = p.Proof[1:] }, block.Header()},
+		{"other height", func(p *types.TxProof) { p.BlockHeight++ }, block.Header()},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			proof := txProof(t, block, 5)
+			tc.tamper(proof)
+			assert.Error(t, proof.Verify(tc.header, trie.VerifyProof))
+		})
+	}
+}
+
+func TestReceiptProof(t *testing.T) {
+	block, txs := makeTxBlock(t, 20)
+	receipts := make(types.Receipts, len(txs))
+	for i, tx := range txs {
+		receipts[i] = types.NewReceipt(i%2 == 0, uint64(i+1)*21000)
+		receipts[i].TxHash = tx.Hash()
+		receipts[i].Logs = []*types.Log{{Address: common.HexToAddress("0x1234"), Data: []byte{byte(i)}}}
+	}
+
+	makeProof := func(index int) *types.ReceiptProof {
+		root, receipt, nodes := deriveProof(t, receipts, index)
+		require.Equal(t, types.DeriveSha(receipts, trie.NewStackTrie(nil)), root)
+		return &types.ReceiptProof{TxProof: txProof(t, block, index), ReceiptsRoot: root, Receipt: receipt, Proof: nodes}
+	}
+
+	root := types.DeriveSha(receipts, trie.NewStackTrie(nil))
+	proof := makeProof(3)
+	require.NoError(t, proof.Verify(block.Header(), root, trie.VerifyProof))
+	receipt, err := proof.DecodeReceipt()
+	require.NoError(t, err)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	assert.EqualValues(t, []byte{3}, receipt.Logs[0].Data)
+
+	// The receipt of another tx.
+	proof = makeProof(3)
+	proof.Receipt = makeProof(4).Receipt
+	assert.Error(t, proof.Verify(block.Header(), root, trie.VerifyProof))
+
+	// The tx proof doesn't match the header.
+	proof = makeProof(3)
+	proof.TxProof.Tx = makeProof(4).TxProof.Tx
+	assert.Error(t, proof.Verify(block.Header(), root, trie.VerifyProof))
+
+	// Receipts forged by the server, consistent with their own root.
+	forged := make(types.Receipts, len(receipts))
+	copy(forged, receipts)
+	forged[3] = types.NewReceipt(false, 21000)
+	forged[3].TxHash = txs[3].Hash()
+	forgedRoot, forgedReceipt, nodes := deriveProof(t, forged, 3)
+	proof = &types.ReceiptProof{TxProof: txProof(t, block, 3), ReceiptsRoot: forgedRoot, Receipt: forgedReceipt, Proof: nodes}
+	require.NoError(t, proof.Verify(block.Header(), forgedRoot, trie.VerifyProof), "valid against its own root")
+	assert.Error(t, proof.Verify(block.Header(), root, trie.VerifyProof))
+}
@@ -30,6 +30,7 @@ import (
 
 	"github.com/kardiachain/go-kardia/lib/common"
 	"github.com/kardiachain/go-kardia/lib/crypto"
+	kmath "github.com/kardiachain/go-kardia/lib/math"
 	"github.com/kardiachain/go-kardia/lib/merkle"
 	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
 )
@@ -262,8 +263,12 @@ func validatorListCopy(valsList []*Validator) []*Validator {
 	return valsCopy
 }
 
-// Copy each validator into a new ValidatorSet.
+// Copy each validator into a new ValidatorSet. The copy of a nil set, such as
+// the last validators of the genesis state, is nil.
 func (vs *ValidatorSet) Copy() *ValidatorSet {
+	if vs == nil {
+		return nil
+	}
 	return &ValidatorSet{
 		Validators:       validatorListCopy(vs.Validators),
 		Proposer:         vs.Proposer,
@@ -698,6 +703,53 @@ func (vs *ValidatorSet) VerifyCommit(chainID string, blockID BlockID, height uin
 	return nil
 }
 
+// VerifyCommitTrusting verifies that more than trustLevel of the set's voting
+// power signed the commit. Unlike VerifyCommit, the set doesn't have to be the
+// one that produced the commit: signatures are matched to validators by
+// address, so a commit can be checked against an older, trusted set.
+func (vs *ValidatorSet) VerifyCommitTrusting(chainID string, commit *Commit, trustLevel kmath.Fraction) error {
+	if vs == nil {
+		return ErrNilValidatorSet
+	}
+	if commit == nil {
+		return ErrNilCommit
+	}
+	if trustLevel.Numerator <= 0 || trustLevel.Denominator <= 0 {
+		return errors.Errorf("invalid trust level %v", trustLevel)
+	}
+	totalVotingPower := vs.TotalVotingPower()
+	if totalVotingPower > math.MaxInt64/trustLevel.Numerator {
+		return errors.Errorf("int64 overflow while calculating voting power needed for trust level %v", trustLevel)
+	}
+	votingPowerNeeded := totalVotingPower * trustLevel.Numerator / trustLevel.Denominator
+
+	talliedVotingPower := int64(0)
+	seenVals := make(map[int]int, len(commit.Signatures))
+	for idx, commitSig := range commit.Signatures {
+		if !commitSig.ForBlock() {
+			continue
+		}
+		valIdx, val := vs.GetByAddress(commitSig.ValidatorAddress)
+		if val == nil {
+			continue // Not a validator of the trusted set.
+		}
+		if firstIdx, ok := seenVals[valIdx]; ok {
+			return errors.Errorf("double vote from %v (#%d and #%d)", val.Address, firstIdx, idx)
+		}
+		seenVals[valIdx] = idx
+
+		signBytes := commit.VoteSignBytes(chainID, uint32(idx))
+		if !VerifySignature(val.Address, crypto.Keccak256(signBytes), commitSig.Signature) {
+			return errors.Errorf("wrong signature (#%d): %X", idx, commitSig.Signature)
+		}
+		talliedVotingPower += val.VotingPower
+		if talliedVotingPower > votingPowerNeeded {
+			return nil
+		}
+	}
+	return ErrNotEnoughVotingPowerSigned{Got: talliedVotingPower, Needed: votingPowerNeeded}
+}
+
 // IsErrTooMuchChange returns too much change error
 func IsErrTooMuchChange(err error) bool {
 	_, ok := errors.Cause(err).(errTooMuchChange)
@@ -28,6 +28,7 @@ import (
 	"strings"
 	"testing"
 	"testing/quick"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -35,6 +36,7 @@ import (
 	"github.com/kardiachain/go-kardia/lib/crypto"
 	kmath "github.com/kardiachain/go-kardia/lib/math"
 	krand "github.com/kardiachain/go-kardia/lib/rand"
+	kproto "github.com/kardiachain/go-kardia/proto/kardiachain/types"
 )
 
 func TestValidatorSetBasic(t *testing.T) {
@@ -171,6 +173,11 @@ func TestCopy(t *testing.T) {
 	if !vsetHash.Equal(vsetCopyHash) {
 		t.Fatalf("ValidatorSet copy had wrong hash. Orig: %X, Copy: %X", vsetHash, vsetCopyHash)
 	}
+
+	var nilSet *ValidatorSet
+	if nilSet.Copy() != nil {
+		t.Fatalf("Copy of a nil ValidatorSet should be nil")
+	}
 }
 
 // Test that IncrementProposerPriority requires positive times.
@@ -1204,3 +1211,26 @@ func (valz validatorsByPriority) Less(i, j int) bool {
 func (valz validatorsByPriority) Swap(i, j int) {
 	valz[i], valz[j] = valz[j], valz[i]
 }
+
+func TestValidatorSetVerifyCommitTrusting(t *testing.T) {
+	voteSet, vals, privVals := randVoteSet(1, 0, kproto.PrecommitType, 4, 10)
+	blockID := BlockID{Hash: common.BytesToHash([]byte("block")),
+		PartsHeader: PartSetHeader{Total: 1, Hash: common.BytesToHash([]byte("parts"))}}
+	commit, err := MakeCommit(blockID, 1, 0, voteSet, privVals, time.Now())
+	assert.NoError(t, err)
+
+	// Half of the signers, along with validators that didn't sign.
+	other, _ := RandValidatorSet(2, 10)
+	mixed := NewValidatorSet(append([]*Validator{vals.Validators[0].Copy(), vals.Validators[1].Copy()},
+		other.Validators...))
+	oneThird := kmath.Fraction{Numerator: 1, Denominator: 3}
+
+	assert.NoError(t, vals.VerifyCommitTrusting("test_chain_id", commit, oneThird))
+	assert.NoError(t, mixed.VerifyCommitTrusting("test_chain_id", commit, oneThird))
+	err = mixed.VerifyCommitTrusting("test_chain_id", commit, kmath.Fraction{Numerator: 2, Denominator: 3})
+	assert.True(t, IsErrNotEnoughVotingPowerSigned(err), "unexpected error: %v", err)
+	err = other.VerifyCommitTrusting("test_chain_id", commit, oneThird)
+	assert.True(t, IsErrNotEnoughVotingPowerSigned(err), "unexpected error: %v", err)
+	assert.Error(t, vals.VerifyCommitTrusting("other_chain_id", commit, oneThird))
+	assert.Error(t, vals.VerifyCommitTrusting("test_chain_id", commit, kmath.Fraction{}))
+}
@@ -20,6 +20,7 @@ package types
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"sync"
 
@@ -444,6 +445,53 @@ func (voteSet *VoteSet) StringShort() string {
 		voteSet.height, voteSet.round, voteSet.signedMsgType, voteSet.maj23, frac, voteSet.votesBitArray, voteSet.peerMaj23s)
 }
 
+// voteSetJSON is the JSON form of a VoteSet.
+type voteSetJSON struct {
+	Votes         []string           `json:"votes"`
+	VotesBitArray string             `json:"votes_bit_array"`
+	PeerMaj23s    map[p2p.ID]BlockID `json:"peer_maj_23s"`
+}
+
+// MarshalJSON marshals the votes, "nil-Vote" for the validators that haven't
+// voted, with the voting power they sum up to.
+func (voteSet *VoteSet) MarshalJSON() ([]byte, error) {
+	voteSet.mtx.Lock()
+	defer voteSet.mtx.Unlock()
+	return json.Marshal(voteSetJSON{
+		Votes:         voteSet.voteStrings(),
+		VotesBitArray: voteSet.bitArrayString(),
+		PeerMaj23s:    voteSet.peerMaj23s,
+	})
+}
+
+// BitArrayString returns the bit array of the votes with the voting power
+// they sum up to, e.g. "BA{4:xx_x} 30/40 = 0.75".
+func (voteSet *VoteSet) BitArrayString() string {
+	if voteSet == nil {
+		return "nil-VoteSet"
+	}
+	voteSet.mtx.Lock()
+	defer voteSet.mtx.Unlock()
+	return voteSet.bitArrayString()
+}
+
+func (voteSet *VoteSet) bitArrayString() string {
+	voted, total, frac := voteSet.sumTotalFrac()
+	return fmt.Sprintf("%s %d/%d = %.2f", voteSet.votesBitArray, voted, total, frac)
+}
+
+func (voteSet *VoteSet) voteStrings() []string {
+	votes := make([]string, len(voteSet.votes))
+	for i, vote := range voteSet.votes {
+		if vote == nil {
+			votes[i] = "nil-Vote"
+		} else {
+			votes[i] = vote.String()
+		}
+	}
+	return votes
+}
+
 // return the power voted, the total, and the fraction
 func (voteSet *VoteSet) sumTotalFrac() (int64, int64, float64) {
 	voted, total := voteSet.sum, voteSet.valSet.TotalVotingPower()